@@ -0,0 +1,519 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/state"
+)
+
+// --- Archive and restore: deployments taken out of apps_path and brought back ---
+//
+// Archive runs terraform destroy first (through the usual destroy confirmation) or keeps the
+// resources, then packs the deployment directory — tfvars, launcher.state with its history,
+// local state, notes; not .terraform — into <archives_path>/<name>-<UTC time>.tar.gz, writes
+// who archived it and in which state to a .yaml next to it, and removes the directory. The
+// archives screen lists them; Restore unpacks one back into apps_path under its name and
+// queues terraform init. The archive stays, so the same one can be restored again later.
+
+const archiveSuffix = ".tar.gz"
+
+// The .yaml next to an archive
+type archiveMeta struct {
+	Name           string   `yaml:"name"`
+	Template       string   `yaml:"template,omitempty"`
+	TemplateCommit string   `yaml:"template_commit,omitempty"`
+	Environment    string   `yaml:"environment,omitempty"`
+	State          string   `yaml:"state"`
+	Destroyed      bool     `yaml:"destroyed"`
+	ArchivedAt     string   `yaml:"archived_at"`
+	By             identity `yaml:"by"`
+	Size           int64    `yaml:"size"`
+	// The .tar.gz, set when listing
+	File string `yaml:"-"`
+}
+
+// Archive waiting for the choice between destroying first and keeping the resources
+type archiveDialog struct {
+	dep deploymentInfo
+}
+
+type archiveDoneMsg struct {
+	dir    string
+	status string
+}
+
+type archivesListedMsg struct {
+	archives []archiveMeta
+	err      error
+}
+
+type archiveRestoredMsg struct {
+	dir string
+	err error
+}
+
+// archives_path, default "archives" next to apps_path so it never shows up as a deployment
+func archivesPath(cfg Config) string {
+	if cfg.ArchivesPath != "" {
+		return cfg.ArchivesPath
+	}
+	return filepath.Join(filepath.Dir(filepath.Clean(cfg.AppsPath)), "archives")
+}
+
+// Left out of archives: init downloads the providers again, and the lock is the job's
+func skipInArchive(rel string) bool {
+	return rel == ".terraform" || rel == deployLockFile
+}
+
+// Packs dir into file (gzipped tar, paths relative to dir) and returns the file's size
+func writeArchive(dir, file string) (size int64, err error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(file)
+		}
+	}()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if skipInArchive(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return st.Size(), nil
+}
+
+// Unpacks file into dest; entries escaping dest are refused, as are symlinks pointing out
+// of it and entries below a symlink of the archive
+func extractArchive(file, dest string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	links := map[string]bool{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("%s: %q is outside the deployment directory", filepath.Base(file), hdr.Name)
+		}
+		for parent := filepath.Dir(name); parent != "."; parent = filepath.Dir(parent) {
+			if links[parent] {
+				return fmt.Errorf("%s: %q is below the symlink %q", filepath.Base(file), hdr.Name, parent)
+			}
+		}
+		path := filepath.Join(dest, name)
+		mode := hdr.FileInfo().Mode()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, mode.Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			target := filepath.FromSlash(hdr.Linkname)
+			if filepath.IsAbs(target) || !filepath.IsLocal(filepath.Join(filepath.Dir(name), target)) {
+				return fmt.Errorf("%s: symlink %q points outside the deployment directory (%s)", filepath.Base(file), hdr.Name, hdr.Linkname)
+			}
+			if err := os.Symlink(hdr.Linkname, path); err != nil {
+				return err
+			}
+			links[filepath.Clean(name)] = true
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Packs the deployment into the archives and removes it from apps_path; destroyed tells
+// whether terraform destroy ran just before. On failure the deployment stays as it was.
+func archiveDeployment(cfg Config, dir string, destroyed bool) (meta archiveMeta, err error) {
+	s, _ := getDeploymentState(dir)
+	// The history inside the archive ends with the archive itself; put the previous
+	// launcher.state back if the archive can't be made
+	statePath := filepath.Join(dir, state.File)
+	previous, readErr := os.ReadFile(statePath)
+	if err := setDeploymentState(dir, s.State, "archive"); err != nil {
+		return archiveMeta{}, err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if readErr == nil {
+			os.WriteFile(statePath, previous, 0644)
+		} else {
+			os.Remove(statePath)
+		}
+	}()
+	now := time.Now().UTC()
+	meta = archiveMeta{
+		Name:           filepath.Base(dir),
+		Template:       s.Template,
+		TemplateCommit: s.TemplateCommit,
+		Environment:    s.Environment,
+		State:          s.State,
+		Destroyed:      destroyed,
+		ArchivedAt:     now.Format(time.RFC3339),
+		By:             currentIdentity(),
+	}
+	root := archivesPath(cfg)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return meta, err
+	}
+	stem := filepath.Join(root, meta.Name+"-"+now.Format("20060102-150405"))
+	meta.File = stem + archiveSuffix
+	size, err := writeArchive(dir, meta.File)
+	if err != nil {
+		recordAudit("archive", dir, meta.File, "failed: "+err.Error())
+		return meta, err
+	}
+	meta.Size = size
+	data, err := yaml.Marshal(meta)
+	if err == nil {
+		err = os.WriteFile(stem+".yaml", data, 0644)
+	}
+	if err == nil {
+		err = os.RemoveAll(dir)
+	}
+	if err != nil {
+		recordAudit("archive", dir, meta.File, "failed: "+err.Error())
+		return meta, err
+	}
+	recordAudit("archive", dir, meta.File, "ok")
+	logger.Info("deployment archived", "component", "archive", "deployment", meta.Name, "file", meta.File, "destroyed", destroyed)
+	return meta, nil
+}
+
+// Archives in archives_path, newest first
+func listArchives(cfg Config) ([]archiveMeta, error) {
+	root := archivesPath(cfg)
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var archives []archiveMeta
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), archiveSuffix) {
+			continue
+		}
+		file := filepath.Join(root, e.Name())
+		var a archiveMeta
+		if data, err := os.ReadFile(strings.TrimSuffix(file, archiveSuffix) + ".yaml"); err == nil {
+			yaml.Unmarshal(data, &a)
+		}
+		if a.Name == "" {
+			// No metadata: the name is what comes before -<date>-<time>
+			stem := strings.TrimSuffix(e.Name(), archiveSuffix)
+			a.Name, a.State = stem, "UNKNOWN"
+			if parts := strings.Split(stem, "-"); len(parts) > 2 {
+				a.Name = strings.Join(parts[:len(parts)-2], "-")
+			}
+		}
+		a.File = file
+		archives = append(archives, a)
+	}
+	slices.SortFunc(archives, func(a, b archiveMeta) int { return strings.Compare(b.ArchivedAt, a.ArchivedAt) })
+	return archives, nil
+}
+
+// Unpacks the archive into apps_path under its name; refused when that directory exists
+// or when the name (from the .yaml next to the archive) isn't a plain directory name
+func restoreArchive(cfg Config, a archiveMeta) (string, error) {
+	if !filepath.IsLocal(a.Name) || strings.ContainsAny(a.Name, `/\`) || a.Name == "." {
+		return "", fmt.Errorf("%s: invalid deployment name %q", filepath.Base(a.File), a.Name)
+	}
+	dir := filepath.Join(cfg.AppsPath, a.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return dir, fmt.Errorf("%s already exists in %s", a.Name, cfg.AppsPath)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return dir, err
+	}
+	if err := extractArchive(a.File, dir); err != nil {
+		os.RemoveAll(dir)
+		recordAudit("restore", dir, a.File, "failed: "+err.Error())
+		return dir, err
+	}
+	s, _ := getDeploymentState(dir)
+	if err := setDeploymentState(dir, s.State, "restore"); err != nil {
+		return dir, err
+	}
+	recordAudit("restore", dir, a.File, "ok")
+	return dir, nil
+}
+
+// Opens the archive choice for the selected deployment
+func openArchiveDialog(m model) (model, tea.Cmd) {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m, nil
+	}
+	dep := m.deployments[idx]
+	if err := deploymentBusy(m, dep.Path); err != nil {
+		m.statusMessage = err.Error()
+		return m, nil
+	}
+	m.archiveConfirm = &archiveDialog{dep: dep}
+	return m, nil
+}
+
+// Destroy goes through the destroy confirmation (and its guard rails); keep archives now
+func updateArchiveDialog(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	dep := m.archiveConfirm.dep
+	m.archiveConfirm = nil
+	k := keys.Archive
+	switch {
+	case key.Matches(msg, k.Destroy):
+		m, cmd := confirmDestroy(m)
+		if m.destroyConfirm != nil {
+			m.destroyConfirm.archive = true
+		}
+		return m, cmd
+	case key.Matches(msg, k.Keep):
+		if err := deploymentBusy(m, dep.Path); err != nil {
+			m.statusMessage = err.Error()
+			return m, nil
+		}
+		cfg, dir := m.cfg, dep.Path
+		m.statusMessage = "Archiving " + dep.Name + " ..."
+		return m, func() tea.Msg {
+			// Held while packing so no job, here or in another launcher, starts on the directory
+			if err := acquireDeployLock(dir, "archive"); err != nil {
+				return archiveDoneMsg{dir: dir, status: "Not archiving " + filepath.Base(dir) + ": " + err.Error()}
+			}
+			defer releaseDeployLock(dir)
+			a, err := archiveDeployment(cfg, dir, false)
+			if err != nil {
+				return archiveDoneMsg{dir: dir, status: "Archive of " + filepath.Base(dir) + " failed: " + err.Error()}
+			}
+			return archiveDoneMsg{dir: dir, status: fmt.Sprintf("Archived %s to %s — its resources were left running", a.Name, a.File)}
+		}
+	}
+	m.statusMessage = "Archive of " + dep.Name + " cancelled"
+	return m, nil
+}
+
+func handleArchiveDone(m model, msg archiveDoneMsg) (model, tea.Cmd) {
+	m.statusMessage = msg.status
+	return m, refreshDeploymentCmd(msg.dir)
+}
+
+func viewArchiveDialog(m model) string {
+	d := m.archiveConfirm
+	k := keys.Archive
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Archive "+d.dep.Name) + "\n\n")
+	if badge := environmentBadge(d.dep.Environment); badge != "" {
+		b.WriteString(badge + " ")
+	}
+	fmt.Fprintf(&b, "%s, %s\n\n", d.dep.State, d.dep.Description)
+	fmt.Fprintf(&b, "The directory is packed into %s and removed from the list.\n\n", archivesPath(m.cfg))
+	fmt.Fprintf(&b, "%s  terraform destroy first, then archive\n", k.Destroy.Help().Key)
+	fmt.Fprintf(&b, "%s  archive now and leave the resources running\n", k.Keep.Help().Key)
+	if d.dep.State != "DESTROYED" {
+		b.WriteString(warnStyle.Render("Kept resources are no longer managed from the launcher until restored.") + "\n")
+	}
+	fmt.Fprintf(&b, "\n%s or any other key cancels", k.Cancel.Help().Key)
+	return dialogBox(b.String())
+}
+
+// --- Archives screen ---
+
+func openArchives(m model) (model, tea.Cmd) {
+	m.archives = nil
+	m.archivesStatus = "Listing " + archivesPath(m.cfg) + " ..."
+	m.archivesTable = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Deployment", Width: 36},
+			{Title: "Archived", Width: 18},
+			{Title: "By", Width: 24},
+			{Title: "State", Width: 12},
+			{Title: "Resources", Width: 12},
+			{Title: "Size", Width: 10},
+		}),
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeys(keys.Archives.Up, keys.Archives.Down)),
+		table.WithStyles(tableStyles()),
+		table.WithHeight(uiHeight-16),
+	)
+	return m.pushScene(sceneArchives), listArchivesCmd(m.cfg)
+}
+
+func listArchivesCmd(cfg Config) tea.Cmd {
+	return func() tea.Msg {
+		archives, err := listArchives(cfg)
+		return archivesListedMsg{archives: archives, err: err}
+	}
+}
+
+func archiveRows(archives []archiveMeta) []table.Row {
+	rows := make([]table.Row, len(archives))
+	for i, a := range archives {
+		archived := filepath.Base(a.File)
+		if t, err := time.Parse(time.RFC3339, a.ArchivedAt); err == nil {
+			archived = localTime(t).Format("2006-01-02 15:04")
+		}
+		resources := "kept"
+		if a.Destroyed || a.State == "DESTROYED" {
+			resources = "destroyed"
+		}
+		size := ""
+		if a.Size > 0 {
+			size = formatBytes(a.Size)
+		}
+		rows[i] = table.Row{a.Name, archived, a.By.String(), a.State, resources, size}
+	}
+	return rows
+}
+
+func updateArchives(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	k := keys.Archives
+	switch msg := msg.(type) {
+	case archivesListedMsg:
+		if msg.err != nil {
+			m.archivesStatus = "Could not list archives: " + msg.err.Error()
+			return m, nil
+		}
+		m.archives = msg.archives
+		m.archivesTable.SetRows(archiveRows(msg.archives))
+		m.archivesStatus = plural(len(msg.archives), "archive") + " in " + archivesPath(m.cfg)
+		return m, nil
+	case archiveRestoredMsg:
+		if msg.err != nil {
+			m.archivesStatus = "Restore failed: " + msg.err.Error()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m, cmd = enqueueJob(m, reinitOperation(msg.dir))
+		m.archivesStatus = fmt.Sprintf("Restored %s to %s; queued job #%d: terraform init", filepath.Base(msg.dir), msg.dir, m.nextJobID)
+		return m, tea.Batch(cmd, refreshDeploymentCmd(msg.dir))
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, k.Back):
+			return m.popScene(), nil
+		case key.Matches(msg, k.Reload):
+			m.archivesStatus = "Reloading..."
+			return m, listArchivesCmd(m.cfg)
+		case key.Matches(msg, k.Restore):
+			i := m.archivesTable.Cursor()
+			if i < 0 || i >= len(m.archives) {
+				return m, nil
+			}
+			cfg, a := m.cfg, m.archives[i]
+			m.archivesStatus = "Restoring " + a.Name + " ..."
+			return m, func() tea.Msg {
+				dir, err := restoreArchive(cfg, a)
+				return archiveRestoredMsg{dir: dir, err: err}
+			}
+		}
+	}
+	var cmd tea.Cmd
+	m.archivesTable, cmd = m.archivesTable.Update(msg)
+	return m, cmd
+}
+
+func viewArchives(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(fmt.Sprintf("Archived deployments — %s restores one into %s and runs terraform init",
+		keys.Archives.Restore.Help().Key, m.cfg.AppsPath))
+	body += "\n" + m.archivesTable.View() + "\n"
+	return body, tooltipStyle.Render(m.archivesStatus)
+}
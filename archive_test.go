@@ -0,0 +1,166 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/filelock"
+)
+
+type tarEntry struct {
+	name, link, body string
+}
+
+// Writes a .tar.gz with the given regular files and symlinks
+func writeTestTar(t *testing.T, entries ...tarEntry) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "test"+archiveSuffix)
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(e.body))}
+		if e.link != "" {
+			hdr = &tar.Header{Name: e.name, Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: e.link}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if e.link == "" {
+			tw.Write([]byte(e.body))
+		}
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+	return file
+}
+
+func TestExtractArchiveRefusesEscapes(t *testing.T) {
+	outside := t.TempDir()
+	tests := map[string][]tarEntry{
+		"dot-dot name":       {{name: "../evil", body: "x"}},
+		"absolute symlink":   {{name: "link", link: outside}},
+		"relative symlink":   {{name: "sub/link", link: "../../evil"}},
+		"write through link": {{name: "sub", link: "."}, {name: "sub/file", body: "x"}},
+	}
+	for name, entries := range tests {
+		dest := t.TempDir()
+		if err := extractArchive(writeTestTar(t, entries...), dest); err == nil {
+			t.Errorf("%s: extracted, want an error", name)
+		}
+	}
+	if files, _ := os.ReadDir(outside); len(files) != 0 {
+		t.Errorf("files written outside dest: %v", files)
+	}
+}
+
+func TestExtractArchiveKeepsLocalSymlinks(t *testing.T) {
+	dest := t.TempDir()
+	file := writeTestTar(t, tarEntry{name: "modules/main.tf", body: "# main"}, tarEntry{name: "main.tf", link: "modules/main.tf"})
+	if err := extractArchive(file, dest); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "main.tf")); err != nil || string(data) != "# main" {
+		t.Errorf("main.tf = %q, %v", data, err)
+	}
+}
+
+func TestRestoreArchiveRefusesBadNames(t *testing.T) {
+	m, _ := newTestModel(t)
+	file := writeTestTar(t, tarEntry{name: "terraform.tfvars", body: "x = 1\n"})
+	for _, name := range []string{"../escape", "a/b", "/abs", ".", ""} {
+		if _, err := restoreArchive(m.cfg, archiveMeta{Name: name, File: file}); err == nil {
+			t.Errorf("restored under %q, want an error", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(m.cfg.AppsPath), "escape")); err == nil {
+		t.Error("../escape was created")
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	m, _ := newTestModel(t, "web_a")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	meta, err := archiveDeployment(m.cfg, dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("deployment still in apps_path: %v", err)
+	}
+	archives, err := listArchives(m.cfg)
+	if err != nil || len(archives) != 1 || archives[0].Name != "web_a" || archives[0].File != meta.File {
+		t.Fatalf("listArchives = %+v, %v", archives, err)
+	}
+	if _, err := restoreArchive(m.cfg, archives[0]); err != nil {
+		t.Fatal(err)
+	}
+	s, err := getDeploymentState(dir)
+	if err != nil || s.State != "DEPLOYED" || s.LastAction != "restore" {
+		t.Errorf("restored state %+v, %v", s, err)
+	}
+	actions := []string{}
+	for _, c := range s.History {
+		actions = append(actions, c.Action)
+	}
+	if got := strings.Join(actions, ","); got != "archive,restore" {
+		t.Errorf("history %s, want archive,restore", got)
+	}
+}
+
+func TestArchiveFailureKeepsState(t *testing.T) {
+	m, _ := newTestModel(t, "web_a")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	before, _ := os.ReadFile(filepath.Join(dir, "launcher.state"))
+	// archives_path is a file, so the archive can't be written
+	m.cfg.ArchivesPath = filepath.Join(dir, "terraform.tfvars")
+	if _, err := archiveDeployment(m.cfg, dir, false); err == nil {
+		t.Fatal("archived into a file, want an error")
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, "launcher.state"))
+	if string(before) != string(after) {
+		t.Errorf("launcher.state changed by the failed archive:\n%s\nwas\n%s", after, before)
+	}
+}
+
+func TestArchiveKeepTakesDeployLock(t *testing.T) {
+	m, _ := newTestModel(t, "web_a")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	m.archiveConfirm = &archiveDialog{dep: deploymentInfo{Name: "web_a", Path: dir}}
+	_, archive := updateArchiveDialog(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if archive == nil {
+		t.Fatal("no archive command")
+	}
+
+	// Another launcher starts a job between the key press and the archive
+	unlock, err := filelock.TryLock(filepath.Join(dir, deployLockFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := archive().(archiveDoneMsg)
+	if !strings.HasPrefix(done.status, "Not archiving web_a") {
+		t.Errorf("status %q, want the archive refused", done.status)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "launcher.state")); err != nil {
+		t.Fatalf("deployment gone while locked: %v", err)
+	}
+
+	unlock()
+	done = archive().(archiveDoneMsg)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("deployment still in apps_path after %q", done.status)
+	}
+	if lockedElsewhere(dir) || deployLocks[dir] != nil {
+		t.Error("deploy lock kept after the archive")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/atotto/clipboard"
+	osc52 "github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/terraform"
+	"launcher/internal/vaultclient"
+)
+
+// --- Post-apply artifacts (kubeconfig, join commands, ...) ---
+
+const defaultVaultArtifactsPath = "secret/data/infra-catalog/artifacts"
+
+// ArtifactsSpec lists the terraform outputs a template produces that must be kept after apply
+type ArtifactsSpec struct {
+	Outputs []string `yaml:"outputs"`
+	// "local" (default) writes 0600 files, "vault" writes a KV v2 secret per deployment
+	Store string `yaml:"store"`
+}
+
+type tfOutput = terraform.Output
+
+func readTerraformOutputs(dir string) (map[string]tfOutput, error) {
+	cmd := terraformCommand("output", "-json", "-no-color")
+	cmd.Dir = dir
+	started := time.Now()
+	out, err := cmd.Output()
+	logTerraform(dir, cmd.Args[1:], started, err)
+	if err != nil {
+		return nil, fmt.Errorf("terraform output failed: %w", err)
+	}
+	return terraform.ParseOutputs(out)
+}
+
+// Fetches the template's artifact outputs, stores them and records their locations in launcher.state
+func collectArtifacts(cfg Config, spec ArtifactsSpec, dir string) error {
+	if len(spec.Outputs) == 0 {
+		return nil
+	}
+	outputs, err := readTerraformOutputs(dir)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	for _, name := range spec.Outputs {
+		o, ok := outputs[name]
+		if !ok {
+			return fmt.Errorf("terraform output %q not found", name)
+		}
+		values[name] = o.String()
+	}
+
+	var locations map[string]string
+	if spec.Store == "vault" {
+		locations, err = storeArtifactsInVault(cfg, filepath.Base(dir), values)
+	} else {
+		locations, err = storeArtifactsLocally(cfg, dir, values)
+	}
+	if err != nil {
+		return err
+	}
+	s, err := getDeploymentState(dir)
+	if err != nil {
+		return err
+	}
+	s.Artifacts = locations
+	return writeDeploymentState(dir, s)
+}
+
+func artifactsDir(cfg Config, dir string) string {
+	if cfg.ArtifactsPath == "" {
+		return filepath.Join(dir, ".artifacts")
+	}
+	return filepath.Join(cfg.ArtifactsPath, filepath.Base(dir))
+}
+
+func storeArtifactsLocally(cfg Config, dir string, values map[string]string) (map[string]string, error) {
+	base := artifactsDir(cfg, dir)
+	if err := os.MkdirAll(base, 0700); err != nil {
+		return nil, err
+	}
+	locations := map[string]string{}
+	for name, v := range values {
+		path := filepath.Join(base, name)
+		if err := os.WriteFile(path, []byte(v), 0600); err != nil {
+			return nil, err
+		}
+		locations[name] = path
+	}
+	return locations, nil
+}
+
+func storeArtifactsInVault(cfg Config, deployment string, values map[string]string) (map[string]string, error) {
+	client, err := newVaultClient()
+	if err != nil {
+		return nil, err
+	}
+	mount := cfg.VaultArtifactsPath
+	if mount == "" {
+		mount = defaultVaultArtifactsPath
+	}
+	path := fmt.Sprintf("%s/%s", mount, deployment)
+	data := map[string]interface{}{}
+	for k, v := range values {
+		data[k] = v
+	}
+	err = vaultclient.WriteKV(client.Logical(), path, data)
+	logVault("write", path, err)
+	if err != nil {
+		return nil, err
+	}
+	locations := map[string]string{}
+	for name := range values {
+		locations[name] = fmt.Sprintf("vault:%s#%s", path, name)
+	}
+	return locations, nil
+}
+
+// Artifact lines for the detail pane
+func artifactLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || len(m.deployments[idx].Artifacts) == 0 {
+		return nil
+	}
+	arts := m.deployments[idx].Artifacts
+	names := make([]string, 0, len(arts))
+	for name := range arts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{"Artifacts ([Y] copy kubeconfig path):"}
+	for _, name := range names {
+		lines = append(lines, truncate(fmt.Sprintf("  %s: %s", name, arts[name]), width))
+	}
+	return lines
+}
+
+// Copies via the system clipboard, falling back to OSC 52 for remote terminals: the
+// returned command prints the sequence through the program (printSequence)
+func copyToClipboard(s string) tea.Cmd {
+	if err := clipboard.WriteAll(s); err == nil {
+		return nil
+	}
+	return printSequence("copied to the terminal clipboard (OSC 52)", osc52.New(s).String())
+}
+
+func artifactsHook(cfg Config, t Template, dir string) func() error {
+	if len(t.Artifacts.Outputs) == 0 {
+		return nil
+	}
+	return func() error {
+		return collectArtifacts(cfg, t.Artifacts, dir)
+	}
+}
+
+func kubeconfigLocation(dep deploymentInfo) (string, bool) {
+	loc, ok := dep.Artifacts["kubeconfig"]
+	return loc, ok
+}
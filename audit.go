@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"launcher/internal/filelock"
+)
+
+// --- Hash-chained audit log ---
+//
+// Every catalog action is appended as a JSON line carrying the hash of the previous
+// line, so editing or dropping an entry breaks the chain from that point on.
+// With audit.gpg_key set each entry hash is also signed. Entries are written by a
+// background goroutine, so gpg never runs on the UI loop, and under a lock on
+// <audit.log>.lock so launchers sharing the log can't fork the chain.
+
+type auditEntry struct {
+	Seq        int    `json:"seq"`
+	Time       string `json:"time"`
+	Action     string `json:"action"`
+	Deployment string `json:"deployment,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	Result     string `json:"result"`
+	// What changed, e.g. "vm_memory: 4096 → 8192" (secret values masked)
+	Changes []string `json:"changes,omitempty"`
+	By      string   `json:"by,omitempty"`
+	Prev    string   `json:"prev"`
+	Hash    string   `json:"hash,omitempty"`
+	Sig     string   `json:"sig,omitempty"`
+}
+
+// One action waiting to be written
+type auditRecord struct {
+	at                                 time.Time
+	action, deployment, detail, result string
+	changes                            []string
+}
+
+type auditTrail struct {
+	mu     sync.Mutex
+	path   string
+	gpgKey string
+
+	// Records waiting for the writer goroutine; nil (before configureAudit, after
+	// closeAudit) writes synchronously
+	qmu   sync.Mutex
+	queue chan auditRecord
+	done  chan struct{}
+}
+
+var auditLog = &auditTrail{}
+
+func configureAudit(cfg AuditConfig) {
+	auditLog.path = cfg.Path
+	if auditLog.path == "" {
+		auditLog.path = filepath.Join(filepath.Dir(logPath()), "audit.log")
+	}
+	auditLog.gpgKey = cfg.GPGKey
+	auditLog.queue = make(chan auditRecord, 256)
+	auditLog.done = make(chan struct{})
+	go auditLog.run(auditLog.queue, auditLog.done)
+}
+
+func (a *auditTrail) run(queue <-chan auditRecord, done chan<- struct{}) {
+	defer close(done)
+	for r := range queue {
+		a.write(r)
+	}
+}
+
+// Waits until every queued entry is written; called before the process exits
+func closeAudit() {
+	a := auditLog
+	a.qmu.Lock()
+	if a.queue == nil {
+		a.qmu.Unlock()
+		return
+	}
+	close(a.queue)
+	a.queue = nil
+	a.qmu.Unlock()
+	<-a.done
+}
+
+func (a *auditTrail) record(r auditRecord) {
+	a.qmu.Lock()
+	defer a.qmu.Unlock()
+	if a.queue != nil {
+		a.queue <- r
+		return
+	}
+	a.write(r)
+}
+
+// Failures go to the app log rather than blocking the action
+func (a *auditTrail) write(r auditRecord) {
+	if err := a.append(r); err != nil {
+		logger.Error("audit log write failed", "component", "audit", "action", r.action, "error", err.Error())
+	}
+}
+
+// Hash over the entry without its hash and signature
+func (e auditEntry) digest() string {
+	e.Hash, e.Sig = "", ""
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Reads the last entry; the file may be written by other launcher processes, so it is re-read each time
+func lastAuditEntry(path string) (auditEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return auditEntry{}, nil
+	}
+	if err != nil {
+		return auditEntry{}, err
+	}
+	defer f.Close()
+	const tail = 64 * 1024
+	if st, err := f.Stat(); err == nil && st.Size() > tail {
+		f.Seek(st.Size()-tail, io.SeekStart)
+	}
+	var last string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "{") {
+			last = line
+		}
+	}
+	if last == "" {
+		return auditEntry{}, scanner.Err()
+	}
+	var e auditEntry
+	err = json.Unmarshal([]byte(last), &e)
+	return e, err
+}
+
+func gpgSign(key, text string) (string, error) {
+	cmd := commands.Command("", "gpg", "--batch", "--yes", "--armor", "--detach-sign", "--local-user", key)
+	cmd.Stdin = strings.NewReader(text)
+	var out, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("gpg sign: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out.String(), nil
+}
+
+func (a *auditTrail) append(r auditRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0700); err != nil {
+		return err
+	}
+	// Other launchers append to the same file: hold the lock from reading the last hash
+	// until the new entry is written
+	unlock, err := filelock.Lock(a.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("cannot lock audit log: %w", err)
+	}
+	defer unlock()
+	prev, err := lastAuditEntry(a.path)
+	if err != nil {
+		return fmt.Errorf("cannot continue audit chain: %w", err)
+	}
+	e := auditEntry{
+		Seq:        prev.Seq + 1,
+		Time:       r.at.UTC().Format(time.RFC3339),
+		Action:     r.action,
+		Deployment: r.deployment,
+		Detail:     r.detail,
+		Result:     r.result,
+		Changes:    r.changes,
+		By:         currentIdentity().String(),
+		Prev:       prev.Hash,
+	}
+	e.Hash = e.digest()
+	if a.gpgKey != "" {
+		if e.Sig, err = gpgSign(a.gpgKey, e.Hash); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// recordAudit queues an entry for the audit log and returns right away
+func recordAudit(action, dir, detail, result string) {
+	recordAuditChanges(action, dir, detail, result, nil)
+}
+
+func recordAuditChanges(action, dir, detail, result string, changes []string) {
+	auditLog.record(auditRecord{at: time.Now(), action: action, deployment: filepath.Base(dir), detail: detail, result: result, changes: changes})
+}
+
+func gpgVerify(sig, text string) error {
+	f, err := os.CreateTemp("", "audit-sig-*.asc")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(sig)
+	f.Close()
+	cmd := commands.Command("", "gpg", "--batch", "--verify", f.Name(), "-")
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// verifyAuditLog walks the chain and returns the number of entries checked
+func verifyAuditLog(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var prev auditEntry
+	n := 0
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return n, fmt.Errorf("line %d: not an audit entry: %w", line, err)
+		}
+		switch {
+		case e.Prev != prev.Hash:
+			return n, fmt.Errorf("line %d (seq %d): chain broken, previous hash doesn't match", line, e.Seq)
+		case e.Seq != prev.Seq+1:
+			return n, fmt.Errorf("line %d: expected seq %d, found %d", line, prev.Seq+1, e.Seq)
+		case e.digest() != e.Hash:
+			return n, fmt.Errorf("line %d (seq %d): entry was modified", line, e.Seq)
+		}
+		if e.Sig != "" {
+			if err := gpgVerify(e.Sig, e.Hash); err != nil {
+				return n, fmt.Errorf("line %d (seq %d): bad signature: %w", line, e.Seq, err)
+			}
+		}
+		prev = e
+		n++
+	}
+	return n, scanner.Err()
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Audit scene: read-only browser over the audit log ---
+
+func readAuditEntries(path string) ([]auditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var entries []auditEntry
+	for scanner.Scan() {
+		var e auditEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func openAudit(m model) model {
+	m.auditFilter = textinput.New()
+	m.auditFilter.Placeholder = "filter (substring)"
+	m.auditAction, m.auditDeployment = "", ""
+	m.auditTable = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "#", Width: 5},
+			{Title: "Time", Width: 19},
+			{Title: "Action", Width: 16},
+			{Title: "Deployment", Width: 22},
+			{Title: "Result", Width: 16},
+			{Title: "By", Width: 22},
+		}),
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeys(keys.Audit.Up, keys.Audit.Down)),
+		table.WithStyles(tableStyles()),
+		table.WithHeight(12),
+	)
+	return reloadAudit(m).pushScene(sceneAudit)
+}
+
+func reloadAudit(m model) model {
+	entries, err := readAuditEntries(auditLog.path)
+	m.auditStatus = ""
+	if err != nil && !os.IsNotExist(err) {
+		m.auditStatus = "Could not read audit log: " + err.Error()
+	}
+	m.auditEntries = entries
+	return filterAudit(m)
+}
+
+// Newest first, narrowed by the action, deployment and text filters
+func filterAudit(m model) model {
+	text := strings.ToLower(m.auditFilter.Value())
+	m.auditShown = nil
+	for i := len(m.auditEntries) - 1; i >= 0; i-- {
+		e := m.auditEntries[i]
+		if m.auditAction != "" && e.Action != m.auditAction {
+			continue
+		}
+		if m.auditDeployment != "" && e.Deployment != m.auditDeployment {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(strings.Join(append([]string{e.Action, e.Deployment, e.Detail, e.Result, e.By}, e.Changes...), " ")), text) {
+			continue
+		}
+		m.auditShown = append(m.auditShown, e)
+	}
+	rows := make([]table.Row, len(m.auditShown))
+	for i, e := range m.auditShown {
+		rows[i] = table.Row{fmt.Sprint(e.Seq), auditTime(e.Time), e.Action, e.Deployment, e.Result, e.By}
+	}
+	m.auditTable.SetRows(rows)
+	m.auditTable.SetCursor(0)
+	return m
+}
+
+func auditTime(ts string) string {
+	if t, err := time.Parse(time.RFC3339, ts); err == nil {
+		return localTime(t).Format("2006-01-02 15:04:05")
+	}
+	return ts
+}
+
+// Next action in the log after the current one; "" (all) after the last
+func nextAuditAction(entries []auditEntry, current string) string {
+	seen := map[string]bool{}
+	var actions []string
+	for _, e := range entries {
+		if !seen[e.Action] {
+			seen[e.Action] = true
+			actions = append(actions, e.Action)
+		}
+	}
+	for i, a := range actions {
+		if a == current {
+			if i+1 < len(actions) {
+				return actions[i+1]
+			}
+			return ""
+		}
+	}
+	if current == "" && len(actions) > 0 {
+		return actions[0]
+	}
+	return ""
+}
+
+func updateAudit(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.auditFilter.Focused() {
+			if key.Matches(keyMsg, keys.Audit.FilterDone) {
+				m.auditFilter.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.auditFilter, cmd = m.auditFilter.Update(msg)
+			return filterAudit(m), cmd
+		}
+		switch {
+		case key.Matches(keyMsg, keys.Audit.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.Audit.Filter):
+			m.auditFilter.Focus()
+			return m, textinput.Blink
+		case key.Matches(keyMsg, keys.Audit.Action):
+			m.auditAction = nextAuditAction(m.auditEntries, m.auditAction)
+			return filterAudit(m), nil
+		case key.Matches(keyMsg, keys.Audit.Deployment):
+			// Toggles between all deployments and the one under the cursor
+			if m.auditDeployment != "" {
+				m.auditDeployment = ""
+			} else if i := m.auditTable.Cursor(); i >= 0 && i < len(m.auditShown) {
+				m.auditDeployment = m.auditShown[i].Deployment
+			}
+			return filterAudit(m), nil
+		case key.Matches(keyMsg, keys.Audit.Reload):
+			return reloadAudit(m), nil
+		}
+	}
+	var cmd tea.Cmd
+	m.auditTable, cmd = m.auditTable.Update(msg)
+	return m, cmd
+}
+
+func viewAudit(m model) (body, tooltip string) {
+	action, deployment := m.auditAction, m.auditDeployment
+	if action == "" {
+		action = "all"
+	}
+	if deployment == "" {
+		deployment = "all"
+	}
+	body += tooltipStyle.Render(fmt.Sprintf("Audit: %s   Action: %s   Deployment: %s   Filter: %s", auditLog.path, action, deployment, m.auditFilter.View()))
+	body += "\n" + m.auditTable.View() + "\n"
+	body += " " + strings.Repeat("─", uiWidth-4) + "\n"
+	i := m.auditTable.Cursor()
+	if i < 0 || i >= len(m.auditShown) {
+		body += "(no matching audit entries)\n"
+	} else {
+		e := m.auditShown[i]
+		body += fmt.Sprintf(" %s %s by %s — %s\n", titleStyle.Render(e.Action), e.Deployment, e.By, e.Result)
+		if e.Detail != "" {
+			body += " " + truncate(e.Detail, uiWidth-6) + "\n"
+		}
+		for _, c := range e.Changes {
+			body += "   " + truncate(c, uiWidth-8) + "\n"
+		}
+	}
+	msg := m.auditStatus
+	if msg == "" {
+		msg = fmt.Sprintf("%d of %d entries — read-only; `launcher audit verify` checks the hash chain", len(m.auditShown), len(m.auditEntries))
+	}
+	return body, tooltipStyle.Render(msg)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuditChainSharedBetweenWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	// Two trails stand for two launcher processes: only the file lock orders them
+	trails := []*auditTrail{{path: path}, {path: path}}
+	var wg sync.WaitGroup
+	for i := range 40 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := auditRecord{at: time.Now(), action: "apply", deployment: fmt.Sprint("web_", i), result: "ok"}
+			if err := trails[i%2].append(r); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	n, err := verifyAuditLog(path)
+	if err != nil || n != 40 {
+		t.Errorf("verified %d entries: %v", n, err)
+	}
+}
+
+func TestAuditQueueFlushesOnClose(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	saved := auditLog
+	auditLog = &auditTrail{}
+	t.Cleanup(func() { auditLog = saved })
+
+	configureAudit(AuditConfig{})
+	for i := range 5 {
+		recordAudit("create", fmt.Sprint("/apps/web_", i), "", "ok")
+	}
+	closeAudit()
+	if n, err := verifyAuditLog(auditLog.path); err != nil || n != 5 {
+		t.Errorf("verified %d entries after close: %v", n, err)
+	}
+	recordAudit("destroy", "/apps/web_0", "", "ok") // written synchronously once closed
+	if n, _ := verifyAuditLog(auditLog.path); n != 6 {
+		t.Errorf("%d entries, want 6", n)
+	}
+}
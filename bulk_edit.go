@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Bulk variable edit over the selected deployments (V on the launcher) ---
+
+// A selected deployment and its terraform.tfvars before and after the edit
+type bulkTarget struct {
+	Name   string
+	Dir    string
+	Before []string
+	After  []string
+	Old    string // current value, "" when the variable isn't set
+	Found  bool
+}
+
+func (t bulkTarget) changed() bool {
+	return t.Found && strings.Join(t.Before, "\n") != strings.Join(t.After, "\n")
+}
+
+var bareValueRe = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|true|false|null)$`)
+
+// HCL form of a typed value: string fields and bare words are quoted, numbers, bools,
+// lists, maps and already quoted values are written as typed
+func bulkHCLValue(meta FieldMeta, v string) string {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, "\"") || strings.HasPrefix(v, "[") || strings.HasPrefix(v, "{") {
+		return v
+	}
+	if meta.Type != "string" && bareValueRe.MatchString(v) {
+		return v
+	}
+	return fmt.Sprintf("%q", v)
+}
+
+func openBulkEdit(m model) (model, tea.Cmd) {
+	if len(m.selected) == 0 {
+		m.statusMessage = fmt.Sprintf("Select deployments with %s first", keys.Launcher.Select.Help().Key)
+		return m, nil
+	}
+	m.bulkTargets = nil
+	for path := range m.selected {
+		dep, ok := deploymentByPath(m.allDeployments, path)
+		if !ok {
+			continue
+		}
+		m.bulkTargets = append(m.bulkTargets, bulkTarget{Name: dep.Name, Dir: path, Before: readLines(filepath.Join(path, "terraform.tfvars"))})
+	}
+	sort.Slice(m.bulkTargets, func(i, j int) bool { return m.bulkTargets[i].Name < m.bulkTargets[j].Name })
+	m.bulkVar = textinput.New()
+	m.bulkVar.Placeholder = "variable, e.g. dns_servers"
+	m.bulkValue = textinput.New()
+	m.bulkValue.Placeholder = `new value, e.g. ["10.0.0.53", "10.0.1.53"]`
+	m.bulkVar.Focus()
+	m.bulkFocus, m.bulkIdx, m.bulkConfirm, m.bulkStatus = 0, 0, false, ""
+	return previewBulkEdit(m).pushScene(sceneBulkEdit), textinput.Blink
+}
+
+// Recomputes every target's edited tfvars from the inputs; nothing is written
+func previewBulkEdit(m model) model {
+	name := strings.TrimSpace(m.bulkVar.Value())
+	value := bulkHCLValue(m.fieldMeta[name], m.bulkValue.Value())
+	for i, t := range m.bulkTargets {
+		t.Found, t.Old, t.After = false, "", t.Before
+		if name != "" {
+			if v, ok := tfvarsAssignment(t.Before, name); ok {
+				t.Found, t.Old = true, v
+				t.After = replaceTfvarsLines(t.Before, map[string]string{name: value})
+			}
+		}
+		m.bulkTargets[i] = t
+	}
+	m.bulkConfirm = false
+	return m
+}
+
+// Value assigned to name in tfvars lines
+func tfvarsAssignment(lines []string, name string) (string, bool) {
+	for _, line := range lines {
+		k, v, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(k) == name {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
+
+func bulkChangedCount(m model) int {
+	n := 0
+	for _, t := range m.bulkTargets {
+		if t.changed() {
+			n++
+		}
+	}
+	return n
+}
+
+// Writes the edit to every deployment it changes; each file is backed up first, so the
+// edit form's rollback can undo it
+func applyBulkEdit(m model) (model, tea.Cmd) {
+	name := strings.TrimSpace(m.bulkVar.Value())
+	meta := m.fieldMeta[name]
+	value := bulkHCLValue(meta, m.bulkValue.Value())
+	var cmds []tea.Cmd
+	var failed []string
+	written := 0
+	for _, t := range m.bulkTargets {
+		if !t.changed() {
+			continue
+		}
+		err := deploymentBusy(m, t.Dir)
+		if err == nil {
+			err = saveTfvars(filepath.Join(t.Dir, "terraform.tfvars"), map[string]string{name: value})
+		}
+		if err != nil {
+			logger.Error("bulk edit failed", "component", "bulk-edit", "deployment", t.Name, "error", err.Error())
+			recordAudit("bulk-edit", t.Dir, name, "failed: "+err.Error())
+			failed = append(failed, t.Name)
+			continue
+		}
+		recordAuditChanges("bulk-edit", t.Dir, name, "ok", []string{fmt.Sprintf("%s: %s → %s", name, t.Old, value)})
+		cmds = append(cmds, refreshDeploymentCmd(t.Dir))
+		written++
+	}
+	m.statusMessage = fmt.Sprintf("Set %s in %s — apply them to roll it out", name, plural(written, "deployment"))
+	if len(failed) > 0 {
+		m.statusMessage += fmt.Sprintf("; failed for %s (see log)", strings.Join(failed, ", "))
+	}
+	return m.homeScene(), tea.Batch(cmds...)
+}
+
+func updateBulkEdit(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	n := len(m.bulkTargets)
+	switch {
+	case key.Matches(keyMsg, keys.BulkEdit.Back):
+		return m.popScene(), nil
+	case key.Matches(keyMsg, keys.BulkEdit.Up):
+		if n > 0 {
+			m.bulkIdx = (m.bulkIdx - 1 + n) % n
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.BulkEdit.Down):
+		if n > 0 {
+			m.bulkIdx = (m.bulkIdx + 1) % n
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.BulkEdit.Next):
+		m.bulkFocus = 1 - m.bulkFocus
+		if m.bulkFocus == 0 {
+			m.bulkValue.Blur()
+			m.bulkVar.Focus()
+		} else {
+			m.bulkVar.Blur()
+			m.bulkValue.Focus()
+		}
+		return m, textinput.Blink
+	case key.Matches(keyMsg, keys.BulkEdit.Apply):
+		name := strings.TrimSpace(m.bulkVar.Value())
+		switch changed := bulkChangedCount(m); {
+		case name == "":
+			m.bulkStatus = "Type the variable to change"
+		case isMaskedField(m.fieldMeta[name]):
+			m.bulkStatus = name + " is a secret or sensitive field — its value isn't in terraform.tfvars; edit it per deployment"
+		case changed == 0:
+			m.bulkStatus = "Nothing to change: no selected deployment would be different"
+		case !m.bulkConfirm:
+			m.bulkConfirm = true
+			m.bulkStatus = fmt.Sprintf("Press %s again to write %s", keys.BulkEdit.Apply.Help().Key, plural(changed, "terraform.tfvars file"))
+		default:
+			return applyBulkEdit(m)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	if m.bulkFocus == 0 {
+		m.bulkVar, cmd = m.bulkVar.Update(msg)
+	} else {
+		m.bulkValue, cmd = m.bulkValue.Update(msg)
+	}
+	m = previewBulkEdit(m)
+	m.bulkStatus = ""
+	return m, cmd
+}
+
+func viewBulkEdit(m model) (body, tooltip string) {
+	const listWidth = 34
+	body += tooltipStyle.Render(fmt.Sprintf("Bulk edit of %s", plural(len(m.bulkTargets), "selected deployment"))) + "\n\n"
+	body += " " + padRight("Variable", 10) + m.bulkVar.View() + "\n"
+	body += " " + padRight("Value", 10) + m.bulkValue.View() + "\n"
+	body += " " + strings.Repeat("─", uiWidth-4) + "\n"
+	name := strings.TrimSpace(m.bulkVar.Value())
+	var left []string
+	for i, t := range m.bulkTargets {
+		mark := "  "
+		switch {
+		case name == "":
+		case !t.Found:
+			mark = "x "
+		case t.changed():
+			mark = "~ "
+		default:
+			mark = "= "
+		}
+		line := padRight(truncate(mark+t.Name, listWidth), listWidth)
+		if i == m.bulkIdx {
+			line = focusedStyle.Render(line)
+		} else {
+			line = normalStyle.Render(line)
+		}
+		left = append(left, line)
+	}
+	// Diff of the deployment under the cursor
+	var right []string
+	if m.bulkIdx < len(m.bulkTargets) {
+		t := m.bulkTargets[m.bulkIdx]
+		switch {
+		case name == "":
+			right = []string{"(type a variable name)"}
+		case !t.Found:
+			right = []string{"(no " + name + " in terraform.tfvars — skipped)"}
+		case !t.changed():
+			right = []string{"(already set to this value)"}
+		}
+		right = append(right, tfvarsDiffLines(m, t.Before, t.After, uiWidth-listWidth-12)...)
+	}
+	body += splitPane(left, right, listWidth, 20)
+	if name != "" {
+		body += "\n " + logDimStyle.Render(fmt.Sprintf("%d to change • ~ changes, = already set, x no such variable (skipped)", bulkChangedCount(m))) + "\n"
+	}
+	if m.bulkStatus != "" {
+		body += "\n " + m.bulkStatus + "\n"
+	}
+	tooltip = tooltipStyle.Render("Only terraform.tfvars is written; apply each deployment afterwards. Every file is backed up first (" + keys.Edit.Rollback.Help().Key + " in the edit form rolls back).")
+	return body, tooltip
+}
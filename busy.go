@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Busy overlay ---
+
+// busyDoneMsg carries the result of the work started by startBusy.
+// token ties it to that run so results of a cancelled run are dropped.
+type busyDoneMsg struct {
+	token int
+	msg   tea.Msg
+}
+
+// Keystrokes kept while busy; typing past this is discarded
+const maxTypeahead = 64
+
+// startBusy runs work off the UI loop behind a modal overlay. Its result is
+// delivered to the current scene once done, unless the user cancelled.
+func startBusy(m model, message string, work func() tea.Msg) (model, tea.Cmd) {
+	m.busyToken++
+	m.isBusy = true
+	m.busyMessage = message
+	m.busyStarted = time.Now()
+	m.typeahead, m.typeaheadDropped = nil, 0
+	token := m.busyToken
+	return m, tea.Batch(m.busySpinner.Tick, func() tea.Msg {
+		return busyDoneMsg{token: token, msg: work()}
+	})
+}
+
+// handleBusyMsg runs before scene dispatch. While busy, typing is queued and
+// replayed into the form afterwards, other keys are discarded except Esc (cancel)
+// and Ctrl+C (quit); everything else keeps flowing so the background stays live.
+func handleBusyMsg(m model, msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case busyDoneMsg:
+		if !m.isBusy || msg.token != m.busyToken {
+			return m, nil, true // cancelled
+		}
+		m.isBusy = false
+		next, cmd := m.update(msg.msg)
+		m, replayCmd := replayTypeahead(next.(model))
+		return m, tea.Batch(cmd, replayCmd), true
+	case spinner.TickMsg:
+		if msg.ID != m.busySpinner.ID() {
+			return m, nil, false
+		}
+		if !m.isBusy {
+			return m, nil, true
+		}
+		var cmd tea.Cmd
+		m.busySpinner, cmd = m.busySpinner.Update(msg)
+		return m, cmd, true
+	case tea.KeyMsg:
+		if !m.isBusy {
+			return m, nil, false
+		}
+		switch {
+		case key.Matches(msg, keys.Busy.Quit):
+			return m, tea.Quit, true
+		case key.Matches(msg, keys.Busy.Cancel):
+			m.isBusy = false
+			m.typeaheadDropped += len(m.typeahead)
+			m.typeahead = nil
+			*sceneStatus(&m) = "Cancelled: " + m.busyMessage + discardedNote(m)
+		default:
+			if isTypingKey(msg) && len(m.typeahead) < maxTypeahead {
+				m.typeahead = append(m.typeahead, msg)
+			} else {
+				m.typeaheadDropped++
+			}
+		}
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// Draws the busy box over the middle of body, leaving the rest of it visible
+func overlayBusy(m model, body string) string {
+	elapsed := time.Since(m.busyStarted).Round(time.Second)
+	queue := ""
+	if n := len(m.typeahead); n > 0 {
+		queue += fmt.Sprintf("\n⌨ %s queued", plural(n, "key"))
+	}
+	if m.typeaheadDropped > 0 {
+		queue += fmt.Sprintf("\n%s discarded (only typing is kept)", plural(m.typeaheadDropped, "key"))
+	}
+	c, q := keys.Busy.Cancel.Help(), keys.Busy.Quit.Help()
+	box := busyBoxStyle.Render(fmt.Sprintf("%s %s\n\nElapsed: %s%s\n\n%s %s │ %s %s", m.busySpinner.View(), m.busyMessage, elapsed, queue, c.Key, c.Desc, q.Key, q.Desc))
+	boxLines := strings.Split(lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, box), "\n")
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	for len(lines) < len(boxLines) {
+		lines = append(lines, "")
+	}
+	top := (len(lines) - len(boxLines)) / 2
+	copy(lines[top:], boxLines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Characters, space and backspace are replayed; navigation and Enter are not,
+// since they would act on a form that may have changed meanwhile
+func isTypingKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace:
+		return !msg.Paste
+	}
+	return false
+}
+
+// Feeds the queued keys to the form that was busy, or reports them as discarded
+// when the work moved on to another scene
+func replayTypeahead(m model) (model, tea.Cmd) {
+	keys := m.typeahead
+	m.typeahead = nil
+	if m.isBusy || (m.currentScene != sceneCreateForm && m.currentScene != sceneEditForm) {
+		m.typeaheadDropped += len(keys)
+		keys = nil
+	}
+	var cmds []tea.Cmd
+	for _, k := range keys {
+		next, cmd := m.update(k)
+		m = next.(model)
+		cmds = append(cmds, cmd)
+	}
+	if m.typeaheadDropped > 0 && !m.isBusy {
+		status := sceneStatus(&m)
+		*status = strings.TrimSpace(*status + discardedNote(m))
+		m.typeaheadDropped = 0
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func discardedNote(m model) string {
+	if m.typeaheadDropped == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s typed while busy discarded)", plural(m.typeaheadDropped, "key"))
+}
+
+// Status line of the current scene
+func sceneStatus(m *model) *string {
+	switch m.currentScene {
+	case sceneCreateForm:
+		return &m.createStatus
+	case sceneEditForm:
+		return &m.editStatus
+	}
+	return &m.statusMessage
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Cancelling jobs ---
+
+// How long terraform gets to stop cleanly (and release the state lock) after SIGINT
+const cancelGracePeriod = 20 * time.Second
+
+// tfStartedMsg hands the running terraform process of a step to the UI loop
+type tfStartedMsg struct {
+	jobID int
+	proc  *os.Process
+}
+
+type cancelEscalateMsg struct {
+	jobID int
+	proc  *os.Process
+}
+
+// cancelJob drops a queued job, or interrupts a running one and schedules a SIGKILL
+func cancelJob(m model, j *job) (model, tea.Cmd) {
+	switch j.Status {
+	case jobQueued:
+		j.Status = jobCancelled
+		j.Finished = time.Now()
+		m.statusMessage = fmt.Sprintf("Job #%d removed from the queue", j.ID)
+		logger.Info("job cancelled", "component", "jobs", "job", j.ID, "label", j.Op.Label, "queued", true)
+		recordAudit("cancel", j.Op.Dir, j.Op.Label, "cancelled")
+		// The state belongs to the job running on the deployment, if there is one
+		for _, r := range m.runningJobs() {
+			if r.Op.Dir == j.Op.Dir {
+				return m, nil
+			}
+		}
+		if err := setDeploymentState(j.Op.Dir, "CANCELLED", j.Op.action()); err != nil {
+			logger.Error("could not record CANCELLED state", "component", "jobs", "job", j.ID, "error", err.Error())
+		}
+		return m, refreshDeploymentCmd(j.Op.Dir)
+	case jobRunning:
+		if j.Op.cancelling {
+			m.statusMessage = fmt.Sprintf("Job #%d is already stopping...", j.ID)
+			return m, nil
+		}
+		j.Op.cancelling = true
+		proc := j.Op.proc
+		if proc == nil {
+			// Not started yet: the step is cancelled as soon as its process is known
+			m.statusMessage = fmt.Sprintf("Cancelling job #%d...", j.ID)
+			return m, nil
+		}
+		cmd := interruptJob(j, proc, &m)
+		return m, cmd
+	}
+	m.statusMessage = fmt.Sprintf("Job #%d is already %s", j.ID, j.Status)
+	return m, nil
+}
+
+func interruptJob(j *job, proc *os.Process, m *model) tea.Cmd {
+	logger.Info("job cancel requested", "component", "jobs", "job", j.ID, "label", j.Op.Label)
+	recordAudit("cancel", j.Op.Dir, j.Op.Label, "requested")
+	if err := proc.Signal(os.Interrupt); err != nil {
+		// Platforms without SIGINT for child processes: go straight to kill
+		_ = proc.Kill()
+		m.statusMessage = fmt.Sprintf("Job #%d killed", j.ID)
+		return nil
+	}
+	m.statusMessage = fmt.Sprintf("Sent interrupt to job #%d; terraform is stopping (killed after %s)", j.ID, cancelGracePeriod)
+	id := j.ID
+	return tea.Tick(cancelGracePeriod, func(time.Time) tea.Msg {
+		return cancelEscalateMsg{jobID: id, proc: proc}
+	})
+}
+
+func handleCancelEscalate(m model, msg cancelEscalateMsg) model {
+	j := m.jobByID(msg.jobID)
+	if j == nil || j.Status != jobRunning || j.Op.proc != msg.proc {
+		return m
+	}
+	logger.Warn("job did not stop after interrupt, killing", "component", "jobs", "job", j.ID)
+	_ = msg.proc.Kill()
+	m.statusMessage = fmt.Sprintf("Job #%d did not stop within %s and was killed", j.ID, cancelGracePeriod)
+	return m
+}
+
+// Job to cancel from the launcher: the selected deployment's running or queued job
+func (m model) selectedDeploymentJob() *job {
+	i := m.deployTable.Cursor()
+	if i < 0 || i >= len(m.deployments) {
+		return nil
+	}
+	for _, status := range []jobStatus{jobRunning, jobQueued} {
+		for _, j := range m.jobs {
+			if j.Status == status && j.Op.Dir == m.deployments[i].Path {
+				return j
+			}
+		}
+	}
+	return nil
+}
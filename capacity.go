@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Cluster capacity check ---
+
+const maxCapacityNodes = 6
+
+type proxmoxResource struct {
+	Type    string  `json:"type"`
+	Node    string  `json:"node"`
+	Status  string  `json:"status"`
+	Storage string  `json:"storage"`
+	Shared  int     `json:"shared"`
+	MaxMem  int64   `json:"maxmem"`
+	Mem     int64   `json:"mem"`
+	MaxCPU  int     `json:"maxcpu"`
+	CPU     float64 `json:"cpu"`
+	MaxDisk int64   `json:"maxdisk"`
+	Disk    int64   `json:"disk"`
+}
+
+type nodeCapacity struct {
+	Node    string
+	Online  bool
+	MaxMem  int64
+	Mem     int64
+	MaxCPU  int
+	CPU     float64 // usage fraction
+	MaxDisk int64   // on the template's storage, 0 when unknown
+	Disk    int64
+}
+
+type clusterCapacity struct {
+	cluster  string
+	template string
+	storage  string // storage holding the template disk ("" when unknown)
+	shared   bool
+	nodes    []nodeCapacity
+	err      error
+}
+
+type capacityMsg clusterCapacity
+
+// What the create form asks for, summed over vm_count
+type capacityRequest struct {
+	count    int
+	memBytes int64 // per VM
+	cores    int   // per VM
+	disk     int64 // per VM
+}
+
+func fetchCapacity(cluster, templateName string) clusterCapacity {
+	c := clusterCapacity{cluster: cluster, template: templateName}
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(cluster)
+	if err != nil {
+		c.err = fmt.Errorf("failed to get Proxmox creds: %w", err)
+		return c
+	}
+	var resources []proxmoxResource
+	if err := proxmoxGet(apiURL, tokenID, tokenSecret, "cluster/resources", &resources); err != nil {
+		c.err = err
+		return c
+	}
+	// Best effort: the storage is only known once the template disk can be resolved
+	if templateName != "" {
+		if vms, err := listProxmoxTemplates(apiURL, tokenID, tokenSecret); err == nil {
+			for _, vm := range vms {
+				if vm.Name == templateName {
+					if volume, err := templateDiskVolume(apiURL, tokenID, tokenSecret, vm); err == nil {
+						c.storage = strings.SplitN(volume, ":", 2)[0]
+					}
+					break
+				}
+			}
+		}
+	}
+	byNode := map[string]*nodeCapacity{}
+	for _, r := range resources {
+		if r.Type == "node" {
+			byNode[r.Node] = &nodeCapacity{
+				Node: r.Node, Online: r.Status == "online",
+				MaxMem: r.MaxMem, Mem: r.Mem, MaxCPU: r.MaxCPU, CPU: r.CPU,
+			}
+		}
+	}
+	for _, r := range resources {
+		if r.Type != "storage" || r.Storage != c.storage {
+			continue
+		}
+		if n, ok := byNode[r.Node]; ok {
+			n.MaxDisk, n.Disk = r.MaxDisk, r.Disk
+			c.shared = r.Shared == 1
+		}
+	}
+	for _, n := range byNode {
+		c.nodes = append(c.nodes, *n)
+	}
+	sort.Slice(c.nodes, func(i, j int) bool { return c.nodes[i].Node < c.nodes[j].Node })
+	return c
+}
+
+func capacityCmd(p Provider, cluster, templateName string) tea.Cmd {
+	if cluster == "" || safeMode {
+		return nil
+	}
+	return func() tea.Msg {
+		return capacityMsg(p.Capacity(cluster, templateName))
+	}
+}
+
+// Parses sizes like "100G", "512M" or "2T"; bare numbers are GB
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.Trim(strings.TrimSpace(s), "\""))
+	s = strings.TrimSuffix(s, "B")
+	mult := int64(1 << 30)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		mult, s = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1<<20, strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		s = strings.TrimSuffix(s, "G")
+	case strings.HasSuffix(s, "T"):
+		mult, s = 1<<40, strings.TrimSuffix(s, "T")
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(n * float64(mult)), nil
+}
+
+func createCapacityRequest(m model) capacityRequest {
+	return sizingRequest(func(key string) string { return createValue(m, key) })
+}
+
+// Reads vm_count/vm_memory/vm_cpu_cores/vm_disk_size through value (form or tfvars)
+func sizingRequest(value func(key string) string) capacityRequest {
+	r := capacityRequest{count: 1}
+	if n, err := strconv.Atoi(value("vm_count")); err == nil && n > 0 {
+		r.count = n
+	}
+	if mb, err := strconv.ParseInt(value("vm_memory"), 10, 64); err == nil {
+		r.memBytes = mb << 20
+	}
+	r.cores, _ = strconv.Atoi(value("vm_cpu_cores"))
+	for _, part := range strings.Split(strings.Trim(value("vm_disk_size"), "[]"), ",") {
+		if size, err := parseSize(part); err == nil {
+			r.disk += size
+		}
+	}
+	return r
+}
+
+// Places the requested VMs greedily on the node with the most free memory and
+// returns how many could not be placed and why
+func checkCapacity(c clusterCapacity, req capacityRequest) (unplaced int, reason string) {
+	freeMem := map[string]int64{}
+	freeDisk := map[string]int64{}
+	var sharedFree int64
+	for _, n := range c.nodes {
+		if !n.Online {
+			continue
+		}
+		freeMem[n.Node] = n.MaxMem - n.Mem
+		freeDisk[n.Node] = n.MaxDisk - n.Disk
+		if c.shared {
+			sharedFree = n.MaxDisk - n.Disk
+		}
+	}
+	checkDisk := c.storage != "" && req.disk > 0
+	for i := 0; i < req.count; i++ {
+		best := ""
+		reason = "not enough free memory"
+		for _, n := range c.nodes {
+			if !n.Online || freeMem[n.Node] < req.memBytes {
+				continue
+			}
+			if req.cores > n.MaxCPU {
+				reason = fmt.Sprintf("%d cores exceed every node's CPU count", req.cores)
+				continue
+			}
+			if checkDisk && !c.shared && freeDisk[n.Node] < req.disk {
+				reason = fmt.Sprintf("not enough free space on %s", c.storage)
+				continue
+			}
+			if best == "" || freeMem[n.Node] > freeMem[best] {
+				best = n.Node
+			}
+		}
+		if best != "" && checkDisk && c.shared && sharedFree < req.disk {
+			best, reason = "", fmt.Sprintf("not enough free space on shared storage %s", c.storage)
+		}
+		if best == "" {
+			return req.count - i, reason
+		}
+		freeMem[best] -= req.memBytes
+		if c.shared {
+			sharedFree -= req.disk
+		} else {
+			freeDisk[best] -= req.disk
+		}
+	}
+	return 0, ""
+}
+
+func capacityMode(cfg Config) string {
+	switch cfg.CapacityCheck {
+	case "block", "off":
+		return cfg.CapacityCheck
+	}
+	return "warn"
+}
+
+// Returns an error describing the shortfall, or nil when the request fits. A cluster whose
+// capacity couldn't be read counts as a shortfall, so capacity_check: block fails closed.
+func capacityShortfall(c clusterCapacity, req capacityRequest) error {
+	if c.err != nil {
+		return fmt.Errorf("could not check capacity on %s: %v", c.cluster, c.err)
+	}
+	if unplaced, reason := checkCapacity(c, req); unplaced > 0 {
+		return fmt.Errorf("%d of %d VM(s) don't fit on %s: %s", unplaced, req.count, c.cluster, reason)
+	}
+	return nil
+}
+
+func capacityBar(frac float64) string {
+	bar := progress.New(progressFill(), progress.WithWidth(20), progress.WithoutPercentage())
+	if frac > 1 {
+		frac = 1
+	}
+	return bar.ViewAs(frac)
+}
+
+func memFraction(n nodeCapacity) float64 {
+	if n.MaxMem == 0 {
+		return 0
+	}
+	return float64(n.Mem) / float64(n.MaxMem)
+}
+
+// Per-node capacity bars for the create form tooltip
+func capacityLines(m model) []string {
+	c := m.capacity
+	if c == nil || c.cluster != createValue(m, "cluster") {
+		return nil
+	}
+	if c.err != nil {
+		return []string{"Capacity unavailable: " + c.err.Error()}
+	}
+	req := createCapacityRequest(m)
+	lines := []string{fmt.Sprintf("Capacity on %s — request: %d × (%s RAM, %d cores, %s disk)",
+		c.cluster, req.count, formatBytes(req.memBytes), req.cores, formatBytes(req.disk))}
+	for i, n := range c.nodes {
+		if i == maxCapacityNodes {
+			lines = append(lines, fmt.Sprintf("  … %d more node(s)", len(c.nodes)-i))
+			break
+		}
+		if !n.Online {
+			lines = append(lines, fmt.Sprintf("  %-12s offline", n.Node))
+			continue
+		}
+		line := fmt.Sprintf("  %-12s MEM %s %6s/%-6s  CPU %s %3.0f%% of %d",
+			n.Node, capacityBar(memFraction(n)), formatBytes(n.Mem), formatBytes(n.MaxMem),
+			capacityBar(n.CPU), n.CPU*100, n.MaxCPU)
+		if n.MaxDisk > 0 {
+			line += fmt.Sprintf("  %s %s %s free", c.storage, capacityBar(float64(n.Disk)/float64(n.MaxDisk)), formatBytes(n.MaxDisk-n.Disk))
+		}
+		lines = append(lines, line)
+	}
+	if err := capacityShortfall(*c, req); err != nil {
+		lines = append(lines, "⚠ "+err.Error())
+	}
+	return lines
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCapacityShortfall(t *testing.T) {
+	nodes := []nodeCapacity{
+		{Node: "pve1", Online: true, MaxMem: 8 << 30, Mem: 2 << 30, MaxCPU: 8},
+		{Node: "pve2", Online: true, MaxMem: 8 << 30, Mem: 6 << 30, MaxCPU: 8},
+	}
+	fits := capacityRequest{count: 2, memBytes: 2 << 30, cores: 2}
+	if err := capacityShortfall(clusterCapacity{cluster: "c1", nodes: nodes}, fits); err != nil {
+		t.Errorf("2 × 2G on 6G+2G free: %v", err)
+	}
+	tooMany := capacityRequest{count: 3, memBytes: 3 << 30, cores: 2}
+	if err := capacityShortfall(clusterCapacity{cluster: "c1", nodes: nodes}, tooMany); err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("3 × 3G: %v", err)
+	}
+	failed := clusterCapacity{cluster: "c1", err: errors.New("401 Unauthorized")}
+	if err := capacityShortfall(failed, fits); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("failed capacity query: %v, want a shortfall", err)
+	}
+}
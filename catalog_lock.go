@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// --- Catalog lock: maintenance by admins blocks create and apply for everyone else ---
+//
+// `launcher maintenance on "template migration in progress" --for 2h` writes
+// catalog-lock.yaml at the root of the terraform checkout (commit it so every workstation
+// gets it). Until it expires or `launcher maintenance off` removes it, creating a deployment
+// and every apply are refused for users not listed in catalog_lock.admins, and each
+// launcher shows the message as a banner under its header. Admins keep working.
+
+type catalogLock struct {
+	Message string   `yaml:"message"`
+	Since   string   `yaml:"since"`
+	Expires string   `yaml:"expires"`
+	By      identity `yaml:"by"`
+}
+
+var errCatalogLocked = errors.New("the catalog is locked for maintenance")
+
+const defaultCatalogLockFor = 2 * time.Hour
+
+func catalogLockPath(cfg Config) string {
+	p := cfg.CatalogLock.Path
+	if p == "" {
+		p = "catalog-lock.yaml"
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(cfg.TerraformPath, p)
+}
+
+// The lock in force, nil when there is none or it has expired
+func readCatalogLock(cfg Config) *catalogLock {
+	data, err := os.ReadFile(catalogLockPath(cfg))
+	if err != nil {
+		return nil
+	}
+	var l catalogLock
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		// An unreadable lock still locks; the file is there for a reason
+		return &catalogLock{Message: "unreadable " + filepath.Base(catalogLockPath(cfg)) + ": " + err.Error()}
+	}
+	if l.expired() {
+		return nil
+	}
+	return &l
+}
+
+func (l catalogLock) expires() time.Time {
+	t, _ := time.Parse(time.RFC3339, l.Expires)
+	return t
+}
+
+func (l catalogLock) expired() bool {
+	t := l.expires()
+	return !t.IsZero() && time.Now().After(t)
+}
+
+// "template migration in progress — alice@ws01, until 16:00 (in 2h)"
+func (l catalogLock) String() string {
+	s := l.Message
+	if l.By.User != "" {
+		s += " — " + l.By.String()
+	}
+	if t := l.expires(); !t.IsZero() {
+		s += fmt.Sprintf(", until %s (%s)", localTime(t).Format("15:04"), relativeTime(t))
+	}
+	return s
+}
+
+// Whether the current user is one of catalog_lock.admins
+func isCatalogAdmin(cfg Config) bool {
+	id := currentIdentity()
+	// "alice as deploy" under sudo is alice
+	login, _, _ := strings.Cut(id.User, " as ")
+	for _, a := range cfg.CatalogLock.Admins {
+		if a == id.User || a == login || (id.SSO != "" && a == id.SSO) {
+			return true
+		}
+	}
+	return false
+}
+
+// Refuses create/apply while the catalog is locked, unless the user is an admin
+func checkCatalogLock(cfg Config) error {
+	l := readCatalogLock(cfg)
+	if l == nil || isCatalogAdmin(cfg) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errCatalogLocked, l)
+}
+
+// Banner under the header while the catalog is locked
+func catalogLockBanner(m model) string {
+	if m.catalogLock == nil {
+		return ""
+	}
+	text := "Catalog locked for maintenance: " + m.catalogLock.String()
+	if isCatalogAdmin(m.cfg) {
+		text += " (you are an admin: create and apply still work)"
+	} else {
+		text += " — create and apply are blocked"
+	}
+	return warnStyle.Render(truncate(text, uiWidth-6))
+}
+
+// launcher maintenance [on <message> [--for 2h] | off]
+//
+//	maintenance                  shows the lock
+//	maintenance on <message>     locks the catalog (admins only), until --for from now
+//	maintenance off              removes the lock (admins only)
+func cliMaintenance(cfg Config, args []string) error {
+	fsFlags := flag.NewFlagSet("maintenance", flag.ContinueOnError)
+	lockFor := fsFlags.Duration("for", defaultCatalogLockFor, "how long the lock lasts")
+	// Allow the message before the flags
+	var words []string
+	rest := args
+	for len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		words, rest = append(words, rest[0]), rest[1:]
+	}
+	if err := fsFlags.Parse(rest); err != nil {
+		return &ValidationError{err}
+	}
+	words = append(words, fsFlags.Args()...)
+	path := catalogLockPath(cfg)
+	if len(words) == 0 {
+		if l := readCatalogLock(cfg); l != nil {
+			fmt.Printf("locked: %s\n", l)
+		} else {
+			fmt.Println("not locked")
+		}
+		return nil
+	}
+	if !slices.Contains([]string{"on", "off"}, words[0]) {
+		return validationErrorf("usage: launcher maintenance [on <message> [--for 2h] | off]")
+	}
+	if !isCatalogAdmin(cfg) {
+		return validationErrorf("only catalog_lock.admins can lock or unlock the catalog (you are %s)", currentIdentity())
+	}
+	if words[0] == "off" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		recordAudit("maintenance-off", "catalog", "catalog lock removed", "ok")
+		fmt.Printf("removed %s\n", path)
+		return nil
+	}
+	message := strings.Join(words[1:], " ")
+	if message == "" {
+		return validationErrorf("say why the catalog is locked: launcher maintenance on \"template migration in progress\"")
+	}
+	if *lockFor <= 0 {
+		return validationErrorf("--for must be positive")
+	}
+	now := time.Now().UTC()
+	l := catalogLock{Message: message, Since: now.Format(time.RFC3339), Expires: now.Add(*lockFor).Format(time.RFC3339), By: currentIdentity()}
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	recordAudit("maintenance-on", "catalog", message, "ok")
+	fmt.Printf("locked: %s\nwrote %s — commit and push it so other workstations see it\n", l, path)
+	return nil
+}
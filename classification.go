@@ -0,0 +1,80 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Deployment classification: environment and criticality, chosen at create time ---
+//
+// The create form's environment and criticality fields go into launcher.state rather than
+// terraform.tfvars; platform_description stays the free-text description. The environment
+// picks the deployment's guard rails (guardrails.go).
+
+const prodEnvironment = "prod"
+
+// Create-form fields kept in launcher.state instead of tfvars
+var classificationFields = map[string]bool{"environment": true, "criticality": true}
+
+func setDeploymentClassification(path, environment, criticality string) error {
+	s, err := getDeploymentState(path)
+	if err != nil {
+		return err
+	}
+	s.Environment = environment
+	s.Criticality = criticality
+	return writeDeploymentState(path, s)
+}
+
+// Env column value: plain text, table cells can't hold colours
+func environmentLabel(info deploymentInfo) string {
+	return strings.ToUpper(info.Environment)
+}
+
+// Colour-coded "PROD" / "STAGE" / "DEV"; "" when the environment isn't set
+func environmentBadge(env string) string {
+	if env == "" {
+		return ""
+	}
+	label := " " + strings.ToUpper(env) + " "
+	switch env {
+	case prodEnvironment:
+		return errorStyle.Bold(true).Reverse(true).Render(label)
+	case "stage":
+		return warnStyle.Reverse(true).Render(label)
+	}
+	return okStyle.Reverse(true).Render(label)
+}
+
+// "PROD  criticality high" for the launcher's detail pane
+func classificationLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || m.deployments[idx].Environment == "" {
+		return nil
+	}
+	d := m.deployments[idx]
+	badge := environmentBadge(d.Environment)
+	if d.Criticality == "" {
+		return []string{badge}
+	}
+	return []string{badge + truncate("  criticality "+d.Criticality, max(width-lipgloss.Width(badge), 0))}
+}
+
+// Badge for the create form header from the environment field
+func createEnvironmentLabel(m model) string {
+	if badge := environmentBadge(createValue(m, "environment")); badge != "" {
+		return badge + " "
+	}
+	return ""
+}
+
+// Badge for the edit form header from the deployment's launcher.state
+func editEnvironmentLabel(m model) string {
+	dep, _ := deploymentByPath(m.allDeployments, filepath.Dir(m.editFormPath))
+	if badge := environmentBadge(dep.Environment); badge != "" {
+		return badge + " "
+	}
+	return ""
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Non-interactive plan / apply ---
+//
+//	launcher plan  <deployment> --plan-file tfplan
+//	launcher apply <deployment> --plan-file tfplan
+//
+// plan saves a terraform plan plus a fingerprint of the deployment directory;
+// apply refuses to run the plan if anything in the directory changed since.
+
+const planMetaSuffix = ".launcher.json"
+
+type planMeta struct {
+	Deployment  string `json:"deployment"`
+	Fingerprint string `json:"fingerprint"`
+	Created     string `json:"created"`
+}
+
+// Skipped when fingerprinting: terraform's own working data and launcher bookkeeping
+var fingerprintSkip = map[string]bool{".terraform": true, ".artifacts": true, "launcher.state": true}
+
+// Hashes every file of the deployment (paths and contents), except the plan artifacts themselves
+func dirFingerprint(dir string, exclude ...string) (string, error) {
+	skip := map[string]bool{}
+	for _, e := range exclude {
+		if abs, err := filepath.Abs(e); err == nil {
+			skip[abs] = true
+		}
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fingerprintSkip[d.Name()] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); skip[abs] || strings.HasSuffix(path, planMetaSuffix) || d.IsDir() {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	h := sha256.New()
+	for _, f := range files {
+		rel, _ := filepath.Rel(dir, f)
+		fmt.Fprintf(h, "%s\x00", rel)
+		in, err := os.Open(f)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, in)
+		in.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Runs terraform in dir with output going to the terminal
+func runTerraformCLI(dir string, args ...string) error {
+	env, err := secretsEnv(dir)
+	if err != nil {
+		return &VaultError{fmt.Errorf("failed to read deployment secrets: %w", err)}
+	}
+	if args[0] == "apply" {
+		if err := requireTerraform(); err != nil {
+			return err
+		}
+	}
+	cmd := terraformCommand(args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	started := time.Now()
+	err = cmd.Run()
+	logTerraform(dir, args, started, err)
+	if err != nil {
+		return &TerraformError{err}
+	}
+	return nil
+}
+
+func resolveDeployment(cfg Config, name string) (string, error) {
+	dir := name
+	if !filepath.IsAbs(name) && !strings.ContainsRune(name, os.PathSeparator) {
+		dir = filepath.Join(cfg.AppsPath, name)
+	}
+	if st, err := os.Stat(dir); err != nil || !st.IsDir() {
+		return "", validationErrorf("deployment %q not found", name)
+	}
+	return dir, nil
+}
+
+func cliPlan(dir, planFile string) error {
+	if initUpToDate(dir) {
+		fmt.Println("Skipping terraform init: .terraform matches the lock file and configuration")
+	} else if err := runTerraformCLI(dir, "init", "-input=false"); err != nil {
+		return fmt.Errorf("terraform init failed: %w", err)
+	} else {
+		recordInit(dir)
+	}
+	if err := runTerraformCLI(dir, "plan", "-input=false", "-out="+planFile); err != nil {
+		return fmt.Errorf("terraform plan failed: %w", err)
+	}
+	fp, err := dirFingerprint(dir, planFile)
+	if err != nil {
+		return err
+	}
+	meta := planMeta{Deployment: filepath.Base(dir), Fingerprint: fp, Created: time.Now().UTC().Format(time.RFC3339)}
+	data, _ := json.MarshalIndent(meta, "", "  ")
+	if err := os.WriteFile(planFile+planMetaSuffix, data, 0644); err != nil {
+		return err
+	}
+	logger.Info("plan saved", "component", "cli", "deployment", meta.Deployment, "plan", planFile)
+	recordAudit("plan", dir, planFile, "saved")
+	fmt.Printf("Saved plan %s for %s. Apply it with: launcher apply %s --plan-file %s\n", planFile, meta.Deployment, meta.Deployment, planFile)
+	return setDeploymentState(dir, "PLANNED", "plan")
+}
+
+func cliApply(cfg Config, templates []Template, dir, planFile string) error {
+	data, err := os.ReadFile(planFile + planMetaSuffix)
+	if err != nil {
+		return validationErrorf("no fingerprint for %s (was it made with `launcher plan`?): %w", planFile, err)
+	}
+	var meta planMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return err
+	}
+	if meta.Deployment != filepath.Base(dir) {
+		return validationErrorf("plan %s was made for %s, not %s", planFile, meta.Deployment, filepath.Base(dir))
+	}
+	fp, err := dirFingerprint(dir, planFile)
+	if err != nil {
+		return err
+	}
+	if fp != meta.Fingerprint {
+		return validationErrorf("%s changed since the plan was made (%s); run plan again", filepath.Base(dir), meta.Created)
+	}
+	// Same guard rails as an apply from the launcher: the reviewed plan replaces the confirmation
+	s, err := getDeploymentState(dir)
+	if err != nil {
+		return err
+	}
+	if guardRailsFor(cfg, s.Environment).SnapshotBeforeApply {
+		if err := snapshotDeployment(templateByName(templates, s.Template), dir); err != nil {
+			recordAudit("apply-plan", dir, planFile, "failed")
+			return fmt.Errorf("snapshot before apply failed: %w", err)
+		}
+	}
+	if err := runTerraformCLI(dir, "apply", "-input=false", planFile); err != nil {
+		setDeploymentState(dir, "FAILED", "apply")
+		recordAudit("apply-plan", dir, planFile, "failed")
+		return fmt.Errorf("terraform apply failed: %w", err)
+	}
+	recordAudit("apply-plan", dir, planFile, "succeeded")
+	if err := commitDeploymentChange(dir, "apply-plan", "succeeded"); err != nil {
+		fmt.Println("WARNING: could not commit deployment change:", err)
+	}
+	logger.Info("plan applied", "component", "cli", "deployment", meta.Deployment, "plan", planFile)
+	return setDeploymentState(dir, "DEPLOYED", "apply")
+}
+
+// launcher list [--format table|json|csv|markdown] [--output file]
+func cliList(cfg Config, templates []Template, args []string) error {
+	fsFlags := flag.NewFlagSet("list", flag.ContinueOnError)
+	format := fsFlags.String("format", "table", "table, json, csv or markdown")
+	output := fsFlags.String("output", "", "file to write instead of stdout")
+	if err := fsFlags.Parse(args); err != nil {
+		return &ValidationError{err}
+	}
+	if _, ok := exportFormats[*format]; !ok && *format != "table" {
+		return validationErrorf("unknown format %q (table, json, csv or markdown)", *format)
+	}
+	deployments, err := listDeployments(cfg.AppsPath)
+	if err != nil {
+		return &ConfigError{fmt.Errorf("could not list %s: %w", cfg.AppsPath, err)}
+	}
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return writeInventory(w, *format, buildInventory(deployments, templates))
+}
+
+// runCLI handles the subcommands; ok is false when args aren't one of them
+func runCLI(cfg Config, templates []Template, args []string) (ok bool, err error) {
+	if len(args) == 2 && args[0] == "audit" && args[1] == "verify" {
+		n, err := verifyAuditLog(auditLog.path)
+		if err != nil {
+			return true, &ValidationError{err}
+		}
+		fmt.Printf("%s: %d entries, chain intact\n", auditLog.path, n)
+		return true, nil
+	}
+	if len(args) > 0 && args[0] == "list" {
+		return true, cliList(cfg, templates, args[1:])
+	}
+	if len(args) > 0 && args[0] == "vmids" {
+		return true, cliVMIDs(cfg, templates, args[1:])
+	}
+	if len(args) > 0 && args[0] == "maintenance" {
+		return true, cliMaintenance(cfg, args[1:])
+	}
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply") {
+		return false, nil
+	}
+	fsFlags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	planFile := fsFlags.String("plan-file", "", "saved plan to write (plan) or apply (apply)")
+	// Allow the deployment name before the flags
+	rest := args[1:]
+	var name string
+	if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		name, rest = rest[0], rest[1:]
+	}
+	if err := fsFlags.Parse(rest); err != nil {
+		return true, &ValidationError{err}
+	}
+	if name == "" && fsFlags.NArg() > 0 {
+		name = fsFlags.Arg(0)
+	}
+	if name == "" || *planFile == "" {
+		return true, validationErrorf("usage: launcher %s <deployment> --plan-file <file>", args[0])
+	}
+	dir, err := resolveDeployment(cfg, name)
+	if err != nil {
+		return true, err
+	}
+	// terraform runs in the deployment directory, so the plan path must not be relative to ours
+	if *planFile, err = filepath.Abs(*planFile); err != nil {
+		return true, err
+	}
+	// Refused while a launcher runs a job on the deployment, and the other way round
+	if err := acquireDeployLock(dir, "launcher "+args[0]); err != nil {
+		return true, &ValidationError{err}
+	}
+	defer releaseDeployLock(dir)
+	if args[0] == "apply" {
+		if err := checkCatalogLock(cfg); err != nil {
+			return true, &ValidationError{err}
+		}
+	}
+	if args[0] == "plan" {
+		return true, cliPlan(dir, *planFile)
+	}
+	return true, cliApply(cfg, templates, dir, *planFile)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Writes path, creating its directory
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDirFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "tfplan")
+	writeTestFile(t, filepath.Join(dir, "main.tf"), "resource \"null_resource\" \"a\" {}\n")
+	writeTestFile(t, filepath.Join(dir, "terraform.tfvars"), "vm_count = 1\n")
+	base, err := dirFingerprint(dir, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, file, content string
+		changes             bool
+	}{
+		{"launcher.state", "launcher.state", "state: PLANNED\n", false},
+		{".terraform", ".terraform/providers/lock", "x", false},
+		{"plan file", "tfplan", "binary plan", false},
+		{"plan fingerprint", "tfplan" + planMetaSuffix, "{}", false},
+		{".tf file", "main.tf", "resource \"null_resource\" \"b\" {}\n", true},
+		{"tfvars", "terraform.tfvars", "vm_count = 2\n", true},
+		{"new file", "extra.tf", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			before, readErr := os.ReadFile(path)
+			writeTestFile(t, path, tt.content)
+			t.Cleanup(func() {
+				if readErr == nil {
+					os.WriteFile(path, before, 0644)
+				} else {
+					os.Remove(path)
+				}
+			})
+			fp, err := dirFingerprint(dir, plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed := fp != base; changed != tt.changes {
+				t.Errorf("fingerprint changed %v, want %v", changed, tt.changes)
+			}
+		})
+	}
+}
+
+func TestCLIApplyRefusesChangedDeployment(t *testing.T) {
+	for _, file := range []string{"main.tf", "terraform.tfvars"} {
+		t.Run(file, func(t *testing.T) {
+			m, fake := newTestModel(t, "web_a")
+			dir := filepath.Join(m.cfg.AppsPath, "web_a")
+			plan := filepath.Join(t.TempDir(), "web.tfplan")
+			if err := cliPlan(dir, plan); err != nil {
+				t.Fatal(err)
+			}
+			writeTestFile(t, filepath.Join(dir, file), "vm_count = 3\n")
+
+			err := cliApply(m.cfg, m.templates, dir, plan)
+			var verr *ValidationError
+			if !errors.As(err, &verr) || !strings.Contains(err.Error(), "changed since the plan was made") {
+				t.Fatalf("cliApply = %v, want it refused", err)
+			}
+			if slices.ContainsFunc(fake.calls, func(c string) bool { return strings.Contains(c, " apply ") }) {
+				t.Errorf("terraform apply ran: %q", fake.calls)
+			}
+		})
+	}
+}
+
+func TestCLIApplyUnchangedDeployment(t *testing.T) {
+	m, fake := newTestModel(t, "web_a")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	plan := filepath.Join(t.TempDir(), "web.tfplan")
+	if err := cliPlan(dir, plan); err != nil {
+		t.Fatal(err)
+	}
+	// Written by plan and apply themselves, so they don't count as changes
+	writeTestFile(t, filepath.Join(dir, ".terraform", "terraform.tfstate"), "{}")
+	if err := cliApply(m.cfg, m.templates, dir, plan); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(fake.calls, func(c string) bool { return strings.HasSuffix(c, "apply -input=false "+plan) }) {
+		t.Errorf("no terraform apply of the plan in %q", fake.calls)
+	}
+	if s, _ := getDeploymentState(dir); s.State != "DEPLOYED" {
+		t.Errorf("state %s, want DEPLOYED", s.State)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Linked vs full clone compatibility ---
+
+// Storage types that can host linked clones of a template (file-based ones only with qcow2 disks)
+var linkedCloneStorageTypes = map[string]bool{
+	"lvmthin": true, "zfspool": true, "rbd": true, "btrfs": true,
+	"dir": true, "nfs": true, "cifs": true, "glusterfs": true,
+}
+
+var fileStorageTypes = map[string]bool{"dir": true, "nfs": true, "cifs": true, "glusterfs": true}
+
+var diskKeyRe = regexp.MustCompile(`^(scsi|virtio|sata|ide)\d+$`)
+
+type cloneCheckMsg struct {
+	mode string
+	err  error
+}
+
+// Returns the first disk volume of a VM, e.g. "local-lvm:base-9000-disk-0"
+func templateDiskVolume(apiUrl, tokenId, tokenSecret string, vm ProxmoxVM) (string, error) {
+	var cfg map[string]interface{}
+	if err := proxmoxGet(apiUrl, tokenId, tokenSecret, fmt.Sprintf("nodes/%s/qemu/%d/config", vm.Node, vm.VmID), &cfg); err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(cfg))
+	for k := range cfg {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, ok := cfg[k].(string)
+		if !ok || !diskKeyRe.MatchString(k) || strings.Contains(v, "media=cdrom") {
+			continue
+		}
+		return strings.SplitN(v, ",", 2)[0], nil
+	}
+	return "", fmt.Errorf("no disk found on template %s", vm.Name)
+}
+
+func proxmoxStorageType(apiUrl, tokenId, tokenSecret, storage string) (string, error) {
+	var st struct {
+		Type string `json:"type"`
+	}
+	if err := proxmoxGet(apiUrl, tokenId, tokenSecret, "storage/"+storage, &st); err != nil {
+		return "", err
+	}
+	return st.Type, nil
+}
+
+// Verifies the template's storage can back the requested clone mode
+func checkCloneMode(cluster, templateName, mode string) error {
+	if mode != "linked" {
+		return nil
+	}
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get Proxmox creds: %w", err)
+	}
+	vms, err := listProxmoxTemplates(apiURL, tokenID, tokenSecret)
+	if err != nil {
+		return fmt.Errorf("failed to list Proxmox VMs: %w", err)
+	}
+	var tpl *ProxmoxVM
+	for i := range vms {
+		if vms[i].Name == templateName {
+			tpl = &vms[i]
+			break
+		}
+	}
+	if tpl == nil {
+		return fmt.Errorf("template %s not found on %s", templateName, cluster)
+	}
+	volume, err := templateDiskVolume(apiURL, tokenID, tokenSecret, *tpl)
+	if err != nil {
+		return err
+	}
+	storage := strings.SplitN(volume, ":", 2)[0]
+	stype, err := proxmoxStorageType(apiURL, tokenID, tokenSecret, storage)
+	if err != nil {
+		return err
+	}
+	if !linkedCloneStorageTypes[stype] {
+		return fmt.Errorf("linked clones are not supported on %s storage '%s' — use a full clone", stype, storage)
+	}
+	if fileStorageTypes[stype] && !strings.HasSuffix(volume, ".qcow2") {
+		return fmt.Errorf("linked clones on %s storage '%s' need a qcow2 template disk (%s) — use a full clone", stype, storage, volume)
+	}
+	return nil
+}
+
+func cloneCheckCmd(p Provider, cluster, templateName, mode string) tea.Cmd {
+	if _, ok := p.(proxmoxProvider); !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return cloneCheckMsg{mode: mode, err: checkCloneMode(cluster, templateName, mode)}
+	}
+}
+
+func cloneCheckStatus(msg cloneCheckMsg) string {
+	if msg.err != nil {
+		return "Clone mode check: " + msg.err.Error()
+	}
+	if msg.mode == "linked" {
+		return "Clone mode check: template storage supports linked clones."
+	}
+	return ""
+}
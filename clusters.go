@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/vaultclient"
+)
+
+// --- Cluster and zone options ---
+
+// Used when config.yaml lists no clusters/zones
+var (
+	defaultClusters = []string{"cl10400", "cl12600k", "cl12900h", "cl13600k"}
+	defaultZones    = []ZoneConfig{{Name: "standard"}, {Name: "admin"}, {Name: "dmz"}}
+)
+
+const defaultClusterVaultPath = "proxmox_api_keys/metadata"
+
+type clustersDiscoveredMsg struct {
+	clusters []string
+	err      error
+}
+
+func configClusters(cfg Config) []string {
+	if len(cfg.Clusters) > 0 {
+		return cfg.Clusters
+	}
+	return defaultClusters
+}
+
+func configZones(cfg Config) []ZoneConfig {
+	if len(cfg.Zones) > 0 {
+		return cfg.Zones
+	}
+	return defaultZones
+}
+
+func zoneNames(zones []ZoneConfig) []string {
+	names := make([]string, len(zones))
+	for i, z := range zones {
+		names[i] = z.Name
+	}
+	return names
+}
+
+// Tooltip line for a zone with metadata, "" when there is none
+func zoneInfo(zones []ZoneConfig, name string) string {
+	for _, z := range zones {
+		if z.Name != name {
+			continue
+		}
+		var parts []string
+		if z.VLAN != 0 {
+			parts = append(parts, fmt.Sprintf("VLAN %d", z.VLAN))
+		}
+		if z.Description != "" {
+			parts = append(parts, z.Description)
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		return "Zone " + z.Name + ": " + strings.Join(parts, " — ")
+	}
+	return ""
+}
+
+// Lists the per-cluster secrets (KV v2 metadata) the Proxmox credentials are read from
+func discoverVaultClusters(path string) ([]string, error) {
+	if path == "" {
+		path = defaultClusterVaultPath
+	}
+	client, err := newVaultClient()
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := vaultclient.ListKeys(client.Logical(), path)
+	logVault("list", path, err)
+	return clusters, err
+}
+
+// Keeps the clusters whose Proxmox API answers with the configured credentials
+func discoverProxmoxClusters(endpoints []string) ([]string, error) {
+	var clusters []string
+	var lastErr error
+	for _, name := range endpoints {
+		if err := (proxmoxProvider{}).Ping(name); err != nil {
+			lastErr = fmt.Errorf("%s: %w", name, err)
+			continue
+		}
+		clusters = append(clusters, name)
+	}
+	if len(clusters) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return clusters, nil
+}
+
+func discoverClustersCmd(cfg Config) tea.Cmd {
+	d := cfg.ClusterDiscovery
+	if d.Source == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		var clusters []string
+		var err error
+		switch d.Source {
+		case "vault":
+			clusters, err = discoverVaultClusters(d.Path)
+		case "proxmox":
+			endpoints := d.Endpoints
+			if len(endpoints) == 0 {
+				endpoints = configClusters(cfg)
+			}
+			clusters, err = discoverProxmoxClusters(endpoints)
+		default:
+			err = fmt.Errorf("unknown cluster_discovery source %q", d.Source)
+		}
+		if err == nil && len(clusters) == 0 {
+			err = fmt.Errorf("no clusters found")
+		}
+		return clustersDiscoveredMsg{clusters: clusters, err: err}
+	}
+}
+
+func handleClustersDiscovered(m model, msg clustersDiscoveredMsg) model {
+	if msg.err != nil {
+		m.statusMessage = "Cluster discovery failed, using configured clusters: " + msg.err.Error()
+		logger.Warn("cluster discovery failed", "component", "clusters", "source", m.cfg.ClusterDiscovery.Source, "error", msg.err.Error())
+		return m
+	}
+	m.clusterOptions = msg.clusters
+	logger.Info("clusters discovered", "component", "clusters", "source", m.cfg.ClusterDiscovery.Source, "count", len(msg.clusters))
+	return m
+}
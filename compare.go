@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Side-by-side comparison of two selected deployments' tfvars ---
+
+const compareRows = 24
+
+// A variable of either deployment; Left/Right are "" when it isn't set there
+type compareRow struct {
+	Key         string
+	Left, Right string
+	InLeft      bool
+	InRight     bool
+}
+
+func (r compareRow) differs() bool {
+	return r.InLeft != r.InRight || r.Left != r.Right
+}
+
+type compareSide struct {
+	Name string
+	Dir  string
+}
+
+// Variables in the order they appear in the files, the left one first
+func compareTfvars(left, right []string) []compareRow {
+	var order []string
+	rows := map[string]*compareRow{}
+	for side, lines := range [][]string{left, right} {
+		for _, line := range lines {
+			t := strings.TrimSpace(line)
+			k, v, ok := strings.Cut(t, "=")
+			if !ok || strings.HasPrefix(t, "#") {
+				continue
+			}
+			k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+			r, seen := rows[k]
+			if !seen {
+				r = &compareRow{Key: k}
+				rows[k] = r
+				order = append(order, k)
+			}
+			if side == 0 {
+				r.Left, r.InLeft = v, true
+			} else {
+				r.Right, r.InRight = v, true
+			}
+		}
+	}
+	out := make([]compareRow, len(order))
+	for i, k := range order {
+		out[i] = *rows[k]
+	}
+	return out
+}
+
+func openCompare(m model) (model, tea.Cmd) {
+	if len(m.selected) != 2 {
+		m.statusMessage = fmt.Sprintf("Select exactly two deployments with %s to compare them (%d selected)", keys.Launcher.Select.Help().Key, len(m.selected))
+		return m, nil
+	}
+	var sides []compareSide
+	for path := range m.selected {
+		if dep, ok := deploymentByPath(m.allDeployments, path); ok {
+			sides = append(sides, compareSide{Name: dep.Name, Dir: path})
+		}
+	}
+	if len(sides) != 2 {
+		return m, nil
+	}
+	sort.Slice(sides, func(i, j int) bool { return sides[i].Name < sides[j].Name })
+	m.compareSides = [2]compareSide{sides[0], sides[1]}
+	m.compareScroll = 0
+	return reloadCompare(m).pushScene(sceneCompare), nil
+}
+
+func reloadCompare(m model) model {
+	m.compareRows = compareTfvars(
+		readLines(filepath.Join(m.compareSides[0].Dir, "terraform.tfvars")),
+		readLines(filepath.Join(m.compareSides[1].Dir, "terraform.tfvars")))
+	return m
+}
+
+// Rows shown: all of them, or only the differences
+func shownCompareRows(m model) []compareRow {
+	if !m.compareDiffOnly {
+		return m.compareRows
+	}
+	var out []compareRow
+	for _, r := range m.compareRows {
+		if r.differs() {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Diff of the shown rows: "key = value" where both sides agree, else what each side sets
+func compareDiffLines(m model) []string {
+	v := diffView{Mode: m.diffMode, Width: uiWidth - 4, Syntax: syntaxHCL, Context: -1}
+	if !m.revealSensitive {
+		v.Mask = func(l string) string { return maskTfvarsLine(l, m.fieldMeta) }
+	}
+	var out []string
+	for _, r := range shownCompareRows(m) {
+		var ops []diffOp
+		switch {
+		case !r.differs():
+			ops = []diffOp{{' ', r.Key + " = " + r.Left}}
+		default:
+			if r.InLeft {
+				ops = append(ops, diffOp{'-', r.Key + " = " + r.Left})
+			}
+			if r.InRight {
+				ops = append(ops, diffOp{'+', r.Key + " = " + r.Right})
+			}
+		}
+		// Row by row so a variable set on one side only doesn't pair with the next one
+		out = append(out, v.renderOps(ops)...)
+	}
+	return out
+}
+
+func updateCompare(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	maxScroll := max(len(compareDiffLines(m))-compareRows, 0)
+	switch {
+	case key.Matches(keyMsg, keys.Compare.Back):
+		return m.popScene(), nil
+	case key.Matches(keyMsg, keys.Compare.Up):
+		m.compareScroll = max(m.compareScroll-1, 0)
+	case key.Matches(keyMsg, keys.Compare.Down):
+		m.compareScroll = min(m.compareScroll+1, maxScroll)
+	case key.Matches(keyMsg, keys.Compare.DiffOnly):
+		m.compareDiffOnly = !m.compareDiffOnly
+		m.compareScroll = 0
+	case key.Matches(keyMsg, keys.Compare.Swap):
+		m.compareSides[0], m.compareSides[1] = m.compareSides[1], m.compareSides[0]
+		m = reloadCompare(m)
+	case key.Matches(keyMsg, keys.Compare.Reload):
+		m = reloadCompare(m)
+		m.compareScroll = min(m.compareScroll, max(len(compareDiffLines(m))-compareRows, 0))
+	}
+	return m, nil
+}
+
+func viewCompare(m model) (body, tooltip string) {
+	differ := 0
+	for _, r := range m.compareRows {
+		if r.differs() {
+			differ++
+		}
+	}
+	left, right := m.compareSides[0].Name, m.compareSides[1].Name
+	body += tooltipStyle.Render(fmt.Sprintf("%s vs %s — %s differ", left, right, plural(differ, "variable"))) + "\n\n"
+	if m.diffMode == diffSideBySide {
+		col := (uiWidth - 4 - 3) / 2
+		body += " " + titleStyle.Render(padRight(truncate(left, col), col)+" │ "+truncate(right, col)) + "\n"
+	} else {
+		body += " " + titleStyle.Render(truncate("- "+left+"   + "+right, uiWidth-4)) + "\n"
+	}
+	body += " " + strings.Repeat("─", uiWidth-4) + "\n"
+	lines := compareDiffLines(m)
+	end := min(m.compareScroll+compareRows, len(lines))
+	for _, l := range lines[min(m.compareScroll, end):end] {
+		body += " " + l + "\n"
+	}
+	if len(lines) == 0 {
+		body += " (no differences)\n"
+	}
+	if len(lines) > compareRows {
+		body += "\n " + logDimStyle.Render(fmt.Sprintf("lines %d–%d of %d", m.compareScroll+1, end, len(lines))) + "\n"
+	}
+	tooltip = tooltipStyle.Render("Variables that differ are colored; a variable only one side sets has nothing on the other. " + keys.Global.DiffMode.Help().Key + " switches unified / side by side")
+	return body, tooltip
+}
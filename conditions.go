@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// --- Conditional fields and validation ---
+
+func (t Template) osFamily() string {
+	if t.OSFamily == "" {
+		return "linux"
+	}
+	return t.OSFamily
+}
+
+// A field applies when it matches the template's OS family and all its showWhen conditions hold
+func fieldApplies(m model, key string) bool {
+	meta := m.fieldMeta[key]
+	if len(meta.OSFamily) > 0 && indexOf(m.activeTemplate.osFamily(), meta.OSFamily) < 0 {
+		return false
+	}
+	for other, want := range meta.ShowWhen {
+		if createValue(m, other) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// A field is shown when it applies and isn't in the collapsed advanced section or a collapsed section
+func fieldVisible(m model, key string) bool {
+	meta := m.fieldMeta[key]
+	if meta.Advanced && !m.showAdvanced || !meta.Advanced && m.collapsedSections[meta.Section] {
+		return false
+	}
+	return fieldApplies(m, key)
+}
+
+// Moves create-form focus by dir, skipping hidden fields
+func nextVisibleField(m model, dir int) int {
+	n := len(m.createInputs)
+	i := m.createFocus
+	for step := 0; step < n; step++ {
+		i = (i + dir + n) % n
+		if fieldVisible(m, m.createLabels[i]) {
+			return i
+		}
+	}
+	return m.createFocus
+}
+
+// Checks required/pattern/options rules on every applicable field, collapsed or not
+func validateCreateForm(m model) error {
+	for i, key := range m.createLabels {
+		if !fieldApplies(m, key) {
+			continue
+		}
+		meta := m.fieldMeta[key]
+		label := meta.Label
+		if label == "" {
+			label = key
+		}
+		v := m.createInputs[i].Value()
+		if err := checkFieldValue(meta, label, v); err != nil {
+			return err
+		}
+		if key == diskSizeField && v != "" {
+			if err := validateDisks(parseDisks(v), createValue(m, diskCountField)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// The fields.yaml rules for one value: required, pattern, options and stepper range
+func checkFieldValue(meta FieldMeta, label, v string) error {
+	if v == "" {
+		if meta.Required {
+			return fmt.Errorf("%s is required", label)
+		}
+		return nil
+	}
+	if meta.Pattern != "" {
+		re, err := regexp.Compile(meta.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for %s in fields.yaml: %v", label, err)
+		}
+		if !re.MatchString(v) {
+			return fmt.Errorf("%s: %q does not match %s", label, maskFieldValue(meta, v), meta.Pattern)
+		}
+	}
+	if len(meta.Options) > 0 && indexOf(v, meta.Options) < 0 {
+		return fmt.Errorf("%s must be one of %v", label, meta.Options)
+	}
+	return checkStepperValue(meta, label, v)
+}
+
+// --- Advanced section ---
+
+// Default form fields plus every field the global fields.yaml flags as advanced, in file order
+func templateDefaultFields(meta map[string]FieldMeta) []string {
+	order, _ := loadFieldOrder(globalFieldsPath)
+	var advanced []string
+	for _, k := range order {
+		if meta[k].Advanced {
+			advanced = append(advanced, k)
+		}
+	}
+	return mergeFieldOrder(defaultFormFields, advanced)
+}
+
+// Moves advanced fields after the common ones so the collapsed section sits at the end of the
+// form, and keeps the fields of each section together
+func formFieldOrder(fields []string, meta map[string]FieldMeta) []string {
+	var common, advanced []string
+	for _, k := range fields {
+		if meta[k].Advanced {
+			advanced = append(advanced, k)
+		} else {
+			common = append(common, k)
+		}
+	}
+	return append(groupBySection(common, meta), advanced...)
+}
+
+func toggleAdvanced(m model) model {
+	m.showAdvanced = !m.showAdvanced
+	if !fieldVisible(m, m.createLabels[m.createFocus]) {
+		m.createInputs[m.createFocus].Blur()
+		m.createFocus = nextVisibleField(m, -1)
+		m.createInputs[m.createFocus].Focus()
+	}
+	return m
+}
+
+func advancedHeader(m model) string {
+	n, set := 0, 0
+	for i, key := range m.createLabels {
+		if m.fieldMeta[key].Advanced && fieldApplies(m, key) {
+			n++
+			if m.createInputs[i].Value() != "" {
+				set++
+			}
+		}
+	}
+	if m.showAdvanced {
+		return titleStyle.Render("▾ Advanced") + logDimStyle.Render(fmt.Sprintf("  (%d fields) — %s collapses", n, keys.Create.Advanced.Help().Key))
+	}
+	return titleStyle.Render("▸ Advanced") + logDimStyle.Render(fmt.Sprintf("  (%d fields, %d set) — %s expands", n, set, keys.Create.Advanced.Help().Key))
+}
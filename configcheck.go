@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/config"
+)
+
+// --- Strict YAML decoding for config.yaml, fields.yaml, template.yaml and the cost model ---
+
+// Reads path and decodes it into out; unknown keys are errors with their line, column
+// and the closest known key, instead of being dropped silently
+func loadYAMLStrict(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return decodeStrict(path, data, out)
+}
+
+func decodeStrict(path string, data []byte, out any) error {
+	if err := config.DecodeStrict(path, data, out); err != nil {
+		return &ConfigError{Err: err}
+	}
+	return nil
+}
+
+// Same as decodeStrict for an already parsed document (e.g. one of several in a file)
+func decodeNodeStrict(path string, doc *yaml.Node, out any) error {
+	if err := config.DecodeNodeStrict(path, doc, out); err != nil {
+		return &ConfigError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+)
+
+// --- Cost estimation ---
+
+// CostModel prices a VM's resources per month; loaded from the file named by cost_model
+type CostModel struct {
+	Currency  string  `yaml:"currency"` // prefix, e.g. "$" or "€"
+	PerCore   float64 `yaml:"per_core"`
+	PerGBRAM  float64 `yaml:"per_gb_ram"`
+	PerGBDisk float64 `yaml:"per_gb_disk"`
+}
+
+// nil when cost_model isn't configured; the cost column and estimate are hidden then
+var costModel *CostModel
+
+func loadCostModel(path string) (*CostModel, error) {
+	var c CostModel
+	if err := loadYAMLStrict(path, &c); err != nil {
+		return nil, err
+	}
+	if c.Currency == "" {
+		c.Currency = "$"
+	}
+	return &c, nil
+}
+
+// Monthly cost of the whole request (all VMs)
+func (c *CostModel) monthly(r capacityRequest) float64 {
+	perVM := float64(r.cores)*c.PerCore +
+		float64(r.memBytes)/(1<<30)*c.PerGBRAM +
+		float64(r.disk)/(1<<30)*c.PerGBDisk
+	return float64(r.count) * perVM
+}
+
+func (c *CostModel) format(amount float64) string {
+	return fmt.Sprintf("%s%.2f", c.Currency, amount)
+}
+
+// Table cell for a deployment; "" when its sizing is unknown
+func deploymentCost(info deploymentInfo) string {
+	if costModel == nil || (info.Size.cores == 0 && info.Size.memBytes == 0 && info.Size.disk == 0) {
+		return ""
+	}
+	return costModel.format(costModel.monthly(info.Size))
+}
+
+// Live estimate for the create form tooltip
+func createCostLine(m model) string {
+	if costModel == nil {
+		return ""
+	}
+	req := createCapacityRequest(m)
+	return fmt.Sprintf("Estimated cost: %s/month (%d × %s/VM)",
+		costModel.format(costModel.monthly(req)), req.count, costModel.format(costModel.monthly(capacityRequest{count: 1, memBytes: req.memBytes, cores: req.cores, disk: req.disk})))
+}
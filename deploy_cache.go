@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Deployment listing cache ---
+
+// fileStamp is the cheap change check; the content hash is only computed when it moves
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func stampOf(path string) fileStamp {
+	st, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{mod: st.ModTime(), size: st.Size()}
+}
+
+type cachedDeployment struct {
+	tfvars fileStamp
+	state  fileStamp
+	hash   [sha256.Size]byte
+	info   deploymentInfo
+}
+
+// deploymentCache keeps parsed deployment info between refreshes, keyed by directory
+type deploymentCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedDeployment
+}
+
+var deploymentInfoCache = &deploymentCache{entries: map[string]cachedDeployment{}}
+
+func (c *deploymentCache) get(dir string) (cachedDeployment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[dir]
+	return e, ok
+}
+
+func (c *deploymentCache) put(dir string, e cachedDeployment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[dir] = e
+}
+
+// Drops directories that no longer exist
+func (c *deploymentCache) retain(dirs map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for dir := range c.entries {
+		if !dirs[dir] {
+			delete(c.entries, dir)
+		}
+	}
+}
+
+func hashDeploymentFiles(dir string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, name := range []string{"terraform.tfvars", "launcher.state"} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// Returns the deployment in dir, re-parsing tfvars/launcher.state only when they changed
+func cachedDeploymentInfo(dir string, modTime time.Time) deploymentInfo {
+	tfvars := stampOf(filepath.Join(dir, "terraform.tfvars"))
+	state := stampOf(filepath.Join(dir, "launcher.state"))
+	prev, ok := deploymentInfoCache.get(dir)
+	switch {
+	case ok && prev.tfvars == tfvars && prev.state == state:
+		// unchanged
+	case ok && hashDeploymentFiles(dir) == prev.hash:
+		// touched but same content
+		prev.tfvars, prev.state = tfvars, state
+		deploymentInfoCache.put(dir, prev)
+	default:
+		prev = cachedDeployment{tfvars: tfvars, state: state, hash: hashDeploymentFiles(dir), info: parseDeploymentInfo(dir)}
+		deploymentInfoCache.put(dir, prev)
+	}
+	info := prev.info
+	info.LastModified = formatTime(modTime)
+	return info
+}
+
+func parseDeploymentInfo(full string) deploymentInfo {
+	desc, zone := "", ""
+	var size capacityRequest
+	if vals, err := loadTfvars(filepath.Join(full, "terraform.tfvars")); err == nil {
+		desc = strings.Trim(vals["platform_description"], "\"")
+		zone = strings.Trim(vals["zone"], "\"")
+		size = sizingRequest(func(key string) string { return strings.Trim(vals[key], "\"") })
+	}
+	st, _ := getDeploymentState(full)
+	lastAt, _ := time.Parse(time.RFC3339, st.Timestamp)
+	lastBy, lastVars := "", []string(nil)
+	if len(st.History) > 0 {
+		lastBy = st.History[len(st.History)-1].By.String()
+		lastVars = st.History[len(st.History)-1].Overrides
+	}
+	return deploymentInfo{
+		Name:         filepath.Base(full),
+		Description:  desc,
+		State:        st.State,
+		LastAction:   st.LastAction,
+		LastActionAt: lastAt,
+		LastBy:       lastBy,
+		LastVars:     lastVars,
+		Path:         full,
+		Zone:         zone,
+		Size:         size,
+
+		Template:       st.Template,
+		TemplateCommit: st.TemplateCommit,
+		Artifacts:      st.Artifacts,
+		SecretsPath:    st.SecretsPath,
+		Secrets:        st.Secrets,
+		Plan:           st.Plan,
+		Failure:        st.Failure,
+		Health:         st.Health,
+		Environment:    st.Environment,
+		Criticality:    st.Criticality,
+	}
+}
+
+// Loads every deployment directory with a small worker pool; order follows dirs
+func loadDeploymentsParallel(dirs []string) []deploymentInfo {
+	infos := make([]deploymentInfo, len(dirs))
+	ok := make([]bool, len(dirs))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				stat, err := os.Stat(dirs[i])
+				if err != nil {
+					continue
+				}
+				infos[i], ok[i] = cachedDeploymentInfo(dirs[i], stat.ModTime()), true
+			}
+		}()
+	}
+	for i := range dirs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	var out []deploymentInfo
+	seen := map[string]bool{}
+	for i, info := range infos {
+		if ok[i] {
+			out = append(out, info)
+			seen[dirs[i]] = true
+		}
+	}
+	deploymentInfoCache.retain(seen)
+	return out
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/state"
+)
+
+// --- Deployment health checks, after apply and on demand ([T] on the launcher) ---
+
+// HealthChecksSpec is the `health_checks:` block of template.yaml
+//
+//	health_checks:
+//	  wait: 2m                 # after an apply, retry failing checks this long while VMs boot
+//	  checks:
+//	    - tcp: 22              # port open on every VM address
+//	    - http: "http://{ip}:8080/healthz"
+//	    - proxmox: true        # every VM of output vm_ids is running
+type HealthChecksSpec struct {
+	Wait       string        `yaml:"wait"`
+	VMIDOutput string        `yaml:"vmid_output"` // terraform output with the VM IDs, default "vm_ids"
+	Checks     []HealthCheck `yaml:"checks"`
+}
+
+// One probe; exactly one of the fields is set
+type HealthCheck struct {
+	TCP     int    `yaml:"tcp"`
+	HTTP    string `yaml:"http"` // {ip} is replaced by each VM address; a status below 400 passes
+	Proxmox bool   `yaml:"proxmox"`
+}
+
+// Outcome of the last checks, in launcher.state until the deployment state changes
+type healthReport = state.Health
+
+const (
+	healthProbeTimeout = 5 * time.Second
+	healthRetryEvery   = 10 * time.Second
+	defaultHealthWait  = 2 * time.Minute
+)
+
+// healthCheckedMsg is sent when on-demand checks finish
+type healthCheckedMsg struct {
+	dir    string
+	report healthReport
+	err    error
+}
+
+func (c HealthCheck) String() string {
+	switch {
+	case c.TCP > 0:
+		return fmt.Sprintf("tcp/%d", c.TCP)
+	case c.HTTP != "":
+		return c.HTTP
+	case c.Proxmox:
+		return "proxmox status"
+	}
+	return "empty check"
+}
+
+func (s HealthChecksSpec) wait() time.Duration {
+	if d, err := time.ParseDuration(s.Wait); err == nil {
+		return d
+	}
+	return defaultHealthWait
+}
+
+func (s HealthChecksSpec) vmidOutput() string {
+	if s.VMIDOutput != "" {
+		return s.VMIDOutput
+	}
+	return "vm_ids"
+}
+
+// Runs every check of t against the deployment in dir, again every healthRetryEvery until
+// they all pass or wait is over
+func runHealthChecks(cfg Config, t Template, dir string, wait time.Duration) (healthReport, error) {
+	outputs, err := readTerraformOutputs(dir)
+	if err != nil {
+		return healthReport{}, err
+	}
+	var ips []string
+	if o, ok := outputs[sshIPOutput(cfg.SSH)]; ok {
+		targets, _ := parseSSHTargets(o.Value)
+		for _, tg := range targets {
+			ips = append(ips, tg.IP)
+		}
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		var failures []string
+		for _, c := range t.HealthChecks.Checks {
+			failures = append(failures, runHealthCheck(c, t, dir, ips, outputs, sshIPOutput(cfg.SSH))...)
+		}
+		if len(failures) == 0 || time.Now().Add(healthRetryEvery).After(deadline) {
+			report := healthReport{Status: "HEALTHY", At: time.Now().UTC().Format(time.RFC3339), Failures: failures}
+			if len(failures) > 0 {
+				report.Status = "UNHEALTHY"
+			}
+			return report, nil
+		}
+		time.Sleep(healthRetryEvery)
+	}
+}
+
+// Failures of one check, one line per VM that failed it
+func runHealthCheck(c HealthCheck, t Template, dir string, ips []string, outputs map[string]tfOutput, ipOutput string) []string {
+	if (c.TCP > 0 || c.HTTP != "") && len(ips) == 0 {
+		return []string{fmt.Sprintf("%s: no VM address in output %q", c, ipOutput)}
+	}
+	var failures []string
+	switch {
+	case c.TCP > 0:
+		for _, ip := range ips {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip, strconv.Itoa(c.TCP)), healthProbeTimeout)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s on %s: %v", c, ip, err))
+				continue
+			}
+			conn.Close()
+		}
+	case c.HTTP != "":
+		client := &http.Client{Timeout: healthProbeTimeout}
+		for _, ip := range ips {
+			url := strings.ReplaceAll(c.HTTP, "{ip}", ip)
+			resp, err := client.Get(url)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", url, err))
+				continue
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 400 {
+				failures = append(failures, fmt.Sprintf("%s: %s", url, resp.Status))
+			}
+		}
+	case c.Proxmox:
+		if err := proxmoxVMsRunning(t, dir, outputs); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", c, err))
+		}
+	}
+	return failures
+}
+
+// Asks the deployment's cluster for the status of every VM ID in the vmid output
+func proxmoxVMsRunning(t Template, dir string, outputs map[string]tfOutput) error {
+	if _, ok := t.provider().(proxmoxProvider); !ok {
+		return fmt.Errorf("the %s provider has no Proxmox status", t.Provider)
+	}
+	if _, ok := outputs[t.HealthChecks.vmidOutput()]; !ok {
+		return fmt.Errorf("terraform output %q not found", t.HealthChecks.vmidOutput())
+	}
+	ids, err := outputVMIDs(t, outputs)
+	if err != nil {
+		return err
+	}
+	tfvars, _ := loadTfvars(filepath.Join(dir, "terraform.tfvars"))
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(strings.Trim(tfvars["cluster"], "\""))
+	if err != nil {
+		return err
+	}
+	var vms []struct {
+		VmID   int    `json:"vmid"`
+		Status string `json:"status"`
+	}
+	if err := proxmoxGet(apiURL, tokenID, tokenSecret, "cluster/resources?type=vm", &vms); err != nil {
+		return err
+	}
+	status := map[int]string{}
+	for _, vm := range vms {
+		status[vm.VmID] = vm.Status
+	}
+	var bad []string
+	for _, id := range ids {
+		if s := status[id]; s != "running" {
+			if s == "" {
+				s = "missing"
+			}
+			bad = append(bad, fmt.Sprintf("%d %s", id, s))
+		}
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("VM %s", strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// VM IDs in the template's vmid output (vm_ids unless health_checks.vmid_output says
+// otherwise); none when the output doesn't exist yet
+func outputVMIDs(t Template, outputs map[string]tfOutput) ([]int, error) {
+	o, ok := outputs[t.HealthChecks.vmidOutput()]
+	if !ok {
+		return nil, nil
+	}
+	var raw interface{}
+	if err := json.Unmarshal(o.Value, &raw); err != nil {
+		return nil, err
+	}
+	return flattenVMIDs(raw), nil
+}
+
+// VM IDs from a number, a list or a map of them (numbers or numeric strings)
+func flattenVMIDs(v interface{}) []int {
+	switch v := v.(type) {
+	case float64:
+		return []int{int(v)}
+	case string:
+		if id, err := strconv.Atoi(v); err == nil {
+			return []int{id}
+		}
+	case []interface{}:
+		var ids []int
+		for _, e := range v {
+			ids = append(ids, flattenVMIDs(e)...)
+		}
+		return ids
+	case map[string]interface{}:
+		var ids []int
+		for _, e := range v {
+			ids = append(ids, flattenVMIDs(e)...)
+		}
+		return ids
+	}
+	return nil
+}
+
+func recordHealth(dir string, report healthReport) error {
+	s, _ := getDeploymentState(dir)
+	s.Health = &report
+	return writeDeploymentState(dir, s)
+}
+
+// Follow-up of an apply: artifacts first, then the health checks. Unhealthy VMs don't fail
+// the job; they show in the Health column.
+func postApplyHook(cfg Config, t Template, dir string) func() error {
+	artifacts := artifactsHook(cfg, t, dir)
+	if len(t.HealthChecks.Checks) == 0 {
+		return artifacts
+	}
+	return func() error {
+		if artifacts != nil {
+			if err := artifacts(); err != nil {
+				return err
+			}
+		}
+		report, err := runHealthChecks(cfg, t, dir, t.HealthChecks.wait())
+		if err != nil {
+			logger.Warn("health checks failed to run", "component", "health", "deployment", filepath.Base(dir), "error", err.Error())
+			return nil
+		}
+		return recordHealth(dir, report)
+	}
+}
+
+// Runs the checks of the selected deployment once, without waiting for failing ones
+func testDeployment(m model) (model, tea.Cmd) {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m, nil
+	}
+	dep := m.deployments[idx]
+	t := templateByName(m.templates, dep.Template)
+	if len(t.HealthChecks.Checks) == 0 {
+		m.statusMessage = fmt.Sprintf("Template %s has no health_checks", t.Name)
+		return m, nil
+	}
+	if dep.State != "DEPLOYED" {
+		m.statusMessage = fmt.Sprintf("%s is %s, only deployed deployments are checked", dep.Name, dep.State)
+		return m, nil
+	}
+	m.statusMessage = fmt.Sprintf("Running %s on %s...", plural(len(t.HealthChecks.Checks), "health check"), dep.Name)
+	cfg := m.cfg
+	return m, func() tea.Msg {
+		report, err := runHealthChecks(cfg, t, dep.Path, 0)
+		if err == nil {
+			err = recordHealth(dep.Path, report)
+		}
+		return healthCheckedMsg{dir: dep.Path, report: report, err: err}
+	}
+}
+
+func handleHealthChecked(m model, msg healthCheckedMsg) (model, tea.Cmd) {
+	name := filepath.Base(msg.dir)
+	switch {
+	case msg.err != nil:
+		m.statusMessage = fmt.Sprintf("Health checks of %s could not run: %v", name, msg.err)
+		return m, nil
+	case msg.report.Status == "HEALTHY":
+		m.statusMessage = name + " is healthy"
+	default:
+		m.statusMessage = fmt.Sprintf("%s is unhealthy: %s", name, strings.Join(msg.report.Failures, "; "))
+	}
+	return m, refreshDeploymentCmd(msg.dir)
+}
+
+// Health column value: blank until a check ran
+func healthBadge(info deploymentInfo) string {
+	if info.Health == nil {
+		return ""
+	}
+	return info.Health.Status
+}
+
+// Failed checks of the selected deployment for the launcher's detail pane
+func healthLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || m.deployments[idx].Health == nil || len(m.deployments[idx].Health.Failures) == 0 {
+		return nil
+	}
+	lines := []string{truncate(fmt.Sprintf("Unhealthy — %s checks again", keys.Launcher.Test.Help().Key), width)}
+	for _, f := range m.deployments[idx].Health.Failures {
+		lines = append(lines, truncate("  "+f, width))
+	}
+	return lines
+}
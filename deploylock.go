@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/filelock"
+)
+
+// --- One operation per deployment, across launcher instances ---
+//
+// A running job holds .launcher.lock in the deployment directory. Within one launcher, jobs
+// for the same deployment queue behind each other (startQueuedJobs); the lock file stops a
+// second launcher, or `launcher plan/apply`, from running terraform there at the same time.
+
+const deployLockFile = ".launcher.lock"
+
+type deployLock struct {
+	PID       int      `yaml:"pid"`
+	By        identity `yaml:"by"`
+	Operation string   `yaml:"operation"`
+	Started   string   `yaml:"started"`
+}
+
+// errDeploymentBusy is returned (wrapped) when another process holds the lock
+var errDeploymentBusy = errors.New("deployment is busy")
+
+func (l deployLock) String() string {
+	s := fmt.Sprintf("%s by %s (pid %d)", l.Operation, l.By, l.PID)
+	if t, err := time.Parse(time.RFC3339, l.Started); err == nil {
+		s += fmt.Sprintf(", running for %s", time.Since(t).Round(time.Second))
+	}
+	return s
+}
+
+func readDeployLock(dir string) (deployLock, bool) {
+	var l deployLock
+	data, err := os.ReadFile(filepath.Join(dir, deployLockFile))
+	if err != nil || yaml.Unmarshal(data, &l) != nil {
+		return l, false
+	}
+	return l, true
+}
+
+// Unlock functions of the deployment locks this process holds, by directory
+var (
+	deployLocksMu sync.Mutex
+	deployLocks   = map[string]func(){}
+)
+
+func deployLockBusy(dir string) error {
+	if l, ok := readDeployLock(dir); ok && l.PID != 0 {
+		return fmt.Errorf("%w: %s — %s", errDeploymentBusy, filepath.Base(dir), l)
+	}
+	return fmt.Errorf("%w: %s — locked by another process", errDeploymentBusy, filepath.Base(dir))
+}
+
+// Takes the lock of dir for operation, or says who holds it. The lock is a flock on the
+// file, so it goes away with the process holding it; the file only tells who that is.
+func acquireDeployLock(dir, operation string) error {
+	path := filepath.Join(dir, deployLockFile)
+	unlock, err := filelock.TryLock(path)
+	if errors.Is(err, filelock.ErrLocked) {
+		return deployLockBusy(dir)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(deployLock{PID: os.Getpid(), By: currentIdentity(), Operation: operation, Started: time.Now().UTC().Format(time.RFC3339)})
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		unlock()
+		return err
+	}
+	deployLocksMu.Lock()
+	deployLocks[dir] = unlock
+	deployLocksMu.Unlock()
+	return nil
+}
+
+// Releases the lock if this process holds it. The file is emptied, not removed: a process
+// that opened it just before a remove would lock a file nobody else can see.
+func releaseDeployLock(dir string) {
+	deployLocksMu.Lock()
+	unlock, ok := deployLocks[dir]
+	delete(deployLocks, dir)
+	deployLocksMu.Unlock()
+	if !ok {
+		return
+	}
+	// Gone when the deployment was archived while locked
+	if err := os.Truncate(filepath.Join(dir, deployLockFile), 0); err != nil && !os.IsNotExist(err) {
+		logger.Error("could not clear deployment lock", "component", "jobs", "deployment", filepath.Base(dir), "error", err.Error())
+	}
+	unlock()
+}
+
+// Whether another process holds the lock of dir
+func lockedElsewhere(dir string) bool {
+	path := filepath.Join(dir, deployLockFile)
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+	deployLocksMu.Lock()
+	_, mine := deployLocks[dir]
+	deployLocksMu.Unlock()
+	if mine {
+		return false
+	}
+	unlock, err := filelock.TryLock(path)
+	if err != nil {
+		return errors.Is(err, filelock.ErrLocked)
+	}
+	unlock()
+	return false
+}
+
+// Why dir can't be changed right now: a job of this launcher, or another process' lock
+func deploymentBusy(m model, dir string) error {
+	for _, j := range m.jobs {
+		if j.Op.Dir == dir && (j.Status == jobRunning || j.Status == jobQueued) {
+			return fmt.Errorf("%w: %s — job #%d (%s) is %s", errDeploymentBusy, filepath.Base(dir), j.ID, j.Op.action(), j.Status)
+		}
+	}
+	if lockedElsewhere(dir) {
+		return deployLockBusy(dir)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"launcher/internal/filelock"
+)
+
+func TestDeployLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, deployLockFile)
+	// left over by a launcher that crashed: nobody holds it
+	if err := os.WriteFile(path, []byte("pid: 1\noperation: apply\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := acquireDeployLock(dir, "apply"); err != nil {
+		t.Fatalf("leftover lock file not taken over: %v", err)
+	}
+	if _, err := filelock.TryLock(path); !errors.Is(err, filelock.ErrLocked) {
+		t.Fatalf("lock not held: %v", err)
+	}
+	releaseDeployLock(dir)
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("holder %q kept after release", data)
+	}
+
+	// held by someone else
+	unlock, err := filelock.TryLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	if err := os.WriteFile(path, []byte("pid: 4242\noperation: destroy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = acquireDeployLock(dir, "apply")
+	if !errors.Is(err, errDeploymentBusy) || !strings.Contains(err.Error(), "destroy") {
+		t.Errorf("acquire while held: %v", err)
+	}
+	if !lockedElsewhere(dir) {
+		t.Error("lockedElsewhere is false while another holder has the lock")
+	}
+}
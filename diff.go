@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Diff rendering: tfvars diffs, template changes, bulk edit and replace previews, compare ---
+//
+// Lines are diffed with an LCS, then drawn unified ("- old" / "+ new") or side by side
+// (old │ new) with removed lines red and added lines green. HCL and YAML keys and comments
+// are highlighted. diff_mode picks the layout; the global diff-mode key flips it at runtime.
+
+type diffMode int
+
+const (
+	diffUnified diffMode = iota
+	diffSideBySide
+)
+
+type diffSyntax int
+
+const (
+	syntaxPlain diffSyntax = iota
+	syntaxHCL
+	syntaxYAML
+)
+
+// Syntax from the file extension: .tf/.tfvars/.hcl are HCL, .yaml/.yml YAML
+func syntaxFor(path string) diffSyntax {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tf", ".tfvars", ".hcl":
+		return syntaxHCL
+	case ".yaml", ".yml":
+		return syntaxYAML
+	}
+	return syntaxPlain
+}
+
+// diff_mode: unified (default) or side-by-side
+func configureDiffMode(cfg Config) (diffMode, error) {
+	switch cfg.DiffMode {
+	case "", "unified":
+		return diffUnified, nil
+	case "side-by-side":
+		return diffSideBySide, nil
+	}
+	return diffUnified, &ConfigError{Err: fmt.Errorf("unknown diff_mode %q (unified or side-by-side)", cfg.DiffMode)}
+}
+
+// A line of the diff: Kind is ' ' (in both), '-' (before only) or '+' (after only)
+type diffOp struct {
+	Kind byte
+	Text string
+}
+
+func (op diffOp) changed() bool {
+	return op.Kind != ' '
+}
+
+// Line diff (LCS) from a to b
+func diffOps(a, b []string) []diffOp {
+	n, k := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, k+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := k - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []diffOp
+	i, j := 0, 0
+	for i < n && j < k {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffOp{'-', a[i]})
+			i++
+		default:
+			out = append(out, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, diffOp{'-', a[i]})
+	}
+	for ; j < k; j++ {
+		out = append(out, diffOp{'+', b[j]})
+	}
+	return out
+}
+
+// How a diff is drawn
+type diffView struct {
+	Mode   diffMode
+	Width  int
+	Syntax diffSyntax
+	// Unchanged lines kept around each change; 0 shows changed lines only, -1 every line
+	Context int
+	// Applied to each line before drawing, e.g. to mask secret values; nil leaves lines as-is
+	Mask func(string) string
+}
+
+func (v diffView) render(before, after []string) []string {
+	return v.renderOps(diffOps(before, after))
+}
+
+// Styled lines, at most Width cells each; "⋯" marks unchanged lines left out
+func (v diffView) renderOps(ops []diffOp) []string {
+	keep := v.keptOps(ops)
+	var out []string
+	skipped := false
+	for i := 0; i < len(ops); {
+		if !keep[i] {
+			skipped = true
+			i++
+			continue
+		}
+		if skipped && len(out) > 0 && v.Context > 0 {
+			out = append(out, logDimStyle.Render("⋯"))
+		}
+		skipped = false
+		if !ops[i].changed() || v.Mode == diffUnified {
+			out = append(out, v.row(ops[i]))
+			i++
+			continue
+		}
+		// Side by side: a run of changes pairs its removed lines with its added ones
+		var removed, added []diffOp
+		for ; i < len(ops) && keep[i] && ops[i].changed(); i++ {
+			if ops[i].Kind == '-' {
+				removed = append(removed, ops[i])
+			} else {
+				added = append(added, ops[i])
+			}
+		}
+		for r := 0; r < max(len(removed), len(added)); r++ {
+			var left, right *diffOp
+			if r < len(removed) {
+				left = &removed[r]
+			}
+			if r < len(added) {
+				right = &added[r]
+			}
+			out = append(out, v.pair(left, right))
+		}
+	}
+	return out
+}
+
+func (v diffView) keptOps(ops []diffOp) []bool {
+	keep := make([]bool, len(ops))
+	for i, op := range ops {
+		if v.Context < 0 || op.changed() {
+			keep[i] = true
+			continue
+		}
+		for j := max(i-v.Context, 0); j <= min(i+v.Context, len(ops)-1); j++ {
+			if ops[j].changed() {
+				keep[i] = true
+				break
+			}
+		}
+	}
+	return keep
+}
+
+// Unified line, or both halves of an unchanged line side by side
+func (v diffView) row(op diffOp) string {
+	if v.Mode == diffUnified {
+		return v.cell(op, v.Width)
+	}
+	return v.pair(&op, &op)
+}
+
+func (v diffView) pair(left, right *diffOp) string {
+	col := max((v.Width-3)/2, 4)
+	var l, r string
+	if left != nil {
+		l = v.cell(*left, col)
+	}
+	if right != nil {
+		r = v.cell(*right, col)
+	}
+	return padVisible(l, col) + logDimStyle.Render(" │ ") + r
+}
+
+// "- text" / "+ text" / "  text" cut to width and highlighted
+func (v diffView) cell(op diffOp, width int) string {
+	text := op.Text
+	if v.Mask != nil {
+		text = v.Mask(text)
+	}
+	line := truncate(string(op.Kind)+" "+text, max(width, 2))
+	base := normalStyle
+	switch op.Kind {
+	case '-':
+		base = errorStyle
+	case '+':
+		base = okStyle
+	}
+	marker, rest := line[:1], line[min(2, len(line)):]
+	return base.Bold(true).Render(marker) + " " + highlightLine(rest, v.Syntax, base, op.changed())
+}
+
+// Keys bold (accent on unchanged lines) and comments dim; the rest in the line's diff color
+func highlightLine(s string, syntax diffSyntax, base lipgloss.Style, changed bool) string {
+	if syntax == syntaxPlain || s == "" {
+		return base.Render(s)
+	}
+	keyStyle := base.Bold(true)
+	if !changed {
+		keyStyle = fg(theme.Accent)
+	}
+	indent := s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+	rest := s[len(indent):]
+	if isCommentStart(rest, syntax) {
+		return indent + logDimStyle.Render(rest)
+	}
+	// YAML list items: the dash stays in the base color
+	if syntax == syntaxYAML && strings.HasPrefix(rest, "- ") {
+		indent += base.Render("- ")
+		rest = rest[2:]
+	}
+	var key, sep, value string
+	switch syntax {
+	case syntaxHCL:
+		if i := strings.IndexAny(rest, "={"); i > 0 && !strings.ContainsAny(rest[:i], `"#`) {
+			key, sep, value = rest[:i], rest[i:i+1], rest[i+1:]
+		}
+	case syntaxYAML:
+		if i := strings.Index(rest, ":"); i > 0 && (i == len(rest)-1 || rest[i+1] == ' ') && !strings.ContainsAny(rest[:i], `"'#`) {
+			key, sep, value = rest[:i], ":", rest[i+1:]
+		}
+	}
+	if key == "" {
+		value = rest
+	}
+	code, comment := splitComment(value, syntax)
+	out := indent
+	if key != "" {
+		out += keyStyle.Render(key) + base.Render(sep)
+	}
+	out += base.Render(code)
+	if comment != "" {
+		out += logDimStyle.Render(comment)
+	}
+	return out
+}
+
+func isCommentStart(s string, syntax diffSyntax) bool {
+	if syntax == syntaxHCL && strings.HasPrefix(s, "//") {
+		return true
+	}
+	return strings.HasPrefix(s, "#")
+}
+
+// Splits a trailing comment ("# …", "// …" in HCL) off s, ignoring markers inside quotes
+func splitComment(s string, syntax diffSyntax) (code, comment string) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || (c == '\'' && syntax == syntaxYAML):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || syntax == syntaxHCL):
+			return s[:i], s[i:]
+		case c == '/' && syntax == syntaxHCL && i+1 < len(s) && s[i+1] == '/':
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+// Diff of two versions of a terraform.tfvars: changed lines only, masked unless revealed
+func tfvarsDiffLines(m model, before, after []string, width int) []string {
+	v := diffView{Mode: m.diffMode, Width: width, Syntax: syntaxHCL}
+	if !m.revealSensitive {
+		v.Mask = func(l string) string { return maskTfvarsLine(l, m.fieldMeta) }
+	}
+	return v.render(before, after)
+}
+
+// Flips unified / side by side for every diff on screen
+func toggleDiffMode(m model) model {
+	if m.diffMode == diffUnified {
+		m.diffMode = diffSideBySide
+		m.statusMessage = "Diffs shown side by side"
+	} else {
+		m.diffMode = diffUnified
+		m.statusMessage = "Diffs shown unified"
+	}
+	if m.currentScene == sceneTemplateDiff && !m.templateDiffLoading {
+		m = renderTemplateDiff(m)
+	}
+	return m
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Disk list editor for vm_disk_size (Ctrl+D in the create and edit forms) ---
+
+const (
+	diskSizeField  = "vm_disk_size"
+	diskCountField = "vm_disk_count"
+	maxDisks       = 16
+)
+
+var diskSizeRe = regexp.MustCompile(`^[1-9][0-9]*[GT]$`)
+
+// Sizes of a vm_disk_size form value: "100G,200G", or `"100G", "200G"` as loaded from tfvars
+func parseDisks(v string) []string {
+	var disks []string
+	for _, part := range strings.Split(v, ",") {
+		if s := strings.Trim(strings.TrimSpace(part), "\"[] "); s != "" {
+			disks = append(disks, s)
+		}
+	}
+	return disks
+}
+
+// Checks every size and, when the form has a disk count, that it matches the list
+func validateDisks(disks []string, count string) error {
+	for i, d := range disks {
+		if !diskSizeRe.MatchString(d) {
+			return fmt.Errorf("disk %d: %q is not a size like 100G or 2T", i+1, d)
+		}
+	}
+	if n, err := strconv.Atoi(count); err == nil && n != len(disks) {
+		return fmt.Errorf("%s has %s but %s is %d — edit the disks with %s", diskSizeField, plural(len(disks), "disk"), diskCountField, n, keys.Create.Disks.Help().Key)
+	}
+	return nil
+}
+
+// Mini table of the disks, shown under the field in the forms
+func diskTableLines(v string) []string {
+	disks := parseDisks(v)
+	if len(disks) == 0 {
+		return nil
+	}
+	lines := []string{logDimStyle.Render(fmt.Sprintf("%30s  %-4s %s", "", "Disk", "Size"))}
+	for i, d := range disks {
+		lines = append(lines, logDimStyle.Render(fmt.Sprintf("%30s  %-4d %s", "", i+1, d)))
+	}
+	return lines
+}
+
+// Swallows typing on the disk field, which is only edited through the list editor;
+// keys bound in the form's keymap keep working, except the number steppers
+func guardDiskField(label string, msg tea.KeyMsg, formKeys any) (string, bool) {
+	if label != diskSizeField {
+		return "", false
+	}
+	_, bindings := namedBindings(formKeys)
+	for name, b := range bindings {
+		if name != "decrease" && name != "increase" && key.Matches(msg, *b) {
+			return "", false
+		}
+	}
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace, tea.KeyDelete:
+		return fmt.Sprintf("Disk sizes are edited as a list — press %s", keys.Create.Disks.Help().Key), true
+	}
+	return "", false
+}
+
+// Opens the editor on the vm_disk_size field of the create or edit form
+func openDiskEditor(m model, from scene) (model, tea.Cmd) {
+	var value string
+	switch from {
+	case sceneCreateForm:
+		if indexOf(diskSizeField, m.createLabels) < 0 {
+			m.createStatus = "This template has no " + diskSizeField + " field"
+			return m, nil
+		}
+		value = createValue(m, diskSizeField)
+	case sceneEditForm:
+		i := indexOf(diskSizeField, m.editFormLabels)
+		if i < 0 {
+			m.editStatus = "This deployment has no " + diskSizeField + " variable"
+			return m, nil
+		}
+		value = m.editFormInputs[i].Value()
+	}
+	m.diskInputs = nil
+	for _, d := range parseDisks(value) {
+		m.diskInputs = append(m.diskInputs, newDiskInput(d))
+	}
+	if len(m.diskInputs) == 0 {
+		m.diskInputs = append(m.diskInputs, newDiskInput(""))
+	}
+	m.diskIdx, m.diskStatus = 0, ""
+	m.diskInputs[0].Focus()
+	return m.pushScene(sceneDisks), textinput.Blink
+}
+
+func newDiskInput(v string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "100G"
+	ti.CharLimit = 8
+	ti.SetValue(v)
+	return ti
+}
+
+func focusDisk(m model, i int) model {
+	m.diskInputs[m.diskIdx].Blur()
+	m.diskIdx = i
+	m.diskInputs[m.diskIdx].Focus()
+	return m
+}
+
+// Writes the list back to the form, and the disk count with it
+func finishDiskEditor(m model) model {
+	var disks []string
+	for _, ti := range m.diskInputs {
+		disks = append(disks, strings.ToUpper(strings.TrimSpace(ti.Value())))
+	}
+	if err := validateDisks(disks, ""); err != nil {
+		m.diskStatus = err.Error()
+		return m
+	}
+	value, count := strings.Join(disks, ","), strconv.Itoa(len(disks))
+	labels, inputs := m.createLabels, m.createInputs
+	if m.previousScene() == sceneEditForm {
+		labels, inputs = m.editFormLabels, m.editFormInputs
+	}
+	inputs[indexOf(diskSizeField, labels)].SetValue(value)
+	if i := indexOf(diskCountField, labels); i >= 0 {
+		inputs[i].SetValue(count)
+	}
+	return m.popScene()
+}
+
+func updateDiskEditor(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	n := len(m.diskInputs)
+	switch {
+	case key.Matches(keyMsg, keys.Disks.Cancel):
+		return m.popScene(), nil
+	case key.Matches(keyMsg, keys.Disks.Done):
+		return finishDiskEditor(m), nil
+	case key.Matches(keyMsg, keys.Disks.Up):
+		return focusDisk(m, (m.diskIdx-1+n)%n), nil
+	case key.Matches(keyMsg, keys.Disks.Down):
+		return focusDisk(m, (m.diskIdx+1)%n), nil
+	case key.Matches(keyMsg, keys.Disks.Add):
+		if n >= maxDisks {
+			m.diskStatus = fmt.Sprintf("At most %d disks", maxDisks)
+			return m, nil
+		}
+		// A new disk starts with the size of the one under the cursor
+		m.diskInputs = append(m.diskInputs[:m.diskIdx+1], append([]textinput.Model{newDiskInput(m.diskInputs[m.diskIdx].Value())}, m.diskInputs[m.diskIdx+1:]...)...)
+		m.diskStatus = ""
+		return focusDisk(m, m.diskIdx+1), textinput.Blink
+	case key.Matches(keyMsg, keys.Disks.Remove):
+		if n == 1 {
+			m.diskStatus = "A VM needs at least one disk"
+			return m, nil
+		}
+		m.diskInputs = append(m.diskInputs[:m.diskIdx], m.diskInputs[m.diskIdx+1:]...)
+		m.diskIdx = min(m.diskIdx, len(m.diskInputs)-1)
+		m.diskInputs[m.diskIdx].Focus()
+		m.diskStatus = ""
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.diskInputs[m.diskIdx], cmd = m.diskInputs[m.diskIdx].Update(msg)
+	m.diskStatus = ""
+	return m, cmd
+}
+
+func viewDiskEditor(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(fmt.Sprintf("Disks of the VM (%s)", plural(len(m.diskInputs), "disk"))) + "\n\n"
+	body += " " + titleStyle.Render(fmt.Sprintf("%-6s %s", "Disk", "Size")) + "\n"
+	for i, ti := range m.diskInputs {
+		mark := ""
+		if v := strings.ToUpper(strings.TrimSpace(ti.Value())); v != "" && !diskSizeRe.MatchString(v) {
+			mark = logErrorStyle.Render("  ✗ size like 100G or 2T")
+		}
+		label := fmt.Sprintf(" %-6d ", i+1)
+		if i == m.diskIdx {
+			label = focusedStyle.Render(label)
+		} else {
+			label = normalStyle.Render(label)
+		}
+		body += label + ti.View() + mark + "\n"
+	}
+	if m.diskStatus != "" {
+		body += "\n " + m.diskStatus + "\n"
+	}
+	tooltip = tooltipStyle.Render(fmt.Sprintf("Sizes in G or T. %s also sets %s to the number of disks.", keys.Disks.Done.Help().Key, diskCountField))
+	return body, tooltip
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// --- Create-form draft, autosaved so a crash or dropped SSH session doesn't lose it ---
+
+// The create form as last typed; secret and sensitive values are never written
+type createDraft struct {
+	Saved    string            `yaml:"saved"`
+	Template string            `yaml:"template"`
+	Preset   string            `yaml:"preset"`
+	CloneOf  string            `yaml:"clone_of,omitempty"`
+	Values   map[string]string `yaml:"values"`
+}
+
+func draftPath() string {
+	return filepath.Join(filepath.Dir(logPath()), "draft.yaml")
+}
+
+func readDraft() (createDraft, bool) {
+	var d createDraft
+	data, err := os.ReadFile(draftPath())
+	if err != nil || yaml.Unmarshal(data, &d) != nil || len(d.Values) == 0 {
+		return d, false
+	}
+	return d, true
+}
+
+func clearDraft() {
+	if err := os.Remove(draftPath()); err != nil && !os.IsNotExist(err) {
+		logger.Warn("could not remove draft", "component", "draft", "error", err.Error())
+	}
+}
+
+// Writes the create form to the draft file when its values changed since the last write;
+// a form back at its preset defaults has nothing worth keeping
+func autosaveDraft(next tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m := next.(model)
+	if m.currentScene != sceneCreateForm {
+		return m, cmd
+	}
+	values := formValues(m.createInputs)
+	if slices.Equal(values, m.draftValues) {
+		return m, cmd
+	}
+	m.draftValues = values
+	if unsavedFields(m) == 0 {
+		clearDraft()
+		return m, cmd
+	}
+	d := createDraft{
+		Saved:    time.Now().Format(time.RFC3339),
+		Template: m.activeTemplate.Name,
+		Preset:   m.presets[m.presetIdx].Name,
+		CloneOf:  m.cloneSource,
+		Values:   map[string]string{},
+	}
+	for i, label := range m.createLabels {
+		if !isMaskedField(m.fieldMeta[label]) && values[i] != "" {
+			d.Values[label] = values[i]
+		}
+	}
+	data, err := yaml.Marshal(d)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(draftPath()), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(draftPath(), data, 0600)
+	}
+	if err != nil {
+		logger.Warn("could not save draft", "component", "draft", "error", err.Error())
+	}
+	return m, cmd
+}
+
+// Startup offer when the last session left a draft behind
+func offerDraft(m model) model {
+	d, ok := readDraft()
+	if !ok {
+		return m
+	}
+	saved := describeTime(d.Saved)
+	what := d.Template
+	if d.CloneOf != "" {
+		what = "clone of " + d.CloneOf
+	}
+	m.draftPrompt = true
+	m.statusMessage = fmt.Sprintf("Unfinished create form from %s (%s, %s) — %s resume, %s discard, any other key keeps it for later",
+		saved, what, plural(len(d.Values), "field"), keys.Draft.Resume.Help().Key, keys.Draft.Discard.Help().Key)
+	return m
+}
+
+// Answer to the startup offer; ok is false when msg is for the launcher
+func answerDraft(m model, msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	m.draftPrompt = false
+	switch {
+	case key.Matches(msg, keys.Draft.Resume):
+		d, ok := readDraft()
+		if !ok {
+			m.statusMessage = "The draft is gone"
+			return m, nil, true
+		}
+		m = resumeDraft(m, d)
+		return m.pushScene(sceneCreateForm), capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template")), true
+	case key.Matches(msg, keys.Draft.Discard):
+		clearDraft()
+		m.statusMessage = "Draft discarded"
+		return m, nil, true
+	}
+	m.statusMessage = ""
+	return m, nil, false
+}
+
+// Fills the create form from the draft: its template and preset, then the typed values
+func resumeDraft(m model, d createDraft) model {
+	m = useTemplate(m, templateByName(m.templates, d.Template))
+	for i, p := range m.presets {
+		if p.Name == d.Preset {
+			m.presetIdx = i
+			m = applyPresetToForm(m, i)
+		}
+	}
+	for i, label := range m.createLabels {
+		if v, ok := d.Values[label]; ok {
+			m.createInputs[i].SetValue(v)
+		}
+	}
+	m.cloneSource = d.CloneOf
+	m.draftValues = formValues(m.createInputs)
+	m.createStatus = "Draft restored — secret fields have to be typed again"
+	return m
+}
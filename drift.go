@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/terraform"
+)
+
+// --- Drift detection ---
+
+type driftStatus int
+
+const (
+	driftChecking driftStatus = iota + 1
+	driftClean
+	driftDetected
+	driftError
+)
+
+const driftConcurrency = 4
+
+type driftResultMsg struct {
+	path   string
+	status driftStatus
+	err    error
+}
+
+// Runs a refresh-only plan; exit code 2 means the real infrastructure no longer matches state
+func checkDrift(dir string) (driftStatus, error) {
+	env, err := secretsEnv(dir)
+	if err != nil {
+		return driftError, err
+	}
+	cmd := terraformCommand("plan", "-detailed-exitcode", "-refresh-only", "-input=false", "-no-color", "-lock=false")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	started := time.Now()
+	out, err := cmd.CombinedOutput()
+	logTerraform(dir, cmd.Args[1:], started, err)
+	changes, err := terraform.PlanChanges(err)
+	switch {
+	case err != nil:
+		return driftError, fmt.Errorf("terraform plan failed: %v\n%s", err, string(out))
+	case changes:
+		return driftDetected, nil
+	}
+	return driftClean, nil
+}
+
+// Whether op runs a terraform command that changes the deployment's state (apply, including
+// -refresh-only and -destroy, refresh or destroy), making a drift result stale
+func changesState(op *tfOperation) bool {
+	for _, s := range op.Steps {
+		switch s.Args[0] {
+		case "apply", "refresh", "destroy":
+			return true
+		}
+	}
+	return false
+}
+
+// Starts drift checks for every deployment, at most driftConcurrency at a time
+func startDriftCheck(m model) (model, tea.Cmd) {
+	sem := make(chan struct{}, driftConcurrency)
+	var cmds []tea.Cmd
+	for _, d := range m.allDeployments {
+		m.drift[d.Path] = driftChecking
+		dir := d.Path
+		cmds = append(cmds, func() tea.Msg {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			status, err := checkDrift(dir)
+			return driftResultMsg{path: dir, status: status, err: err}
+		})
+	}
+	m.deployTable.SetRows(deploymentRows(m.deployments, m.drift, m.selected, m.favorites))
+	m.tablesVersion++
+	return m, tea.Batch(cmds...)
+}
+
+func handleDriftResult(m model, msg driftResultMsg) model {
+	m.drift[msg.path] = msg.status
+	applyDeploymentFilter(&m)
+	pending, drifted := 0, 0
+	for _, st := range m.drift {
+		switch st {
+		case driftChecking:
+			pending++
+		case driftDetected:
+			drifted++
+		}
+	}
+	if msg.err != nil {
+		m.statusMessage = "Drift check failed: " + msg.err.Error()
+	} else if pending == 0 {
+		m.statusMessage = fmt.Sprintf("Drift check complete: %d deployment(s) drifted", drifted)
+	} else {
+		m.statusMessage = fmt.Sprintf("Checking drift... %d remaining", pending)
+	}
+	return m
+}
+
+// State column value, with the drift badge taking precedence
+func stateBadge(info deploymentInfo, drift map[string]driftStatus) string {
+	switch drift[info.Path] {
+	case driftChecking:
+		return info.State + " …"
+	case driftDetected:
+		return "DRIFTED"
+	}
+	return failedBadge(info)
+}
+
+func deploymentRows(infos []deploymentInfo, drift map[string]driftStatus, selected, favorites map[string]bool) []table.Row {
+	rows := make([]table.Row, len(infos))
+	for i, info := range infos {
+		name := info.Name
+		if favorites[filepath.Base(info.Path)] {
+			name = favoriteMarker() + name
+		}
+		if selected[info.Path] {
+			name = selectedMarker + name
+		}
+		rows[i] = table.Row{name, info.Description, environmentLabel(info), stateBadge(info, drift), lastActionAge(info), healthBadge(info)}
+		if costModel != nil {
+			rows[i] = append(rows[i], deploymentCost(info))
+		}
+	}
+	return rows
+}
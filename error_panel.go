@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Error panel: long and multi-line status messages (terraform errors) behind a one-line summary ---
+//
+// The tooltip keeps one line; the full text opens in a scrollable popup from any screen, where
+// it can be copied and the log viewer opened for the context around it.
+
+const (
+	errorPanelLines = 20
+	errorPanelWidth = uiWidth - 28
+)
+
+type errorReport struct {
+	At     time.Time
+	Source string // launcher, create or edit, as in the message history
+	Lines  []string
+}
+
+// Whether text needs the panel: more than one line, or more than the tooltip holds
+func longStatus(text string) bool {
+	return strings.Contains(strings.TrimSpace(text), "\n") || len([]rune(text)) > uiWidth-8
+}
+
+// Keeps the full text of a long status message for the panel
+func captureErrorReport(m model, source, text string) model {
+	if !longStatus(text) {
+		return m
+	}
+	m.errorReport = &errorReport{At: time.Now(), Source: source, Lines: strings.Split(strings.TrimSpace(text), "\n")}
+	return m
+}
+
+// Lines of the report wrapped to the popup, nothing cut off
+func (r *errorReport) rows() []string {
+	var rows []string
+	for _, l := range r.Lines {
+		runes := []rune(l)
+		for len(runes) > errorPanelWidth {
+			rows = append(rows, string(runes[:errorPanelWidth]))
+			runes = runes[errorPanelWidth:]
+		}
+		rows = append(rows, string(runes))
+	}
+	return rows
+}
+
+// First line of the message, plus terraform's first "Error:" line when it comes later
+func statusSummary(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	summary := lines[0]
+	for _, l := range lines[1:] {
+		if isTerraformError(l) {
+			summary += " — " + strings.TrimSpace(strings.TrimLeft(l, "│ "))
+			break
+		}
+	}
+	return summary
+}
+
+// Tooltip for a status line: as-is when it fits, else its summary and the key showing all of it
+func statusTooltip(text string) string {
+	if !longStatus(text) {
+		return tooltipStyle.Render(text)
+	}
+	hint := fmt.Sprintf(" (%s details)", keys.Global.Errors.Help().Key)
+	return tooltipStyle.Render(truncate(statusSummary(text), uiWidth-8-len([]rune(hint))) + logDimStyle.Render(hint))
+}
+
+func openErrorPanel(m model) model {
+	if m.errorReport == nil {
+		m.statusMessage = "No error to show"
+		return m
+	}
+	m.showErrorPanel, m.errorPanelScroll, m.errorPanelStatus = true, 0, ""
+	m.showKeyHelp, m.showMessages = false, false
+	return m
+}
+
+func updateErrorPanel(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	maxScroll := max(len(m.errorReport.rows())-errorPanelLines, 0)
+	k := keys.ErrorPanel
+	switch {
+	case key.Matches(keyMsg, k.Close):
+		m.showErrorPanel = false
+	case key.Matches(keyMsg, k.Up):
+		m.errorPanelScroll = max(m.errorPanelScroll-1, 0)
+	case key.Matches(keyMsg, k.Down):
+		m.errorPanelScroll = min(m.errorPanelScroll+1, maxScroll)
+	case key.Matches(keyMsg, k.PageUp):
+		m.errorPanelScroll = max(m.errorPanelScroll-errorPanelLines, 0)
+	case key.Matches(keyMsg, k.PageDown):
+		m.errorPanelScroll = min(m.errorPanelScroll+errorPanelLines, maxScroll)
+	case key.Matches(keyMsg, k.Copy):
+		m.errorPanelStatus = fmt.Sprintf("Copied %s to the clipboard", plural(len(m.errorReport.Lines), "line"))
+		return m, copyToClipboard(strings.Join(m.errorReport.Lines, "\n"))
+	case key.Matches(keyMsg, k.Log):
+		m.showErrorPanel = false
+		return openLogViewer(m), nil
+	}
+	return m, nil
+}
+
+func viewErrorPanel(m model) string {
+	r := m.errorReport
+	rows := r.rows()
+	end := min(m.errorPanelScroll+errorPanelLines, len(rows))
+	var lines []string
+	for _, l := range rows[m.errorPanelScroll:end] {
+		if isTerraformError(l) {
+			l = logErrorStyle.Render(l)
+		}
+		lines = append(lines, l)
+	}
+	title := fmt.Sprintf("Full %s message at %s — lines %d–%d of %d", r.Source, localTime(r.At).Format("15:04:05"), m.errorPanelScroll+1, end, len(rows))
+	foot := logDimStyle.Render(fmt.Sprintf("Log: %s (%s opens it)", logPath(), keys.ErrorPanel.Log.Help().Key))
+	if m.errorPanelStatus != "" {
+		foot = m.errorPanelStatus
+	}
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(color(theme.Error)).
+		Padding(0, 1).
+		Width(uiWidth - 24).
+		Render(title + "\n\n" + strings.Join(lines, "\n") + "\n\n" + foot)
+}
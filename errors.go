@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// --- Error classes and CLI exit codes ---
+//
+// Wrapper scripts branch on the exit code of the headless commands:
+//
+//	1 anything else, 2 config, 3 validation, 4 vault, 5 proxmox, 6 terraform
+const (
+	exitFailure    = 1
+	exitConfig     = 2
+	exitValidation = 3
+	exitVault      = 4
+	exitProxmox    = 5
+	exitTerraform  = 6
+)
+
+// The error types keep the wrapped message as-is, so status lines read the same
+
+type ConfigError struct{ Err error }
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+type ValidationError struct{ Err error }
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+type VaultError struct{ Err error }
+
+func (e *VaultError) Error() string { return e.Err.Error() }
+func (e *VaultError) Unwrap() error { return e.Err }
+
+type ProxmoxError struct{ Err error }
+
+func (e *ProxmoxError) Error() string { return e.Err.Error() }
+func (e *ProxmoxError) Unwrap() error { return e.Err }
+
+type TerraformError struct{ Err error }
+
+func (e *TerraformError) Error() string { return e.Err.Error() }
+func (e *TerraformError) Unwrap() error { return e.Err }
+
+func validationErrorf(format string, args ...any) error {
+	return &ValidationError{fmt.Errorf(format, args...)}
+}
+
+// exitCode maps an error to the process exit code of its class
+func exitCode(err error) int {
+	var (
+		configErr     *ConfigError
+		validationErr *ValidationError
+		vaultErr      *VaultError
+		proxmoxErr    *ProxmoxError
+		terraformErr  *TerraformError
+	)
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &configErr):
+		return exitConfig
+	case errors.As(err, &validationErr):
+		return exitValidation
+	case errors.As(err, &vaultErr):
+		return exitVault
+	case errors.As(err, &proxmoxErr):
+		return exitProxmox
+	case errors.As(err, &terraformErr):
+		return exitTerraform
+	}
+	return exitFailure
+}
+
+// Short class name for error output
+func errorClass(err error) string {
+	return [...]string{"error", "error", "config", "validation", "vault", "proxmox", "terraform"}[exitCode(err)]
+}
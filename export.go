@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Inventory export (launcher list --format, [⇧E] on the launcher) ---
+
+var exportFormats = map[string]string{"json": "json", "csv": "csv", "markdown": "md"}
+
+type inventoryEntry struct {
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Zone         string            `json:"zone"`
+	Environment  string            `json:"environment"`
+	Criticality  string            `json:"criticality"`
+	State        string            `json:"state"`
+	LastAction   string            `json:"last_action"`
+	LastActionAt string            `json:"last_action_at"`
+	Template     string            `json:"template"`
+	Tfvars       map[string]string `json:"tfvars"`
+	// Where the template's artifacts (kubeconfig, ...) were stored, as recorded in launcher.state
+	Artifacts map[string]string `json:"artifacts"`
+}
+
+// Collects each deployment's state, tfvars (secret and sensitive fields left out) and artifact locations
+func buildInventory(deployments []deploymentInfo, templates []Template) []inventoryEntry {
+	entries := make([]inventoryEntry, 0, len(deployments))
+	for _, d := range deployments {
+		st, _ := getDeploymentState(d.Path)
+		e := inventoryEntry{
+			Name: d.Name, Description: d.Description, Zone: d.Zone, Environment: d.Environment, Criticality: d.Criticality, State: d.State,
+			LastAction: st.LastAction, LastActionAt: st.Timestamp, Template: d.Template,
+			Tfvars: map[string]string{}, Artifacts: d.Artifacts,
+		}
+		vals, _ := loadTfvars(filepath.Join(d.Path, "terraform.tfvars"))
+		meta := templateByName(templates, d.Template).fieldMeta
+		for k, v := range vals {
+			if !isMaskedField(meta[k]) {
+				e.Tfvars[k] = strings.Trim(v, "\"")
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// "k=v; k=v" in key order, for the flat formats
+func joinPairs(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + m[k]
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e inventoryEntry) columns() []string {
+	return []string{e.Name, e.Description, e.Zone, e.Environment, e.Criticality, e.State, e.LastAction, e.LastActionAt, e.Template, joinPairs(e.Tfvars), joinPairs(e.Artifacts)}
+}
+
+var inventoryHeader = []string{"Name", "Description", "Zone", "Environment", "Criticality", "State", "Last action", "At", "Template", "tfvars", "Artifacts"}
+
+func writeInventory(w io.Writer, format string, entries []inventoryEntry) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(inventoryHeader)
+		for _, e := range entries {
+			cw.Write(e.columns())
+		}
+		cw.Flush()
+		return cw.Error()
+	case "markdown":
+		cell := strings.NewReplacer("|", `\|`, "\n", " ")
+		fmt.Fprintf(w, "| %s |\n", strings.Join(inventoryHeader, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(inventoryHeader)))
+		for _, e := range entries {
+			cols := e.columns()
+			for i := range cols {
+				cols[i] = cell.Replace(cols[i])
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cols, " | "))
+		}
+		return nil
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tZONE\tSTATE\tLAST ACTION\tAT\tTEMPLATE")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Name, e.Zone, e.State, e.LastAction, e.LastActionAt, e.Template)
+		}
+		return tw.Flush()
+	}
+	return validationErrorf("unknown format %q (table, json, csv or markdown)", format)
+}
+
+// Writes the inventory to ./inventory-<time>.<ext> and returns the file name
+func exportInventory(deployments []deploymentInfo, templates []Template, format string) (string, error) {
+	name := fmt.Sprintf("inventory-%s.%s", time.Now().Format("20060102-150405"), exportFormats[format])
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := writeInventory(f, format, buildInventory(deployments, templates)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Handles the format key after [⇧E]; the launcher table's current rows are exported
+func exportFromLauncher(m model, msg tea.KeyMsg) model {
+	m.exportPrompt = false
+	format := ""
+	switch {
+	case key.Matches(msg, keys.Export.JSON):
+		format = "json"
+	case key.Matches(msg, keys.Export.CSV):
+		format = "csv"
+	case key.Matches(msg, keys.Export.Markdown):
+		format = "markdown"
+	default:
+		m.statusMessage = "Export cancelled"
+		return m
+	}
+	name, err := exportInventory(m.deployments, m.templates, format)
+	if err != nil {
+		m.statusMessage = "Export failed: " + err.Error()
+		return m
+	}
+	m.statusMessage = fmt.Sprintf("Exported %d deployment(s) to %s", len(m.deployments), name)
+	return m
+}
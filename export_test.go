@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteInventoryArtifacts(t *testing.T) {
+	entries := []inventoryEntry{{Name: "web_a", Tfvars: map[string]string{"vm_count": "2"}, Artifacts: map[string]string{"kubeconfig": "/srv/artifacts/web_a/kubeconfig"}}}
+	var b bytes.Buffer
+	if err := writeInventory(&b, "json", entries); err != nil {
+		t.Fatal(err)
+	}
+	if out := b.String(); !strings.Contains(out, `"artifacts": {`) || strings.Contains(out, `"outputs"`) {
+		t.Errorf("json inventory:\n%s", out)
+	}
+	b.Reset()
+	if err := writeInventory(&b, "csv", entries); err != nil {
+		t.Fatal(err)
+	}
+	if out := b.String(); !strings.Contains(out, ",Artifacts\n") || !strings.Contains(out, "kubeconfig=/srv/artifacts/web_a/kubeconfig") {
+		t.Errorf("csv inventory:\n%s", out)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// --- Pinned deployments: kept at the top of the launcher, saved in session.yaml ---
+
+func favoriteMarker() string {
+	if icons == asciiIcons {
+		return "* "
+	}
+	return "★ "
+}
+
+// Favorites are stored by deployment directory name so they survive an apps_path move
+func loadFavorites() map[string]bool {
+	favorites := map[string]bool{}
+	for _, name := range readSession().Favorites {
+		favorites[name] = true
+	}
+	return favorites
+}
+
+func saveFavorites(favorites map[string]bool) error {
+	return updateSession(func(s *sessionFile) {
+		s.Favorites = s.Favorites[:0]
+		for name := range favorites {
+			s.Favorites = append(s.Favorites, name)
+		}
+		sort.Strings(s.Favorites)
+	})
+}
+
+func (m model) isFavorite(d deploymentInfo) bool {
+	return m.favorites[filepath.Base(d.Path)]
+}
+
+// Moves pinned deployments ahead of the others, keeping the listing order within each group
+func pinFavorites(m model, infos []deploymentInfo) []deploymentInfo {
+	sort.SliceStable(infos, func(i, j int) bool {
+		return m.isFavorite(infos[i]) && !m.isFavorite(infos[j])
+	})
+	return infos
+}
+
+// Pins or unpins the deployment under the cursor
+func toggleFavorite(m model) model {
+	i := m.deployTable.Cursor()
+	if i < 0 || i >= len(m.deployments) {
+		return m
+	}
+	d := m.deployments[i]
+	name := filepath.Base(d.Path)
+	if m.favorites[name] {
+		delete(m.favorites, name)
+		m.statusMessage = "Unpinned " + d.Name
+	} else {
+		m.favorites[name] = true
+		m.statusMessage = "Pinned " + d.Name + " to the top"
+	}
+	if err := saveFavorites(m.favorites); err != nil {
+		m.statusMessage = "Could not save favorites: " + err.Error()
+	}
+	applyDeploymentFilter(&m)
+	return m
+}
+
+// Moves the cursor to the next pinned deployment after it, wrapping around
+func jumpToFavorite(m model) model {
+	n := len(m.deployments)
+	cur := m.deployTable.Cursor()
+	for step := 1; step <= n; step++ {
+		i := (cur + step) % n
+		if m.isFavorite(m.deployments[i]) {
+			m.deployTable.SetCursor(i)
+			m.tablesVersion++
+			loadDeploymentDetail(&m, i)
+			return m
+		}
+	}
+	m.statusMessage = "No pinned deployments — pin one with " + keys.Launcher.Pin.Help().Key
+	return m
+}
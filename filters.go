@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Launcher quick filters ---
+
+type stateFilter int
+
+const (
+	filterAllStates stateFilter = iota
+	filterDeployed
+	filterFailedOrDrifted
+)
+
+func (m model) deploymentMatches(d deploymentInfo) bool {
+	switch m.stateFilter {
+	case filterDeployed:
+		if d.State != "DEPLOYED" || m.drift[d.Path] == driftDetected {
+			return false
+		}
+	case filterFailedOrDrifted:
+		if d.State != "FAILED" && m.drift[d.Path] != driftDetected {
+			return false
+		}
+	}
+	return m.zoneFilter == "" || d.Zone == m.zoneFilter
+}
+
+// applyDeploymentFilter rebuilds the visible deployments from the full list, keeping the
+// selected deployment selected when it is still visible
+func applyDeploymentFilter(m *model) {
+	selected := ""
+	if i := m.deployTable.Cursor(); i >= 0 && i < len(m.deployments) {
+		selected = m.deployments[i].Path
+	}
+	var visible []deploymentInfo
+	cursor := 0
+	for _, d := range m.allDeployments {
+		if m.deploymentMatches(d) {
+			visible = append(visible, d)
+		}
+	}
+	visible = pinFavorites(*m, visible)
+	for i, d := range visible {
+		if d.Path == selected {
+			cursor = i
+		}
+	}
+	m.deployments = visible
+	m.deployTable.SetRows(deploymentRows(visible, m.drift, m.selected, m.favorites))
+	m.deployTable.SetCursor(cursor)
+	m.tablesVersion++
+	loadDeploymentDetail(m, cursor)
+}
+
+// Toggles a state filter: pressing the active filter's key again clears it
+func toggleStateFilter(m model, f stateFilter) model {
+	if m.stateFilter == f {
+		m.stateFilter = filterAllStates
+	} else {
+		m.stateFilter = f
+	}
+	applyDeploymentFilter(&m)
+	return m
+}
+
+// Cycles the zone filter through the known zones and back to all zones
+func cycleZoneFilter(m model) model {
+	zones := zoneNames(m.zones)
+	if m.zoneFilter == "" {
+		m.zoneFilter = zones[0]
+	} else if i := indexOf(m.zoneFilter, zones); i < 0 || i == len(zones)-1 {
+		m.zoneFilter = ""
+	} else {
+		m.zoneFilter = zones[i+1]
+	}
+	applyDeploymentFilter(&m)
+	return m
+}
+
+// Header label for the active filters, "" when everything is shown
+func filterLabel(m model) string {
+	var parts []string
+	switch m.stateFilter {
+	case filterDeployed:
+		parts = append(parts, "DEPLOYED")
+	case filterFailedOrDrifted:
+		parts = append(parts, "FAILED/DRIFTED")
+	}
+	if m.zoneFilter != "" {
+		parts = append(parts, "zone "+m.zoneFilter)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return filterLabelStyle.Render(fmt.Sprintf("  [Filter: %s — %d/%d]", strings.Join(parts, ", "), len(m.deployments), len(m.allDeployments)))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Create form sections (fields.yaml `section:`) and scrolling ---
+
+// Rows of the create form shown at once; longer forms scroll to keep the focused field in view
+const createFormRows = 20
+
+// Groups fields by section, sections in the order they first appear; fields without a
+// section form a group of their own at the position of the first one
+func groupBySection(fields []string, meta map[string]FieldMeta) []string {
+	var order []string
+	groups := map[string][]string{}
+	for _, k := range fields {
+		s := meta[k].Section
+		if _, ok := groups[s]; !ok {
+			order = append(order, s)
+		}
+		groups[s] = append(groups[s], k)
+	}
+	out := make([]string, 0, len(fields))
+	for _, s := range order {
+		out = append(out, groups[s]...)
+	}
+	return out
+}
+
+// Number of applicable fields in a section and how many of them have a value
+func sectionCounts(m model, section string) (n, set int) {
+	for i, key := range m.createLabels {
+		meta := m.fieldMeta[key]
+		if meta.Section == section && !meta.Advanced && fieldApplies(m, key) {
+			n++
+			if m.createInputs[i].Value() != "" {
+				set++
+			}
+		}
+	}
+	return n, set
+}
+
+func sectionHeader(m model, section string) string {
+	n, set := sectionCounts(m, section)
+	if m.collapsedSections[section] {
+		return titleStyle.Render("▸ "+section) + logDimStyle.Render(fmt.Sprintf("  (%d fields, %d set) — %s to expand", n, set, keys.Create.ExpandSections.Help().Key))
+	}
+	return titleStyle.Render("▾ "+section) + logDimStyle.Render(fmt.Sprintf("  (%d fields) — %s to collapse", n, keys.Create.CollapseSection.Help().Key))
+}
+
+// Collapses the focused field's section and moves focus past it
+func collapseSection(m model) model {
+	section := m.fieldMeta[m.createLabels[m.createFocus]].Section
+	if section == "" || m.fieldMeta[m.createLabels[m.createFocus]].Advanced {
+		m.createStatus = "This field isn't in a section"
+		return m
+	}
+	if m.collapsedSections == nil {
+		m.collapsedSections = map[string]bool{}
+	}
+	m.collapsedSections[section] = true
+	m.createInputs[m.createFocus].Blur()
+	m.createFocus = nextVisibleField(m, +1)
+	m.createInputs[m.createFocus].Focus()
+	return m
+}
+
+func expandSections(m model) model {
+	m.collapsedSections = nil
+	return m
+}
+
+// Window of height lines around focus, with "more above/below" lines when it doesn't all fit
+func scrollWindow(lines []string, focus, height int) []string {
+	if len(lines) <= height {
+		return lines
+	}
+	h := height - 2
+	start := min(max(focus-h/2, 0), len(lines)-h)
+	more := func(n int, arrow string) string {
+		if n == 0 {
+			return ""
+		}
+		return logDimStyle.Render(fmt.Sprintf("  %s %d more", arrow, n))
+	}
+	out := []string{more(start, "↑")}
+	out = append(out, lines[start:start+h]...)
+	return append(out, more(len(lines)-start-h, "↓"))
+}
+
+// Field rows of the create form with section and advanced headers, scrolled to the focus
+func createFormLines(m model) string {
+	var lines []string
+	focusLine := 0
+	advancedShown, section := false, ""
+	for i, ti := range m.createInputs {
+		label := m.createLabels[i]
+		meta := m.fieldMeta[label]
+		if meta.Advanced && !advancedShown && fieldApplies(m, label) {
+			lines = append(lines, advancedHeader(m))
+			advancedShown = true
+		}
+		if !meta.Advanced && meta.Section != section && fieldApplies(m, label) {
+			section = meta.Section
+			if section != "" {
+				lines = append(lines, sectionHeader(m, section))
+			}
+		}
+		if !fieldVisible(m, label) {
+			continue
+		}
+		if i == m.createFocus {
+			focusLine = len(lines)
+		}
+		lines = append(lines, formFieldLine(m, fmt.Sprintf("create:%d", i), meta.Label, inputDisplay(ti), i == m.createFocus, createFieldModified(m, i))+
+			fieldGauge(m, label, ti.Value(), createValue(m, "zone"), createValue(m, "cluster")))
+		if label == diskSizeField {
+			lines = append(lines, diskTableLines(ti.Value())...)
+		}
+	}
+	return strings.Join(scrollWindow(lines, focusLine, createFormRows), "\n")
+}
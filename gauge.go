@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+// --- Capacity gauges: vm_memory and vm_cpu_cores drawn against their limit in the forms ---
+//
+// On terminals wide enough for the side-by-side launcher, the memory and CPU fields get a bar
+// showing the value as a share of the tightest limit known: the zone's max_memory /
+// max_cpu_cores, the largest online node of the cluster (once its capacity is fetched) and
+// the field's max in fields.yaml. A value past the limit fills the bar in the error color, so
+// 640 GB typed for 64 stands out before the capacity check or terraform says so.
+
+const gaugeWidth = 20
+
+// Fields with a gauge
+var gaugeFields = map[string]bool{"vm_memory": true, "vm_cpu_cores": true}
+
+// Smallest limit for key (MB for vm_memory, cores for vm_cpu_cores) and where it comes from;
+// 0 when nothing bounds it
+func gaugeLimit(m model, key, zone, cluster string) (limit int, source string) {
+	consider := func(n int, from string) {
+		if n > 0 && (limit == 0 || n < limit) {
+			limit, source = n, from
+		}
+	}
+	for _, z := range m.zones {
+		if z.Name != zone {
+			continue
+		}
+		if key == "vm_memory" {
+			consider(z.MaxMemory, "zone "+z.Name)
+		} else {
+			consider(z.MaxCPUCores, "zone "+z.Name)
+		}
+	}
+	if c := m.capacity; c != nil && c.err == nil && c.cluster == cluster {
+		largest, node := 0, ""
+		for _, n := range c.nodes {
+			v := n.MaxCPU
+			if key == "vm_memory" {
+				v = int(n.MaxMem >> 20)
+			}
+			if n.Online && v > largest {
+				largest, node = v, n.Node
+			}
+		}
+		consider(largest, "node "+node+" of "+cluster)
+	}
+	if meta := m.fieldMeta[key]; meta.Max != nil {
+		consider(*meta.Max, "fields.yaml max")
+	}
+	return limit, source
+}
+
+// "  ████░░░░ 6% of 64G (node pve01 of lab)" after a form field, "" when it has no gauge
+func fieldGauge(m model, key, value, zone, cluster string) string {
+	if !gaugeFields[key] || m.layout.stacked {
+		return ""
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	limit, source := gaugeLimit(m, key, zone, cluster)
+	if limit == 0 {
+		return ""
+	}
+	format := func(v int) string {
+		if key == "vm_memory" {
+			return formatBytes(int64(v) << 20)
+		}
+		return plural(v, "core")
+	}
+	frac := float64(n) / float64(limit)
+	if n > limit {
+		bar := progress.New(progress.WithSolidFill(theme.Error), progress.WithWidth(gaugeWidth), progress.WithoutPercentage())
+		return "  " + bar.ViewAs(1) + " " + errorStyle.Render(fmt.Sprintf("⚠ %.1f× the %s limit (%s)", frac, format(limit), source))
+	}
+	bar := progress.New(progressFill(), progress.WithWidth(gaugeWidth), progress.WithoutPercentage())
+	return "  " + bar.ViewAs(frac) + " " + logDimStyle.Render(fmt.Sprintf("%.0f%% of %s (%s)", frac*100, format(limit), source))
+}
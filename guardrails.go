@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Guard rails per environment: apply confirmation, reviewed plans, snapshots, destroy ---
+
+const (
+	snapshotPrefix      = "launcher-"
+	snapshotTaskTimeout = 5 * time.Minute
+)
+
+var prodGuardRails = GuardRails{ConfirmApply: true, RequirePlan: true, SnapshotBeforeApply: true, TypeNameToDestroy: true}
+
+func guardRailsFor(cfg Config, environment string) GuardRails {
+	if g, ok := cfg.GuardRails[environment]; ok {
+		return g
+	}
+	if environment == prodEnvironment {
+		return prodGuardRails
+	}
+	return GuardRails{}
+}
+
+// Apply held back until the user confirms it
+type pendingApply struct {
+	op          *tfOperation
+	name        string
+	environment string
+}
+
+// Destroy waiting for confirmation; typed asks for the deployment name
+type destroyDialog struct {
+	dep   deploymentInfo
+	typed bool
+	input textinput.Model
+	// Archive the deployment once it is destroyed
+	archive bool
+}
+
+// True when op runs `terraform apply -auto-approve`, i.e. applies without a reviewed plan
+func (op *tfOperation) autoApproves() bool {
+	for _, s := range op.Steps {
+		if len(s.Args) > 0 && s.Args[0] == "apply" && slices.Contains(s.Args, "-auto-approve") {
+			return true
+		}
+	}
+	return false
+}
+
+// Queues an apply through dep's guard rails: refused without a reviewed plan, a snapshot
+// taken first, or held for confirmation. held reports that the op wasn't queued; the status
+// says why, so the caller leaves it alone.
+func guardApply(m model, dep deploymentInfo, op *tfOperation) (_ model, cmd tea.Cmd, held bool) {
+	if err := checkCatalogLock(m.cfg); err != nil {
+		m.statusMessage = "Not applying " + dep.Name + ": " + err.Error()
+		m.editStatus = m.statusMessage
+		return m, nil, true
+	}
+	rails := guardRailsFor(m.cfg, dep.Environment)
+	if rails.RequirePlan && op.autoApproves() {
+		m.statusMessage = fmt.Sprintf("%s is %s: only a reviewed plan is applied — %s plans, %s applies it",
+			dep.Name, dep.Environment, keys.Launcher.Plan.Help().Key, keys.Launcher.ApplyPlan.Help().Key)
+		m.editStatus = m.statusMessage
+		return m, nil, true
+	}
+	if rails.SnapshotBeforeApply {
+		t, dir := templateByName(m.templates, dep.Template), dep.Path
+		op.BeforeApply = func() error { return snapshotDeployment(t, dir) }
+	}
+	if rails.ConfirmApply {
+		m.applyConfirm = &pendingApply{op: op, name: dep.Name, environment: dep.Environment}
+		return m, nil, true
+	}
+	m, cmd = enqueueJob(m, op)
+	return m, cmd, false
+}
+
+// Keys while the apply confirmation is open: confirm queues the apply, anything else drops it
+func updateApplyConfirm(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := *m.applyConfirm
+	m.applyConfirm = nil
+	if !key.Matches(msg, keys.Confirm.Yes) {
+		m.statusMessage = "Apply of " + p.name + " cancelled"
+		m.editStatus = m.statusMessage
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m, cmd = enqueueJob(m, p.op)
+	m.statusMessage = fmt.Sprintf("Queued job #%d: %s", m.nextJobID, p.op.Label)
+	m.editStatus = m.statusMessage
+	return m, cmd
+}
+
+func viewApplyConfirm(m model) string {
+	p := m.applyConfirm
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Apply to "+p.environment) + "\n\n")
+	fmt.Fprintf(&b, "%s %s\n%s\n\n", environmentBadge(p.environment), p.name, p.op.Label)
+	if p.op.BeforeApply != nil {
+		b.WriteString("Every VM is snapshotted before terraform apply runs.\n")
+	}
+	b.WriteString(warnStyle.Render("terraform apply changes live "+p.environment+" resources.") + "\n\n")
+	fmt.Fprintf(&b, "Apply? %s yes, any other key cancels", keys.Confirm.Yes.Help().Key)
+	return dialogBox(b.String())
+}
+
+// Takes a Proxmox snapshot "launcher-<UTC time>" of every VM in the template's vm_ids
+// output; nothing to do before the first apply
+func snapshotDeployment(t Template, dir string) error {
+	if _, ok := t.provider().(proxmoxProvider); !ok {
+		return fmt.Errorf("the %s provider can't take snapshots; set snapshot_before_apply: false for this environment", t.Provider)
+	}
+	outputs, err := readTerraformOutputs(dir)
+	if err != nil {
+		return err
+	}
+	ids, err := outputVMIDs(t, outputs)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	tfvars, _ := loadTfvars(filepath.Join(dir, "terraform.tfvars"))
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(strings.Trim(tfvars["cluster"], "\""))
+	if err != nil {
+		return err
+	}
+	var vms []struct {
+		VmID int    `json:"vmid"`
+		Node string `json:"node"`
+	}
+	if err := proxmoxGet(apiURL, tokenID, tokenSecret, "cluster/resources?type=vm", &vms); err != nil {
+		return err
+	}
+	nodes := map[int]string{}
+	for _, vm := range vms {
+		nodes[vm.VmID] = vm.Node
+	}
+	name := snapshotPrefix + time.Now().UTC().Format("20060102-150405")
+	form := url.Values{"snapname": {name}, "description": {"Before terraform apply of " + filepath.Base(dir)}}
+	for _, id := range ids {
+		node, ok := nodes[id]
+		if !ok {
+			return fmt.Errorf("VM %d not found on the cluster", id)
+		}
+		var upid string
+		if err := proxmoxPost(apiURL, tokenID, tokenSecret, fmt.Sprintf("nodes/%s/qemu/%d/snapshot", node, id), form, &upid); err != nil {
+			return fmt.Errorf("snapshot of VM %d: %w", id, err)
+		}
+		if err := waitProxmoxTask(apiURL, tokenID, tokenSecret, node, upid); err != nil {
+			return fmt.Errorf("snapshot of VM %d: %w", id, err)
+		}
+	}
+	logger.Info("snapshots taken before apply", "component", "guard_rails", "deployment", filepath.Base(dir), "snapshot", name)
+	return nil
+}
+
+// Polls a Proxmox task until it stops; an exit status other than OK is an error
+func waitProxmoxTask(apiURL, tokenID, tokenSecret, node, upid string) error {
+	deadline := time.Now().Add(snapshotTaskTimeout)
+	for time.Now().Before(deadline) {
+		var task struct {
+			Status     string `json:"status"`
+			ExitStatus string `json:"exitstatus"`
+		}
+		if err := proxmoxGet(apiURL, tokenID, tokenSecret, fmt.Sprintf("nodes/%s/tasks/%s/status", node, url.PathEscape(upid)), &task); err != nil {
+			return err
+		}
+		if task.Status == "stopped" {
+			if task.ExitStatus != "OK" {
+				return fmt.Errorf("task %s", task.ExitStatus)
+			}
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("task still running after %s", snapshotTaskTimeout)
+}
+
+func destroyOperation(dir string) *tfOperation {
+	return &tfOperation{
+		Label:          "Destroying " + filepath.Base(dir),
+		Dir:            dir,
+		Steps:          []tfStep{tfInitStep, tfDestroyStep},
+		SuccessMessage: filepath.Base(dir) + " destroyed",
+	}
+}
+
+// Opens the destroy confirmation for the selected deployment
+func confirmDestroy(m model) (model, tea.Cmd) {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m, nil
+	}
+	dep := m.deployments[idx]
+	if err := deploymentBusy(m, dep.Path); err != nil {
+		m.statusMessage = err.Error()
+		return m, nil
+	}
+	d := &destroyDialog{dep: dep, typed: guardRailsFor(m.cfg, dep.Environment).TypeNameToDestroy}
+	if d.typed {
+		d.input = textinput.New()
+		d.input.Placeholder = dep.Name
+		d.input.Width = 40
+		d.input.Focus()
+	}
+	m.destroyConfirm = d
+	return m, textinput.Blink
+}
+
+// Keys while the destroy confirmation is open. Typed: Enter destroys once the name matches,
+// Esc cancels; otherwise the confirm key destroys and anything else cancels.
+func updateDestroyDialog(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.destroyConfirm
+	k := keys.Destroy
+	switch {
+	case d.typed && key.Matches(msg, k.Cancel), !d.typed && !key.Matches(msg, keys.Confirm.Yes):
+		m.destroyConfirm = nil
+		m.statusMessage = "Destroy of " + d.dep.Name + " cancelled"
+		return m, nil
+	case d.typed && key.Matches(msg, k.Confirm) && d.input.Value() != d.dep.Name:
+		m.statusMessage = "The name doesn't match " + d.dep.Name
+		return m, nil
+	case d.typed && !key.Matches(msg, k.Confirm):
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(msg)
+		return m, cmd
+	}
+	m.destroyConfirm = nil
+	op := destroyOperation(d.dep.Path)
+	cfg, name, dir := m.cfg, d.dep.Name, d.dep.Path
+	op.OnSuccess = func() error { return releaseVMIDRange(cfg, name) }
+	if d.archive {
+		op.SuccessMessage = name + " destroyed and archived to " + archivesPath(cfg)
+		op.OnSuccess = func() error {
+			if err := releaseVMIDRange(cfg, name); err != nil {
+				return err
+			}
+			_, err := archiveDeployment(cfg, dir, true)
+			return err
+		}
+	}
+	var cmd tea.Cmd
+	m, cmd = enqueueJob(m, op)
+	m.statusMessage = fmt.Sprintf("Queued job #%d: terraform destroy of %s", m.nextJobID, d.dep.Name)
+	if d.archive {
+		m.statusMessage += ", then archive"
+	}
+	return m, cmd
+}
+
+func viewDestroyDialog(m model) string {
+	d := m.destroyConfirm
+	var b strings.Builder
+	title := "Destroy " + d.dep.Name
+	if d.archive {
+		title += " and archive it"
+	}
+	b.WriteString(titleStyle.Render(title) + "\n\n")
+	if badge := environmentBadge(d.dep.Environment); badge != "" {
+		b.WriteString(badge + " ")
+	}
+	fmt.Fprintf(&b, "%s, %s\n\n", d.dep.State, d.dep.Description)
+	b.WriteString(warnStyle.Render("terraform destroy deletes every resource of this deployment.") + "\n\n")
+	if d.typed {
+		fmt.Fprintf(&b, "Type the deployment name to confirm:\n%s\n\n%s destroys, %s cancels",
+			d.input.View(), keys.Destroy.Confirm.Help().Key, keys.Destroy.Cancel.Help().Key)
+	} else {
+		fmt.Fprintf(&b, "Destroy? %s yes, any other key cancels", keys.Confirm.Yes.Help().Key)
+	}
+	return dialogBox(b.String())
+}
+
+func dialogBox(text string) string {
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(popupBorder()).
+		Padding(0, 1).
+		Render(text)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Opens the typed destroy confirmation for web_a with name already typed
+func typedDestroyModel(t *testing.T, name string) model {
+	t.Helper()
+	m, _ := newTestModel(t, "web_a:DEPLOYED")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	t.Cleanup(func() { releaseDeployLock(dir) })
+	d := &destroyDialog{dep: deploymentInfo{Name: "web_a", Path: dir, Environment: prodEnvironment}, typed: true, input: textinput.New()}
+	d.input.SetValue(name)
+	m.destroyConfirm = d
+	return m
+}
+
+func TestDestroyDialogTypedName(t *testing.T) {
+	tests := []struct {
+		name, typed string
+		key         tea.KeyMsg
+		open        bool
+		jobs        int
+		status      string
+	}{
+		{"mismatch", "web_b", tea.KeyMsg{Type: tea.KeyEnter}, true, 0, "The name doesn't match web_a"},
+		{"match", "web_a", tea.KeyMsg{Type: tea.KeyEnter}, false, 1, "terraform destroy of web_a"},
+		{"cancel", "web_a", tea.KeyMsg{Type: tea.KeyEsc}, false, 0, "Destroy of web_a cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, _ := updateDestroyDialog(typedDestroyModel(t, tt.typed), tt.key)
+			m := next.(model)
+			if open := m.destroyConfirm != nil; open != tt.open {
+				t.Errorf("dialog open %v, want %v", open, tt.open)
+			}
+			if len(m.jobs) != tt.jobs {
+				t.Errorf("%d jobs queued, want %d", len(m.jobs), tt.jobs)
+			}
+			if !strings.Contains(m.statusMessage, tt.status) {
+				t.Errorf("status %q, want it to contain %q", m.statusMessage, tt.status)
+			}
+		})
+	}
+}
+
+func TestGuardApplyRequiresPlan(t *testing.T) {
+	m, _ := newTestModel(t, "web_a:DEPLOYED")
+	dep := deploymentInfo{Name: "web_a", Path: filepath.Join(m.cfg.AppsPath, "web_a"), Environment: prodEnvironment}
+
+	m, cmd, held := guardApply(m, dep, deployOperation(dep.Path, ""))
+	if !held || cmd != nil {
+		t.Fatalf("held %v, cmd %v; want the auto-approved apply refused", held, cmd != nil)
+	}
+	if len(m.jobs) != 0 || m.applyConfirm != nil {
+		t.Errorf("%d jobs queued, confirmation %v; want neither", len(m.jobs), m.applyConfirm != nil)
+	}
+	if !strings.Contains(m.statusMessage, "only a reviewed plan is applied") {
+		t.Errorf("status %q", m.statusMessage)
+	}
+
+	// Not a prod deployment: the same op is queued
+	dep.Environment = "dev"
+	m, _, held = guardApply(m, dep, deployOperation(dep.Path, ""))
+	t.Cleanup(func() { releaseDeployLock(dep.Path) })
+	if held || len(m.jobs) != 1 {
+		t.Errorf("held %v with %d jobs; want the dev apply queued", held, len(m.jobs))
+	}
+}
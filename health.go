@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"launcher/internal/vaultclient"
+)
+
+// --- Vault and AWS health for the status bar (checked on every background refresh) ---
+
+const (
+	healthTimeout = 5 * time.Second
+	// A good AWS result is reused this long (or until the credentials expire); failures are
+	// checked again on every refresh so a re-login shows up right away
+	awsHealthTTL = 5 * time.Minute
+)
+
+// Outcome of the AWS check; expired is set when logging in again is the fix
+type awsResult struct {
+	ok, expired bool
+	detail      string
+}
+
+var awsHealthCache struct {
+	sync.Mutex
+	result awsResult
+	until  time.Time
+}
+
+// Vault: sys/health when the secrets provider is Vault, which tells a sealed or unreachable
+// server apart from missing credentials. Other providers only need to be Ready.
+func vaultHealth() (ok bool, detail string) {
+	switch secretsProvider.(type) {
+	case vaultAppRoleProvider:
+		if !secretsProvider.Ready() {
+			return false, "AppRole credentials not set (TF_VAR_role_id / TF_VAR_secret_id)"
+		}
+	case vaultTokenProvider:
+		if !secretsProvider.Ready() {
+			return false, "no token (VAULT_TOKEN or ~/.vault-token)"
+		}
+	default:
+		if !secretsProvider.Ready() {
+			return false, fmt.Sprintf("%s secrets provider is not configured", secretsProvider.Name())
+		}
+		return true, fmt.Sprintf("%s secrets provider, Vault not used", secretsProvider.Name())
+	}
+	client, err := vaultclient.New()
+	if err != nil {
+		return false, err.Error()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+	h, err := client.Sys().HealthWithContext(ctx)
+	switch {
+	case err != nil:
+		return false, fmt.Sprintf("unreachable at %s: %v", client.Address(), err)
+	case !h.Initialized:
+		return false, client.Address() + " is not initialized"
+	case h.Sealed:
+		return false, client.Address() + " is sealed"
+	}
+	detail = fmt.Sprintf("%s, version %s", client.Address(), h.Version)
+	if h.Standby {
+		detail += " (standby)"
+	}
+	return true, detail
+}
+
+// AWS, cached: the credentials of the configured profile, who they belong to (sts
+// get-caller-identity) and whether they reach the state bucket (s3 HeadBucket)
+func awsHealth(cfg Config, force bool) awsResult {
+	awsHealthCache.Lock()
+	defer awsHealthCache.Unlock()
+	if !force && time.Now().Before(awsHealthCache.until) {
+		return awsHealthCache.result
+	}
+	r, expires := checkAWS(cfg)
+	awsHealthCache.result, awsHealthCache.until = r, time.Time{}
+	if r.ok {
+		awsHealthCache.until = time.Now().Add(awsHealthTTL)
+		if !expires.IsZero() && expires.Before(awsHealthCache.until) {
+			awsHealthCache.until = expires
+		}
+	}
+	return r
+}
+
+func checkAWS(cfg Config) (awsResult, time.Time) {
+	awsCfg, err := loadAWSConfig(cfg)
+	if err != nil {
+		return awsResult{detail: err.Error()}, time.Time{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return awsFailure("no credentials", err), time.Time{}
+	}
+	out, err := sts.NewFromConfig(awsCfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return awsFailure("get-caller-identity failed", err), time.Time{}
+	}
+	detail := fmt.Sprintf("%s (%s)", aws.ToString(out.Arn), awsCfg.Region)
+	if cfg.S3Bucket != "" {
+		_, err := s3.NewFromConfig(awsCfg).HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(cfg.S3Bucket)})
+		var resp *smithyhttp.ResponseError
+		switch {
+		case err != nil && errors.As(err, &resp) && resp.HTTPStatusCode() == http.StatusForbidden:
+			return awsResult{detail: fmt.Sprintf("%s has no access to s3://%s", aws.ToString(out.Arn), cfg.S3Bucket)}, time.Time{}
+		case err != nil && errors.As(err, &resp) && resp.HTTPStatusCode() == http.StatusNotFound:
+			return awsResult{detail: fmt.Sprintf("bucket s3://%s not found in %s", cfg.S3Bucket, awsCfg.Region)}, time.Time{}
+		case err != nil:
+			return awsFailure("s3://"+cfg.S3Bucket+" unreachable", err), time.Time{}
+		}
+		detail += ", s3://" + cfg.S3Bucket + " reachable"
+	}
+	var expires time.Time
+	if creds.CanExpire {
+		expires = creds.Expires
+		detail += fmt.Sprintf(", credentials expire in %s", time.Until(expires).Round(time.Minute))
+	}
+	return awsResult{ok: true, detail: detail}, expires
+}
+
+// An SSO session or session token that ran out is told apart from other failures
+func awsFailure(what string, err error) awsResult {
+	var sso *ssocreds.InvalidTokenError
+	var api smithy.APIError
+	expired := errors.As(err, &sso) ||
+		errors.As(err, &api) && strings.Contains(api.ErrorCode(), "Expired") ||
+		strings.Contains(strings.ToLower(err.Error()), "expired")
+	if expired {
+		return awsResult{expired: true, detail: "credentials expired — run aws sso login (" + err.Error() + ")"}
+	}
+	return awsResult{detail: what + ": " + err.Error()}
+}
+
+// Runs both checks on a snapshot; only called off the UI loop. force skips the AWS cache.
+func checkHealth(cfg Config, s *statusSnapshot, force bool) {
+	r := awsHealth(cfg, force)
+	s.awsOK, s.awsExpired, s.awsDetail = r.ok, r.expired, r.detail
+	s.vaultOK, s.vaultDetail = vaultHealth()
+	s.healthChecked = true
+}
+
+// Status bar icon: dim until the first check, red with ✗ when it failed
+func healthIcon(icon string, ok, checked bool) string {
+	switch {
+	case !checked:
+		return logDimStyle.Render(icon)
+	case ok:
+		return okStyle.Render(icon)
+	}
+	return errorStyle.Render(icon + noColorMark("✗"))
+}
+
+// AWS icon: like healthIcon, but amber with ⌛ when only a re-login is missing
+func awsIcon(ok, expired, checked bool) string {
+	if checked && expired {
+		return warnStyle.Render(icons.AWS + noColorMark("⌛"))
+	}
+	return healthIcon(icons.AWS, ok, checked)
+}
+
+// "AWS: … • Vault: …" for the launcher status line
+func healthSummary(m model) string {
+	line := func(name string, ok bool, detail string) string {
+		switch {
+		case detail == "":
+			return name + ": not checked yet"
+		case ok:
+			return name + ": " + detail
+		}
+		return name + ": ✗ " + detail
+	}
+	return strings.Join([]string{line("AWS", m.awsOK, m.awsDetail), line("Vault", m.vaultOK, m.vaultDetail)}, " • ")
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- F1 help browser ---
+
+const helpListWidth = 44
+
+// tfVariable is what variables.tf says about one variable
+type tfVariable struct {
+	Description string
+	Type        string
+	Default     string
+}
+
+type helpEntry struct {
+	Key   string
+	Meta  FieldMeta
+	TFVar *tfVariable
+}
+
+var (
+	tfVariableRe = regexp.MustCompile(`^\s*variable\s+"([^"]+)"\s*\{`)
+	tfAttrRe     = regexp.MustCompile(`^\s*(description|type|default)\s*=\s*(.*)$`)
+)
+
+// Reads variable descriptions/types/defaults from every *.tf file in dir.
+// Only single-line attributes are picked up, which covers the usual module layout.
+func loadTerraformVariables(dir string) map[string]tfVariable {
+	vars := map[string]tfVariable{}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		current, depth := "", 0
+		for _, line := range strings.Split(string(data), "\n") {
+			if current == "" {
+				if mm := tfVariableRe.FindStringSubmatch(line); mm != nil {
+					current, depth = mm[1], 0
+					vars[current] = tfVariable{}
+				} else {
+					continue
+				}
+			}
+			if mm := tfAttrRe.FindStringSubmatch(line); mm != nil && depth == 1 {
+				v := vars[current]
+				val := strings.TrimSpace(mm[2])
+				switch mm[1] {
+				case "description":
+					v.Description = strings.Trim(val, "\"")
+				case "type":
+					v.Type = val
+				case "default":
+					v.Default = val
+				}
+				vars[current] = v
+			}
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			if depth <= 0 {
+				current = ""
+			}
+		}
+	}
+	return vars
+}
+
+// Builds the entry list from the form's field metadata and the module's variables
+func buildHelpEntries(fieldMeta map[string]FieldMeta, order []string, dir string) []helpEntry {
+	tfVars := loadTerraformVariables(dir)
+	seen := map[string]bool{}
+	var entries []helpEntry
+	add := func(key string) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		e := helpEntry{Key: key, Meta: fieldMeta[key]}
+		if v, ok := tfVars[key]; ok {
+			e.TFVar = &v
+		}
+		entries = append(entries, e)
+	}
+	for _, k := range order {
+		add(k)
+	}
+	var rest []string
+	for k := range fieldMeta {
+		rest = append(rest, k)
+	}
+	for k := range tfVars {
+		rest = append(rest, k)
+	}
+	sort.Strings(rest)
+	for _, k := range rest {
+		add(k)
+	}
+	return entries
+}
+
+// Opens the help browser on the focused field of the current form
+func openHelpBrowser(m model) model {
+	var order []string
+	dir, focus := m.activeTemplate.Path, ""
+	switch m.currentScene {
+	case sceneCreateForm:
+		order = m.createLabels
+		focus = m.createLabels[m.createFocus]
+		if d, err := createModuleDir(m); err == nil {
+			dir = d
+		}
+	case sceneEditForm:
+		order = m.editFormLabels
+		focus = m.editFormLabels[m.editFocusIndex]
+		dir = filepath.Dir(m.editFormPath)
+	}
+	m.helpEntries = buildHelpEntries(m.fieldMeta, order, dir)
+	m.helpFilter = textinput.New()
+	m.helpFilter.Placeholder = "search fields and variables"
+	m.helpFilter.Focus()
+	m.helpIdx = 0
+	for i, e := range m.helpEntries {
+		if e.Key == focus {
+			m.helpIdx = i
+		}
+	}
+	return m.pushScene(sceneHelp)
+}
+
+func (e helpEntry) matches(q string) bool {
+	if q == "" {
+		return true
+	}
+	text := e.Key + " " + e.Meta.Label + " " + e.Meta.Help
+	if e.TFVar != nil {
+		text += " " + e.TFVar.Description
+	}
+	return strings.Contains(strings.ToLower(text), q)
+}
+
+func filteredHelpEntries(m model) []helpEntry {
+	q := strings.ToLower(strings.TrimSpace(m.helpFilter.Value()))
+	var out []helpEntry
+	for _, e := range m.helpEntries {
+		if e.matches(q) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func updateHelpBrowser(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		n := len(filteredHelpEntries(m))
+		switch {
+		case key.Matches(keyMsg, keys.HelpBrowser.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.HelpBrowser.Up):
+			if n > 0 {
+				m.helpIdx = (m.helpIdx - 1 + n) % n
+			}
+			return m, nil
+		case key.Matches(keyMsg, keys.HelpBrowser.Down):
+			if n > 0 {
+				m.helpIdx = (m.helpIdx + 1) % n
+			}
+			return m, nil
+		}
+		prev := m.helpFilter.Value()
+		var cmd tea.Cmd
+		m.helpFilter, cmd = m.helpFilter.Update(msg)
+		if m.helpFilter.Value() != prev {
+			m.helpIdx = 0
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+func viewHelpBrowser(m model) (body, tooltip string) {
+	entries := filteredHelpEntries(m)
+	body += tooltipStyle.Render("Search: " + m.helpFilter.View())
+	body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+
+	const maxRows = 24
+	start := 0
+	if m.helpIdx >= maxRows {
+		start = m.helpIdx - maxRows + 1
+	}
+	var left []string
+	for i := start; i < len(entries) && i < start+maxRows; i++ {
+		label := entries[i].Meta.Label
+		if label == "" {
+			label = entries[i].Key
+		}
+		line := padRight(truncate(label, helpListWidth-2), helpListWidth)
+		if i == m.helpIdx {
+			line = focusedStyle.Render(line)
+		} else {
+			line = normalStyle.Render(line)
+		}
+		left = append(left, line)
+	}
+	if len(entries) == 0 {
+		left = append(left, "(no matches)")
+	}
+
+	var right []string
+	if m.helpIdx < len(entries) {
+		e := entries[m.helpIdx]
+		detailWidth := uiWidth - helpListWidth - 10
+		right = append(right, helpKeyStyle.Render(e.Key))
+		if e.Meta.Help != "" {
+			right = append(right, "", "Form help:")
+			right = append(right, wrapText(e.Meta.Help, detailWidth)...)
+		}
+		if len(e.Meta.Options) > 0 {
+			right = append(right, "", "Options: "+strings.Join(e.Meta.Options, ", "))
+		}
+		if e.TFVar != nil {
+			right = append(right, "", "In the module (variables.tf):")
+			if e.TFVar.Description != "" {
+				right = append(right, wrapText(e.TFVar.Description, detailWidth)...)
+			}
+			if e.TFVar.Type != "" {
+				right = append(right, "Type:    "+e.TFVar.Type)
+			}
+			if e.TFVar.Default != "" {
+				right = append(right, "Default: "+truncate(e.TFVar.Default, detailWidth-9))
+			}
+		} else {
+			right = append(right, "", "Not declared in the module's variables.tf")
+		}
+	}
+	for i := 0; i < max(len(left), len(right)); i++ {
+		l, r := strings.Repeat(" ", helpListWidth), ""
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		body += " " + padRight(l, helpListWidth) + " │ " + r + "\n"
+	}
+	tooltip = tooltipStyle.Render(fmt.Sprintf("%d of %d entries — type to search field labels, help and module descriptions", len(entries), len(m.helpEntries)))
+	return body, tooltip
+}
+
+// Word-wraps s to lines of at most width runes
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, w := range strings.Fields(s) {
+		if line != "" && len([]rune(line))+1+len([]rune(w)) > width {
+			lines = append(lines, line)
+			line = w
+		} else if line == "" {
+			line = w
+		} else {
+			line += " " + w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
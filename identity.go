@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"launcher/internal/git"
+	"launcher/internal/state"
+)
+
+// --- Acting user identity ---
+
+type identity = state.Identity
+
+// How long sso_command may take before the identity falls back to $INFRA_CATALOG_SSO_USER
+const identityTimeout = 10 * time.Second
+
+var (
+	identityOnce   sync.Once
+	currentIdent   identity
+	identityConfig IdentityConfig
+)
+
+func configureIdentity(cfg IdentityConfig) {
+	identityConfig = cfg
+}
+
+// currentIdentity is looked up once per process, at startup, so the SSO command never runs from Update
+func currentIdentity() identity {
+	identityOnce.Do(func() {
+		currentIdent.User = os.Getenv("USER")
+		if u, err := user.Current(); err == nil {
+			currentIdent.User = u.Username
+		}
+		currentIdent.Host, _ = os.Hostname()
+		// Who actually logged in when the shared account is reached through sudo
+		if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && sudoUser != currentIdent.User {
+			currentIdent.User = sudoUser + " as " + currentIdent.User
+		}
+		currentIdent.SSO = os.Getenv("INFRA_CATALOG_SSO_USER")
+		if identityConfig.SSOCommand != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), identityTimeout)
+			defer cancel()
+			cmd := exec.CommandContext(ctx, "sh", "-c", identityConfig.SSOCommand)
+			if out, err := cmd.Output(); err == nil {
+				currentIdent.SSO = strings.TrimSpace(string(out))
+			} else {
+				logger.Warn("sso identity command failed", "component", "identity", "error", err.Error())
+			}
+		}
+	})
+	return currentIdent
+}
+
+// Git trailers naming who ran the action
+func identityTrailers(id identity) string {
+	t := fmt.Sprintf("Acted-By: %s\nActed-On: %s", id.User, id.Host)
+	if id.SSO != "" {
+		t += "\nSSO-Identity: " + id.SSO
+	}
+	return t
+}
+
+func gitRun(dir string, args ...string) error {
+	return git.Run(commands, dir, args...)
+}
+
+// Files of a deployment directory that are committed: what the launcher writes and the
+// template's terraform. Backups, artifacts, plans, local state and the deploy lock never are.
+var committedFiles = []string{"terraform.tfvars", "launcher.state", sopsSecretsFile, notesFile, ".terraform.lock.hcl"}
+
+// Existing committedFiles and *.tf / *.tf.json files of dir, relative to dir
+func deploymentCommitPaths(dir string) []string {
+	var paths []string
+	for _, name := range committedFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			paths = append(paths, name)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.Type().IsRegular() && (strings.HasSuffix(e.Name(), ".tf") || strings.HasSuffix(e.Name(), ".tf.json")) {
+			paths = append(paths, e.Name())
+		}
+	}
+	return paths
+}
+
+// Commits the deployment's files (deploymentCommitPaths), if identity.git_commit is on
+func commitDeploymentChange(dir, action, result string) error {
+	if !identityConfig.GitCommit {
+		return nil
+	}
+	if safeMode {
+		logger.Info("git commit skipped in safe mode", "component", "git", "dir", dir)
+		return nil
+	}
+	paths := deploymentCommitPaths(dir)
+	if len(paths) == 0 {
+		return nil
+	}
+	if err := gitRun(dir, append([]string{"add", "--"}, paths...)...); err != nil {
+		return err
+	}
+	// Nothing staged for these files: nothing to commit
+	if !git.HasStagedChanges(commands, dir, paths...) {
+		return nil
+	}
+	id := currentIdentity()
+	msg := fmt.Sprintf("%s %s: %s\n\n%s", action, filepath.Base(dir), result, identityTrailers(id))
+	started := time.Now()
+	err := gitRun(dir, append([]string{"commit", "-q", "-m", msg, "--"}, paths...)...)
+	if err != nil {
+		logger.Error("git commit failed", "component", "git", "dir", dir, "error", err.Error(), "duration_ms", time.Since(started).Milliseconds())
+	}
+	return err
+}
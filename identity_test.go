@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommitDeploymentChangeStagesExplicitPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"terraform.tfvars", "launcher.state", "main.tf", "s3.tf", "terraform.tfstate", "tfplan", deployLockFile} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, tfvarsHistoryDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeCommands{}
+	oldCommands, oldConfig := commands, identityConfig
+	commands, identityConfig = fake, IdentityConfig{GitCommit: true}
+	t.Cleanup(func() { commands, identityConfig = oldCommands, oldConfig })
+
+	if err := commitDeploymentChange(dir, "apply", "succeeded"); err != nil {
+		t.Fatal(err)
+	}
+	want := "git add -- terraform.tfvars launcher.state main.tf s3.tf"
+	if len(fake.calls) == 0 || fake.calls[0] != want {
+		t.Fatalf("calls = %q, want first %q", fake.calls, want)
+	}
+}
+
+func TestSafeModeRunsNoGit(t *testing.T) {
+	fake := &fakeCommands{}
+	oldCommands, oldConfig, oldSafe := commands, identityConfig, safeMode
+	commands, identityConfig, safeMode = fake, IdentityConfig{GitCommit: true}, true
+	t.Cleanup(func() { commands, identityConfig, safeMode = oldCommands, oldConfig, oldSafe })
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "terraform.tfvars"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitDeploymentChange(dir, "apply", "succeeded"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getTemplateCommit(dir); err != errSafeMode {
+		t.Errorf("getTemplateCommit error = %v, want errSafeMode", err)
+	}
+	if _, err := templateChangeList(dir, "a", "b"); err != errSafeMode {
+		t.Errorf("templateChangeList error = %v, want errSafeMode", err)
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("git ran in safe mode: %q", fake.calls)
+	}
+}
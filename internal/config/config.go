@@ -0,0 +1,250 @@
+// Package config holds the config.yaml types and the strict YAML decoder used for
+// config.yaml, fields.yaml, template.yaml and the cost model.
+package config
+
+// Config is config.yaml
+type Config struct {
+	Repo          string `yaml:"repo"`
+	AppsPath      string `yaml:"apps_path"`
+	TemplatePath  string `yaml:"template_path"`
+	PresetsPath   string `yaml:"presets_path"`
+	AWSProfile    string `yaml:"aws_profile"`
+	S3Bucket      string `yaml:"s3_bucket"`
+	AWSRegion     string `yaml:"aws_region"`
+	TerraformPath string `yaml:"terraform_path"`
+	// RefreshInterval is a Go duration ("30s", "2m"); "0" disables background refresh
+	RefreshInterval string `yaml:"refresh_interval"`
+	// TemplatesPath is a catalog dir with one sub-directory per template; overrides TemplatePath when set
+	TemplatesPath string `yaml:"templates_path"`
+	// Where post-apply artifacts are stored: ArtifactsPath for local files (default <deployment>/.artifacts),
+	// VaultArtifactsPath for the KV v2 path used by templates with `store: vault`
+	ArtifactsPath      string `yaml:"artifacts_path"`
+	VaultArtifactsPath string `yaml:"vault_artifacts_path"`
+	// KV v2 path under which generated deployment secrets are written
+	VaultSecretsPath string `yaml:"vault_secrets_path"`
+	// What to do when a new deployment doesn't fit the cluster: "warn" (default), "block" or "off"
+	CapacityCheck string `yaml:"capacity_check"`
+	// Where Proxmox API credentials come from (default: Vault AppRole)
+	SecretsProvider SecretsProvider `yaml:"secrets_provider"`
+	// How many terraform jobs may run at the same time (default 2)
+	MaxConcurrentJobs int `yaml:"max_concurrent_jobs"`
+	// Options for the cluster and zone fields; built-in lists when empty
+	Clusters []string `yaml:"clusters"`
+	Zones    []Zone   `yaml:"zones"`
+	// Optional: discover clusters at startup instead of using Clusters
+	ClusterDiscovery ClusterDiscovery `yaml:"cluster_discovery"`
+	// Bell/desktop/webhook notifications when long jobs finish
+	Notifications Notify `yaml:"notifications"`
+	// Hosts of the libvirt clusters used by templates with `provider: libvirt`
+	Libvirt Libvirt `yaml:"libvirt"`
+	// Which Proxmox templates the create form offers, globally and per cluster
+	TemplateFilter TemplateFilters `yaml:"template_filter"`
+	// [S] SSH: login user, identity file and the terraform output listing VM IPs
+	SSH SSH `yaml:"ssh"`
+	// Hash-chained log of catalog actions
+	Audit Audit `yaml:"audit"`
+	// SSO identity lookup and git commits of deployment changes
+	Identity Identity `yaml:"identity"`
+	// Start in safe mode after this many unclean exits in a row (default 3, -1 never)
+	SafeModeAfter int `yaml:"safe_mode_after"`
+	// Terraform/OpenTofu version constraint checked at startup, e.g. ">= 1.6, < 2.0"; apply is refused outside it
+	RequiredVersion string `yaml:"required_version"`
+	// "terraform", "tofu" or an absolute path; default terraform, then tofu, from PATH
+	TerraformBinary string `yaml:"terraform_binary"`
+	// Extra arguments per subcommand, e.g. apply: ["-lock-timeout=5m", "-parallelism=4"]
+	TerraformArgs map[string][]string `yaml:"terraform_args"`
+	// Reduced redraws for slow SSH links: slow spinners, ASCII borders, no gradients, capped frame rate
+	LowBandwidth bool `yaml:"low_bandwidth"`
+	// Width of the launcher's deployments table in % (default 60, 20-80); narrow terminals stack the panes
+	LauncherSplit int `yaml:"launcher_split"`
+	// YAML file with monthly prices per core, GB RAM and GB disk; adds a cost column and create-form estimate
+	CostModel string `yaml:"cost_model"`
+	// Show the running operation (e.g. "applying proxmox_web_dmz_12 3m21s") in the terminal title / tmux status line
+	StatusTitle bool `yaml:"status_title"`
+	// Deployment directory naming template and rules
+	Naming Naming `yaml:"naming"`
+	// Shared file of allocated VMID ranges, consulted when creating and reconciled by `launcher vmids`
+	VMIDRegistry VMIDRegistry `yaml:"vmid_registry"`
+	// Per-environment guard rails (confirm, reviewed plans, snapshots, typed destroy); prod has all by default
+	GuardRails map[string]GuardRails `yaml:"guard_rails"`
+	// Diffs (rollback, bulk edit, replace, compare, template changes): unified (default) or side-by-side
+	DiffMode string `yaml:"diff_mode"`
+	// IANA zone times are shown in, e.g. "Europe/Paris"; default the system's local zone
+	Timezone string `yaml:"timezone"`
+	// Color theme: dark (default), light or high-contrast; NO_COLOR turns colors off
+	Theme string `yaml:"theme"`
+	// Status bar icons: nerd (default, needs a Nerd Font) or ascii
+	Icons string `yaml:"icons"`
+	// Shared provider cache (TF_PLUGIN_CACHE_DIR); default ~/.terraform.d/plugin-cache, "off" to disable
+	PluginCacheDir string `yaml:"plugin_cache_dir"`
+	// age recipients for secrets.sops.json of sensitive fields; default: the repo's .sops.yaml creation rules
+	SopsAgeRecipients string `yaml:"sops_age_recipients"`
+	// Oldest saved plan [A] still applies, Go duration (default 1h)
+	PlanMaxAge string `yaml:"plan_max_age"`
+	// Turn off watching apps_path for tfvars/launcher.state changes made outside the launcher
+	DisableWatch bool `yaml:"disable_watch"`
+	// Admins who may lock the catalog for maintenance (`launcher maintenance`), and the lock file
+	CatalogLock CatalogLock `yaml:"catalog_lock"`
+	// Where archived deployments go (default "archives" next to apps_path)
+	ArchivesPath string `yaml:"archives_path"`
+}
+
+// SecretsProvider selects and configures the SecretsProvider
+type SecretsProvider struct {
+	// "vault-approle" (default), "vault-token", "env", "sops" or "file"
+	Type string `yaml:"type"`
+	// sops/file: encrypted YAML with a `clusters:` map of credentials. A relative path is taken
+	// from the terraform repo checkout (terraform_path) for sops, from the working directory for file
+	File string `yaml:"file"`
+	// file: age identity used for *.age files (default: $SOPS_AGE_KEY_FILE or ~/.config/sops/age/keys.txt)
+	AgeIdentity string `yaml:"age_identity"`
+}
+
+// Zone is one entry of `zones:` in config.yaml
+type Zone struct {
+	Name        string `yaml:"name"`
+	VLAN        int    `yaml:"vlan"`
+	Description string `yaml:"description"`
+	// Largest VM the zone takes, for the form gauges: vm_memory in MB and vm_cpu_cores
+	MaxMemory   int `yaml:"max_memory"`
+	MaxCPUCores int `yaml:"max_cpu_cores"`
+}
+
+// ClusterDiscovery: "vault" lists the cluster secrets under Path,
+// "proxmox" keeps the Endpoints (default: clusters) whose API answers
+type ClusterDiscovery struct {
+	Source    string   `yaml:"source"`
+	Path      string   `yaml:"path"`
+	Endpoints []string `yaml:"endpoints"`
+}
+
+// Notify is the `notifications:` block of config.yaml
+type Notify struct {
+	// Ring the terminal bell
+	Bell bool `yaml:"bell"`
+	// Desktop notification escape sequence: "osc777" (rxvt, foot, WezTerm) or "osc9" (iTerm2, Windows Terminal)
+	Desktop string `yaml:"desktop"`
+	// Optional webhook; the message is POSTed as {"text": ...}, which Slack incoming webhooks accept
+	WebhookURL string `yaml:"webhook_url"`
+	// text/template over .Deployment, .Operation, .Result, .Duration and .Error
+	Template string `yaml:"template"`
+	// Only notify for jobs that ran at least this long (Go duration, default "30s")
+	MinDuration string `yaml:"min_duration"`
+	// Ring the bell / raise the desktop notification only while the terminal window is
+	// unfocused (needs a terminal that reports focus; the webhook is always sent)
+	WhenUnfocused bool `yaml:"when_unfocused"`
+}
+
+// Libvirt lists the hosts of each libvirt "cluster" and the pool holding template images
+type Libvirt struct {
+	// Cluster name -> connection URIs, e.g. qemu+ssh://root@kvm01/system; each host is a node
+	Clusters map[string][]string `yaml:"clusters"`
+	// Storage pool with the template volumes (default "default")
+	Pool string `yaml:"pool"`
+}
+
+// TemplateFilter selects VM templates by name: a template is offered when it matches
+// any Include regex (or Include is empty) and none of the Exclude regexes
+type TemplateFilter struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+}
+
+// TemplateFilters is the global filter plus per-cluster overrides.
+// A cluster's include list replaces the global one; exclude lists add up.
+type TemplateFilters struct {
+	TemplateFilter `yaml:",inline"`
+	Clusters       map[string]TemplateFilter `yaml:"clusters"`
+}
+
+// SSH is the `ssh:` block of config.yaml
+type SSH struct {
+	User     string   `yaml:"user"`      // default "ubuntu"
+	Key      string   `yaml:"key"`       // identity file passed with -i
+	IPOutput string   `yaml:"ip_output"` // terraform output holding the VM IPs, default "vm_ips"
+	Options  []string `yaml:"options"`   // extra ssh arguments, e.g. ["-o", "StrictHostKeyChecking=accept-new"]
+}
+
+// Audit is the `audit:` block of config.yaml
+type Audit struct {
+	// Default: audit.log next to the app log; point it at a shared path on multi-user hosts
+	Path string `yaml:"path"`
+	// Optional GPG key id; entries are signed with `gpg --detach-sign`
+	GPGKey string `yaml:"gpg_key"`
+}
+
+// Identity is the `identity:` block of config.yaml
+type Identity struct {
+	// Command printing the SSO identity, e.g. `aws sts get-caller-identity --query Arn --output text`.
+	// Default: $INFRA_CATALOG_SSO_USER when set.
+	SSOCommand string `yaml:"sso_command"`
+	// Commit deployment changes to git after create/apply/destroy, with identity trailers
+	GitCommit bool `yaml:"git_commit"`
+}
+
+// Naming controls how deployment directory names are built and checked
+type Naming struct {
+	// Go text/template over .Provider, .App, .Zone, .PlatformID and .Fields (every create-form value)
+	Template string `yaml:"template"`
+	// Allowed names (default: letters, digits, '_', '.', '-', not starting with a separator)
+	Pattern   string `yaml:"pattern"`
+	MaxLength int    `yaml:"max_length"`
+}
+
+// VMIDRegistry is `vmid_registry:` in config.yaml; an empty path turns the registry off
+type VMIDRegistry struct {
+	Path     string `yaml:"path"`     // relative to terraform_path
+	Min      int    `yaml:"min"`      // lowest ID handed out, default 1000
+	Max      int    `yaml:"max"`      // highest, default 99999
+	Variable string `yaml:"variable"` // tfvars variable receiving the first ID, default vm_id_start
+}
+
+func (c VMIDRegistry) Enabled() bool { return c.Path != "" }
+
+// Lowest and highest ID handed out, with the defaults applied
+func (c VMIDRegistry) Bounds() (int, int) {
+	lo, hi := c.Min, c.Max
+	if lo <= 0 {
+		lo = 1000
+	}
+	if hi <= 0 {
+		hi = 99999
+	}
+	return lo, hi
+}
+
+// Name of the tfvars variable receiving the first ID
+func (c VMIDRegistry) VarName() string {
+	if c.Variable != "" {
+		return c.Variable
+	}
+	return "vm_id_start"
+}
+
+// GuardRails is one entry of `guard_rails:` in config.yaml, keyed by environment:
+//
+//	guard_rails:
+//	  prod:
+//	    confirm_apply: true          # ask before every apply
+//	    require_plan: true           # no init+apply -auto-approve; plan with [P], apply the plan
+//	    snapshot_before_apply: true  # Proxmox snapshot of every VM before terraform apply
+//	    type_name_to_destroy: true   # destroy asks for the deployment name instead of y
+//	  stage:
+//	    confirm_apply: true
+//
+// Without an entry prod gets all four and the other environments none.
+type GuardRails struct {
+	ConfirmApply        bool `yaml:"confirm_apply"`
+	RequirePlan         bool `yaml:"require_plan"`
+	SnapshotBeforeApply bool `yaml:"snapshot_before_apply"`
+	TypeNameToDestroy   bool `yaml:"type_name_to_destroy"`
+}
+
+// CatalogLock is `catalog_lock:` in config.yaml
+type CatalogLock struct {
+	// Users allowed to lock the catalog and to create/apply while it is locked: login names
+	// (as in the audit log) or SSO identities
+	Admins []string `yaml:"admins"`
+	// Lock file, relative to terraform_path (default catalog-lock.yaml)
+	Path string `yaml:"path"`
+}
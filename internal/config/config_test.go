@@ -0,0 +1,89 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	data := []byte(`
+apps_path: /srv/apps
+zones:
+  - name: dmz
+    vlan: 20
+template_filter:
+  include: ["^ubuntu"]
+  clusters:
+    pve1:
+      exclude: ["-old$"]
+guard_rails:
+  prod:
+    confirm_apply: true
+`)
+	var cfg Config
+	if err := DecodeStrict("config.yaml", data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AppsPath != "/srv/apps" || cfg.Zones[0].VLAN != 20 || !cfg.GuardRails["prod"].ConfirmApply {
+		t.Errorf("decoded %+v", cfg)
+	}
+	if cfg.TemplateFilter.Include[0] != "^ubuntu" || cfg.TemplateFilter.Clusters["pve1"].Exclude[0] != "-old$" {
+		t.Errorf("template_filter %+v", cfg.TemplateFilter)
+	}
+}
+
+func TestDecodeStrictUnknownKeys(t *testing.T) {
+	data := []byte("apps_pth: /srv/apps\nzones:\n  - name: dmz\n    vlna: 20\nguard_rails:\n  prod:\n    confirm: true\n")
+	var cfg Config
+	err := DecodeStrict("config.yaml", data, &cfg)
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, want := range []string{
+		`config.yaml:1:1: unknown key "apps_pth" (did you mean "apps_path"?)`,
+		`config.yaml:4:5: unknown key "vlna" (did you mean "vlan"?)`,
+		`config.yaml:7:5: unknown key "confirm"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q lacks %q", err, want)
+		}
+	}
+}
+
+func TestDecodeStrictEmpty(t *testing.T) {
+	var cfg Config
+	if err := DecodeStrict("config.yaml", nil, &cfg); err != nil {
+		t.Errorf("empty document: %v", err)
+	}
+	if err := DecodeStrict("config.yaml", []byte("apps_path: [\n"), &cfg); err == nil {
+		t.Error("invalid YAML: want an error")
+	}
+}
+
+func TestVMIDRegistryDefaults(t *testing.T) {
+	var r VMIDRegistry
+	if r.Enabled() {
+		t.Error("no path: want disabled")
+	}
+	if lo, hi := r.Bounds(); lo != 1000 || hi != 99999 {
+		t.Errorf("Bounds = %d, %d", lo, hi)
+	}
+	if r.VarName() != "vm_id_start" {
+		t.Errorf("VarName = %q", r.VarName())
+	}
+	r = VMIDRegistry{Path: "vmids.yaml", Min: 5000, Max: 6000, Variable: "first_vmid"}
+	if lo, hi := r.Bounds(); !r.Enabled() || lo != 5000 || hi != 6000 || r.VarName() != "first_vmid" {
+		t.Errorf("configured registry: %v %d %d %q", r.Enabled(), lo, hi, r.VarName())
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		d    int
+	}{{"", "abc", 3}, {"vlan", "vlna", 2}, {"kitten", "sitting", 3}, {"same", "same", 0}} {
+		if d := editDistance(tt.a, tt.b); d != tt.d {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, d, tt.d)
+		}
+	}
+}
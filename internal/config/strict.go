@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeStrict decodes data into out; unknown keys are errors with their line, column
+// and the closest known key, instead of being dropped silently. path only labels errors.
+func DecodeStrict(path string, data []byte, out any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return DecodeNodeStrict(path, &doc, out)
+}
+
+// DecodeNodeStrict is DecodeStrict for an already parsed document (e.g. one of several in a file)
+func DecodeNodeStrict(path string, doc *yaml.Node, out any) error {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	var problems []string
+	checkKnownKeys(path, doc.Content[0], reflect.TypeOf(out), &problems)
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	if err := doc.Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+var yamlUnmarshaler = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// Walks the YAML tree next to the Go type it decodes into and reports mapping keys
+// that match no yaml tag; maps, slices and free-form values are followed or skipped
+func checkKnownKeys(path string, n *yaml.Node, t reflect.Type, problems *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(yamlUnmarshaler) {
+		return // decodes itself
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	switch {
+	case t.Kind() == reflect.Struct && n.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			ft, ok := fields[k.Value]
+			if !ok {
+				msg := fmt.Sprintf("%s:%d:%d: unknown key %q", path, k.Line, k.Column, k.Value)
+				if s := closestKey(k.Value, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				*problems = append(*problems, msg)
+				continue
+			}
+			checkKnownKeys(path, v, ft, problems)
+		}
+	case t.Kind() == reflect.Map && n.Kind == yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			checkKnownKeys(path, n.Content[i], t.Elem(), problems)
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && n.Kind == yaml.SequenceNode:
+		for _, item := range n.Content {
+			checkKnownKeys(path, item, t.Elem(), problems)
+		}
+	}
+}
+
+// Key name -> field type, following the same rules as yaml.v3 (lowercased field name
+// when untagged, "-" skipped, ",inline" structs merged)
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") {
+			for k, v := range yamlFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// Known key within a small edit distance of key, or "" when nothing is close enough
+func closestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", len(key)/3+2
+	for k := range fields {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(k)); d < bestDist || (d == bestDist && best != "" && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// Levenshtein distance
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,28 @@
+// Package execx runs external commands behind an interface, so code that shells out to git
+// or terraform can be handed a fake in tests.
+package execx
+
+import "os/exec"
+
+// Runner runs commands in a directory ("" for the current one)
+type Runner interface {
+	// Output runs name with args and returns what it wrote to stdout; a failure is an
+	// *exec.ExitError carrying stderr
+	Output(dir, name string, args ...string) ([]byte, error)
+	// Command builds the command without starting it, for callers that stream its output,
+	// set its environment or wait on it themselves
+	Command(dir, name string, args ...string) *exec.Cmd
+}
+
+// System runs commands with os/exec
+type System struct{}
+
+func (s System) Output(dir, name string, args ...string) ([]byte, error) {
+	return s.Command(dir, name, args...).Output()
+}
+
+func (System) Command(dir, name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd
+}
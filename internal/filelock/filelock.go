@@ -0,0 +1,55 @@
+// Package filelock takes exclusive advisory locks on files shared between launcher
+// processes (the audit log, the VMID registry, deployment locks). The lock is held on a
+// dedicated lock file and released when the process exits, so a crash never leaves a
+// stale lock behind.
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrLocked is returned by TryLock while another process holds the lock
+var ErrLocked = errors.New("locked by another process")
+
+// Lock creates path if needed and blocks until it holds the lock; unlock releases it
+func Lock(path string) (unlock func(), err error) {
+	return acquire(path, true)
+}
+
+// TryLock is Lock without waiting: ErrLocked when the lock is taken
+func TryLock(path string) (unlock func(), err error) {
+	return acquire(path, false)
+}
+
+// LockTimeout retries TryLock until it succeeds or timeout has passed
+func LockTimeout(path string, timeout time.Duration) (unlock func(), err error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		unlock, err := TryLock(path)
+		if !errors.Is(err, ErrLocked) || time.Now().After(deadline) {
+			return unlock, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func acquire(path string, block bool) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, block); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,56 @@
+package filelock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTryLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "x.lock")
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a second open of the file conflicts, as another process would
+	if _, err := TryLock(path); !errors.Is(err, ErrLocked) {
+		t.Errorf("TryLock while held = %v, want ErrLocked", err)
+	}
+	if _, err := LockTimeout(path, 100*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Errorf("LockTimeout while held = %v, want ErrLocked", err)
+	}
+	unlock()
+	unlock2, err := TryLock(path)
+	if err != nil {
+		t.Fatalf("TryLock after unlock = %v", err)
+	}
+	unlock2()
+}
+
+func TestLockWaits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan struct{})
+	go func() {
+		u, err := Lock(path)
+		if err == nil {
+			u()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second Lock didn't wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second Lock never got the lock")
+	}
+}
@@ -0,0 +1,115 @@
+// Package git reads the catalog checkout and template history through git commands run by
+// an execx.Runner.
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"launcher/internal/execx"
+)
+
+// Run runs a git command in dir for its effect; a failure carries git's stderr
+func Run(r execx.Runner, dir string, args ...string) error {
+	if _, err := r.Output(dir, "git", args...); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return nil
+}
+
+// HasStagedChanges reports whether the index differs from HEAD under the given paths
+func HasStagedChanges(r execx.Runner, dir string, paths ...string) bool {
+	_, err := r.Output(dir, "git", append([]string{"diff", "--cached", "--quiet", "--"}, paths...)...)
+	return err != nil
+}
+
+// Status is the checkout's branch and whether it has uncommitted changes
+func Status(r execx.Runner, repo string) (branch string, dirty bool, err error) {
+	out, err := r.Output(repo, "git", "status", "--porcelain", "--branch")
+	if err != nil {
+		return "", false, err
+	}
+	branch, dirty = ParseStatus(string(out))
+	return branch, dirty, nil
+}
+
+// ParseStatus reads `git status --porcelain --branch` output; the branch is "main" when the
+// header line is missing
+func ParseStatus(out string) (branch string, dirty bool) {
+	lines := strings.Split(out, "\n")
+	branch = "main"
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "## ") {
+		branchLine := lines[0][3:]
+		if idx := strings.Index(branchLine, "..."); idx > 0 {
+			branch = branchLine[:idx]
+		} else if idx := strings.Index(branchLine, " "); idx > 0 {
+			branch = branchLine[:idx]
+		} else {
+			branch = branchLine
+		}
+	}
+	for _, l := range lines[1:] {
+		if len(strings.TrimSpace(l)) > 0 {
+			return branch, true
+		}
+	}
+	return branch, false
+}
+
+// LastCommit is the newest commit touching dir
+func LastCommit(r execx.Runner, dir string) (string, error) {
+	out, err := r.Output(dir, "git", "log", "-1", "--format=%H", "--", ".")
+	if err != nil {
+		return "", err
+	}
+	commit := strings.TrimSpace(string(out))
+	if commit == "" {
+		return "", fmt.Errorf("no commits found for %s", dir)
+	}
+	return commit, nil
+}
+
+// Log is "<short> <subject>" for each commit touching dir between from and to, newest first
+func Log(r execx.Runner, dir, from, to string) ([]string, error) {
+	out, err := r.Output(dir, "git", "log", "--oneline", "--no-decorate", from+".."+to, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	for _, l := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(l) != "" {
+			changes = append(changes, l)
+		}
+	}
+	return changes, nil
+}
+
+// ChangedFiles lists the files under dir that differ between two commits, relative to dir
+func ChangedFiles(r execx.Runner, dir, from, to string) ([]string, error) {
+	out, err := r.Output(dir, "git", "diff", "--name-only", "--relative", from, to)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(string(out), "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// ShowLines is path (relative to dir) at commit, split in lines; nil when it isn't there
+func ShowLines(r execx.Runner, dir, commit, path string) []string {
+	out, err := r.Output(dir, "git", "show", commit+":./"+path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+}
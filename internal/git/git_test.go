@@ -0,0 +1,98 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeRunner answers git commands from a table keyed by the joined arguments
+type fakeRunner struct {
+	out   map[string]string
+	err   map[string]error
+	calls []string
+}
+
+func (f *fakeRunner) Output(dir, name string, args ...string) ([]byte, error) {
+	call := strings.Join(args, " ")
+	f.calls = append(f.calls, dir+": "+name+" "+call)
+	return []byte(f.out[call]), f.err[call]
+}
+
+func (f *fakeRunner) Command(dir, name string, args ...string) *exec.Cmd {
+	return exec.Command("false")
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		out    string
+		branch string
+		dirty  bool
+	}{
+		{"## main...origin/main\n", "main", false},
+		{"## feature/x...origin/feature/x [ahead 1]\n M main.go\n", "feature/x", true},
+		{"## HEAD (no branch)\n", "HEAD", false},
+		{"", "main", false},
+	}
+	for _, tt := range tests {
+		branch, dirty := ParseStatus(tt.out)
+		if branch != tt.branch || dirty != tt.dirty {
+			t.Errorf("ParseStatus(%q) = %q, %v; want %q, %v", tt.out, branch, dirty, tt.branch, tt.dirty)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	r := &fakeRunner{out: map[string]string{"status --porcelain --branch": "## dev\n?? new.tf\n"}}
+	branch, dirty, err := Status(r, "/repo")
+	if err != nil || branch != "dev" || !dirty {
+		t.Fatalf("Status = %q, %v, %v", branch, dirty, err)
+	}
+	if r.calls[0] != "/repo: git status --porcelain --branch" {
+		t.Errorf("ran %q", r.calls[0])
+	}
+}
+
+func TestLastCommit(t *testing.T) {
+	r := &fakeRunner{out: map[string]string{"log -1 --format=%H -- .": "abc123\n"}}
+	if c, err := LastCommit(r, "/tpl"); err != nil || c != "abc123" {
+		t.Errorf("LastCommit = %q, %v", c, err)
+	}
+	if _, err := LastCommit(&fakeRunner{}, "/tpl"); err == nil {
+		t.Error("LastCommit with no commits: want an error")
+	}
+}
+
+func TestLogAndChangedFiles(t *testing.T) {
+	r := &fakeRunner{out: map[string]string{
+		"log --oneline --no-decorate a..b -- .": "b2 second\nb1 first\n\n",
+		"diff --name-only --relative a b":       "main.tf\n\nvariables.tf\n",
+	}}
+	log, err := Log(r, "/tpl", "a", "b")
+	if err != nil || !slices.Equal(log, []string{"b2 second", "b1 first"}) {
+		t.Errorf("Log = %q, %v", log, err)
+	}
+	files, err := ChangedFiles(r, "/tpl", "a", "b")
+	if err != nil || !slices.Equal(files, []string{"main.tf", "variables.tf"}) {
+		t.Errorf("ChangedFiles = %q, %v", files, err)
+	}
+}
+
+func TestRunAndStaged(t *testing.T) {
+	r := &fakeRunner{err: map[string]error{"commit -m x": errors.New("exit status 1")}}
+	if err := Run(r, "/repo", "add", "a.tf"); err != nil {
+		t.Errorf("Run(add) = %v", err)
+	}
+	if err := Run(r, "/repo", "commit", "-m", "x"); err == nil || !strings.HasPrefix(err.Error(), "git commit:") {
+		t.Errorf("Run(commit) = %v", err)
+	}
+	if HasStagedChanges(r, "/repo", "a.tf") {
+		t.Error("HasStagedChanges: want false when diff --cached succeeds")
+	}
+	r.err["diff --cached --quiet -- a.tf"] = errors.New("exit status 1")
+	if !HasStagedChanges(r, "/repo", "a.tf") {
+		t.Error("HasStagedChanges: want true when diff --cached fails")
+	}
+}
@@ -0,0 +1,72 @@
+package proxmox
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// doerFunc answers requests with a function
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func reply(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestGet(t *testing.T) {
+	var got *http.Request
+	c := &Client{Host: "pve1", TokenID: "root@pam!tf", TokenSecret: "s3cret", HTTP: doerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req
+		return reply(200, `{"data":[{"node":"pve1","maxmem":1024}]}`), nil
+	})}
+	var nodes []struct {
+		Node   string `json:"node"`
+		MaxMem int64  `json:"maxmem"`
+	}
+	if err := c.Get("nodes", &nodes); err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Node != "pve1" || nodes[0].MaxMem != 1024 {
+		t.Errorf("decoded %+v", nodes)
+	}
+	if got.URL.String() != "https://pve1:8006/api2/json/nodes" {
+		t.Errorf("URL %s", got.URL)
+	}
+	if a := got.Header.Get("Authorization"); a != "PVEAPIToken=root@pam!tf=s3cret" {
+		t.Errorf("Authorization %q", a)
+	}
+}
+
+func TestPost(t *testing.T) {
+	c := &Client{Host: "pve1", HTTP: doerFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if req.Method != "POST" || string(body) != "snapname=pre" || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("%s %q %q", req.Method, body, req.Header.Get("Content-Type"))
+		}
+		return reply(200, `{"data":"UPID:pve1:1"}`), nil
+	})}
+	var upid string
+	if err := c.Post("nodes/pve1/qemu/100/snapshot", url.Values{"snapname": {"pre"}}, &upid); err != nil || upid != "UPID:pve1:1" {
+		t.Errorf("Post = %q, %v", upid, err)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	for name, d := range map[string]Doer{
+		"status":  doerFunc(func(*http.Request) (*http.Response, error) { return reply(401, "no"), nil }),
+		"network": doerFunc(func(*http.Request) (*http.Response, error) { return nil, errors.New("connection refused") }),
+	} {
+		c := &Client{Host: "pve1", HTTP: d}
+		var out any
+		err := c.Get("version", &out)
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			t.Errorf("%s: got %v, want a *RequestError", name, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package state reads and writes launcher.state, the YAML file in each deployment directory
+// holding its state, what was done to it and by whom.
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/terraform"
+)
+
+// File is the state file's name in a deployment directory
+const File = "launcher.state"
+
+// MaxHistory is how many history entries are kept, oldest dropped first
+const MaxHistory = 50
+
+// Unknown is the state of a deployment whose launcher.state is missing or unreadable
+const Unknown = "UNKNOWN"
+
+// Identity is who acted: login, host and, when known, the SSO identity
+type Identity struct {
+	User string `yaml:"user" json:"user"`
+	Host string `yaml:"host" json:"host"`
+	SSO  string `yaml:"sso,omitempty" json:"sso,omitempty"`
+}
+
+func (id Identity) String() string {
+	s := id.User + "@" + id.Host
+	if id.SSO != "" {
+		s += " (" + id.SSO + ")"
+	}
+	return s
+}
+
+type Deployment struct {
+	State      string `yaml:"state"`
+	Timestamp  string `yaml:"timestamp"`
+	LastAction string `yaml:"last_action"`
+	// Template name and git commit the deployment was created from
+	Template       string `yaml:"template,omitempty"`
+	TemplateCommit string `yaml:"template_commit,omitempty"`
+	// Post-apply artifacts (terraform output name -> file path or vault reference)
+	Artifacts map[string]string `yaml:"artifacts,omitempty"`
+	// Vault path holding generated secrets, and the terraform variables they feed
+	SecretsPath string   `yaml:"secrets_path,omitempty"`
+	Secrets     []string `yaml:"secrets,omitempty"`
+	// Who changed the state and when, newest last
+	History []Change `yaml:"history,omitempty"`
+	// Plan saved by [P], waiting to be applied with [A]
+	Plan *Plan `yaml:"plan,omitempty"`
+	// Step the last job failed at, while the deployment is FAILED
+	Failure *Failure `yaml:"failure,omitempty"`
+	// Last health checks, until the state changes again
+	Health *Health `yaml:"health,omitempty"`
+	// Classification chosen at create time: prod/stage/dev and low/medium/high
+	Environment string `yaml:"environment,omitempty"`
+	Criticality string `yaml:"criticality,omitempty"`
+}
+
+type Change struct {
+	Timestamp string   `yaml:"timestamp"`
+	Action    string   `yaml:"action"`
+	State     string   `yaml:"state"`
+	By        Identity `yaml:"by"`
+	// -var overrides the step ran with ("name=value"), terraform.tfvars left unchanged
+	Overrides []string `yaml:"overrides,omitempty"`
+}
+
+// Plan is recorded when a plan job succeeds
+type Plan struct {
+	File      string   `yaml:"file"`
+	CreatedAt string   `yaml:"created_at"`
+	By        Identity `yaml:"by"`
+	// "Plan: 1 to add, 2 to change, 0 to destroy" or "No changes"
+	Summary string `yaml:"summary"`
+	// sha256 of terraform.tfvars and secrets.sops.json when the plan was made
+	TfvarsHash string `yaml:"tfvars_hash"`
+}
+
+// Failure is where the last job of a deployment failed, kept until a later step succeeds
+type Failure struct {
+	Label string           `yaml:"label"`
+	Phase string           `yaml:"phase"` // name of the failed step: init, plan, apply...
+	Error string           `yaml:"error"`
+	At    string           `yaml:"at"`
+	Steps []terraform.Step `yaml:"steps"` // the failed step and the ones after it
+}
+
+// Health is the outcome of the last health checks, kept until the state changes
+type Health struct {
+	Status   string   `yaml:"status"` // HEALTHY or UNHEALTHY
+	At       string   `yaml:"at"`
+	Failures []string `yaml:"failures,omitempty"`
+}
+
+// Read loads dir's launcher.state; the state is Unknown when it is missing or unreadable
+func Read(dir string) (Deployment, error) {
+	var s Deployment
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if err != nil {
+		return Deployment{State: Unknown}, err
+	}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		s.State = Unknown
+		return s, err
+	}
+	return s, nil
+}
+
+func Write(dir string, s Deployment) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, File), data, 0644)
+}
+
+// Record sets the state and last action and appends them to the history. A new state
+// drops the health report, and any state but FAILED the recorded failure.
+func (s *Deployment) Record(action, newState string, by Identity, overrides []string, now time.Time) {
+	s.State = newState
+	if newState != "FAILED" {
+		s.Failure = nil
+	}
+	s.Health = nil
+	s.Timestamp = now.UTC().Format(time.RFC3339)
+	s.LastAction = action
+	s.History = append(s.History, Change{Timestamp: s.Timestamp, Action: action, State: newState, By: by, Overrides: overrides})
+	if len(s.History) > MaxHistory {
+		s.History = s.History[len(s.History)-MaxHistory:]
+	}
+}
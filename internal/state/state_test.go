@@ -0,0 +1,70 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadWrite(t *testing.T) {
+	dir := t.TempDir()
+	if s, err := Read(dir); err == nil || s.State != Unknown {
+		t.Fatalf("missing file: %+v, %v", s, err)
+	}
+	in := Deployment{State: "DEPLOYED", Template: "vm", Environment: "prod", Secrets: []string{"db_password"}}
+	if err := Write(dir, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.State != "DEPLOYED" || out.Template != "vm" || out.Environment != "prod" || len(out.Secrets) != 1 {
+		t.Errorf("read back %+v", out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, File), []byte("state: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := Read(dir); err == nil || s.State != Unknown {
+		t.Errorf("garbled file: %+v, %v", s, err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 12, 0, 0, time.FixedZone("CEST", 2*3600))
+	by := Identity{User: "ops", Host: "bastion", SSO: "arn:aws:sts::1:assumed-role/ops"}
+	s := Deployment{State: "FAILED", Failure: &Failure{Phase: "apply"}, Health: &Health{Status: "UNHEALTHY"}}
+	s.Record("apply", "DEPLOYED", by, []string{"vm_count=3"}, now)
+	if s.State != "DEPLOYED" || s.LastAction != "apply" || s.Failure != nil || s.Health != nil {
+		t.Errorf("after Record: %+v", s)
+	}
+	if s.Timestamp != "2026-10-15T07:12:00Z" {
+		t.Errorf("timestamp %q, want UTC", s.Timestamp)
+	}
+	if len(s.History) != 1 || s.History[0].By != by || s.History[0].Overrides[0] != "vm_count=3" {
+		t.Errorf("history %+v", s.History)
+	}
+
+	s.Failure = &Failure{Phase: "init"}
+	s.Record("init", "FAILED", by, nil, now)
+	if s.Failure == nil {
+		t.Error("a FAILED state keeps the failure")
+	}
+	for i := range MaxHistory + 5 {
+		s.Record(fmt.Sprint(i), "DEPLOYED", by, nil, now)
+	}
+	if len(s.History) != MaxHistory || s.History[MaxHistory-1].Action != fmt.Sprint(MaxHistory+4) {
+		t.Errorf("history has %d entries, last %q", len(s.History), s.History[len(s.History)-1].Action)
+	}
+}
+
+func TestIdentityString(t *testing.T) {
+	if s := (Identity{User: "ops", Host: "h"}).String(); s != "ops@h" {
+		t.Errorf("got %q", s)
+	}
+	if s := (Identity{User: "ops", Host: "h", SSO: "sso"}).String(); s != "ops@h (sso)" {
+		t.Errorf("got %q", s)
+	}
+}
@@ -0,0 +1,190 @@
+// Package terraform builds terraform (or OpenTofu) invocations and reads what they print:
+// the version gate, `output -json` and the exit codes of `plan -detailed-exitcode`.
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"launcher/internal/execx"
+)
+
+// Step is one terraform invocation within an operation
+type Step struct {
+	Name  string   `yaml:"name"`            // shown in the UI and recorded as last_action
+	Args  []string `yaml:"args"`            // terraform arguments
+	State string   `yaml:"state,omitempty"` // launcher.state written when the step succeeds ("" keeps the current one)
+}
+
+// VarOverrides is "name=value" for each -var of the step
+func (s Step) VarOverrides() []string {
+	var vars []string
+	for i := 0; i+1 < len(s.Args); i++ {
+		if s.Args[i] == "-var" {
+			vars = append(vars, s.Args[i+1])
+		}
+	}
+	return vars
+}
+
+// Engine is the terraform (or OpenTofu) binary found at startup
+type Engine struct {
+	Binary  string // path to the binary, "" when none was found
+	Name    string // "terraform" or "tofu"
+	Version string
+	// Extra arguments per subcommand (terraform_args)
+	Args map[string][]string
+	// Missing binary or version outside required_version; apply refuses to run while set
+	Err error
+}
+
+// Command builds an invocation: the binary, with the configured extra arguments for the
+// subcommand inserted right after it
+func (e Engine) Command(r execx.Runner, args ...string) *exec.Cmd {
+	binary := e.Binary
+	if binary == "" {
+		binary = "terraform"
+	}
+	if extra := e.Args[args[0]]; len(extra) > 0 {
+		args = append(append([]string{args[0]}, extra...), args[1:]...)
+	}
+	return r.Command("", binary, args...)
+}
+
+// Version asks the binary at path for its version
+func Version(r execx.Runner, path string) (string, error) {
+	out, err := r.Output("", path, "version", "-json")
+	if err != nil {
+		return "", err
+	}
+	var v struct {
+		Version string `json:"terraform_version"` // tofu uses the same key
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		return "", err
+	}
+	return v.Version, nil
+}
+
+// Output is one value of `terraform output -json`
+type Output struct {
+	Value     json.RawMessage `json:"value"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+func ParseOutputs(data []byte) (map[string]Output, error) {
+	outputs := map[string]Output{}
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// String is a plain string output as-is, anything else as JSON
+func (o Output) String() string {
+	var s string
+	if err := json.Unmarshal(o.Value, &s); err == nil {
+		return s
+	}
+	return string(o.Value)
+}
+
+// PlanChanges reads the result of `plan -detailed-exitcode`: exit code 0 is no changes, 2 is
+// changes present, anything else (and any other error) is a failure
+func PlanChanges(err error) (changes bool, failure error) {
+	if err == nil {
+		return false, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return true, nil
+	}
+	return false, err
+}
+
+// ParseVersion reads "1.9.5", "v1.10.0-beta1" into numeric parts
+func ParseVersion(s string) ([]int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+func CompareVersions(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		x, y := 0, 0
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckVersionConstraint checks version against a terraform-style constraint list, e.g.
+// ">= 1.6, < 2.0" or "~> 1.9". An empty constraint allows any version.
+func CheckVersionConstraint(version, constraints string) error {
+	if strings.TrimSpace(constraints) == "" {
+		return nil
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+	for _, c := range strings.Split(constraints, ",") {
+		c = strings.TrimSpace(c)
+		op := strings.TrimRight(c, "0123456789.v ")
+		want, err := ParseVersion(strings.TrimSpace(c[len(op):]))
+		if err != nil {
+			return fmt.Errorf("invalid required_version %q: %w", constraints, err)
+		}
+		cmp := CompareVersions(v, want)
+		var ok bool
+		switch strings.TrimSpace(op) {
+		case "", "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case "~>":
+			// Only the rightmost given part may increase
+			upper := append([]int{}, want[:max(len(want)-1, 1)]...)
+			upper[len(upper)-1]++
+			ok = cmp >= 0 && CompareVersions(v, upper) < 0
+		default:
+			return fmt.Errorf("invalid required_version %q: unknown operator %q", constraints, op)
+		}
+		if !ok {
+			return fmt.Errorf("does not satisfy required_version %q", constraints)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package terraform
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+type fakeRunner struct {
+	out  string
+	err  error
+	name string
+	args []string
+}
+
+func (f *fakeRunner) Output(dir, name string, args ...string) ([]byte, error) {
+	f.name, f.args = name, args
+	return []byte(f.out), f.err
+}
+
+func (f *fakeRunner) Command(dir, name string, args ...string) *exec.Cmd {
+	f.name, f.args = name, args
+	return exec.Command(name, args...)
+}
+
+func TestCheckVersionConstraint(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		ok                  bool
+	}{
+		{"1.9.5", "", true},
+		{"1.9.5", ">= 1.6, < 2.0", true},
+		{"2.0.0", ">= 1.6, < 2.0", false},
+		{"1.5.7", ">= 1.6", false},
+		{"1.9.8", "~> 1.9", true},
+		{"2.0.0", "~> 1.9", false},
+		{"1.9.8", "~> 1.9.5", true},
+		{"1.10.0", "~> 1.9.5", false},
+		{"v1.10.0-beta1", "= 1.10.0", true},
+		{"1.8.0", "!= 1.8.0", false},
+	}
+	for _, tt := range tests {
+		err := CheckVersionConstraint(tt.version, tt.constraint)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckVersionConstraint(%q, %q) = %v, want ok=%v", tt.version, tt.constraint, err, tt.ok)
+		}
+	}
+	if err := CheckVersionConstraint("1.9.0", "=> 1.6"); err == nil {
+		t.Error("unknown operator: want an error")
+	}
+}
+
+func TestVarOverrides(t *testing.T) {
+	s := Step{Name: "apply", Args: []string{"apply", "-auto-approve", "-var", "vm_count=3", "-var", "zone=dmz", "-var"}}
+	if got := s.VarOverrides(); !slices.Equal(got, []string{"vm_count=3", "zone=dmz"}) {
+		t.Errorf("VarOverrides = %q", got)
+	}
+}
+
+func TestEngineCommand(t *testing.T) {
+	r := &fakeRunner{}
+	e := Engine{Binary: "/usr/bin/tofu", Args: map[string][]string{"apply": {"-parallelism=4"}}}
+	e.Command(r, "apply", "-auto-approve")
+	if r.name != "/usr/bin/tofu" || !slices.Equal(r.args, []string{"apply", "-parallelism=4", "-auto-approve"}) {
+		t.Errorf("ran %s %q", r.name, r.args)
+	}
+	Engine{}.Command(r, "init")
+	if r.name != "terraform" || !slices.Equal(r.args, []string{"init"}) {
+		t.Errorf("ran %s %q", r.name, r.args)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	r := &fakeRunner{out: `{"terraform_version":"1.9.5","platform":"linux_amd64"}`}
+	if v, err := Version(r, "terraform"); err != nil || v != "1.9.5" {
+		t.Errorf("Version = %q, %v", v, err)
+	}
+	if _, err := Version(&fakeRunner{err: errors.New("not found")}, "terraform"); err == nil {
+		t.Error("Version: want the runner's error")
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	outs, err := ParseOutputs([]byte(`{"name":{"value":"web"},"ips":{"value":["10.0.0.1"]},"pw":{"value":"x","sensitive":true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outs["name"].String() != "web" || outs["ips"].String() != `["10.0.0.1"]` || !outs["pw"].Sensitive {
+		t.Errorf("outputs %+v", outs)
+	}
+}
+
+func TestPlanChanges(t *testing.T) {
+	if changes, err := PlanChanges(nil); changes || err != nil {
+		t.Errorf("exit 0: %v, %v", changes, err)
+	}
+	if changes, err := PlanChanges(exec.Command("sh", "-c", "exit 2").Run()); !changes || err != nil {
+		t.Errorf("exit 2: %v, %v", changes, err)
+	}
+	if changes, err := PlanChanges(exec.Command("sh", "-c", "exit 1").Run()); changes || err == nil {
+		t.Errorf("exit 1: %v, %v", changes, err)
+	}
+}
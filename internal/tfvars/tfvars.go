@@ -0,0 +1,82 @@
+// Package tfvars reads and rewrites terraform.tfvars files as flat `name = value` lines.
+package tfvars
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Parse reads the assignments of a tfvars file, values as written (quotes and brackets
+// kept); blank lines, comments and lines without "=" are skipped
+func Parse(r io.Reader) (map[string]string, error) {
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		vars[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return vars, scanner.Err()
+}
+
+func Load(filename string) (map[string]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// ReplaceLines is a copy of lines with the assignments of the updated variables replaced;
+// variables missing from lines are not added
+func ReplaceLines(lines []string, updates map[string]string) []string {
+	out, _ := replace(lines, updates)
+	return out
+}
+
+// SetLines is ReplaceLines, with the variables missing from lines appended in name order
+// (before any trailing blank lines)
+func SetLines(lines []string, updates map[string]string) []string {
+	out, found := replace(lines, updates)
+	var missing []string
+	for key := range updates {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	slices.Sort(missing)
+	end := len(out)
+	for end > 0 && strings.TrimSpace(out[end-1]) == "" {
+		end--
+	}
+	added := make([]string, len(missing))
+	for i, key := range missing {
+		added[i] = fmt.Sprintf("%s = %s", key, updates[key])
+	}
+	return slices.Insert(out, end, added...)
+}
+
+func replace(lines []string, updates map[string]string) ([]string, map[string]bool) {
+	out := slices.Clone(lines)
+	found := map[string]bool{}
+	for i, line := range out {
+		for key, newval := range updates {
+			if strings.HasPrefix(strings.TrimSpace(line), key+" ") || strings.HasPrefix(strings.TrimSpace(line), key+"=") {
+				out[i] = fmt.Sprintf("%s = %s", key, newval)
+				found[key] = true
+			}
+		}
+	}
+	return out, found
+}
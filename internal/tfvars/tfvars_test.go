@@ -0,0 +1,51 @@
+package tfvars
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	in := `# comment
+app_name = "web"
+
+vm_count=3
+tags = ["a", "b"]
+not an assignment
+`
+	vars, err := Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"app_name": `"web"`, "vm_count": "3", "tags": `["a", "b"]`}
+	if len(vars) != len(want) {
+		t.Fatalf("got %v, want %v", vars, want)
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+}
+
+func TestReplaceLines(t *testing.T) {
+	lines := []string{`# header`, `app_name = "web"`, `vm_count=3`, `vm_count_max = 5`}
+	got := ReplaceLines(lines, map[string]string{"vm_count": "4", "missing": "1"})
+	want := []string{`# header`, `app_name = "web"`, `vm_count = 4`, `vm_count_max = 5`}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if lines[2] != "vm_count=3" {
+		t.Errorf("input modified: %q", lines[2])
+	}
+}
+
+func TestSetLinesAddsMissingKeys(t *testing.T) {
+	lines := []string{`app_name = "web"`, `vm_count = 3`, ``}
+	got := SetLines(lines, map[string]string{"vm_count": "4", "vm_numa": "true", "vm_cpu_type": `"host"`})
+	want := []string{`app_name = "web"`, `vm_count = 4`, `vm_cpu_type = "host"`, `vm_numa = true`, ``}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
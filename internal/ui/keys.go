@@ -0,0 +1,102 @@
+// Package ui holds the presentation helpers shared by the launcher's scenes: key binding
+// labels and help entries, and short text formatting.
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+var keyLabels = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "Space",
+	"enter": "Enter", "esc": "Esc", "tab": "Tab", "shift+tab": "⇧Tab",
+	"pgup": "PgUp", "pgdown": "PgDn", "backspace": "⌫",
+}
+
+// KeyLabel is how a key is shown in help: "↑", "Enter", "⇧A", "Ctrl+X"
+func KeyLabel(k string) string {
+	if l, ok := keyLabels[k]; ok {
+		return l
+	}
+	r := []rune(k)
+	switch {
+	case len(r) == 1 && unicode.IsUpper(r[0]):
+		return "⇧" + k
+	case len(r) == 1:
+		return strings.ToUpper(k)
+	case strings.HasPrefix(k, "f") && strings.Trim(k[1:], "0123456789") == "":
+		return strings.ToUpper(k)
+	case strings.HasPrefix(k, "ctrl+"):
+		return "Ctrl+" + strings.ToUpper(strings.TrimPrefix(k, "ctrl+"))
+	case strings.HasPrefix(k, "alt+"):
+		return "Alt+" + strings.ToUpper(strings.TrimPrefix(k, "alt+"))
+	}
+	return k
+}
+
+// HelpKey is the "[...]" label of a key list: "[C]" for c/C, "[Enter/E]" for enter/e;
+// shift is only shown when the lower case isn't bound too
+func HelpKey(ks []string) string {
+	var labels []string
+	for _, k := range ks {
+		if r := []rune(k); len(r) == 1 && unicode.IsUpper(r[0]) && slices.Contains(ks, strings.ToLower(k)) {
+			continue
+		}
+		if l := KeyLabel(k); !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return "[" + strings.Join(labels, "/") + "]"
+}
+
+// Bind is a binding whose help shows its keys, e.g. Bind("Create", "c", "C")
+func Bind(desc string, ks ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(ks...), key.WithHelp(HelpKey(ks), desc))
+}
+
+// TableKeys is a table key map whose row movement follows the scene's up/down bindings
+func TableKeys(up, down key.Binding) table.KeyMap {
+	km := table.DefaultKeyMap()
+	km.LineUp, km.LineDown = up, down
+	return km
+}
+
+// PairHelp is a display-only binding for a pair such as up/down: "[↑/↓] Field"
+func PairHelp(a, b key.Binding, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(append(a.Keys(), b.Keys()...)...),
+		key.WithHelp(fmt.Sprintf("[%s/%s]", KeyLabel(a.Keys()[0]), KeyLabel(b.Keys()[0])), desc))
+}
+
+// GroupHelp is a display-only binding listing the keys of several bindings: "[1/2/Z/0] Filter"
+func GroupHelp(desc string, bs ...key.Binding) key.Binding {
+	var ks []string
+	for _, b := range bs {
+		ks = append(ks, b.Keys()...)
+	}
+	return key.NewBinding(key.WithKeys(ks...), key.WithHelp(HelpKey(ks), desc))
+}
+
+// NamedBindings returns a scene key map's bindings (scene is a pointer to a struct of
+// key.Binding) by their keys.yaml name, in declaration order
+func NamedBindings(scene any) ([]string, map[string]*key.Binding) {
+	v := reflect.ValueOf(scene).Elem()
+	var names []string
+	out := map[string]*key.Binding{}
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("yaml")
+		names = append(names, name)
+		out[name] = v.Field(i).Addr().Interface().(*key.Binding)
+	}
+	return names, out
+}
+
+// HintHelp is a footer hint for input that isn't a binding, like "[Type] Search"
+func HintHelp(label, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(label), key.WithHelp("["+label+"]", desc))
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Truncate cuts s to n runes, ending with "…" when something was cut
+func Truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// PadRight pads s with spaces to n bytes
+func PadRight(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return s + strings.Repeat(" ", n-len(s))
+}
+
+// Plural is "1 deployment", "3 deployments"
+func Plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// FormatBytes is a size in its largest unit: 512M, 16G, 1.5T
+func FormatBytes(b int64) string {
+	switch {
+	case b >= 1<<40:
+		return fmt.Sprintf("%.1fT", float64(b)/(1<<40))
+	case b >= 1<<30:
+		return fmt.Sprintf("%.0fG", float64(b)/(1<<30))
+	default:
+		return fmt.Sprintf("%.0fM", float64(b)/(1<<20))
+	}
+}
+
+// RoundDuration keeps the largest unit only: 45s, 12m, 5h, 3d
+func RoundDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
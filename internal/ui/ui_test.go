@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestKeyLabel(t *testing.T) {
+	for k, want := range map[string]string{
+		"up": "↑", "enter": "Enter", "c": "C", "A": "⇧A", "f5": "F5", "ctrl+x": "Ctrl+X", "alt+enter": "Alt+ENTER", "?": "?",
+	} {
+		if got := KeyLabel(k); got != want {
+			t.Errorf("KeyLabel(%q) = %q, want %q", k, got, want)
+		}
+	}
+}
+
+func TestHelpKey(t *testing.T) {
+	for _, tt := range []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"c", "C"}, "[C]"},
+		{[]string{"A"}, "[⇧A]"},
+		{[]string{"enter", "e"}, "[Enter/E]"},
+		{[]string{"esc", "q"}, "[Esc/Q]"},
+	} {
+		if got := HelpKey(tt.keys); got != tt.want {
+			t.Errorf("HelpKey(%q) = %q, want %q", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestBindings(t *testing.T) {
+	up, down := Bind("Up", "up", "k"), Bind("Down", "down", "j")
+	if h := PairHelp(up, down, "Move").Help(); h.Key != "[↑/↓]" || h.Desc != "Move" {
+		t.Errorf("PairHelp = %+v", h)
+	}
+	g := GroupHelp("Filter", Bind("", "1"), Bind("", "2"))
+	if g.Help().Key != "[1/2]" || !slices.Equal(g.Keys(), []string{"1", "2"}) {
+		t.Errorf("GroupHelp = %+v %q", g.Help(), g.Keys())
+	}
+	if km := TableKeys(up, down); !slices.Equal(km.LineUp.Keys(), up.Keys()) {
+		t.Errorf("TableKeys LineUp %q", km.LineUp.Keys())
+	}
+	if h := HintHelp("Type", "Search").Help(); h.Key != "[Type]" {
+		t.Errorf("HintHelp = %+v", h)
+	}
+}
+
+func TestNamedBindings(t *testing.T) {
+	scene := struct {
+		Create key.Binding `yaml:"create"`
+		Quit   key.Binding `yaml:"quit"`
+	}{Bind("Create", "c"), Bind("Quit", "q")}
+	names, byName := NamedBindings(&scene)
+	if !slices.Equal(names, []string{"create", "quit"}) {
+		t.Errorf("names %q", names)
+	}
+	byName["quit"].SetKeys("x")
+	if !slices.Equal(scene.Quit.Keys(), []string{"x"}) {
+		t.Error("bindings are not addressable through the map")
+	}
+}
+
+func TestText(t *testing.T) {
+	if got := Truncate("proxmox_web_dmz", 8); got != "proxmox…" {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := Truncate("short", 8); got != "short" {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := PadRight("ab", 4); got != "ab  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if Plural(1, "job") != "1 job" || Plural(3, "job") != "3 jobs" {
+		t.Error("Plural")
+	}
+	for b, want := range map[int64]string{512 << 20: "512M", 16 << 30: "16G", 3 << 39: "1.5T"} {
+		if got := FormatBytes(b); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", b, got, want)
+		}
+	}
+	for d, want := range map[time.Duration]string{45 * time.Second: "45s", 12 * time.Minute: "12m", 5 * time.Hour: "5h", 72 * time.Hour: "3d"} {
+		if got := RoundDuration(d); got != want {
+			t.Errorf("RoundDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
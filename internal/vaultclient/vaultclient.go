@@ -0,0 +1,134 @@
+// Package vaultclient wraps the Vault calls the launcher makes: AppRole and token login,
+// KV v2 reads and writes, listing and the Proxmox API credentials stored per cluster.
+// Everything past the login goes through Logical so it can be faked.
+package vaultclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// Logical is the part of *vault.Logical the launcher uses
+type Logical interface {
+	Read(path string) (*vault.Secret, error)
+	Write(path string, data map[string]interface{}) (*vault.Secret, error)
+	List(path string) (*vault.Secret, error)
+}
+
+// KV v2 mount holding one secret per cluster, and the fields of each
+const (
+	CredsMount     = "proxmox_api_keys"
+	URLField       = "proxmox_api_url"
+	TokenIDField   = "proxmox_api_token_id"
+	TokenSecField  = "proxmox_api_token_secret"
+	defaultAddress = "http://127.0.0.1:8200"
+)
+
+// New returns an unauthenticated client for $VAULT_ADDR (default http://127.0.0.1:8200)
+func New() (*vault.Client, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = defaultAddress
+	}
+	cfg := vault.DefaultConfig()
+	cfg.Address = addr
+	return vault.NewClient(cfg)
+}
+
+// LoginAppRole logs in with an AppRole and returns the client token
+func LoginAppRole(l Logical, roleID, secretID string) (string, error) {
+	secret, err := l.Write("auth/approle/login", map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil || secret == nil || secret.Auth == nil {
+		return "", fmt.Errorf("vault appRole login failed: %v", err)
+	}
+	return secret.Auth.ClientToken, nil
+}
+
+// Token is $VAULT_TOKEN, or the token `vault login` leaves in ~/.vault-token
+func Token() string {
+	if t := os.Getenv("VAULT_TOKEN"); t != "" {
+		return t
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Data returns a secret's fields, unwrapping the KV v2 "data" envelope; nil for an empty secret
+func Data(s *vault.Secret) map[string]interface{} {
+	if s == nil || s.Data == nil {
+		return nil
+	}
+	if v2, ok := s.Data["data"].(map[string]interface{}); ok {
+		return v2
+	}
+	return s.Data
+}
+
+// ReadKV reads a KV secret's fields; a missing secret is an error
+func ReadKV(l Logical, path string) (map[string]interface{}, error) {
+	s, err := l.Read(path)
+	data := Data(s)
+	if err != nil || data == nil {
+		return nil, fmt.Errorf("vault read failed for %s: %v", path, err)
+	}
+	return data, nil
+}
+
+// WriteKV writes fields as a KV v2 secret
+func WriteKV(l Logical, path string, data map[string]interface{}) error {
+	if _, err := l.Write(path, map[string]interface{}{"data": data}); err != nil {
+		return fmt.Errorf("vault write failed for %s: %w", path, err)
+	}
+	return nil
+}
+
+// ListKeys lists the secrets (not sub-folders) under path, sorted
+func ListKeys(l Logical, path string) ([]string, error) {
+	s, err := l.List(path)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || s.Data == nil {
+		return nil, fmt.Errorf("no secrets under %s", path)
+	}
+	raw, _ := s.Data["keys"].([]interface{})
+	var keys []string
+	for _, k := range raw {
+		if name, ok := k.(string); ok && !strings.HasSuffix(name, "/") {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// CredsPath is the KV v2 data path of a cluster's Proxmox credentials
+func CredsPath(cluster string) string {
+	return CredsMount + "/data/" + cluster
+}
+
+// ProxmoxCreds picks the API URL, token ID and token secret out of a cluster's secret
+func ProxmoxCreds(data map[string]interface{}) (apiURL, tokenID, tokenSecret string, err error) {
+	apiURL, _ = data[URLField].(string)
+	tokenID, _ = data[TokenIDField].(string)
+	tokenSecret, _ = data[TokenSecField].(string)
+	if apiURL == "" || tokenID == "" || tokenSecret == "" {
+		return "", "", "", fmt.Errorf("missing fields")
+	}
+	return apiURL, tokenID, tokenSecret, nil
+}
@@ -0,0 +1,121 @@
+package vaultclient
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// fakeLogical is an in-memory KV store
+type fakeLogical struct {
+	secrets map[string]*vault.Secret
+	writes  map[string]map[string]interface{}
+	err     error
+}
+
+func (f *fakeLogical) Read(path string) (*vault.Secret, error) { return f.secrets[path], f.err }
+
+func (f *fakeLogical) Write(path string, data map[string]interface{}) (*vault.Secret, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.writes == nil {
+		f.writes = map[string]map[string]interface{}{}
+	}
+	f.writes[path] = data
+	return f.secrets[path], nil
+}
+
+func (f *fakeLogical) List(path string) (*vault.Secret, error) { return f.secrets[path], f.err }
+
+func TestProxmoxCredsV2(t *testing.T) {
+	l := &fakeLogical{secrets: map[string]*vault.Secret{
+		CredsPath("pve1"): {Data: map[string]interface{}{"data": map[string]interface{}{
+			URLField: "https://pve1:8006/api2/json", TokenIDField: "root@pam!tf", TokenSecField: "s3cret",
+		}}},
+		CredsPath("pve2"): {Data: map[string]interface{}{"data": map[string]interface{}{URLField: "https://pve2"}}},
+	}}
+	data, err := ReadKV(l, CredsPath("pve1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, id, secret, err := ProxmoxCreds(data)
+	if err != nil || url != "https://pve1:8006/api2/json" || id != "root@pam!tf" || secret != "s3cret" {
+		t.Errorf("ProxmoxCreds = %q %q %q %v", url, id, secret, err)
+	}
+	data, _ = ReadKV(l, CredsPath("pve2"))
+	if _, _, _, err := ProxmoxCreds(data); err == nil {
+		t.Error("incomplete secret: want an error")
+	}
+	if _, err := ReadKV(l, CredsPath("missing")); err == nil {
+		t.Error("missing secret: want an error")
+	}
+}
+
+func TestDataV1(t *testing.T) {
+	s := &vault.Secret{Data: map[string]interface{}{"k": "v"}}
+	if Data(s)["k"] != "v" {
+		t.Errorf("Data = %v", Data(s))
+	}
+	if Data(nil) != nil {
+		t.Error("Data(nil) != nil")
+	}
+}
+
+func TestWriteKV(t *testing.T) {
+	l := &fakeLogical{}
+	if err := WriteKV(l, "secret/data/web", map[string]interface{}{"pw": "x"}); err != nil {
+		t.Fatal(err)
+	}
+	inner, _ := l.writes["secret/data/web"]["data"].(map[string]interface{})
+	if inner["pw"] != "x" {
+		t.Errorf("wrote %v", l.writes)
+	}
+	l.err = errors.New("permission denied")
+	if err := WriteKV(l, "secret/data/web", nil); err == nil {
+		t.Error("want the write error")
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	l := &fakeLogical{secrets: map[string]*vault.Secret{
+		"proxmox_api_keys/metadata": {Data: map[string]interface{}{"keys": []interface{}{"pve2", "old/", "pve1"}}},
+	}}
+	keys, err := ListKeys(l, "proxmox_api_keys/metadata")
+	if err != nil || !slices.Equal(keys, []string{"pve1", "pve2"}) {
+		t.Errorf("ListKeys = %q, %v", keys, err)
+	}
+	if _, err := ListKeys(l, "empty/metadata"); err == nil {
+		t.Error("nothing listed: want an error")
+	}
+}
+
+func TestLoginAppRole(t *testing.T) {
+	l := &fakeLogical{secrets: map[string]*vault.Secret{
+		"auth/approle/login": {Auth: &vault.SecretAuth{ClientToken: "hvs.token"}},
+	}}
+	token, err := LoginAppRole(l, "role", "secret")
+	if err != nil || token != "hvs.token" {
+		t.Errorf("LoginAppRole = %q, %v", token, err)
+	}
+	if l.writes["auth/approle/login"]["role_id"] != "role" {
+		t.Errorf("login body %v", l.writes["auth/approle/login"])
+	}
+	if _, err := LoginAppRole(&fakeLogical{}, "role", "secret"); err == nil {
+		t.Error("no auth in the answer: want an error")
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("HOME", t.TempDir())
+	if Token() != "" {
+		t.Errorf("Token = %q, want none", Token())
+	}
+	t.Setenv("VAULT_TOKEN", "hvs.env")
+	if Token() != "hvs.env" {
+		t.Errorf("Token = %q", Token())
+	}
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Job queue ---
+
+type jobStatus int
+
+const (
+	jobQueued jobStatus = iota
+	jobRunning
+	jobSucceeded
+	jobFailed
+	jobCancelled
+)
+
+func (s jobStatus) String() string {
+	return [...]string{"queued", "running", "succeeded", "failed", "cancelled"}[s]
+}
+
+const (
+	defaultMaxJobs = 2
+	jobOutputLimit = 5000
+)
+
+// job is one queued terraform operation and everything it printed
+type job struct {
+	ID       int
+	Op       *tfOperation
+	Status   jobStatus
+	Output   []string
+	Err      string
+	Queued   time.Time
+	Finished time.Time
+}
+
+func maxConcurrentJobs(cfg Config) int {
+	if cfg.MaxConcurrentJobs > 0 {
+		return cfg.MaxConcurrentJobs
+	}
+	return defaultMaxJobs
+}
+
+func (m model) jobByID(id int) *job {
+	for _, j := range m.jobs {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m model) runningJobs() []*job {
+	var out []*job
+	for _, j := range m.jobs {
+		if j.Status == jobRunning {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+func (m model) queuedJobCount() int {
+	n := 0
+	for _, j := range m.jobs {
+		if j.Status == jobQueued {
+			n++
+		}
+	}
+	return n
+}
+
+// enqueueJob adds op to the job queue and starts it if a slot is free
+func enqueueJob(m model, op *tfOperation) (model, tea.Cmd) {
+	m.nextJobID++
+	op.jobID = m.nextJobID
+	m.jobs = append(m.jobs, &job{ID: op.jobID, Op: op, Status: jobQueued, Queued: time.Now()})
+	logger.Info("job queued", "component", "jobs", "job", op.jobID, "label", op.Label)
+	recordAudit(op.action(), op.Dir, op.Label, "queued")
+	return startQueuedJobs(m)
+}
+
+// startQueuedJobs starts queued jobs in order while fewer than the configured maximum are running.
+// Jobs for a deployment that already has a running job wait, since they would contend for the state lock;
+// a job whose deployment is locked by another launcher fails right away.
+func startQueuedJobs(m model) (model, tea.Cmd) {
+	running := m.runningJobs()
+	busyDirs := map[string]bool{}
+	for _, j := range running {
+		busyDirs[j.Op.Dir] = true
+	}
+	var cmds []tea.Cmd
+	if len(running) == 0 {
+		cmds = append(cmds, m.opSpinner.Tick)
+	}
+	for _, j := range m.jobs {
+		if len(running) >= maxConcurrentJobs(m.cfg) {
+			break
+		}
+		if j.Status != jobQueued || busyDirs[j.Op.Dir] {
+			continue
+		}
+		if err := acquireDeployLock(j.Op.Dir, j.Op.Label); err != nil {
+			j.Status = jobRunning // until the failure below is handled
+			cmds = append(cmds, finishJob(j.ID, false, err.Error()))
+			continue
+		}
+		j.Status = jobRunning
+		j.Op.started = time.Now()
+		busyDirs[j.Op.Dir] = true
+		running = append(running, j)
+		cmds = append(cmds, runStepCmd(j.Op))
+	}
+	if len(cmds) == 1 && len(running) == 0 {
+		return m, nil
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func completeJob(j *job) tea.Cmd {
+	return func() tea.Msg {
+		if j.Op.OnSuccess != nil {
+			if err := j.Op.OnSuccess(); err != nil {
+				return BusyFinishedMsg{JobID: j.ID, Success: false, ErrorMessage: "Terraform succeeded but the follow-up step failed: " + err.Error()}
+			}
+		}
+		return BusyFinishedMsg{JobID: j.ID, Success: true}
+	}
+}
+
+func finishJob(id int, success bool, errMsg string) tea.Cmd {
+	return func() tea.Msg {
+		return BusyFinishedMsg{JobID: id, Success: success, ErrorMessage: errMsg}
+	}
+}
+
+// handleJobMsg processes job messages regardless of the current scene.
+// The bool result reports whether msg was consumed.
+func handleJobMsg(m model, msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tfStartedMsg:
+		j := m.jobByID(msg.jobID)
+		if j == nil {
+			return m, nil, true
+		}
+		j.Op.proc = msg.proc
+		var cmd tea.Cmd
+		if j.Op.cancelling {
+			cmd = interruptJob(j, msg.proc, &m)
+		}
+		return m, tea.Batch(cmd, waitForOpEvent(j.Op.events)), true
+	case cancelEscalateMsg:
+		return handleCancelEscalate(m, msg), nil, true
+	case tfLineMsg:
+		j := m.jobByID(msg.jobID)
+		if j == nil {
+			return m, nil, true
+		}
+		j.Op.recordLine(msg.line)
+		j.Output = append(j.Output, msg.line)
+		if len(j.Output) > jobOutputLimit {
+			j.Output = j.Output[len(j.Output)-jobOutputLimit:]
+		}
+		return m, waitForOpEvent(j.Op.events), true
+	case tfStepDoneMsg:
+		j := m.jobByID(msg.jobID)
+		if j == nil {
+			return m, nil, true
+		}
+		op := j.Op
+		op.proc = nil
+		step := op.Steps[op.step]
+		if msg.err != nil && op.cancelling {
+			return m, cancelJobAt(j, step, fmt.Sprintf("terraform %s cancelled", step.Name)), true
+		}
+		if msg.err != nil {
+			if err := setDeploymentStateWithVars(op.Dir, "FAILED", step.Name, step.VarOverrides()); err != nil {
+				logger.Error("could not record FAILED state", "component", "jobs", "job", j.ID, "error", err.Error())
+			} else if err := recordFailure(op, msg.err); err != nil {
+				logger.Error("could not record the failed step", "component", "jobs", "job", j.ID, "error", err.Error())
+			}
+			return m, finishJob(j.ID, false, fmt.Sprintf("terraform %s failed: %v\n%s", step.Name, msg.err, strings.Join(op.output, "\n"))), true
+		}
+		refresh := tea.Cmd(nil)
+		if step.State != "" {
+			if err := setDeploymentStateWithVars(op.Dir, step.State, step.Name, step.VarOverrides()); err != nil {
+				return m, finishJob(j.ID, false, fmt.Sprintf("Failed to update launcher.state (%s): %v", step.Name, err)), true
+			}
+			refresh = refreshDeploymentCmd(op.Dir)
+		}
+		op.step++
+		if op.step < len(op.Steps) {
+			if op.cancelling {
+				// the step finished before the interrupt reached it; don't start the next one
+				next := op.Steps[op.step]
+				return m, tea.Batch(cancelJobAt(j, next, fmt.Sprintf("cancelled before terraform %s", next.Name)), refresh), true
+			}
+			return m, tea.Batch(runStepCmd(op), refresh), true
+		}
+		// every step succeeded, a cancel that came too late doesn't change that
+		op.cancelling = false
+		return m, completeJob(j), true
+	case BusyFinishedMsg:
+		j := m.jobByID(msg.JobID)
+		if j == nil {
+			return m, nil, true
+		}
+		j.Finished = time.Now()
+		releaseDeployLock(j.Op.Dir)
+		text := j.Op.SuccessMessage
+		if j.Op.cancelling && !msg.Success {
+			j.Status = jobCancelled
+			text = fmt.Sprintf("Job #%d cancelled; %s marked CANCELLED", j.ID, filepath.Base(j.Op.Dir))
+		} else if msg.Success {
+			j.Status = jobSucceeded
+		} else {
+			j.Status = jobFailed
+			j.Err = msg.ErrorMessage
+			text = fmt.Sprintf("Job #%d failed: %s", j.ID, msg.ErrorMessage)
+			if l, ok := parseStateLock(j.Output); ok {
+				l.Dir = j.Op.Dir
+				m.lockDialog = &l
+				text = fmt.Sprintf("Job #%d failed: %s", j.ID, l.summary())
+			}
+		}
+		logger.Info("job finished", "component", "jobs", "job", j.ID, "label", j.Op.Label, "status", j.Status.String())
+		if changesState(j.Op) {
+			// the drift check predates this job; the refresh below redraws the badge
+			delete(m.drift, j.Op.Dir)
+		}
+		recordAudit(j.Op.action(), j.Op.Dir, strings.SplitN(j.Err, "\n", 2)[0], j.Status.String())
+		if m.currentScene == sceneEditForm && filepath.Dir(m.editFormPath) == j.Op.Dir {
+			m.editStatus = text
+		} else {
+			m.statusMessage = text
+		}
+		m, startCmd := startQueuedJobs(m)
+		return m, tea.Batch(startCmd, refreshDeploymentCmd(j.Op.Dir), notifyJobCmd(m.cfg.Notifications, j, m.focus), commitJobCmd(j)), true
+	case gitCommittedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("git commit of %s failed: %v", filepath.Base(msg.dir), msg.err)
+		}
+		return m, nil, true
+	case spinner.TickMsg:
+		if msg.ID != m.opSpinner.ID() {
+			return m, nil, false
+		}
+		if len(m.runningJobs()) == 0 {
+			return m, nil, true
+		}
+		var cmd tea.Cmd
+		m.opSpinner, cmd = m.opSpinner.Update(msg)
+		return m, cmd, true
+	}
+	return m, nil, false
+}
+
+// Marks the deployment CANCELLED at step and ends the job
+func cancelJobAt(j *job, step tfStep, message string) tea.Cmd {
+	if err := setDeploymentStateWithVars(j.Op.Dir, "CANCELLED", step.Name, step.VarOverrides()); err != nil {
+		logger.Error("could not record CANCELLED state", "component", "jobs", "job", j.ID, "error", err.Error())
+	}
+	return finishJob(j.ID, false, message)
+}
+
+// Commits the deployment directory after a job (identity.git_commit)
+func commitJobCmd(j *job) tea.Cmd {
+	if !identityConfig.GitCommit {
+		return nil
+	}
+	dir, action, result := j.Op.Dir, j.Op.action(), j.Status.String()
+	return func() tea.Msg {
+		return gitCommittedMsg{dir: dir, err: commitDeploymentChange(dir, action, result)}
+	}
+}
+
+// Result of commitJobCmd; a failure goes to the status line
+type gitCommittedMsg struct {
+	dir string
+	err error
+}
+
+// Running job to show on the launcher: the selected deployment's, else the oldest
+func (m model) spotlightJob() *job {
+	running := m.runningJobs()
+	if len(running) == 0 {
+		return nil
+	}
+	if i := m.deployTable.Cursor(); i >= 0 && i < len(m.deployments) {
+		for _, j := range running {
+			if j.Op.Dir == m.deployments[i].Path {
+				return j
+			}
+		}
+	}
+	return running[0]
+}
+
+func jobsSummary(m model) string {
+	running, queued := len(m.runningJobs()), m.queuedJobCount()
+	if running <= 1 && queued == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d job(s) running, %d queued — [⇧J] Jobs", running, queued)
+}
+
+// --- Jobs scene ---
+
+func openJobs(m model) model {
+	m.jobsTable = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "#", Width: 4},
+			{Title: "Operation", Width: 48},
+			{Title: "Status", Width: 10},
+			{Title: "Step", Width: 12},
+			{Title: "Elapsed", Width: 10},
+			{Title: "Resources", Width: 24},
+		}),
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeys(keys.Jobs.Up, keys.Jobs.Down)),
+		table.WithStyles(tableStyles()),
+		table.WithHeight(10),
+	)
+	m.jobsTable.SetRows(jobRows(m))
+	if len(m.jobs) > 0 {
+		m.jobsTable.SetCursor(len(m.jobs) - 1)
+	}
+	return m.pushScene(sceneJobs)
+}
+
+// Full output of the job under the cursor in the pager; it keeps growing while the job runs
+func openJobOutput(m model) model {
+	i := m.jobsTable.Cursor()
+	if i < 0 || i >= len(m.jobs) {
+		return m
+	}
+	m.outputJobID = m.jobs[i].ID
+	m.outputPager = newPager(uiWidth-4, uiHeight-14)
+	m.outputPager.setLines(jobPagerLines(m.jobs[i]))
+	return m.pushScene(sceneOutput)
+}
+
+func jobPagerLines(j *job) []pagerLine {
+	lines := make([]pagerLine, len(j.Output))
+	for i, l := range j.Output {
+		lines[i] = pagerLine{Text: l, Style: normalStyle}
+		if isTerraformError(l) {
+			lines[i].Style, lines[i].Error = logErrorStyle, true
+		}
+	}
+	return lines
+}
+
+func updateJobOutput(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.outputPager.searching() && key.Matches(keyMsg, keys.Jobs.Back) {
+		return m.popScene(), nil
+	}
+	if j := m.jobByID(m.outputJobID); j != nil {
+		m.outputPager.setLines(jobPagerLines(j))
+	}
+	var cmd tea.Cmd
+	m.outputPager, cmd = m.outputPager.update(msg)
+	return m, cmd
+}
+
+func viewJobOutput(m model) (body, tooltip string) {
+	j := m.jobByID(m.outputJobID)
+	if j == nil {
+		return "", ""
+	}
+	// Lines are taken again on every render so a running job's output stays live
+	p := m.outputPager
+	p.setLines(jobPagerLines(j))
+	body += tooltipStyle.Render(fmt.Sprintf("Job #%d %s — %s", j.ID, j.Op.Label, j.Status))
+	body += "\n" + p.view() + "\n"
+	tooltip = tooltipStyle.Render(p.statusLine())
+	return body, tooltip
+}
+
+func jobElapsed(j *job) time.Duration {
+	switch j.Status {
+	case jobQueued:
+		return 0
+	case jobRunning:
+		return time.Since(j.Op.started).Round(time.Second)
+	}
+	return j.Finished.Sub(j.Op.started).Round(time.Second)
+}
+
+func jobRows(m model) []table.Row {
+	rows := make([]table.Row, len(m.jobs))
+	for i, j := range m.jobs {
+		op := j.Op
+		step := ""
+		if j.Status == jobRunning {
+			step = fmt.Sprintf("%s %d/%d", op.Steps[op.step].Name, op.step+1, len(op.Steps))
+		}
+		rows[i] = table.Row{
+			fmt.Sprint(j.ID), op.Label, j.Status.String(), step, jobElapsed(j).String(),
+			fmt.Sprintf("+%d ~%d -%d", op.added, op.changed, op.destroyed),
+		}
+	}
+	return rows
+}
+
+func updateJobs(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Jobs.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.Jobs.ScrollUp):
+			m.jobsScroll += 10
+			return m, nil
+		case key.Matches(keyMsg, keys.Jobs.ScrollDown):
+			m.jobsScroll = max(m.jobsScroll-10, 0)
+			return m, nil
+		case key.Matches(keyMsg, keys.Jobs.Up, keys.Jobs.Down):
+			m.jobsScroll = 0
+		case key.Matches(keyMsg, keys.Jobs.Output):
+			return openJobOutput(m), nil
+		case key.Matches(keyMsg, keys.Jobs.Cancel):
+			if i := m.jobsTable.Cursor(); i >= 0 && i < len(m.jobs) {
+				var cmd tea.Cmd
+				m, cmd = cancelJob(m, m.jobs[i])
+				return m, cmd
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.jobsTable, cmd = m.jobsTable.Update(msg)
+	return m, cmd
+}
+
+func viewJobs(m model) (body, tooltip string) {
+	// Rows are rebuilt on every render so status and elapsed time stay live
+	t := m.jobsTable
+	t.SetRows(jobRows(m))
+	body += tooltipStyle.Render(fmt.Sprintf("Jobs — up to %d run at once; jobs for the same deployment run one after another", maxConcurrentJobs(m.cfg)))
+	body += "\n" + t.View() + "\n"
+	body += " " + strings.Repeat("─", uiWidth-4) + "\n"
+
+	const outputLines = 14
+	i := t.Cursor()
+	if i < 0 || i >= len(m.jobs) {
+		return body + "No jobs yet. Deployments started from the create and edit forms show up here.\n", tooltipStyle.Render("")
+	}
+	j := m.jobs[i]
+	end := max(len(j.Output)-m.jobsScroll, 0)
+	start := max(end-outputLines, 0)
+	for _, line := range j.Output[start:end] {
+		body += " " + truncate(line, uiWidth-6) + "\n"
+	}
+	switch j.Status {
+	case jobRunning:
+		tooltip = tooltipStyle.Render(viewOperation(m, j.Op) + "\n" + m.statusMessage)
+	case jobFailed:
+		tooltip = tooltipStyle.Render(truncate(strings.SplitN(j.Err, "\n", 2)[0], uiWidth-8))
+	default:
+		tooltip = tooltipStyle.Render(fmt.Sprintf("Job #%d %s — %d output lines", j.ID, j.Status, len(j.Output)))
+	}
+	return body, tooltip
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// Runs a job's last step to completion with a cancel pending and returns the job's end
+func finishLastStep(t *testing.T, stepErr error) (BusyFinishedMsg, DeploymentState, bool) {
+	t.Helper()
+	m, _ := newTestModel(t, "web_a")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	succeeded := false
+	op := &tfOperation{Dir: dir, Steps: []tfStep{{Name: "init"}, {Name: "apply", State: "DEPLOYED"}},
+		OnSuccess: func() error { succeeded = true; return nil }, step: 1, cancelling: true}
+	m.jobs = []*job{{ID: 1, Op: op, Status: jobRunning}}
+
+	_, cmd, ok := handleJobMsg(m, tfStepDoneMsg{jobID: 1, err: stepErr})
+	if !ok || cmd == nil {
+		t.Fatal("tfStepDoneMsg not handled")
+	}
+	done, ok := cmd().(BusyFinishedMsg)
+	if !ok {
+		t.Fatal("no BusyFinishedMsg")
+	}
+	s, _ := getDeploymentState(dir)
+	return done, s, succeeded
+}
+
+func TestStepSucceededDespiteCancel(t *testing.T) {
+	done, s, succeeded := finishLastStep(t, nil)
+	if !done.Success || !succeeded {
+		t.Errorf("success %v, OnSuccess ran %v; want both", done.Success, succeeded)
+	}
+	if s.State != "DEPLOYED" {
+		t.Errorf("state %s, want DEPLOYED", s.State)
+	}
+}
+
+func TestStepInterruptedByCancel(t *testing.T) {
+	done, s, succeeded := finishLastStep(t, errors.New("signal: interrupt"))
+	if done.Success || succeeded {
+		t.Errorf("success %v, OnSuccess ran %v; want neither", done.Success, succeeded)
+	}
+	if s.State != "CANCELLED" {
+		t.Errorf("state %s, want CANCELLED", s.State)
+	}
+}
+
+func TestApplyJobClearsDrift(t *testing.T) {
+	m, _ := newTestModel(t, "web_a", "web_b")
+	applied, other := filepath.Join(m.cfg.AppsPath, "web_a"), filepath.Join(m.cfg.AppsPath, "web_b")
+	m.drift[applied], m.drift[other] = driftDetected, driftDetected
+	op := &tfOperation{Dir: applied, Steps: []tfStep{{Name: "init", Args: []string{"init"}}, {Name: "apply", Args: []string{"apply", "-auto-approve"}}}}
+	m.jobs = []*job{{ID: 1, Op: op, Status: jobRunning}}
+
+	m, _, _ = handleJobMsg(m, BusyFinishedMsg{JobID: 1, Success: true})
+	if _, ok := m.drift[applied]; ok {
+		t.Error("drift result kept after apply")
+	}
+	if m.drift[other] != driftDetected {
+		t.Error("drift result of another deployment dropped")
+	}
+}
+
+func TestCancelQueuedJobRecordsState(t *testing.T) {
+	m, _ := newTestModel(t, "web_a", "web_b")
+	queued, busy := filepath.Join(m.cfg.AppsPath, "web_a"), filepath.Join(m.cfg.AppsPath, "web_b")
+	m.jobs = []*job{
+		{ID: 1, Op: planOperation(queued), Status: jobQueued},
+		{ID: 2, Op: planOperation(busy), Status: jobRunning},
+		{ID: 3, Op: planOperation(busy), Status: jobQueued},
+	}
+
+	m, _ = cancelJob(m, m.jobs[0])
+	if s, _ := getDeploymentState(queued); s.State != "CANCELLED" || s.LastAction != "init+plan" {
+		t.Errorf("state %s after %s, want CANCELLED after init+plan", s.State, s.LastAction)
+	}
+	// Job #2 still runs on web_b and owns its state
+	cancelJob(m, m.jobs[2])
+	if s, _ := getDeploymentState(busy); s.State != "DEPLOYED" {
+		t.Errorf("state %s, want the running job's DEPLOYED kept", s.State)
+	}
+}
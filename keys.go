@@ -0,0 +1,1017 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/ui"
+)
+
+// --- Key bindings (defaults, keys.yaml overrides, footer and "?" help) ---
+
+const keysPath = "keys.yaml"
+
+// Each scene's bindings; the yaml tags are the names used in keys.yaml
+type globalKeyMap struct {
+	Help     key.Binding `yaml:"help"`
+	Messages key.Binding `yaml:"messages"`
+	Frames   key.Binding `yaml:"frames"`
+	Reveal   key.Binding `yaml:"reveal"`
+	DiffMode key.Binding `yaml:"diff_mode"`
+	Errors   key.Binding `yaml:"errors"`
+}
+
+type busyKeyMap struct {
+	Cancel key.Binding `yaml:"cancel"`
+	Quit   key.Binding `yaml:"quit"`
+}
+
+type launcherKeyMap struct {
+	Up             key.Binding `yaml:"up"`
+	Down           key.Binding `yaml:"down"`
+	New            key.Binding `yaml:"new"`
+	Clone          key.Binding `yaml:"clone"`
+	Edit           key.Binding `yaml:"edit"`
+	Drift          key.Binding `yaml:"drift"`
+	SSH            key.Binding `yaml:"ssh"`
+	Select         key.Binding `yaml:"select"`
+	BulkEdit       key.Binding `yaml:"bulk_edit"`
+	Replace        key.Binding `yaml:"replace"`
+	Compare        key.Binding `yaml:"compare"`
+	Notes          key.Binding `yaml:"notes"`
+	TemplateDiff   key.Binding `yaml:"template_diff"`
+	Pin            key.Binding `yaml:"pin"`
+	NextFavorite   key.Binding `yaml:"next_favorite"`
+	Tfvars         key.Binding `yaml:"tfvars"`
+	QuickOpen      key.Binding `yaml:"quick_open"`
+	FilterDeployed key.Binding `yaml:"filter_deployed"`
+	FilterFailed   key.Binding `yaml:"filter_failed"`
+	FilterZone     key.Binding `yaml:"filter_zone"`
+	FilterClear    key.Binding `yaml:"filter_clear"`
+	Logs           key.Binding `yaml:"logs"`
+	Audit          key.Binding `yaml:"audit"`
+	Plan           key.Binding `yaml:"plan"`
+	ApplyPlan      key.Binding `yaml:"apply_plan"`
+	Retry          key.Binding `yaml:"retry"`
+	Destroy        key.Binding `yaml:"destroy"`
+	Archive        key.Binding `yaml:"archive"`
+	Archives       key.Binding `yaml:"archives"`
+	ApplyVars      key.Binding `yaml:"apply_vars"`
+	Test           key.Binding `yaml:"test"`
+	StateBrowser   key.Binding `yaml:"state_browser"`
+	Vault          key.Binding `yaml:"vault"`
+	Jobs           key.Binding `yaml:"jobs"`
+	CancelJob      key.Binding `yaml:"cancel_job"`
+	CopyKubeconfig key.Binding `yaml:"copy_kubeconfig"`
+	Export         key.Binding `yaml:"export"`
+	Reinit         key.Binding `yaml:"reinit"`
+	Health         key.Binding `yaml:"health"`
+	Refresh        key.Binding `yaml:"refresh"`
+	Quit           key.Binding `yaml:"quit"`
+}
+
+type createKeyMap struct {
+	Up         key.Binding `yaml:"up"`
+	Down       key.Binding `yaml:"down"`
+	Next       key.Binding `yaml:"next"`
+	Prev       key.Binding `yaml:"prev"`
+	OptionPrev key.Binding `yaml:"option_prev"`
+	OptionNext key.Binding `yaml:"option_next"`
+	Decrease   key.Binding `yaml:"decrease"`
+	Increase   key.Binding `yaml:"increase"`
+	Disks      key.Binding `yaml:"disks"`
+	PrevPreset key.Binding `yaml:"prev_preset"`
+	NextPreset key.Binding `yaml:"next_preset"`
+	Presets    key.Binding `yaml:"presets"`
+	Advanced   key.Binding `yaml:"advanced"`
+	// Sections from fields.yaml
+	CollapseSection key.Binding `yaml:"collapse_section"`
+	ExpandSections  key.Binding `yaml:"expand_sections"`
+	Capacity        key.Binding `yaml:"capacity"`
+	AllTemplates    key.Binding `yaml:"all_templates"`
+	Help            key.Binding `yaml:"help"`
+	Undo            key.Binding `yaml:"undo"`
+	Redo            key.Binding `yaml:"redo"`
+	ResetField      key.Binding `yaml:"reset_field"`
+	ResetAll        key.Binding `yaml:"reset_all"`
+	Save            key.Binding `yaml:"save"`
+	Cancel          key.Binding `yaml:"cancel"`
+}
+
+type editKeyMap struct {
+	Up         key.Binding `yaml:"up"`
+	Down       key.Binding `yaml:"down"`
+	Next       key.Binding `yaml:"next"`
+	Prev       key.Binding `yaml:"prev"`
+	OptionPrev key.Binding `yaml:"option_prev"`
+	OptionNext key.Binding `yaml:"option_next"`
+	Decrease   key.Binding `yaml:"decrease"`
+	Increase   key.Binding `yaml:"increase"`
+	Disks      key.Binding `yaml:"disks"`
+	Save       key.Binding `yaml:"save"`
+	Apply      key.Binding `yaml:"apply"`
+	Rollback   key.Binding `yaml:"rollback"`
+	Help       key.Binding `yaml:"help"`
+	Undo       key.Binding `yaml:"undo"`
+	Redo       key.Binding `yaml:"redo"`
+	ResetField key.Binding `yaml:"reset_field"`
+	ResetAll   key.Binding `yaml:"reset_all"`
+	Cancel     key.Binding `yaml:"cancel"`
+}
+
+// Simple list scenes: template picker, SSH picker
+type pickerKeyMap struct {
+	Up     key.Binding `yaml:"up"`
+	Down   key.Binding `yaml:"down"`
+	Select key.Binding `yaml:"select"`
+	Back   key.Binding `yaml:"back"`
+}
+
+type presetsKeyMap struct {
+	Up     key.Binding `yaml:"up"`
+	Down   key.Binding `yaml:"down"`
+	Use    key.Binding `yaml:"use"`
+	Save   key.Binding `yaml:"save"`
+	Rename key.Binding `yaml:"rename"`
+	Delete key.Binding `yaml:"delete"`
+	Back   key.Binding `yaml:"back"`
+	// Name prompt when saving or renaming
+	ConfirmName key.Binding `yaml:"confirm_name"`
+	CancelName  key.Binding `yaml:"cancel_name"`
+}
+
+type rollbackKeyMap struct {
+	Up           key.Binding `yaml:"up"`
+	Down         key.Binding `yaml:"down"`
+	Restore      key.Binding `yaml:"restore"`
+	RestoreApply key.Binding `yaml:"restore_apply"`
+	Back         key.Binding `yaml:"back"`
+}
+
+// Bulk variable edit; typing goes to the variable or value input
+type bulkEditKeyMap struct {
+	Up    key.Binding `yaml:"up"`
+	Down  key.Binding `yaml:"down"`
+	Next  key.Binding `yaml:"next"`
+	Apply key.Binding `yaml:"apply"`
+	Back  key.Binding `yaml:"back"`
+}
+
+// Find and replace; typing goes to the find or replace input
+type replaceKeyMap struct {
+	Up    key.Binding `yaml:"up"`
+	Down  key.Binding `yaml:"down"`
+	Next  key.Binding `yaml:"next"`
+	Regex key.Binding `yaml:"regex"`
+	Skip  key.Binding `yaml:"skip"`
+	Apply key.Binding `yaml:"apply"`
+	Back  key.Binding `yaml:"back"`
+}
+
+// Disk list editor; typing goes to the size of the disk under the cursor
+type disksKeyMap struct {
+	Up     key.Binding `yaml:"up"`
+	Down   key.Binding `yaml:"down"`
+	Add    key.Binding `yaml:"add"`
+	Remove key.Binding `yaml:"remove"`
+	Done   key.Binding `yaml:"done"`
+	Cancel key.Binding `yaml:"cancel"`
+}
+
+type notesKeyMap struct {
+	Edit   key.Binding `yaml:"edit"`
+	Reload key.Binding `yaml:"reload"`
+	Top    key.Binding `yaml:"top"`
+	Bottom key.Binding `yaml:"bottom"`
+	Back   key.Binding `yaml:"back"`
+}
+
+type templateDiffKeyMap struct {
+	Top    key.Binding `yaml:"top"`
+	Bottom key.Binding `yaml:"bottom"`
+	Back   key.Binding `yaml:"back"`
+}
+
+type compareKeyMap struct {
+	Up       key.Binding `yaml:"up"`
+	Down     key.Binding `yaml:"down"`
+	DiffOnly key.Binding `yaml:"diff_only"`
+	Swap     key.Binding `yaml:"swap"`
+	Reload   key.Binding `yaml:"reload"`
+	Back     key.Binding `yaml:"back"`
+}
+
+type jobsKeyMap struct {
+	Up         key.Binding `yaml:"up"`
+	Down       key.Binding `yaml:"down"`
+	ScrollUp   key.Binding `yaml:"scroll_up"`
+	ScrollDown key.Binding `yaml:"scroll_down"`
+	Cancel     key.Binding `yaml:"cancel_job"`
+	Output     key.Binding `yaml:"output"`
+	Back       key.Binding `yaml:"back"`
+}
+
+type stateKeyMap struct {
+	Up       key.Binding `yaml:"up"`
+	Down     key.Binding `yaml:"down"`
+	Download key.Binding `yaml:"download"`
+	Delete   key.Binding `yaml:"delete"`
+	Reload   key.Binding `yaml:"reload"`
+	Back     key.Binding `yaml:"back"`
+}
+
+// Proxmox credentials in Vault; Form* apply while an entry is being edited
+type vaultKeyMap struct {
+	Up         key.Binding `yaml:"up"`
+	Down       key.Binding `yaml:"down"`
+	New        key.Binding `yaml:"new"`
+	Edit       key.Binding `yaml:"edit"`
+	Test       key.Binding `yaml:"test"`
+	Reload     key.Binding `yaml:"reload"`
+	Back       key.Binding `yaml:"back"`
+	FormNext   key.Binding `yaml:"form_next"`
+	FormPrev   key.Binding `yaml:"form_prev"`
+	FormSave   key.Binding `yaml:"form_save"`
+	FormCancel key.Binding `yaml:"form_cancel"`
+}
+
+type helpBrowserKeyMap struct {
+	Up   key.Binding `yaml:"up"`
+	Down key.Binding `yaml:"down"`
+	Back key.Binding `yaml:"back"`
+}
+
+type logsKeyMap struct {
+	Filter     key.Binding `yaml:"filter"`
+	FilterDone key.Binding `yaml:"filter_done"`
+	Level      key.Binding `yaml:"level"`
+	Reload     key.Binding `yaml:"reload"`
+	Back       key.Binding `yaml:"back"`
+}
+
+// Scrolling and search in long output: the log and a job's output
+type pagerKeyMap struct {
+	Up           key.Binding `yaml:"up"`
+	Down         key.Binding `yaml:"down"`
+	PageUp       key.Binding `yaml:"page_up"`
+	PageDown     key.Binding `yaml:"page_down"`
+	Top          key.Binding `yaml:"top"`
+	Bottom       key.Binding `yaml:"bottom"`
+	Search       key.Binding `yaml:"search"`
+	SearchDone   key.Binding `yaml:"search_done"`
+	SearchCancel key.Binding `yaml:"search_cancel"`
+	Next         key.Binding `yaml:"next"`
+	Prev         key.Binding `yaml:"prev"`
+	FirstError   key.Binding `yaml:"first_error"`
+	Wrap         key.Binding `yaml:"wrap"`
+}
+
+type auditKeyMap struct {
+	Up         key.Binding `yaml:"up"`
+	Down       key.Binding `yaml:"down"`
+	Filter     key.Binding `yaml:"filter"`
+	FilterDone key.Binding `yaml:"filter_done"`
+	Action     key.Binding `yaml:"action"`
+	Deployment key.Binding `yaml:"deployment"`
+	Reload     key.Binding `yaml:"reload"`
+	Back       key.Binding `yaml:"back"`
+}
+
+// Full text of the last long status message
+type errorPanelKeyMap struct {
+	Up       key.Binding `yaml:"up"`
+	Down     key.Binding `yaml:"down"`
+	PageUp   key.Binding `yaml:"page_up"`
+	PageDown key.Binding `yaml:"page_down"`
+	Copy     key.Binding `yaml:"copy"`
+	Log      key.Binding `yaml:"log"`
+	Close    key.Binding `yaml:"close"`
+}
+
+type messagesKeyMap struct {
+	Up       key.Binding `yaml:"up"`
+	Down     key.Binding `yaml:"down"`
+	PageUp   key.Binding `yaml:"page_up"`
+	PageDown key.Binding `yaml:"page_down"`
+	Oldest   key.Binding `yaml:"oldest"`
+	Newest   key.Binding `yaml:"newest"`
+	Close    key.Binding `yaml:"close"`
+}
+
+// Ctrl+O deployment finder on the launcher
+type quickOpenKeyMap struct {
+	Up     key.Binding `yaml:"up"`
+	Down   key.Binding `yaml:"down"`
+	Open   key.Binding `yaml:"open"`
+	Cancel key.Binding `yaml:"cancel"`
+}
+
+// Launcher tfvars pane once focused, and the single-value edit opened from it
+type tfvarsKeyMap struct {
+	Up         key.Binding `yaml:"up"`
+	Down       key.Binding `yaml:"down"`
+	Edit       key.Binding `yaml:"edit"`
+	Back       key.Binding `yaml:"back"`
+	OptionPrev key.Binding `yaml:"option_prev"`
+	OptionNext key.Binding `yaml:"option_next"`
+	Decrease   key.Binding `yaml:"decrease"`
+	Increase   key.Binding `yaml:"increase"`
+	Save       key.Binding `yaml:"save"`
+	Cancel     key.Binding `yaml:"cancel"`
+}
+
+// Archive choice: destroy first or keep the resources
+type archiveKeyMap struct {
+	Destroy key.Binding `yaml:"destroy"`
+	Keep    key.Binding `yaml:"keep"`
+	Cancel  key.Binding `yaml:"cancel"`
+}
+
+type archivesKeyMap struct {
+	Up      key.Binding `yaml:"up"`
+	Down    key.Binding `yaml:"down"`
+	Restore key.Binding `yaml:"restore"`
+	Reload  key.Binding `yaml:"reload"`
+	Back    key.Binding `yaml:"back"`
+}
+
+// Prompt for one-off -var overrides before an apply
+type overridesKeyMap struct {
+	Apply  key.Binding `yaml:"apply"`
+	Cancel key.Binding `yaml:"cancel"`
+}
+
+// Format choice after the launcher's export key
+type exportKeyMap struct {
+	JSON     key.Binding `yaml:"json"`
+	CSV      key.Binding `yaml:"csv"`
+	Markdown key.Binding `yaml:"markdown"`
+}
+
+// Save/Discard/Cancel when leaving a form with unsaved changes
+type leaveKeyMap struct {
+	Save    key.Binding `yaml:"save"`
+	Discard key.Binding `yaml:"discard"`
+	Cancel  key.Binding `yaml:"cancel"`
+}
+
+// Startup offer to resume the create-form draft
+type draftKeyMap struct {
+	Resume  key.Binding `yaml:"resume"`
+	Discard key.Binding `yaml:"discard"`
+}
+
+// Answers to y/N prompts
+type confirmKeyMap struct {
+	Yes key.Binding `yaml:"yes"`
+}
+
+// Destroy dialog when the deployment name has to be typed
+type destroyKeyMap struct {
+	Confirm key.Binding `yaml:"confirm"`
+	Cancel  key.Binding `yaml:"cancel"`
+}
+
+type keyMap struct {
+	Global       globalKeyMap       `yaml:"global"`
+	Busy         busyKeyMap         `yaml:"busy"`
+	Launcher     launcherKeyMap     `yaml:"launcher"`
+	Create       createKeyMap       `yaml:"create"`
+	Edit         editKeyMap         `yaml:"edit"`
+	Templates    pickerKeyMap       `yaml:"templates"`
+	SSH          pickerKeyMap       `yaml:"ssh"`
+	Presets      presetsKeyMap      `yaml:"presets"`
+	Rollback     rollbackKeyMap     `yaml:"rollback"`
+	Jobs         jobsKeyMap         `yaml:"jobs"`
+	State        stateKeyMap        `yaml:"s3_state"`
+	Vault        vaultKeyMap        `yaml:"vault"`
+	HelpBrowser  helpBrowserKeyMap  `yaml:"help_browser"`
+	Logs         logsKeyMap         `yaml:"logs"`
+	Pager        pagerKeyMap        `yaml:"pager"`
+	Audit        auditKeyMap        `yaml:"audit"`
+	BulkEdit     bulkEditKeyMap     `yaml:"bulk_edit"`
+	Replace      replaceKeyMap      `yaml:"replace"`
+	Compare      compareKeyMap      `yaml:"compare"`
+	Disks        disksKeyMap        `yaml:"disks"`
+	Notes        notesKeyMap        `yaml:"notes"`
+	TemplateDiff templateDiffKeyMap `yaml:"template_diff"`
+	Messages     messagesKeyMap     `yaml:"messages"`
+	ErrorPanel   errorPanelKeyMap   `yaml:"error_panel"`
+	Confirm      confirmKeyMap      `yaml:"confirm"`
+	Destroy      destroyKeyMap      `yaml:"destroy"`
+	Export       exportKeyMap       `yaml:"export"`
+	Leave        leaveKeyMap        `yaml:"leave"`
+	Draft        draftKeyMap        `yaml:"draft"`
+	QuickOpen    quickOpenKeyMap    `yaml:"quick_open"`
+	Tfvars       tfvarsKeyMap       `yaml:"tfvars"`
+	Overrides    overridesKeyMap    `yaml:"overrides"`
+	Archive      archiveKeyMap      `yaml:"archive"`
+	Archives     archivesKeyMap     `yaml:"archives"`
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Global: globalKeyMap{
+			Help:     bind("Keys", "?"),
+			Messages: bind("Messages", "f8"),
+			Frames:   bind("Frame times", "f12"),
+			Reveal:   bind("Reveal sensitive values", "alt+v"),
+			DiffMode: bind("Unified/side-by-side diffs", "alt+d"),
+			Errors:   bind("Error details", "alt+e"),
+		},
+		Busy: busyKeyMap{
+			Cancel: bind("Cancel", "esc"),
+			Quit:   bind("Quit", "ctrl+c"),
+		},
+		Launcher: launcherKeyMap{
+			Up:             bind("Up", "up", "k"),
+			Down:           bind("Down", "down", "j"),
+			New:            bind("New", "n"),
+			Clone:          bind("Clone", "c", "C"),
+			Edit:           bind("Edit", "enter", "e"),
+			Drift:          bind("Drift", "f", "F"),
+			SSH:            bind("SSH", "s", "S"),
+			Select:         bind("Select", " "),
+			BulkEdit:       bind("Bulk edit selected", "V"),
+			Replace:        bind("Find/replace in tfvars", "ctrl+f"),
+			Compare:        bind("Compare two selected", "d", "D"),
+			Notes:          bind("Notes", "N"),
+			TemplateDiff:   bind("Template changes", "u", "U"),
+			Pin:            bind("Pin", "p"),
+			NextFavorite:   bind("Next pinned", "'"),
+			Tfvars:         bind("Quick-edit tfvars", "tab"),
+			QuickOpen:      bind("Open by name", "ctrl+o"),
+			FilterDeployed: bind("Deployed only", "1"),
+			FilterFailed:   bind("Failed/drifted only", "2"),
+			FilterZone:     bind("Zone filter", "z", "Z"),
+			FilterClear:    bind("Clear filters", "0"),
+			Logs:           bind("Logs", "l", "L"),
+			Audit:          bind("Audit", "a"),
+			Plan:           bind("Plan to tfplan", "P"),
+			ApplyPlan:      bind("Apply saved plan", "A"),
+			Retry:          bind("Retry failed step", "ctrl+r"),
+			Destroy:        bind("Destroy", "delete"),
+			Archive:        bind("Archive", "ctrl+x"),
+			Archives:       bind("Archives", "ctrl+a"),
+			ApplyVars:      bind("Apply with -var overrides", "o", "O"),
+			Test:           bind("Health checks", "T"),
+			StateBrowser:   bind("S3 State", "b", "B"),
+			Vault:          bind("Proxmox credentials", "K"),
+			Jobs:           bind("Jobs", "J"),
+			CancelJob:      bind("Cancel job", "x", "X"),
+			CopyKubeconfig: bind("Copy kubeconfig path", "y", "Y"),
+			Export:         bind("Export", "E"),
+			Reinit:         bind("Force terraform init", "I"),
+			Health:         bind("Vault/AWS status", "i"),
+			Refresh:        bind("Refresh", "r", "R"),
+			Quit:           bind("Quit", "q", "esc"),
+		},
+		Create: createKeyMap{
+			Up:              bind("Previous field", "up"),
+			Down:            bind("Next field", "down"),
+			Next:            bind("Next", "tab"),
+			Prev:            bind("Previous", "shift+tab"),
+			OptionPrev:      bind("Previous option", "left"),
+			OptionNext:      bind("Next option", "right", " "),
+			Decrease:        bind("Decrease", "-"),
+			Increase:        bind("Increase", "+", "="),
+			Disks:           bind("Edit disks", "ctrl+d"),
+			PrevPreset:      bind("Previous preset", "f2"),
+			NextPreset:      bind("Next preset", "f3"),
+			Presets:         bind("Presets", "f4"),
+			Advanced:        bind("Advanced", "f5"),
+			CollapseSection: bind("Collapse section", "ctrl+t"),
+			ExpandSections:  bind("Expand all sections", "alt+t"),
+			Capacity:        bind("Capacity", "f6"),
+			AllTemplates:    bind("All templates", "f7"),
+			Help:            bind("Help", "f1"),
+			Undo:            bind("Undo", "ctrl+z"),
+			Redo:            bind("Redo", "ctrl+y"),
+			ResetField:      bind("Reset field to preset", "ctrl+r"),
+			ResetAll:        bind("Reset all fields to preset", "alt+r"),
+			Save:            bind("Save", "enter"),
+			Cancel:          bind("Cancel", "esc", "ctrl+c"),
+		},
+		Edit: editKeyMap{
+			Up:         bind("Previous field", "up"),
+			Down:       bind("Next field", "down"),
+			Next:       bind("Next", "tab"),
+			Prev:       bind("Previous", "shift+tab"),
+			OptionPrev: bind("Previous option", "left"),
+			OptionNext: bind("Next option", "right", " "),
+			Decrease:   bind("Decrease", "-"),
+			Increase:   bind("Increase", "+", "="),
+			Disks:      bind("Edit disks", "ctrl+d"),
+			Save:       bind("Save", "enter"),
+			Apply:      bind("Apply", "a"),
+			Rollback:   bind("Rollback", "f9"),
+			Help:       bind("Help", "f1"),
+			Undo:       bind("Undo", "ctrl+z"),
+			Redo:       bind("Redo", "ctrl+y"),
+			ResetField: bind("Reset field to saved", "ctrl+r"),
+			ResetAll:   bind("Reset all fields to saved", "alt+r"),
+			Cancel:     bind("Cancel", "esc", "q"),
+		},
+		Templates: pickerKeyMap{
+			Up:     bind("Up", "up", "k"),
+			Down:   bind("Down", "down", "j"),
+			Select: bind("Select", "enter"),
+			Back:   bind("Cancel", "esc", "q"),
+		},
+		SSH: pickerKeyMap{
+			Up:     bind("Up", "up", "k"),
+			Down:   bind("Down", "down", "j"),
+			Select: bind("SSH", "enter"),
+			Back:   bind("Back", "esc", "q"),
+		},
+		Presets: presetsKeyMap{
+			Up:     bind("Up", "up", "k"),
+			Down:   bind("Down", "down", "j"),
+			Use:    bind("Use", "enter"),
+			Save:   bind("Save form as preset", "s", "S"),
+			Rename: bind("Rename", "r", "R"),
+			Delete: bind("Delete", "d", "D"),
+			Back:   bind("Back", "esc", "q"),
+
+			ConfirmName: bind("Confirm name", "enter"),
+			CancelName:  bind("Cancel", "esc"),
+		},
+		Rollback: rollbackKeyMap{
+			Up:           bind("Up", "up", "k"),
+			Down:         bind("Down", "down", "j"),
+			Restore:      bind("Restore", "enter"),
+			RestoreApply: bind("Restore and apply", "a", "A"),
+			Back:         bind("Back", "esc", "q", "f9"),
+		},
+		Jobs: jobsKeyMap{
+			Up:         bind("Up", "up", "k"),
+			Down:       bind("Down", "down", "j"),
+			ScrollUp:   bind("Scroll output up", "pgup"),
+			ScrollDown: bind("Scroll output down", "pgdown"),
+			Cancel:     bind("Cancel job", "x", "X"),
+			Output:     bind("Full output", "enter"),
+			Back:       bind("Back", "esc", "q"),
+		},
+		State: stateKeyMap{
+			Up:       bind("Up", "up", "k"),
+			Down:     bind("Down", "down", "j"),
+			Download: bind("Download orphaned state", "d", "D"),
+			Delete:   bind("Delete orphaned state", "x", "X"),
+			Reload:   bind("Reload", "r", "R"),
+			Back:     bind("Back", "esc", "q"),
+		},
+		Vault: vaultKeyMap{
+			Up:         bind("Up", "up", "k"),
+			Down:       bind("Down", "down", "j"),
+			New:        bind("New cluster", "n", "N"),
+			Edit:       bind("Edit", "enter", "e"),
+			Test:       bind("Test credentials", "t", "T"),
+			Reload:     bind("Reload", "r", "R"),
+			Back:       bind("Back", "esc", "q"),
+			FormNext:   bind("Next field", "tab", "down"),
+			FormPrev:   bind("Previous field", "shift+tab", "up"),
+			FormSave:   bind("Save and test", "enter"),
+			FormCancel: bind("Cancel", "esc"),
+		},
+		HelpBrowser: helpBrowserKeyMap{
+			Up:   bind("Up", "up"),
+			Down: bind("Down", "down"),
+			Back: bind("Back to form", "esc", "f1"),
+		},
+		Logs: logsKeyMap{
+			Filter:     bind("Filter", "&"),
+			FilterDone: bind("Close filter", "enter", "esc"),
+			Level:      bind("Level", "l"),
+			Reload:     bind("Reload", "r"),
+			Back:       bind("Back", "esc", "q"),
+		},
+		Pager: pagerKeyMap{
+			Up:           bind("Up", "up", "k"),
+			Down:         bind("Down", "down", "j"),
+			PageUp:       bind("Page up", "pgup", "b"),
+			PageDown:     bind("Page down", "pgdown", " ", "f"),
+			Top:          bind("Top", "g", "home"),
+			Bottom:       bind("Bottom/follow", "G", "end"),
+			Search:       bind("Search", "/"),
+			SearchDone:   bind("Find", "enter"),
+			SearchCancel: bind("Cancel search", "esc"),
+			Next:         bind("Next match", "n"),
+			Prev:         bind("Previous match", "N"),
+			FirstError:   bind("First error", "e"),
+			Wrap:         bind("Wrap lines", "w"),
+		},
+		Audit: auditKeyMap{
+			Up:         bind("Up", "up", "k"),
+			Down:       bind("Down", "down", "j"),
+			Filter:     bind("Filter", "/"),
+			FilterDone: bind("Close filter", "enter", "esc"),
+			Action:     bind("Action filter", "a", "A"),
+			Deployment: bind("This deployment only", "d", "D"),
+			Reload:     bind("Reload", "r", "R"),
+			Back:       bind("Back", "esc", "q"),
+		},
+		BulkEdit: bulkEditKeyMap{
+			Up:    bind("Previous deployment", "up"),
+			Down:  bind("Next deployment", "down"),
+			Next:  bind("Variable/value", "tab", "shift+tab"),
+			Apply: bind("Preview/apply", "enter"),
+			Back:  bind("Back", "esc"),
+		},
+		Replace: replaceKeyMap{
+			Up:    bind("Previous file", "up"),
+			Down:  bind("Next file", "down"),
+			Next:  bind("Find/replace", "tab", "shift+tab"),
+			Regex: bind("Literal/regex", "ctrl+r"),
+			Skip:  bind("Skip/include file", "ctrl+x"),
+			Apply: bind("Replace", "enter"),
+			Back:  bind("Back", "esc"),
+		},
+		Disks: disksKeyMap{
+			Up:     bind("Previous disk", "up"),
+			Down:   bind("Next disk", "down"),
+			Add:    bind("Add disk", "+", "ctrl+n"),
+			Remove: bind("Remove disk", "-", "ctrl+x"),
+			Done:   bind("Done", "enter"),
+			Cancel: bind("Cancel", "esc"),
+		},
+		Notes: notesKeyMap{
+			Edit:   bind("Edit in $EDITOR", "e", "E"),
+			Reload: bind("Reload", "r"),
+			Top:    bind("Top", "g"),
+			Bottom: bind("Bottom", "G"),
+			Back:   bind("Back", "esc", "q"),
+		},
+		TemplateDiff: templateDiffKeyMap{
+			Top:    bind("Top", "g"),
+			Bottom: bind("Bottom", "G"),
+			Back:   bind("Back", "esc", "q"),
+		},
+		Compare: compareKeyMap{
+			Up:       bind("Up", "up", "k"),
+			Down:     bind("Down", "down", "j"),
+			DiffOnly: bind("Differences only", "o", "O"),
+			Swap:     bind("Swap sides", "s", "S"),
+			Reload:   bind("Reload", "r", "R"),
+			Back:     bind("Back", "esc", "q"),
+		},
+		ErrorPanel: errorPanelKeyMap{
+			Up:       bind("Up", "up", "k"),
+			Down:     bind("Down", "down", "j"),
+			PageUp:   bind("Page up", "pgup"),
+			PageDown: bind("Page down", "pgdown"),
+			Copy:     bind("Copy", "c", "y"),
+			Log:      bind("Open the log", "l"),
+			Close:    bind("Close", "esc", "q", "alt+e"),
+		},
+		Messages: messagesKeyMap{
+			Up:       bind("Older", "up", "k"),
+			Down:     bind("Newer", "down", "j"),
+			PageUp:   bind("Page up", "pgup"),
+			PageDown: bind("Page down", "pgdown"),
+			Oldest:   bind("Oldest", "g"),
+			Newest:   bind("Newest", "G"),
+			Close:    bind("Close", "esc", "q", "f8"),
+		},
+		Confirm: confirmKeyMap{
+			Yes: bind("Yes", "y", "Y"),
+		},
+		Destroy: destroyKeyMap{
+			Confirm: bind("Destroy", "enter"),
+			Cancel:  bind("Cancel", "esc"),
+		},
+		Leave: leaveKeyMap{
+			Save:    bind("Save", "s", "S"),
+			Discard: bind("Discard", "d", "D"),
+			Cancel:  bind("Keep editing", "esc", "c", "C"),
+		},
+		Draft: draftKeyMap{
+			Resume:  bind("Resume draft", "y", "Y"),
+			Discard: bind("Discard draft", "d", "D"),
+		},
+		Export: exportKeyMap{
+			JSON:     bind("JSON", "j", "J"),
+			CSV:      bind("CSV", "c", "C"),
+			Markdown: bind("Markdown", "m", "M"),
+		},
+		QuickOpen: quickOpenKeyMap{
+			Up:     bind("Up", "up", "ctrl+p"),
+			Down:   bind("Down", "down", "ctrl+n"),
+			Open:   bind("Select", "enter"),
+			Cancel: bind("Cancel", "esc", "ctrl+o"),
+		},
+		Tfvars: tfvarsKeyMap{
+			Up:         bind("Up", "up", "k"),
+			Down:       bind("Down", "down", "j"),
+			Edit:       bind("Edit value", "enter", "e"),
+			Back:       bind("Back to deployments", "tab", "esc"),
+			OptionPrev: bind("Previous option", "left"),
+			OptionNext: bind("Next option", "right"),
+			Decrease:   bind("Decrease", "-"),
+			Increase:   bind("Increase", "+"),
+			Save:       bind("Save", "enter"),
+			Cancel:     bind("Cancel", "esc"),
+		},
+		Overrides: overridesKeyMap{
+			Apply:  bind("Apply", "enter"),
+			Cancel: bind("Cancel", "esc"),
+		},
+		Archive: archiveKeyMap{
+			Destroy: bind("Destroy, then archive", "d", "D"),
+			Keep:    bind("Archive, keep resources", "k", "K"),
+			Cancel:  bind("Cancel", "esc"),
+		},
+		Archives: archivesKeyMap{
+			Up:      bind("Up", "up", "k"),
+			Down:    bind("Down", "down", "j"),
+			Restore: bind("Restore", "enter"),
+			Reload:  bind("Reload", "r", "R"),
+			Back:    bind("Back", "esc", "q"),
+		},
+	}
+}
+
+// keys is the active key map: the defaults with keys.yaml applied
+var keys = defaultKeyMap()
+
+// Binding and help helpers, see internal/ui
+var (
+	keyLabel      = ui.KeyLabel
+	helpKey       = ui.HelpKey
+	bind          = ui.Bind
+	tableKeys     = ui.TableKeys
+	pairHelp      = ui.PairHelp
+	groupHelp     = ui.GroupHelp
+	hintHelp      = ui.HintHelp
+	namedBindings = ui.NamedBindings
+)
+
+// Applies keys.yaml ("scene: {action: [keys...]}") on top of km; a missing file is fine
+func loadKeyBindings(path string, km *keyMap) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var overrides map[string]map[string][]string
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return err
+	}
+	scenes := reflect.ValueOf(km).Elem()
+	byScene := map[string]any{}
+	for i := 0; i < scenes.NumField(); i++ {
+		byScene[scenes.Type().Field(i).Tag.Get("yaml")] = scenes.Field(i).Addr().Interface()
+	}
+	for scene, actions := range overrides {
+		target, ok := byScene[scene]
+		if !ok {
+			return fmt.Errorf("unknown scene %q", scene)
+		}
+		_, bindings := namedBindings(target)
+		for action, ks := range actions {
+			b, ok := bindings[action]
+			if !ok {
+				return fmt.Errorf("unknown action %q in %s", action, scene)
+			}
+			if len(ks) == 0 {
+				return fmt.Errorf("%s.%s: no keys given", scene, action)
+			}
+			*b = bind(b.Help().Desc, ks...)
+		}
+	}
+	return nil
+}
+
+// Bindings of the current scene for the "?" overlay
+func sceneKeyMap(m model) any {
+	switch m.currentScene {
+	case sceneLauncher:
+		if m.quickOpen {
+			return &keys.QuickOpen
+		}
+		if m.tfvarsFocus {
+			return &keys.Tfvars
+		}
+		return &keys.Launcher
+	case sceneCreateForm:
+		return &keys.Create
+	case sceneEditForm:
+		return &keys.Edit
+	case scenePickTemplate:
+		return &keys.Templates
+	case sceneSSH:
+		return &keys.SSH
+	case scenePresets:
+		return &keys.Presets
+	case sceneRollback:
+		return &keys.Rollback
+	case sceneJobs:
+		return &keys.Jobs
+	case sceneS3State:
+		return &keys.State
+	case sceneArchives:
+		return &keys.Archives
+	case sceneVault:
+		return &keys.Vault
+	case sceneHelp:
+		return &keys.HelpBrowser
+	case sceneLogs:
+		return &keys.Logs
+	case sceneAudit:
+		return &keys.Audit
+	case sceneBulkEdit:
+		return &keys.BulkEdit
+	case sceneReplace:
+		return &keys.Replace
+	case sceneCompare:
+		return &keys.Compare
+	case sceneDisks:
+		return &keys.Disks
+	case sceneNotes:
+		return &keys.Notes
+	case sceneTemplateDiff:
+		return &keys.TemplateDiff
+	case sceneOutput:
+		return &keys.Pager
+	}
+	return nil
+}
+
+// Scenes where "?" is a key like any other because a text input has focus
+func typingScene(m model) bool {
+	if m.quickOpen || m.quickEdit != nil || m.varsPrompt != nil {
+		return true
+	}
+	switch m.currentScene {
+	case sceneCreateForm, sceneEditForm, sceneHelp, sceneBulkEdit, sceneReplace, sceneDisks:
+		return true
+	case sceneLogs:
+		return m.logFilter.Focused() || m.logPager.searching()
+	case sceneOutput:
+		return m.outputPager.searching()
+	case sceneVault:
+		return m.vaultForm != nil
+	case sceneAudit:
+		return m.auditFilter.Focused()
+	case scenePresets:
+		return m.presetMgrMode == presetMgrSave || m.presetMgrMode == presetMgrRename
+	}
+	return false
+}
+
+func newHelp() help.Model {
+	h := help.New()
+	h.ShortSeparator = " │ "
+	h.FullSeparator = "    "
+	h.Width = uiWidth - 8
+	return h
+}
+
+// Footer line from the given bindings; boxSection wraps it rather than cutting it off
+func footerHelp(bindings ...key.Binding) string {
+	h := newHelp()
+	h.Width = 0
+	return centerText(h.ShortHelpView(bindings), uiWidth)
+}
+
+// Bindings of a scene shown as one footer entry, by keys.yaml name; an empty desc takes the
+// scene's item name ("[↑/↓] Deployment")
+var footerPairs = []struct{ first, second, desc string }{
+	{"up", "down", ""},
+	{"form_prev", "form_next", "Field"},
+	{"option_prev", "option_next", "Option"},
+	{"decrease", "increase", "Step number"},
+	{"prev_preset", "next_preset", "Preset"},
+	{"collapse_section", "expand_sections", "Collapse/expand sections"},
+	{"undo", "redo", "Undo/Redo"},
+	{"reset_field", "reset_all", "Reset field/all"},
+	{"scroll_up", "scroll_down", "Scroll output"},
+	{"page_up", "page_down", "Page"},
+	{"top", "bottom", "Top/Bottom"},
+}
+
+// Bindings of a scene shown as one "[1/2/Z/0] Filter" entry
+var footerGroups = []struct {
+	desc    string
+	members []string
+}{
+	{"Filter", []string{"filter_deployed", "filter_failed", "filter_zone", "filter_clear"}},
+}
+
+// Footer bindings of the current scene, from sceneKeyMap like the "?" overlay so a binding
+// can't be missing from one of them: keys.yaml order, footerPairs and footerGroups merged,
+// skip naming the bindings of another mode of the scene (a form, a name prompt)
+func sceneFooter(m model, item string, skip ...string) []key.Binding {
+	scene := sceneKeyMap(m)
+	if scene == nil {
+		return nil
+	}
+	names, bindings := namedBindings(scene)
+	done := map[string]bool{}
+	for _, name := range skip {
+		done[name] = true
+	}
+	var out []key.Binding
+	for _, name := range names {
+		if done[name] {
+			continue
+		}
+		done[name] = true
+		if b, ok := footerEntry(name, item, bindings, done); ok {
+			out = append(out, b)
+			continue
+		}
+		out = append(out, *bindings[name])
+	}
+	return out
+}
+
+// The pair or group name belongs to, marking its other members done
+func footerEntry(name, item string, bindings map[string]*key.Binding, done map[string]bool) (key.Binding, bool) {
+	for _, p := range footerPairs {
+		if name != p.first && name != p.second {
+			continue
+		}
+		other := p.second
+		if name == p.second {
+			other = p.first
+		}
+		if bindings[other] == nil || done[other] {
+			return key.Binding{}, false
+		}
+		done[other] = true
+		desc := p.desc
+		if desc == "" {
+			desc = item
+		}
+		return pairHelp(*bindings[p.first], *bindings[p.second], desc), true
+	}
+	for _, g := range footerGroups {
+		if !slices.Contains(g.members, name) {
+			continue
+		}
+		var bs []key.Binding
+		for _, member := range g.members {
+			if b := bindings[member]; b != nil && (member == name || !done[member]) {
+				done[member] = true
+				bs = append(bs, *b)
+			}
+		}
+		return groupHelp(g.desc, bs...), true
+	}
+	return key.Binding{}, false
+}
+
+// Pager bindings for the footer of a scene showing long output
+func pagerHelp() []key.Binding {
+	k := keys.Pager
+	return []key.Binding{pairHelp(k.Up, k.Down, "Scroll"), pairHelp(k.PageUp, k.PageDown, "Page"), pairHelp(k.Top, k.Bottom, "Top/Bottom"),
+		k.Search, pairHelp(k.Next, k.Prev, "Next/previous match"), k.FirstError, k.Wrap}
+}
+
+func pagerSearchFooter() string {
+	k := keys.Pager
+	return footerHelp(hintHelp("Type", "Search"), k.SearchDone, k.SearchCancel)
+}
+
+// Expanded "?" overlay: every binding of the current scene with its keys.yaml name, plus the global ones
+func viewKeyHelp(m model) string {
+	var all []key.Binding
+	scenes := []any{sceneKeyMap(m)}
+	if m.currentScene == sceneLogs {
+		// the log viewer scrolls and searches with the pager's keys
+		scenes = append(scenes, &keys.Pager)
+	}
+	for _, scene := range scenes {
+		if scene == nil {
+			continue
+		}
+		names, bindings := namedBindings(scene)
+		for _, name := range names {
+			h := bindings[name].Help()
+			all = append(all, key.NewBinding(key.WithKeys(bindings[name].Keys()...), key.WithHelp(h.Key, h.Desc+" ("+name+")")))
+		}
+	}
+	var columns [][]key.Binding
+	for len(all) > 0 {
+		n := min(len(all), 8)
+		columns = append(columns, all[:n])
+		all = all[n:]
+	}
+	columns = append(columns, []key.Binding{keys.Global.Help, keys.Global.Messages, keys.Global.Reveal, keys.Global.DiffMode, keys.Global.Errors, keys.Global.Frames})
+	h := newHelp()
+	h.ShowAll = true
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(popupBorder()).
+		Padding(0, 1).
+		Render("Keys — override them in " + keysPath + " (any key closes)\n\n" + h.FullHelpView(columns))
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLauncherFooterListsEveryBinding(t *testing.T) {
+	m, _ := newTestModel(t, "web_a")
+	footer := footerForScene(m)
+	names, bindings := namedBindings(&keys.Launcher)
+	for _, name := range names {
+		if name == "up" || name == "down" || strings.HasPrefix(name, "filter_") {
+			continue // shown as "[↑/↓] Deployment" and "[…] Filter"
+		}
+		if desc := bindings[name].Help().Desc; !strings.Contains(footer, desc) {
+			t.Errorf("launcher footer lacks %s (%q)", name, desc)
+		}
+	}
+	if !strings.Contains(footer, keys.Launcher.Vault.Help().Key) {
+		t.Errorf("launcher footer lacks the Vault key %s", keys.Launcher.Vault.Help().Key)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Launcher layout: deployments | tfvars side by side, stacked on narrow terminals ---
+
+const (
+	defaultLauncherSplit = 60  // % of the width given to the deployments table
+	stackedLayoutWidth   = 140 // narrower terminals get the tfvars pane under the deployments
+	tableCellPadding     = 2   // bubbles/table pads every cell with a space on each side
+	minFlexColumn        = 10
+)
+
+type launcherLayout struct {
+	left, right int // pane widths; both are the full width when stacked
+	stacked     bool
+}
+
+// Pane widths for a terminal termWidth columns wide (0 before the first WindowSizeMsg)
+func computeLauncherLayout(cfg Config, termWidth int) launcherLayout {
+	width := uiWidth
+	if termWidth > 0 && termWidth < width {
+		width = termWidth
+	}
+	if width < stackedLayoutWidth {
+		return launcherLayout{left: width - 1, right: width - 1, stacked: true}
+	}
+	split := cfg.LauncherSplit
+	if split < 20 || split > 80 {
+		split = defaultLauncherSplit
+	}
+	// " │ " between the panes
+	left := (width - 3) * split / 100
+	return launcherLayout{left: left, right: width - 3 - left}
+}
+
+// Fits both tables to the current layout: the description and value columns take what the
+// fixed columns leave, and stacked panes get half the height each
+func resizeLauncherTables(m *model) {
+	m.layout = computeLauncherLayout(m.cfg, m.termWidth)
+	m.deployTable.SetColumns(fitColumns(m.deployTable.Columns(), 1, m.layout.left))
+	m.tfvarsTable.SetColumns(fitColumns(m.tfvarsTable.Columns(), 1, m.layout.right))
+	if m.layout.stacked {
+		m.deployTable.SetHeight(10)
+		m.tfvarsTable.SetHeight(8)
+	} else {
+		m.deployTable.SetHeight(20)
+		m.tfvarsTable.SetHeight(20)
+	}
+	m.tablesVersion++
+}
+
+// Gives column flex the width the other columns don't use
+func fitColumns(cols []table.Column, flex, width int) []table.Column {
+	if flex >= len(cols) {
+		return cols
+	}
+	out := make([]table.Column, len(cols))
+	copy(out, cols)
+	rest := width - tableCellPadding*len(cols)
+	for i, c := range out {
+		if i != flex {
+			rest -= c.Width
+		}
+	}
+	out[flex].Width = max(rest, minFlexColumn)
+	return out
+}
+
+// Pads s with spaces to width visible cells (ANSI sequences don't count)
+func padVisible(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// Body of the launcher: the two panes side by side, or the tfvars pane and detail lines
+// under the deployments when stacked
+func launcherBody(l launcherLayout, deployTableStr, tfvarsTableStr string, detail []string) string {
+	if l.stacked {
+		parts := []string{deployTableStr, " " + strings.Repeat("─", l.left-2), tfvarsTableStr}
+		if len(detail) > 0 {
+			parts = append(parts, strings.Join(detail, "\n"))
+		}
+		return strings.Join(parts, "\n") + "\n"
+	}
+	lines1 := strings.Split(deployTableStr, "\n")
+	lines2 := strings.Split(tfvarsTableStr, "\n")
+	// detail lines go under the longer of the two tables
+	for len(lines2) < len(lines1) {
+		lines2 = append(lines2, "")
+	}
+	lines2 = append(lines2, detail...)
+	n := max(len(lines1), len(lines2))
+	var out strings.Builder
+	for i := 0; i < n; i++ {
+		var a, b string
+		if i < len(lines1) {
+			a = lines1[i]
+		}
+		if i < len(lines2) {
+			b = lines2[i]
+		}
+		out.WriteString(padVisible(a, l.left) + " │ " + padVisible(b, l.right) + "\n")
+	}
+	return out.String()
+}
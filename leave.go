@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Unsaved-changes guard when leaving the create and edit forms ---
+
+// Fields that differ from the preset (create) or the saved terraform.tfvars (edit)
+func unsavedFields(m model) int {
+	switch m.currentScene {
+	case sceneCreateForm:
+		return countModified(len(m.createInputs), func(i int) bool { return createFieldModified(m, i) })
+	case sceneEditForm:
+		return countModified(len(m.editFormInputs), func(i int) bool { return editFieldModified(m, i) })
+	}
+	return 0
+}
+
+// Cancel in a form: leaves right away, or asks first when fields were changed
+func leaveForm(m model) (tea.Model, tea.Cmd) {
+	if unsavedFields(m) == 0 {
+		if m.currentScene == sceneCreateForm {
+			clearDraft()
+		}
+		return m.popScene(), nil
+	}
+	m.leaveDialog = true
+	return m, nil
+}
+
+// Keys while the dialog is open; other keys are ignored so nothing is lost by accident
+func updateLeaveDialog(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Leave.Cancel):
+		m.leaveDialog = false
+	case key.Matches(msg, keys.Leave.Discard):
+		m.leaveDialog = false
+		m.statusMessage = fmt.Sprintf("Discarded %s", plural(unsavedFields(m), "changed field"))
+		if m.currentScene == sceneCreateForm {
+			clearDraft()
+		}
+		return m.popScene(), nil
+	case key.Matches(msg, keys.Leave.Save):
+		m.leaveDialog = false
+		if m.currentScene == sceneCreateForm {
+			return submitCreateForm(m, false)
+		}
+		m, cmd := saveEditForm(m)
+		if unsavedFields(m) > 0 {
+			return m, cmd // save failed; editStatus says why
+		}
+		m.statusMessage = "Saved " + filepath.Base(filepath.Dir(m.editFormPath))
+		return m.popScene(), cmd
+	}
+	return m, nil
+}
+
+func viewLeaveDialog(m model) string {
+	save := "save to terraform.tfvars"
+	if m.currentScene == sceneCreateForm {
+		save = "create the deployment"
+	}
+	k := keys.Leave
+	text := titleStyle.Render(fmt.Sprintf("%s not saved", plural(unsavedFields(m), "changed field"))) + "\n\n" +
+		fmt.Sprintf("%s %s\n%s discard the changes and leave\n%s keep editing", k.Save.Help().Key, save, k.Discard.Help().Key, k.Cancel.Help().Key)
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(popupBorder()).
+		Padding(0, 1).
+		Render(text)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Structured logging ---
+
+// logger writes JSON lines to the app log; discards until initLogging succeeds
+var logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+func logPath() string {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(base, "infra-catalog", "app.log")
+}
+
+func initLogging() (io.Closer, error) {
+	path := logPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	logger = slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return f, nil
+}
+
+func logTerraform(dir string, args []string, started time.Time, err error) {
+	attrs := []any{
+		"component", "terraform",
+		"deployment", filepath.Base(dir),
+		"args", strings.Join(args, " "),
+		"duration_ms", time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		logger.Error("terraform run failed", append(attrs, "error", err.Error())...)
+		return
+	}
+	logger.Info("terraform run", attrs...)
+}
+
+func logVault(op, path string, err error) {
+	if err != nil {
+		logger.Error("vault call failed", "component", "vault", "op", op, "path", path, "error", err.Error())
+		return
+	}
+	logger.Info("vault call", "component", "vault", "op", op, "path", path)
+}
+
+func logProxmox(apiUrl, path string, started time.Time, err error) {
+	attrs := []any{"component", "proxmox", "host", apiUrl, "path", path, "duration_ms", time.Since(started).Milliseconds()}
+	if err != nil {
+		logger.Error("proxmox request failed", append(attrs, "error", err.Error())...)
+		return
+	}
+	logger.Info("proxmox request", attrs...)
+}
+
+func logS3(op, bucket, key string, err error) {
+	if err != nil {
+		logger.Error("s3 call failed", "component", "s3", "op", op, "bucket", bucket, "key", key, "error", err.Error())
+		return
+	}
+	logger.Info("s3 call", "component", "s3", "op", op, "bucket", bucket, "key", key)
+}
+
+// --- Log viewer scene ---
+
+const logViewerMaxLines = 2000
+
+var logLevels = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+type logEntry struct {
+	Time  string
+	Level string
+	Msg   string
+	Attrs string
+	Raw   string
+}
+
+// Reads the last logViewerMaxLines entries of the app log
+func readLogEntries(path string) ([]logEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > logViewerMaxLines {
+			lines = lines[1:]
+		}
+	}
+	entries := make([]logEntry, 0, len(lines))
+	for _, l := range lines {
+		entries = append(entries, parseLogLine(l))
+	}
+	return entries, scanner.Err()
+}
+
+func parseLogLine(line string) logEntry {
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &rec); err != nil {
+		return logEntry{Msg: line, Raw: line}
+	}
+	e := logEntry{Raw: line}
+	e.Time, _ = rec["time"].(string)
+	e.Level, _ = rec["level"].(string)
+	e.Msg, _ = rec["msg"].(string)
+	if t, err := time.Parse(time.RFC3339Nano, e.Time); err == nil {
+		e.Time = localTime(t).Format("2006-01-02 15:04:05")
+	}
+	delete(rec, "time")
+	delete(rec, "level")
+	delete(rec, "msg")
+	var attrs []string
+	for _, k := range sortedKeys(rec) {
+		attrs = append(attrs, fmt.Sprintf("%s=%v", k, rec[k]))
+	}
+	e.Attrs = strings.Join(attrs, " ")
+	return e
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func openLogViewer(m model) model {
+	m.logFilter = textinput.New()
+	m.logFilter.Placeholder = "filter (substring)"
+	m.logMinLevel = 0
+	m.logPager = newPager(uiWidth-4, uiHeight-14)
+	return reloadLogViewer(m).pushScene(sceneLogs)
+}
+
+func reloadLogViewer(m model) model {
+	entries, err := readLogEntries(logPath())
+	if err != nil && !os.IsNotExist(err) {
+		m.logStatus = "Could not read log: " + err.Error()
+	} else {
+		m.logStatus = ""
+	}
+	m.logEntries = entries
+	m.logPager.setLines(logPagerLines(m))
+	m.logPager.gotoBottom()
+	return m
+}
+
+func logLevelIndex(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return 0
+}
+
+// Entries at or above the level that contain the filter, errors and warnings in color
+func logPagerLines(m model) []pagerLine {
+	filter := strings.ToLower(m.logFilter.Value())
+	var lines []pagerLine
+	for _, e := range m.logEntries {
+		if logLevelIndex(e.Level) < m.logMinLevel {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(e.Raw), filter) {
+			continue
+		}
+		l := pagerLine{Text: fmt.Sprintf("%s %-5s %s", e.Time, e.Level, e.Msg), Attrs: e.Attrs, Style: normalStyle}
+		switch e.Level {
+		case "ERROR":
+			l.Style, l.Error = logErrorStyle, true
+		case "WARN":
+			l.Style = logWarnStyle
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) == 0 {
+		return []pagerLine{{Text: "(no matching log entries)", Style: normalStyle}}
+	}
+	return lines
+}
+
+func updateLogViewer(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.logPager.searching() {
+		if m.logFilter.Focused() {
+			switch {
+			case key.Matches(keyMsg, keys.Logs.FilterDone):
+				m.logFilter.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.logFilter, cmd = m.logFilter.Update(msg)
+			m.logPager.setLines(logPagerLines(m))
+			m.logPager.gotoBottom()
+			return m, cmd
+		}
+		switch {
+		case key.Matches(keyMsg, keys.Logs.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.Logs.Filter):
+			m.logFilter.Focus()
+			return m, textinput.Blink
+		case key.Matches(keyMsg, keys.Logs.Level):
+			m.logMinLevel = (m.logMinLevel + 1) % len(logLevels)
+			m.logPager.setLines(logPagerLines(m))
+			m.logPager.gotoBottom()
+			return m, nil
+		case key.Matches(keyMsg, keys.Logs.Reload):
+			return reloadLogViewer(m), nil
+		}
+	}
+	var cmd tea.Cmd
+	m.logPager, cmd = m.logPager.update(msg)
+	return m, cmd
+}
+
+func viewLogViewer(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(fmt.Sprintf("Log: %s   Level ≥ %s   Filter: %s", logPath(), logLevels[m.logMinLevel], m.logFilter.View()))
+	body += "\n" + m.logPager.view() + "\n"
+	msg := m.logStatus
+	if msg == "" {
+		msg = fmt.Sprintf("%d entries loaded  •  %s", len(m.logEntries), m.logPager.statusLine())
+	}
+	tooltip = tooltipStyle.Render(msg)
+	return body, tooltip
+}
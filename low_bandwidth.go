@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Low-bandwidth rendering (low_bandwidth: true) ---
+
+// Redraws per second in low-bandwidth mode (Bubble Tea's default is 60)
+const lowBandwidthFPS = 10
+
+var lowBandwidth bool
+
+// Switches to ASCII borders and plain field text; called before the model is built
+func configureLowBandwidth(cfg Config) {
+	lowBandwidth = cfg.LowBandwidth
+	if !lowBandwidth {
+		return
+	}
+	tooltipStyle = tooltipStyle.Border(lipgloss.ASCIIBorder())
+	busyBoxStyle = busyBoxStyle.Border(lipgloss.ASCIIBorder())
+	normalStyle = lipgloss.NewStyle()
+}
+
+func boxBorder() lipgloss.Border {
+	if lowBandwidth {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
+
+// Spinners step once a second in low-bandwidth mode, which is enough to keep elapsed times moving
+func newSpinner(s spinner.Spinner) spinner.Model {
+	if lowBandwidth {
+		s.FPS = time.Second
+	}
+	return spinner.New(spinner.WithSpinner(s))
+}
+
+// Gradients repaint every cell in its own color; a solid fill needs one color change
+func progressFill() progress.Option {
+	if lowBandwidth || theme.Gradient[0] == "" || noColor {
+		return progress.WithSolidFill(theme.OK)
+	}
+	return progress.WithGradient(theme.Gradient[0], theme.Gradient[1])
+}
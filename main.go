@@ -1,37 +1,69 @@
 package main
 
 import (
-	"bufio"
-	"context"
-	"crypto/tls"
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
+	"maps"
+	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
 	"time"
 
-	vault "github.com/hashicorp/vault/api"
-
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"gopkg.in/yaml.v3"
+
+	"launcher/internal/config"
+	"launcher/internal/execx"
+	"launcher/internal/git"
+	"launcher/internal/proxmox"
+	"launcher/internal/state"
+	"launcher/internal/tfvars"
+	"launcher/internal/ui"
 )
 
 const uiWidth = 160
 const uiHeight = 40
 
+const globalFieldsPath = "fields.yaml"
+
+// Text formatting helpers, see internal/ui
 var (
-	focusedStyle = lipgloss.NewStyle().Background(lipgloss.Color("#FFEB3B")).Foreground(lipgloss.Color("#111")).Bold(true)
-	normalStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#EEE"))
-	tooltipStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Foreground(lipgloss.Color("240")).Width(uiWidth - 4)
+	truncate      = ui.Truncate
+	padRight      = ui.PadRight
+	plural        = ui.Plural
+	formatBytes   = ui.FormatBytes
+	roundDuration = ui.RoundDuration
+)
+
+// config.yaml types, see internal/config
+type (
+	Config                 = config.Config
+	SecretsProviderConfig  = config.SecretsProvider
+	ZoneConfig             = config.Zone
+	ClusterDiscoveryConfig = config.ClusterDiscovery
+	NotifyConfig           = config.Notify
+	LibvirtConfig          = config.Libvirt
+	TemplateFilter         = config.TemplateFilter
+	TemplateFilterConfig   = config.TemplateFilters
+	SSHConfig              = config.SSH
+	AuditConfig            = config.Audit
+	IdentityConfig         = config.Identity
+	NamingConfig           = config.Naming
+	VMIDRegistryConfig     = config.VMIDRegistry
+	GuardRails             = config.GuardRails
+	CatalogLockConfig      = config.CatalogLock
 )
 
 type FieldMeta struct {
@@ -39,6 +71,26 @@ type FieldMeta struct {
 	Help     string `yaml:"help"`
 	ReadOnly bool   `yaml:"readOnly"`
 	Type     string `yaml:"type"`
+
+	// Conditional display: only for these template OS families, and/or when other fields hold given values
+	OSFamily []string          `yaml:"osFamily"`
+	ShowWhen map[string]string `yaml:"showWhen"`
+	// Validation applied to visible fields before create
+	Required bool     `yaml:"required"`
+	Pattern  string   `yaml:"pattern"`
+	Options  []string `yaml:"options"` // also makes the field a left/right select
+	// Advanced fields go in a collapsed section at the end of the create form; left out of tfvars when empty
+	Advanced bool `yaml:"advanced"`
+	// Heading the field is grouped under in the create form; sections collapse with Ctrl+T
+	Section string `yaml:"section"`
+	// type: secret only: keep the value in Vault and write just a reference into tfvars
+	Vault bool `yaml:"vault"`
+	// Keep the value in the deployment's sops-encrypted secrets.sops.json instead of tfvars
+	Sensitive bool `yaml:"sensitive"`
+	// Whole-number fields: left/right and -/+ step by Step within Min..Max, typing takes digits only
+	Step int  `yaml:"step"`
+	Min  *int `yaml:"min"`
+	Max  *int `yaml:"max"`
 }
 
 // FieldsYaml is the structure for the fields.yaml file
@@ -48,138 +100,85 @@ type FieldsYaml struct {
 
 func loadFieldMeta(path string) (map[string]FieldMeta, error) {
 	var fy FieldsYaml
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	if err := yaml.Unmarshal(data, &fy); err != nil {
+	if err := loadYAMLStrict(path, &fy); err != nil {
 		return nil, err
 	}
 	return fy.Fields, nil
 }
 
-type Config struct {
-	Repo          string `yaml:"repo"`
-	AppsPath      string `yaml:"apps_path"`
-	TemplatePath  string `yaml:"template_path"`
-	PresetsPath   string `yaml:"presets_path"`
-	AWSProfile    string `yaml:"aws_profile"`
-	S3Bucket      string `yaml:"s3_bucket"`
-	AWSRegion     string `yaml:"aws_region"`
-	TerraformPath string `yaml:"terraform_path"`
-}
+// git and the Proxmox API are reached through these, so tests can swap in fakes
+var (
+	commands    execx.Runner = execx.System{}
+	proxmoxHTTP proxmox.Doer = proxmox.InsecureHTTP()
+)
 
 // Utility: check git dirty state and branch
 func getGitStatus(repoPath string) (branch string, dirty bool, err error) {
-	cmd := exec.Command("git", "-C", repoPath, "status", "--porcelain", "--branch")
-	out, err := cmd.Output()
-	if err != nil {
-		return "", false, err
-	}
-	lines := strings.Split(string(out), "\n")
-	branch = "main"
-	if len(lines) > 0 && strings.HasPrefix(lines[0], "## ") {
-		branchLine := lines[0][3:]
-		if idx := strings.Index(branchLine, "..."); idx > 0 {
-			branch = branchLine[:idx]
-		} else if idx := strings.Index(branchLine, " "); idx > 0 {
-			branch = branchLine[:idx]
-		} else {
-			branch = branchLine
-		}
-	}
-	for _, l := range lines[1:] {
-		if len(strings.TrimSpace(l)) > 0 {
-			return branch, true, nil
-		}
-	}
-	return branch, false, nil
+	return git.Status(commands, repoPath)
 }
 
-func updateStatusBars(m *model) {
-	// AWS
-	awsIcon := ""
-	awsStyleOK := lipgloss.NewStyle().Foreground(lipgloss.Color("#44cc11"))  // green
-	awsStyleErr := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff4444")) // red
-	awsOK, vaultOK := getEnvStatus(m.cfg)
-	if awsOK {
-		m.awsStatus = awsStyleOK.Render(awsIcon)
-	} else {
-		m.awsStatus = awsStyleErr.Render(awsIcon)
-	}
-
-	// Vault
-	vaultIcon := "󰌾"
-	vaultStyleOK := lipgloss.NewStyle().Foreground(lipgloss.Color("#44cc11"))  // green
-	vaultStyleErr := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff4444")) // red
-	if vaultOK {
-		m.vaultStatus = vaultStyleOK.Render(vaultIcon)
-	} else {
-		m.vaultStatus = vaultStyleErr.Render(vaultIcon)
-	}
+// statusSnapshot holds the raw health/git checks so they can be gathered off the UI loop
+type statusSnapshot struct {
+	awsOK         bool
+	awsExpired    bool
+	vaultOK       bool
+	awsDetail     string
+	vaultDetail   string
+	healthChecked bool // false until checkHealth ran; the icons stay dim
+	branch        string
+	dirty         bool
+	gitErr        error
+	catalogLock   *catalogLock
+}
 
-	// Git
-	branch, dirty, err := getGitStatus(m.cfg.TerraformPath)
-	gitIcon := ""
-	gitStyleClean := lipgloss.NewStyle().Foreground(lipgloss.Color("#44cc11")) // green
-	gitStyleDirty := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")) // orange
-	gitStyleErr := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff4444"))   // red
-	if err != nil {
-		m.gitStatus = gitStyleErr.Render(fmt.Sprintf("%s ?", gitIcon))
-	} else if dirty {
-		m.gitStatus = gitStyleDirty.Render(fmt.Sprintf("%s %s", gitIcon, branch))
-	} else {
-		m.gitStatus = gitStyleClean.Render(fmt.Sprintf("%s %s", gitIcon, branch))
+// Git status only; the Vault/AWS API calls are added by checkHealth on background refreshes
+func collectStatus(cfg Config) statusSnapshot {
+	var s statusSnapshot
+	s.catalogLock = readCatalogLock(cfg)
+	if safeMode {
+		s.gitErr = errSafeMode
+		s.awsDetail, s.vaultDetail, s.healthChecked = errSafeMode.Error(), errSafeMode.Error(), true
+		return s
 	}
+	s.branch, s.dirty, s.gitErr = getGitStatus(cfg.TerraformPath)
+	return s
 }
 
-func getProxmoxCredsFromVault(cluster string) (apiUrl, tokenId, tokenSecret string, err error) {
-	vaultAddr := os.Getenv("VAULT_ADDR")
-	if vaultAddr == "" {
-		vaultAddr = "http://127.0.0.1:8200" // change as needed
-	}
-	roleID := os.Getenv("TF_VAR_role_id")
-	secretID := os.Getenv("TF_VAR_secret_id")
-	if roleID == "" || secretID == "" {
-		return "", "", "", fmt.Errorf("vault approle credentials not set")
-	}
+func updateStatusBars(m *model) {
+	applyStatusSnapshot(m, collectStatus(m.cfg))
+}
 
-	cfg := vault.DefaultConfig()
-	cfg.Address = vaultAddr
-	client, err := vault.NewClient(cfg)
-	if err != nil {
-		return "", "", "", err
+// Engine and version for the status bar, red when apply would be refused
+func terraformStatus() string {
+	text := tfEngine.Name + " " + tfEngine.Version
+	if tfEngine.Binary == "" {
+		text = "no terraform"
 	}
-	// Login with AppRole
-	secret, err := client.Logical().Write("auth/approle/login", map[string]interface{}{
-		"role_id":   roleID,
-		"secret_id": secretID,
-	})
-	if err != nil || secret == nil || secret.Auth == nil {
-		return "", "", "", fmt.Errorf("vault appRole login failed: %v", err)
-	}
-	client.SetToken(secret.Auth.ClientToken)
-
-	// Read secret for cluster
-	secretPath := fmt.Sprintf("proxmox_api_keys/data/%s", cluster)
-	kv, err := client.Logical().Read(secretPath)
-	if err != nil || kv == nil || kv.Data == nil {
-		return "", "", "", fmt.Errorf("vault read failed for %s: %v", secretPath, err)
+	if tfEngine.Err != nil {
+		return errorStyle.Render(text + " ✗")
 	}
-	data := kv.Data
+	return okStyle.Render(text)
+}
 
-	// Vault kv v2 compat
-	if v2, ok := data["data"].(map[string]interface{}); ok {
-		data = v2
+func applyStatusSnapshot(m *model, s statusSnapshot) {
+	// AWS and Vault
+	m.awsStatus = awsIcon(s.awsOK, s.awsExpired, s.healthChecked)
+	m.vaultStatus = healthIcon(icons.Vault, s.vaultOK, s.healthChecked)
+	m.catalogLock = s.catalogLock
+	if s.healthChecked {
+		m.awsOK, m.awsDetail = s.awsOK, s.awsDetail
+		m.vaultOK, m.vaultDetail = s.vaultOK, s.vaultDetail
 	}
 
-	apiUrl, _ = data["proxmox_api_url"].(string)
-	tokenId, _ = data["proxmox_api_token_id"].(string)
-	tokenSecret, _ = data["proxmox_api_token_secret"].(string)
-	if apiUrl == "" || tokenId == "" || tokenSecret == "" {
-		return "", "", "", fmt.Errorf("missing fields in Vault secret %s", secretPath)
+	// Git
+	branch, dirty, err := s.branch, s.dirty, s.gitErr
+	if err != nil {
+		m.gitStatus = errorStyle.Render(fmt.Sprintf("%s ?", icons.Git))
+	} else if dirty {
+		m.gitStatus = warnStyle.Render(fmt.Sprintf("%s %s%s", icons.Git, branch, noColorMark("*")))
+	} else {
+		m.gitStatus = okStyle.Render(fmt.Sprintf("%s %s", icons.Git, branch))
 	}
-	return apiUrl, tokenId, tokenSecret, nil
 }
 
 type ProxmoxVM struct {
@@ -189,35 +188,39 @@ type ProxmoxVM struct {
 	Template int    `json:"template"`
 }
 
-func listProxmoxTemplates(apiUrl, tokenId, tokenSecret string) ([]ProxmoxVM, error) {
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // only for trusted internal use!
-		},
-	}
-	url := fmt.Sprintf("https://%s:8006/api2/json/cluster/resources?type=vm", apiUrl)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// Performs an authenticated GET against the Proxmox API and decodes the "data" member into out
+func proxmoxGet(apiUrl, tokenId, tokenSecret, path string, out interface{}) error {
+	return proxmoxRequest("GET", apiUrl, tokenId, tokenSecret, path, nil, out)
+}
 
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("PVEAPIToken=%s=%s", tokenId, tokenSecret))
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// POST with form parameters, e.g. to take a snapshot; out receives the task's UPID
+func proxmoxPost(apiUrl, tokenId, tokenSecret, path string, form url.Values, out interface{}) error {
+	return proxmoxRequest("POST", apiUrl, tokenId, tokenSecret, path, form, out)
+}
+
+func proxmoxRequest(method, apiUrl, tokenId, tokenSecret, path string, form url.Values, out interface{}) (err error) {
+	started := time.Now()
+	defer func() { logProxmox(apiUrl, path, started, err) }()
+	client := &proxmox.Client{Host: apiUrl, TokenID: tokenId, TokenSecret: tokenSecret, HTTP: proxmoxHTTP}
+	if method == "POST" {
+		err = client.Post(path, form, out)
+	} else {
+		err = client.Get(path, out)
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	var parsed struct {
-		Data []ProxmoxVM `json:"data"`
+	var re *proxmox.RequestError
+	if errors.As(err, &re) {
+		return &ProxmoxError{re.Err}
 	}
-	if err := json.Unmarshal(body, &parsed); err != nil {
+	return err
+}
+
+func listProxmoxTemplates(apiUrl, tokenId, tokenSecret string) ([]ProxmoxVM, error) {
+	var vms []ProxmoxVM
+	if err := proxmoxGet(apiUrl, tokenId, tokenSecret, "cluster/resources?type=vm", &vms); err != nil {
 		return nil, err
 	}
 	var templates []ProxmoxVM
-	for _, vm := range parsed.Data {
+	for _, vm := range vms {
 		if vm.Template == 1 {
 			templates = append(templates, vm)
 		}
@@ -226,10 +229,10 @@ func listProxmoxTemplates(apiUrl, tokenId, tokenSecret string) ([]ProxmoxVM, err
 }
 
 func fetchTemplatesForCluster(cluster string) ([]string, error) {
-	apiURL, tokenID, tokenSecret, err := getProxmoxCredsFromVault(cluster)
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(cluster)
 	if err != nil {
 		//fmt.Printf("[DEBUG] Vault error: %v\n", err)
-		return nil, fmt.Errorf("failed to get Proxmox creds from Vault: %w", err)
+		return nil, fmt.Errorf("failed to get Proxmox creds: %w", err)
 	}
 	//fmt.Printf("[DEBUG] Vault returned: apiURL=%q, tokenID=%q\n", apiURL, tokenID)
 
@@ -239,34 +242,64 @@ func fetchTemplatesForCluster(cluster string) ([]string, error) {
 		return nil, fmt.Errorf("failed to list Proxmox VMs: %w", err)
 	}
 
+	// Every template on the cluster; the create form applies template_filter
 	var templates []string
-	includeRe := regexp.MustCompile(`^ubuntu-server-24\.04\..*`)
 	for _, vm := range vms {
 		if vm.Template == 1 {
-			name := vm.Name
-			// Only include if matches includeRe AND does NOT end with -test
-			if includeRe.MatchString(name) && !strings.HasSuffix(name, "-test") {
-				templates = append(templates, name)
-			}
+			templates = append(templates, vm.Name)
 		}
 	}
 	//fmt.Printf("[DEBUG] Templates found: %#v\n", templates)
 	return templates, nil
 }
 
-func loadConfig(path string) (Config, error) {
-	var cfg Config
-	f, err := os.ReadFile(path)
+// Returns the field keys in the order they appear in a fields.yaml file
+func loadFieldOrder(path string) ([]string, error) {
+	var doc struct {
+		Fields yaml.Node `yaml:"fields"`
+	}
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return cfg, err
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
 	}
-	err = yaml.Unmarshal(f, &cfg)
+	var keys []string
+	for i := 0; i+1 < len(doc.Fields.Content); i += 2 {
+		keys = append(keys, doc.Fields.Content[i].Value)
+	}
+	return keys, nil
+}
+
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	err := loadYAMLStrict(path, &cfg)
 	return cfg, err
 }
 
 type Preset struct {
 	Name   string
 	Values map[string]interface{}
+	Path   string
+	presetDoc
+}
+
+// Optional first document of a preset file, before the values:
+//
+//	description: 3 VMs spread over hosts
+//	docs: |
+//	  Longer notes shown in the preset manager.
+//	template_dir: ../k8s-node   # optional: terraform module to deploy instead of the template's
+//	---
+//	vm_count: 3
+type presetDoc struct {
+	Description string `yaml:"description"`
+	Docs        string `yaml:"docs"`
+	// Directory copied into new deployments instead of the template's own; relative to the template dir
+	TemplateDir string `yaml:"template_dir"`
+	// Preferred clusters per zone; the create form picks the first with room
+	Placement placementPolicy `yaml:"placement"`
 }
 
 func loadPresets(presetsDir string) ([]Preset, error) {
@@ -278,119 +311,112 @@ func loadPresets(presetsDir string) ([]Preset, error) {
 	for _, e := range entries {
 		if !e.IsDir() && strings.HasSuffix(e.Name(), ".yaml") {
 			path := filepath.Join(presetsDir, e.Name())
-			values, err := loadPreset(path)
+			values, doc, err := loadPreset(path)
 			if err != nil {
+				logger.Warn("skipping preset", "path", path, "error", err.Error())
 				continue
 			}
 			name := strings.TrimSuffix(e.Name(), ".yaml")
-			out = append(out, Preset{Name: name, Values: values})
+			out = append(out, Preset{Name: name, Values: values, Path: path, presetDoc: doc})
 		}
 	}
 	return out, nil
 }
-func loadPreset(path string) (map[string]interface{}, error) {
+
+// Reads a preset file: the values alone, or a presetDoc document followed by the values
+func loadPreset(path string) (map[string]interface{}, presetDoc, error) {
 	var out map[string]interface{}
+	var doc presetDoc
 	f, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, doc, err
+	}
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(f))
+	for {
+		var n yaml.Node
+		if err := dec.Decode(&n); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, doc, err
+		}
+		docs = append(docs, &n)
+	}
+	switch len(docs) {
+	case 0:
+		return out, doc, nil
+	case 1:
+		err = docs[0].Decode(&out)
+	case 2:
+		if err := decodeNodeStrict(path, docs[0], &doc); err != nil {
+			return nil, doc, err
+		}
+		err = docs[1].Decode(&out)
+	default:
+		err = fmt.Errorf("%s: expected at most two YAML documents (docs, then values), found %d", path, len(docs))
 	}
-	err = yaml.Unmarshal(f, &out)
-	return out, err
+	return out, doc, err
 }
 
 func loadTfvars(filename string) (map[string]string, error) {
-	m := make(map[string]string)
-	f, err := os.Open(filename)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
-			continue
-		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(parts[0])
-		val := strings.TrimSpace(parts[1])
-		m[key] = val
-	}
-	return m, scanner.Err()
+	return tfvars.Load(filename)
 }
+
 func saveTfvars(filename string, updates map[string]string) error {
+	return rewriteTfvars(filename, updates, replaceTfvarsLines)
+}
+
+// saveTfvars for a new deployment: variables the template's terraform.tfvars doesn't assign
+// (advanced, conditional or registry fields) are appended instead of dropped
+func setTfvars(filename string, updates map[string]string) error {
+	return rewriteTfvars(filename, updates, tfvars.SetLines)
+}
+
+func rewriteTfvars(filename string, updates map[string]string, rewrite func([]string, map[string]string) []string) error {
 	input, err := os.ReadFile(filename)
 	if err != nil {
 		return err
 	}
-	lines := strings.Split(string(input), "\n")
-	for i, line := range lines {
-		for key, newval := range updates {
-			if strings.HasPrefix(strings.TrimSpace(line), key+" ") || strings.HasPrefix(strings.TrimSpace(line), key+"=") {
-				lines[i] = fmt.Sprintf("%s = %s", key, newval)
-			}
-		}
+	if err := backupTfvars(filename); err != nil {
+		return fmt.Errorf("could not back up tfvars: %w", err)
 	}
-	output := strings.Join(lines, "\n")
+	output := strings.Join(rewrite(strings.Split(string(input), "\n"), updates), "\n")
 	return os.WriteFile(filename, []byte(output), 0644)
 }
 
-func runTerraformInit(appDir string) error {
-	cmd := exec.Command("terraform", "init", "-input=false")
-	cmd.Dir = appDir
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("terraform init failed: %v\n%s", err, string(out))
-	}
-	return nil
+func replaceTfvarsLines(lines []string, updates map[string]string) []string {
+	return tfvars.ReplaceLines(lines, updates)
 }
 
-func runTerraformApply(appDir string) error {
-	cmd := exec.Command("terraform", "apply", "-auto-approve", "-input=false")
-	cmd.Dir = appDir
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("terraform apply failed: %v\n%s", err, string(out))
-	}
-	return nil
-}
+// launcher.state, see internal/state
+type (
+	DeploymentState = state.Deployment
+	stateChange     = state.Change
+)
 
-type DeploymentState struct {
-	State      string `yaml:"state"`
-	Timestamp  string `yaml:"timestamp"`
-	LastAction string `yaml:"last_action"`
+// Updates state/action in launcher.state, keeping any other recorded fields
+func setDeploymentState(path string, newState string, action string) error {
+	return setDeploymentStateWithVars(path, newState, action, nil)
 }
 
-func setDeploymentState(path string, state string, action string) error {
-	s := DeploymentState{
-		State:      state,
-		Timestamp:  time.Now().UTC().Format(time.RFC3339),
-		LastAction: action,
-	}
-	data, err := yaml.Marshal(s)
-	if err != nil {
-		return err
+// setDeploymentState for a step run with -var overrides, recorded in the history entry
+func setDeploymentStateWithVars(path string, newState string, action string, overrides []string) error {
+	s, _ := getDeploymentState(path)
+	changes := []string{"state: " + s.State + " → " + newState}
+	for _, o := range overrides {
+		changes = append(changes, "-var "+o)
 	}
-	return os.WriteFile(filepath.Join(path, "launcher.state"), data, 0644)
+	recordAuditChanges("state", path, action, "ok", changes)
+	s.Record(action, newState, currentIdentity(), overrides, time.Now())
+	return writeDeploymentState(path, s)
+}
+
+func writeDeploymentState(path string, s DeploymentState) error {
+	return state.Write(path, s)
 }
 
 func getDeploymentState(path string) (DeploymentState, error) {
-	var s DeploymentState
-	data, err := os.ReadFile(filepath.Join(path, "launcher.state"))
-	if err != nil {
-		s.State = "UNKNOWN"
-		s.Timestamp = ""
-		s.LastAction = ""
-		return s, err
-	}
-	err = yaml.Unmarshal(data, &s)
-	if err != nil {
-		s.State = "UNKNOWN"
-	}
-	return s, err
+	return state.Read(path)
 }
 
 // --- Deployments Listing ---
@@ -400,8 +426,25 @@ type deploymentInfo struct {
 	Description  string
 	State        string
 	LastAction   string
+	LastActionAt time.Time // zero when launcher.state has no timestamp
+	LastBy       string
+	LastVars     []string // -var overrides of the last action
 	LastModified string
 	Path         string
+	Zone         string
+	// Template name/commit stamped at creation time (empty for older deployments)
+	Template       string
+	TemplateCommit string
+	Artifacts      map[string]string
+	SecretsPath    string
+	Secrets        []string
+	Plan           *savedPlan
+	Failure        *failedRun
+	Health         *healthReport
+	Environment    string
+	Criticality    string
+	// VM sizing from tfvars, priced by the cost model
+	Size capacityRequest
 }
 
 func listDeployments(appsDir string) ([]deploymentInfo, error) {
@@ -409,36 +452,23 @@ func listDeployments(appsDir string) ([]deploymentInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	var infos []deploymentInfo
+	var dirs []string
 	for _, e := range entries {
 		if e.IsDir() {
-			full := filepath.Join(appsDir, e.Name())
-			stat, err := os.Stat(full)
-			if err != nil {
-				continue
-			}
-			desc := ""
-			tfvarsPath := filepath.Join(full, "terraform.tfvars")
-			if vals, err := loadTfvars(tfvarsPath); err == nil {
-				desc = strings.Trim(vals["platform_description"], "\"")
-			}
-			st, _ := getDeploymentState(full)
-			state := st.State
-			lastAction := ""
-			if st.Timestamp != "" {
-				lastAction = st.Timestamp[:16] // YYYY-MM-DDTHH:MM
-			}
-			infos = append(infos, deploymentInfo{
-				Name:         e.Name(),
-				Description:  desc,
-				State:        state,
-				LastAction:   lastAction,
-				LastModified: stat.ModTime().Format("2006-01-02 15:04"),
-				Path:         full,
-			})
+			dirs = append(dirs, filepath.Join(appsDir, e.Name()))
+		}
+	}
+	// Unchanged deployments come from the cache, so refreshing large catalogs stays cheap
+	return loadDeploymentsParallel(dirs), nil
+}
+
+func deploymentByPath(infos []deploymentInfo, path string) (deploymentInfo, bool) {
+	for _, d := range infos {
+		if d.Path == path {
+			return d, true
 		}
 	}
-	return infos, nil
+	return deploymentInfo{}, false
 }
 
 func copyDir(src string, dst string) error {
@@ -485,11 +515,6 @@ func copyDir(src string, dst string) error {
 
 // --- UI Constants, Helpers, and Styles ---
 
-var (
-	clusterOptions = []string{"cl10400", "cl12600k", "cl12900h", "cl13600k"}
-	zoneOptions    = []string{"standard", "admin", "dmz"}
-)
-
 func cycleOption(current string, options []string, dir int) string {
 	for i, opt := range options {
 		if opt == current {
@@ -507,22 +532,151 @@ const (
 	sceneCreateForm
 	sceneEditTable
 	sceneEditForm
+	scenePickTemplate
+	scenePresets
+	sceneLogs
+	sceneHelp
+	sceneS3State
+	sceneJobs
+	sceneSSH
+	sceneRollback
+	sceneAudit
+	sceneBulkEdit
+	sceneReplace
+	sceneCompare
+	sceneDisks
+	sceneNotes
+	sceneVault
+	sceneTemplateDiff
+	sceneOutput
+	sceneArchives
 )
 
 type model struct {
-	cfg           Config
-	presets       []Preset
-	presetIdx     int
-	fieldMeta     map[string]FieldMeta
-	helpText      string
-	currentScene  scene
-	statusMessage string
-
-	createInputs []textinput.Model
-	createLabels []string
-	createFocus  int
-
-	deployments []deploymentInfo
+	cfg          Config
+	templates    []Template
+	templateIdx  int
+	presets      []Preset
+	presetIdx    int
+	fieldMeta    map[string]FieldMeta
+	helpText     string
+	currentScene scene
+	navStack     []scene // scenes under currentScene, Esc goes back to the last one
+	termWidth    int     // from the last WindowSizeMsg
+	// Cluster chosen by the preset's placement policy, and whether it is being resolved
+	placement        *placementResult
+	placementPending bool
+	layout           launcherLayout
+	statusMessage    string
+
+	createInputs   []textinput.Model
+	createLabels   []string
+	createFocus    int
+	activeTemplate Template
+	// Value history for Ctrl+Z / Ctrl+Y
+	createHistory formHistory
+	// Deployment the create form was cloned from, if any
+	cloneSource string
+	// Validation/creation errors shown in the create form
+	createStatus string
+	// Whether the advanced section of the create form is expanded
+	showAdvanced bool
+	// Secret and sensitive values shown in clear (global reveal key); masked by default
+	revealSensitive bool
+	// Unified or side-by-side diffs (diff_mode, flipped with the global diff-mode key)
+	diffMode diffMode
+	// Create form sections folded with the collapse key
+	collapsedSections map[string]bool
+	// Node capacity of the selected cluster; capacityConfirmed is set after a capacity warning
+	capacity          *clusterCapacity
+	capacityConfirmed bool
+	// VMID range the pre-deploy checks picked, reserved when the deployment is created
+	vmids *vmidRange
+
+	presetMgrIdx    int
+	presetMgrMode   presetMgrMode
+	presetMgrStatus string
+	presetNameInput textinput.Model
+
+	// In-app log viewer
+	logEntries  []logEntry
+	logPager    pager
+	logFilter   textinput.Model
+	logMinLevel int
+	logStatus   string
+
+	// NOTES.md viewer of one deployment
+	notesDir      string
+	notesViewport viewport.Model
+	notesStatus   string
+
+	// File diff of a deployment's template commit against the current one
+	templateDiffTitle    string
+	templateDiffFiles    []templateFileDiff
+	templateDiffViewport viewport.Model
+	templateDiffLoading  bool // templateDiffCmd hasn't answered yet
+
+	// F1 help browser
+	helpEntries []helpEntry
+	helpFilter  textinput.Model
+	helpIdx     int
+
+	// Read-only audit log browser; auditShown is newest first after the filters
+	auditEntries    []auditEntry
+	auditShown      []auditEntry
+	auditTable      table.Model
+	auditFilter     textinput.Model
+	auditAction     string
+	auditDeployment string
+	auditStatus     string
+
+	// S3 remote state browser
+	stateEntries       []stateEntry
+	stateTable         table.Model
+	stateStatus        string
+	stateConfirmDelete bool
+	archives           []archiveMeta
+	archivesTable      table.Model
+	archivesStatus     string
+
+	// Proxmox credentials in Vault
+	vaultCreds       []vaultCred
+	vaultTable       table.Model
+	vaultCredsStatus string
+	vaultTests       map[string]string // cluster -> last test result
+	vaultForm        *vaultCredForm
+
+	// Waiting for the export format after [⇧E]
+	exportPrompt bool
+	// Cancel was pressed in a form with unsaved changes
+	leaveDialog bool
+	// Startup offer to resume the create-form draft; draftValues were last autosaved
+	draftPrompt bool
+	draftValues []string
+	// Stale state lock reported by a failed job, offered for force-unlock
+	lockDialog *stateLock
+	// Apply held by the deployment's guard rails until confirmed
+	applyConfirm *pendingApply
+	// Destroy of the selected deployment waiting for confirmation
+	destroyConfirm *destroyDialog
+	archiveConfirm *archiveDialog
+	// Apply with one-off -var overrides, waiting for the values
+	varsPrompt *varsPrompt
+
+	// Ctrl+O deployment finder over the launcher
+	quickOpen      bool
+	quickOpenInput textinput.Model
+	quickOpenIdx   int
+
+	// All deployments on disk, and the ones the launcher filters let through (shown in deployTable)
+	allDeployments []deploymentInfo
+	deployments    []deploymentInfo
+	stateFilter    stateFilter
+	zoneFilter     string
+	// Deployment paths marked with Space on the launcher
+	selected map[string]bool
+	// Deployment directory names pinned to the top (session.yaml)
+	favorites map[string]bool
 
 	editStatus string
 
@@ -530,217 +684,505 @@ type model struct {
 	editFormLabels []string
 	editFormPath   string
 	editFocusIndex int
+	editHistory    formHistory
+	// Values as last loaded from or saved to terraform.tfvars
+	editSaved []string
 
 	gitStatus   string
 	awsStatus   string
 	vaultStatus string
+	// Maintenance lock on the catalog, from the last status refresh
+	catalogLock *catalogLock
+	// Last health check results, shown by the Status key
+	awsOK, vaultOK         bool
+	awsDetail, vaultDetail string
 
 	deployTable table.Model
 	tfvarsTable table.Model
+	// Variable of each tfvars row, and the pane's focus and single-value edit (Tab on the launcher)
+	tfvarsKeys  []string
+	tfvarsFocus bool
+	quickEdit   *quickEdit
+
+	// Drift check results keyed by deployment path
+	drift map[string]driftStatus
+
+	// Current commit of the selected deployment's template and the changes it is behind by,
+	// looked up in the background for the deployment at templateInfoFor
+	templateInfoFor string
+	templateCommit  string
+	templateChanges []string
 
 	templatesForCluster []string
+	// All templates on the selected cluster; templatesForCluster is the filtered view unless showAllTemplates
+	clusterTemplates []string
+	showAllTemplates bool
 	// Optionally, a busy flag/loading state for UX
 	isFetchingTemplates bool
 
 	// --- NEW FIELDS ---
 	isBusy      bool
 	busyMessage string
+	busyStarted time.Time
+	busyToken   int
+	busySpinner spinner.Model
+	// Keys typed while busy, replayed into the form afterwards
+	typeahead        []tea.KeyMsg
+	typeaheadDropped int
+
+	refreshSpinner spinner.Model
+	isRefreshing   bool
+	// Debounced changes from the apps_path watcher; nil when watching is off
+	watchEvents chan tea.Msg
+
+	// Terraform job queue
+	jobs       []*job
+	nextJobID  int
+	jobsTable  table.Model
+	jobsScroll int // lines scrolled back from the end of the selected job's output
+	// Full output of one job in the pager
+	outputJobID int
+	outputPager pager
+	opSpinner   spinner.Model
+	opProgress  progress.Model
+
+	// Values the cluster and zone fields cycle through
+	clusterOptions []string
+	zones          []ZoneConfig
+
+	// tfvars rollback picker
+	rollbackVersions []tfvarsVersion
+	rollbackIdx      int
+
+	// Bulk variable edit over the selected deployments
+	bulkVar     textinput.Model
+	bulkValue   textinput.Model
+	bulkFocus   int
+	bulkTargets []bulkTarget
+	bulkIdx     int
+	bulkConfirm bool
+	bulkStatus  string
+
+	// Find and replace across all tfvars files
+	replaceFind    textinput.Model
+	replaceWith    textinput.Model
+	replaceRegex   bool
+	replaceFocus   int
+	replaceFiles   []replaceFile
+	replaceIdx     int
+	replaceConfirm bool
+	replaceStatus  string
+
+	// Side-by-side tfvars of two selected deployments
+	compareSides    [2]compareSide
+	compareRows     []compareRow
+	compareScroll   int
+	compareDiffOnly bool
+
+	// Disk list editor for vm_disk_size, opened from the create or edit form
+	diskInputs []textinput.Model
+	diskIdx    int
+	diskStatus string
+
+	// SSH target picker
+	sshTargets []sshTarget
+	sshIdx     int
+	sshDir     string
+
+	// History of status lines, shown in the F8 popup
+	messages     *messageLog
+	showMessages bool
+	// Full text of the last long status message (error panel)
+	errorReport      *errorReport
+	showErrorPanel   bool
+	errorPanelScroll int
+	errorPanelStatus string
+	messagesScroll   int
+
+	// Expanded key help ("?")
+	showKeyHelp bool
+
+	// Rendered panes kept between frames, plus the F12 frame-time overlay
+	render *renderCache
+	// Bumped whenever deployTable or tfvarsTable change (rows, columns, styles, scrolling);
+	// keys the launcher pane
+	tablesVersion int
+
+	// Last terminal title sent (status_title)
+	lastTitle string
+	// Terminal window focus, for notifications.when_unfocused
+	focus windowFocus
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	if safeMode {
+		// Only the local deployment list is refreshed
+		return tea.Batch(scheduleRefresh(refreshInterval(m.cfg)), waitForWatchEvent(m.watchEvents))
+	}
+	// The first refresh checks Vault and AWS health right away
+	return tea.Batch(scheduleRefresh(refreshInterval(m.cfg)), waitForWatchEvent(m.watchEvents), discoverClustersCmd(m.cfg), refreshCmd(m.cfg, false))
 }
 
 func main() {
 	cfg, err := loadConfig("config.yaml")
 	if err != nil {
 		fmt.Println("ERROR: could not load config.yaml:", err)
-		os.Exit(1)
+		os.Exit(exitConfig)
+	}
+	if err := configureTheme(cfg); err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(exitConfig)
 	}
 	presets, err := loadPresets(cfg.PresetsPath)
 	if err != nil {
 		fmt.Println("ERROR: could not load presets from presets dir:", err)
-		os.Exit(1)
+		os.Exit(exitConfig)
 	}
 	if len(presets) == 0 {
 		fmt.Println("No presets found in presets dir!")
-		os.Exit(1)
+		os.Exit(exitConfig)
 	}
-	fieldMeta, err := loadFieldMeta("fields.yaml")
+	fieldMeta, err := loadFieldMeta(globalFieldsPath)
 	if err != nil {
 		fmt.Println("ERROR: could not load fields.yaml:", err)
-		os.Exit(1)
+		os.Exit(exitConfig)
+	}
+	templates, err := loadTemplates(cfg, fieldMeta, presets)
+	if err != nil {
+		fmt.Println("ERROR: could not load templates:", err)
+		os.Exit(exitConfig)
+	}
+	if logFile, err := initLogging(); err != nil {
+		fmt.Println("WARNING: could not open log file:", err)
+	} else {
+		defer logFile.Close()
+	}
+	if secretsProvider, err = newSecretsProvider(cfg); err != nil {
+		fmt.Println("ERROR: invalid secrets_provider:", err)
+		os.Exit(exitConfig)
+	}
+	configureAudit(cfg.Audit)
+	configureProviders(cfg)
+	configureIdentity(cfg.Identity)
+	tfEngine = detectTerraform(cfg)
+	if err := configurePluginCache(cfg); err != nil {
+		fmt.Println("WARNING: could not set up the terraform plugin cache:", err)
+	}
+	currentIdentity() // resolved before the UI starts; sso_command may be slow
+	if ok, err := runCLI(cfg, templates, os.Args[1:]); ok {
+		closeAudit()
+		if err != nil {
+			fmt.Printf("ERROR (%s): %v\n", errorClass(err), err)
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+	configureLowBandwidth(cfg)
+	// Config errors exit before the session starts, so they don't count as unclean exits
+	if err := configureTimezone(cfg); err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(exitConfig)
+	}
+	if _, err := configureDiffMode(cfg); err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(exitConfig)
+	}
+	if cfg.CostModel != "" {
+		if costModel, err = loadCostModel(cfg.CostModel); err != nil {
+			fmt.Println("ERROR: could not load cost_model:", err)
+			os.Exit(exitConfig)
+		}
+	}
+	if err := loadKeyBindings(keysPath, &keys); err != nil {
+		fmt.Println("ERROR: could not load "+keysPath+":", err)
+		os.Exit(exitConfig)
+	}
+	safeReason := beginSession(cfg, slices.Contains(os.Args[1:], "--safe-mode"))
+	safeMode = safeReason != ""
+	logger.Info("starting", "apps_path", cfg.AppsPath, "templates", len(templates), "secrets_provider", secretsProvider.Name(), "safe_mode", safeMode)
+	m := initialModel(cfg, templates)
+	if !cfg.DisableWatch {
+		if m.watchEvents, err = startWatcher(cfg.AppsPath); err != nil {
+			logger.Warn("could not watch apps_path, changes made outside the launcher show on refresh", "component", "watch", "error", err.Error())
+		}
+	}
+	if safeMode {
+		m.statusMessage = "SAFE MODE (" + safeReason + "): Vault, Proxmox and git checks are off. Restart without --safe-mode once fixed."
 	}
-	m := initialModel(cfg, presets, fieldMeta)
-	if _, err := tea.NewProgram(m).Run(); err != nil {
+	_, err = tea.NewProgram(m, programOptions(cfg)...).Run()
+	closeAudit() // entries still queued by the last actions
+	if err != nil {
 		log.Fatal(err)
 	}
+	endSession()
 }
 
-func initialModel(cfg Config, presets []Preset, fieldMeta map[string]FieldMeta) model {
-	labels := []string{
-		"vm_app", "platform_description", "zone", "platform_id", "vm_network_suffix", "vm_id_prefix",
-		"vm_memory", "vm_cpu_cores", "vm_disk_count", "vm_disk_size", "vm_count", "vm_template",
-		"cluster",
+// Frame rate cap for low_bandwidth, focus events for notifications.when_unfocused
+func programOptions(cfg Config) []tea.ProgramOption {
+	var opts []tea.ProgramOption
+	if lowBandwidth {
+		opts = append(opts, tea.WithFPS(lowBandwidthFPS))
+	}
+	if cfg.Notifications.WhenUnfocused {
+		opts = append(opts, tea.WithReportFocus())
 	}
+	return opts
+}
 
-	// Deployments table
+func initialModel(cfg Config, templates []Template) model {
+	// Deployments table; the description column is sized by resizeLauncherTables
 	deployCols := []table.Column{
 		{Title: "Name", Width: 24},
 		{Title: "Description", Width: 32},
+		{Title: "Env", Width: 5},
 		{Title: "State", Width: 13},
-		{Title: "Last Action", Width: 20},
+		{Title: "Last Action", Width: 16},
+		{Title: "Health", Width: 9},
 	}
-	deployInfos, _ := listDeployments(cfg.AppsPath)
-	deployRows := make([]table.Row, len(deployInfos))
-	for i, info := range deployInfos {
-		deployRows[i] = table.Row{info.Name, info.Description, info.State, info.LastAction}
+	if costModel != nil {
+		deployCols = append(deployCols, table.Column{Title: "Cost/mo", Width: 10})
 	}
+	deployInfos, _ := listDeployments(cfg.AppsPath)
 	deployTable := table.New(
 		table.WithColumns(deployCols),
-		table.WithRows(deployRows),
+		table.WithRows(deploymentRows(deployInfos, nil, nil, nil)),
 		table.WithFocused(true),
+		table.WithKeyMap(tableKeys(keys.Launcher.Up, keys.Launcher.Down)),
+		table.WithStyles(tableStyles()),
 	)
 	deployTable.SetHeight(20)
 
-	tfvarsTable := loadTfvarsTableForDeployment(
-		cfg.AppsPath,
-		deployInfos,
-		0, // show first deployment at launch
-		fieldMeta,
-	)
-
-	inputs := make([]textinput.Model, len(labels))
-	presetIdx := 0
-	for i, name := range labels {
-		ti := textinput.New()
-		ti.Placeholder = name
-		if val, ok := presets[presetIdx].Values[name]; ok {
-			switch v := val.(type) {
-			case string:
-				ti.SetValue(v)
-			case int:
-				ti.SetValue(fmt.Sprintf("%d", v))
-			case []interface{}:
-				strs := []string{}
-				for _, e := range v {
-					strs = append(strs, fmt.Sprintf("%v", e))
-				}
-				ti.SetValue(strings.Join(strs, ","))
-			default:
-				ti.SetValue(fmt.Sprintf("%v", v))
-			}
-		}
-		inputs[i] = ti
-	}
-	// === ADD THIS SNIPPET BELOW ===
-	templateIdx := indexOf("vm_template", labels)
-	if templateIdx >= 0 {
-		inputs[templateIdx].Width = 40 // pick the width you want!
-	}
-	// ==============================
-	inputs[0].Focus()
-
 	m := model{
 		cfg:            cfg,
-		presets:        presets,
-		presetIdx:      0,
+		templates:      templates,
 		currentScene:   sceneLauncher,
-		createInputs:   inputs,
-		createLabels:   labels,
-		createFocus:    0,
-		fieldMeta:      fieldMeta,
 		helpText:       "",
 		editFormLabels: []string{"vm_cpu_cores", "vm_memory", "vm_count", "vm_disk_count", "vm_disk_size"},
+		allDeployments: deployInfos,
 		deployments:    deployInfos,
 		deployTable:    deployTable,
-		tfvarsTable:    tfvarsTable,
-	}
-
+		drift:          map[string]driftStatus{},
+		selected:       map[string]bool{},
+		favorites:      loadFavorites(),
+		refreshSpinner: newSpinner(spinner.Line),
+		opSpinner:      newSpinner(spinner.Dot),
+		busySpinner:    newSpinner(spinner.MiniDot),
+		opProgress:     newOpProgress(),
+		messages:       newMessageLog(),
+		render:         newRenderCache(),
+		clusterOptions: configClusters(cfg),
+		zones:          configZones(cfg),
+	}
+	m.diffMode, _ = configureDiffMode(cfg)
+
+	m = useTemplate(m, templates[0])
+	resizeLauncherTables(&m)
 	updateStatusBars(&m) // ← THIS IS ALL YOU NEED
-	return m
+
+	// show first deployment at launch, pinned ones on top
+	applyDeploymentFilter(&m)
+	return offerDraft(m)
 }
 
 // --- UI Rendering ---
 func (m model) View() string {
+	started := time.Now()
 	var header, body, tooltip, footer string
 
-	status := padLeft(fmt.Sprintf("%s  %s  %s", m.awsStatus, m.vaultStatus, m.gitStatus), uiWidth+65-len("Infrastructure Catalog"))
+	status := padLeft(fmt.Sprintf("%s  %s  %s  %s  %s", refreshIndicator(m), terraformStatus(), m.awsStatus, m.vaultStatus, m.gitStatus), uiWidth+68-len("Infrastructure Catalog"))
 
 	// ---- HEADER (bubbles/box style) ----
-	headerText := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("81")). // Light blue
-		Render("Infrastructure Catalog")
-	header = tooltipStyle.Render(centerText(headerText, uiWidth-len(status)) + status)
-	header += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+	filter, summary := filterLabel(m), headerSummary(m)
+	if crumbs := breadcrumbs(m); crumbs != "" {
+		summary = titleStyle.Render(crumbs) + headerSummaryStyle.Render(" • ") + summary
+	}
+	banner := catalogLockBanner(m)
+	header = m.render.pane("header", paneKey(status, filter, summary, banner), func() string {
+		headerText := titleStyle.Render("Infrastructure Catalog") + filter
+		h := tooltipStyle.Render(centerText(headerText, uiWidth-len(status)) + status)
+		h += "\n" + lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, summary)
+		if banner != "" {
+			h += "\n" + lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, banner)
+		}
+		return h + "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+	})
 
 	// ---- BODY (scene switch) ----
 	switch m.currentScene {
 	case sceneLauncher:
-		deployTableStr := m.deployTable.View()
-		tfvarsTableStr := m.tfvarsTable.View()
-		lines1 := strings.Split(deployTableStr, "\n")
-		lines2 := strings.Split(tfvarsTableStr, "\n")
-		col1Width := 89
-		col2Width := 68
-		maxLines := max(len(lines1), len(lines2))
-		for len(lines1) < maxLines {
-			lines1 = append(lines1, strings.Repeat(" ", col1Width))
-		}
-		for len(lines2) < maxLines {
-			lines2 = append(lines2, strings.Repeat(" ", col2Width))
-		}
-		var out string
-		for i := 0; i < maxLines; i++ {
-			out += padRight(lines1[i], col1Width) + " │ " + padRight(lines2[i], col2Width) + "\n"
-		}
-		body = out
-		tooltip = tooltipStyle.Render(m.statusMessage)
+		col2Width := m.layout.right
+		var detail []string
+		detail = append(detail, quickEditLines(m, col2Width)...)
+		detail = append(detail, classificationLines(m, col2Width)...)
+		detail = append(detail, lastActionLines(m, col2Width)...)
+		detail = append(detail, templateVersionLines(m, col2Width)...)
+		detail = append(detail, artifactLines(m, col2Width)...)
+		detail = append(detail, secretsLines(m, col2Width)...)
+		detail = append(detail, notesLines(m, col2Width)...)
+		detail = append(detail, planLines(m, col2Width)...)
+		detail = append(detail, failureLines(m, col2Width)...)
+		detail = append(detail, healthLines(m, col2Width)...)
+		// Keyed on what the tables show rather than their View, so a cache hit renders neither
+		tables := fmt.Sprint(m.layout, m.tablesVersion, m.deployTable.Cursor(), m.tfvarsTable.Cursor())
+		body = m.render.pane("launcher", paneKey(tables, filter, strings.Join(detail, "\n")), func() string {
+			return launcherBody(m.layout, m.deployTable.View(), m.tfvarsTable.View(), detail)
+		})
+		tooltip = statusTooltip(m.statusMessage)
 	case sceneCreateForm:
-		body += tooltipStyle.Render(fmt.Sprintf("[Preset: %s] (F2/F3 to switch)", m.presets[m.presetIdx].Name))
+		k := keys.Create
+		presetLine := fmt.Sprintf("[Preset: %s] (%s switch, %s manage)", m.presets[m.presetIdx].Name,
+			pairHelp(k.PrevPreset, k.NextPreset, "").Help().Key, k.Presets.Help().Key)
+		if dir := m.presets[m.presetIdx].TemplateDir; dir != "" {
+			presetLine = fmt.Sprintf("[Module: %s] ", dir) + presetLine
+		}
+		if len(m.templates) > 1 {
+			presetLine = fmt.Sprintf("[Template: %s] ", m.activeTemplate.Name) + presetLine
+		}
+		presetLine = createEnvironmentLabel(m) + placementLabel(m) + presetLine
+		if m.cloneSource != "" {
+			presetLine = fmt.Sprintf("[Cloning: %s — set a new Platform ID / Application Code] ", m.cloneSource) + presetLine
+		}
+		presetLine += modifiedSummary(countModified(len(m.createInputs), func(i int) bool { return createFieldModified(m, i) }), "from the preset")
+		body += tooltipStyle.Render(presetLine)
+		if notes := presetNotes(m.presets[m.presetIdx], 2); notes != "" {
+			body += "\n" + tooltipStyle.Render(notes)
+		}
 		body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
-		for i, ti := range m.createInputs {
-			cursor := " "
-			isFocused := i == m.createFocus
-			label := m.fieldMeta[m.createLabels[i]].Label
-			val := ti.Value()
-			display := padRight(val, 38)
-			field := ""
-			if isFocused {
-				field = focusedStyle.Render(fmt.Sprintf("%s %-25s: > %s", cursor, label, display))
-			} else {
-				field = normalStyle.Render(fmt.Sprintf("%s %-25s: > %s", cursor, label, display))
+		body += createFormLines(m) + "\n"
+		body += "\n  " + namePreviewLine(m) + "\n"
+		if m.createStatus != "" {
+			tooltip = statusTooltip(m.createStatus)
+		} else {
+			tooltip = tooltipStyle.Render(fieldHelp(m.fieldMeta[m.createLabels[m.createFocus]]))
+			if m.createLabels[m.createFocus] == "zone" {
+				if info := zoneInfo(m.zones, createValue(m, "zone")); info != "" {
+					tooltip += "\n" + tooltipStyle.Render(info)
+				}
 			}
-			body += field + "\n"
 		}
-		tooltip = tooltipStyle.Render(m.fieldMeta[m.createLabels[m.createFocus]].Help)
+		if lines := capacityLines(m); len(lines) > 0 {
+			tooltip += "\n" + tooltipStyle.Render(strings.Join(lines, "\n"))
+		}
+		if line := createCostLine(m); line != "" {
+			tooltip += "\n" + tooltipStyle.Render(line)
+		}
 	case sceneEditForm:
+		body += editEnvironmentLabel(m) + tooltipStyle.Render("Editing "+m.editFormPath+modifiedSummary(countModified(len(m.editFormInputs), func(i int) bool { return editFieldModified(m, i) }), "from the saved file"))
+		body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
 		for i, ti := range m.editFormInputs {
-			cursor := " "
-			isFocused := i == m.editFocusIndex
-			label := m.fieldMeta[m.editFormLabels[i]].Label
-			val := ti.Value()
-			display := padRight(val, 38)
-			field := ""
-			if isFocused {
-				field = focusedStyle.Render(fmt.Sprintf("%s %-25s: > %s", cursor, label, display))
-			} else {
-				field = normalStyle.Render(fmt.Sprintf("%s %-25s: > %s", cursor, label, display))
+			body += formFieldLine(m, fmt.Sprintf("edit:%d", i), m.fieldMeta[m.editFormLabels[i]].Label, inputDisplay(ti), i == m.editFocusIndex, editFieldModified(m, i)) +
+				fieldGauge(m, m.editFormLabels[i], ti.Value(), editValue(m, "zone"), editValue(m, "cluster")) + "\n"
+			if m.editFormLabels[i] == diskSizeField {
+				for _, l := range diskTableLines(ti.Value()) {
+					body += l + "\n"
+				}
 			}
-			body += field + "\n"
 		}
 		if m.editStatus != "" {
-			tooltip = tooltipStyle.Render(m.editStatus)
+			tooltip = statusTooltip(m.editStatus)
 		} else {
-			tooltip = tooltipStyle.Render(m.fieldMeta[m.editFormLabels[m.editFocusIndex]].Help)
+			tooltip = tooltipStyle.Render(fieldHelp(m.fieldMeta[m.editFormLabels[m.editFocusIndex]]))
+			if m.editFormLabels[m.editFocusIndex] == "zone" {
+				if info := zoneInfo(m.zones, m.editFormInputs[m.editFocusIndex].Value()); info != "" {
+					tooltip += "\n" + tooltipStyle.Render(info)
+				}
+			}
 		}
+	case scenePickTemplate:
+		body, tooltip = viewTemplatePicker(m)
+	case scenePresets:
+		body, tooltip = viewPresetManager(m)
+	case sceneLogs:
+		body, tooltip = viewLogViewer(m)
+	case sceneHelp:
+		body, tooltip = viewHelpBrowser(m)
+	case sceneS3State:
+		body, tooltip = viewStateBrowser(m)
+	case sceneArchives:
+		body, tooltip = viewArchives(m)
+	case sceneVault:
+		body, tooltip = viewVaultBrowser(m)
+	case sceneJobs:
+		body, tooltip = viewJobs(m)
+	case sceneSSH:
+		body, tooltip = viewSSHPicker(m)
+	case sceneRollback:
+		body, tooltip = viewRollback(m)
+	case sceneAudit:
+		body, tooltip = viewAudit(m)
+	case sceneBulkEdit:
+		body, tooltip = viewBulkEdit(m)
+	case sceneReplace:
+		body, tooltip = viewReplace(m)
+	case sceneCompare:
+		body, tooltip = viewCompare(m)
+	case sceneDisks:
+		body, tooltip = viewDiskEditor(m)
+	case sceneNotes:
+		body, tooltip = viewNotes(m)
+	case sceneTemplateDiff:
+		body, tooltip = viewTemplateDiff(m)
+	case sceneOutput:
+		body, tooltip = viewJobOutput(m)
 	default:
 		body, tooltip = "", ""
 	}
+	if m.isBusy {
+		body = overlayBusy(m, body)
+	}
+	if m.showMessages {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewMessagePopup(m)) + "\n"
+	} else if m.showErrorPanel {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewErrorPanel(m)) + "\n"
+	} else if m.lockDialog != nil {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewLockDialog(m)) + "\n"
+	} else if m.applyConfirm != nil {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewApplyConfirm(m)) + "\n"
+	} else if m.destroyConfirm != nil {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewDestroyDialog(m)) + "\n"
+	} else if m.archiveConfirm != nil {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewArchiveDialog(m)) + "\n"
+	} else if m.varsPrompt != nil {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewVarsPrompt(m)) + "\n"
+	} else if m.leaveDialog {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewLeaveDialog(m)) + "\n"
+	} else if m.quickOpen && m.currentScene == sceneLauncher {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewQuickOpen(m)) + "\n"
+	} else if m.showKeyHelp {
+		body = lipgloss.PlaceHorizontal(uiWidth-4, lipgloss.Center, viewKeyHelp(m)) + "\n"
+	}
+	if j := m.spotlightJob(); j != nil && m.currentScene == sceneLauncher {
+		text := viewOperation(m, j.Op)
+		if summary := jobsSummary(m); summary != "" {
+			text += "\n" + summary
+		}
+		tooltip = tooltipStyle.Render(text)
+	}
 
 	// ---- FOOTER: scene-dependent ----
 	footer = footerForScene(m)
+	if m.showMessages {
+		k := keys.Messages
+		footer = footerHelp(pairHelp(k.Up, k.Down, "Scroll"), pairHelp(k.PageUp, k.PageDown, "Page"), pairHelp(k.Oldest, k.Newest, "Top/Bottom"), k.Close)
+	} else if m.showErrorPanel {
+		k := keys.ErrorPanel
+		footer = footerHelp(pairHelp(k.Up, k.Down, "Scroll"), pairHelp(k.PageUp, k.PageDown, "Page"), k.Copy, k.Log, k.Close)
+	} else if m.leaveDialog {
+		k := keys.Leave
+		footer = footerHelp(k.Save, k.Discard, k.Cancel)
+	} else if m.destroyConfirm != nil && m.destroyConfirm.typed {
+		k := keys.Destroy
+		footer = footerHelp(hintHelp("Type", "Name"), k.Confirm, k.Cancel)
+	} else if m.archiveConfirm != nil {
+		k := keys.Archive
+		footer = footerHelp(k.Destroy, k.Keep, k.Cancel)
+	} else if m.varsPrompt != nil {
+		k := keys.Overrides
+		footer = footerHelp(hintHelp("Type", "name=value"), k.Apply, k.Cancel)
+	}
+	if m.render.debug {
+		tooltip += "\n" + logDimStyle.Render(m.render.overlay())
+	}
 
 	// ---- BOX WRAP ----
 	var result strings.Builder
@@ -758,19 +1200,86 @@ func (m model) View() string {
 	}
 	result.WriteString(strings.Repeat("\n", paddingLines))
 	result.WriteString("\n") // <-- Adds a blank line between tooltip and footer
-	result.WriteString(boxSection(footer))
+	result.WriteString(m.render.pane("footer", paneKey(footer), func() string { return boxSection(footer) }))
+	m.render.recordFrame(time.Since(started))
 	return result.String()
 }
 
-// Use this for your scene-based footer logic
+// One create/edit form row, re-styled only when its text or focus changes
+func formFieldLine(m model, name, label, value string, focused, modified bool) string {
+	text := fmt.Sprintf(" %-25s: > %s", label, padRight(value, 38))
+	return m.render.pane(name, paneKey(text, fmt.Sprint(focused), fmt.Sprint(modified)), func() string {
+		marker := modifiedStyle.Render(fieldMarker(modified))
+		if focused {
+			return marker + focusedStyle.Render(text)
+		}
+		return marker + normalStyle.Render(text)
+	})
+}
+
+// Footer built from the scene's key bindings, so it follows keys.yaml
 func footerForScene(m model) string {
+	help := keys.Global.Help
 	switch m.currentScene {
 	case sceneLauncher:
-		return centerText("[↑/↓] Field  │  [N] New  │  [A] Apply  │  [U] Update  │  [D] Destroy  │  [R] Refresh  │  [Esc] Cancel", uiWidth)
-	case sceneCreateForm:
-		return centerText("[↑/↓] Field │ [Tab] Next │ [Enter] Save │ [Esc] Cancel", uiWidth)
-	case sceneEditForm:
-		return centerText("[↑/↓] Field │ [Tab] Next │ [Enter] Save │ [A] Apply │ [Esc] Cancel", uiWidth)
+		if m.quickOpen {
+			return footerHelp(append([]key.Binding{hintHelp("Type", "Name")}, sceneFooter(m, "Match")...)...)
+		}
+		if m.quickEdit != nil {
+			return footerHelp(append([]key.Binding{hintHelp("Type", "Value")}, sceneFooter(m, "", "up", "down", "edit", "back")...)...)
+		}
+		if m.tfvarsFocus {
+			return footerHelp(append(sceneFooter(m, "Variable", "option_prev", "option_next", "decrease", "increase", "save", "cancel"), help)...)
+		}
+		return footerHelp(append(sceneFooter(m, "Deployment"), help)...)
+	case sceneCreateForm, sceneEditForm:
+		return footerHelp(sceneFooter(m, "Field")...)
+	case scenePickTemplate:
+		return footerHelp(append(sceneFooter(m, "Template"), help)...)
+	case scenePresets:
+		return footerHelp(append(sceneFooter(m, "Preset", "confirm_name", "cancel_name"), help)...)
+	case sceneSSH:
+		return footerHelp(append(sceneFooter(m, "VM"), help)...)
+	case sceneRollback:
+		return footerHelp(append(sceneFooter(m, "Version"), help)...)
+	case sceneJobs:
+		return footerHelp(append(sceneFooter(m, "Job"), help)...)
+	case sceneS3State:
+		return footerHelp(append(sceneFooter(m, "Select"), help)...)
+	case sceneArchives:
+		return footerHelp(append(sceneFooter(m, "Archive"), help)...)
+	case sceneVault:
+		if m.vaultForm != nil {
+			return footerHelp(sceneFooter(m, "", "up", "down", "new", "edit", "test", "reload", "back")...)
+		}
+		return footerHelp(append(sceneFooter(m, "Cluster", "form_next", "form_prev", "form_save", "form_cancel"), help)...)
+	case sceneHelp:
+		return footerHelp(append([]key.Binding{hintHelp("Type", "Search")}, sceneFooter(m, "Entry")...)...)
+	case sceneLogs:
+		if m.logPager.searching() {
+			return pagerSearchFooter()
+		}
+		// the log viewer scrolls and searches with the pager's keys
+		return footerHelp(append(append(pagerHelp(), sceneFooter(m, "", "filter_done")...), help)...)
+	case sceneAudit:
+		return footerHelp(append(sceneFooter(m, "Entry", "filter_done"), help)...)
+	case sceneBulkEdit:
+		return footerHelp(sceneFooter(m, "Deployment")...)
+	case sceneReplace:
+		return footerHelp(sceneFooter(m, "File")...)
+	case sceneCompare:
+		return footerHelp(append(sceneFooter(m, "Scroll"), help)...)
+	case sceneDisks:
+		return footerHelp(append([]key.Binding{hintHelp("Type", "Size")}, sceneFooter(m, "Disk")...)...)
+	case sceneNotes:
+		return footerHelp(append(append([]key.Binding{hintHelp("↑/↓ PgUp/PgDn", "Scroll")}, sceneFooter(m, "")...), help)...)
+	case sceneOutput:
+		if m.outputPager.searching() {
+			return pagerSearchFooter()
+		}
+		return footerHelp(append(sceneFooter(m, "Scroll", "search_done", "search_cancel"), keys.Jobs.Back, help)...)
+	case sceneTemplateDiff:
+		return footerHelp(append(append([]key.Binding{hintHelp("↑/↓ PgUp/PgDn", "Scroll")}, sceneFooter(m, "")...), keys.Global.DiffMode, help)...)
 	default:
 		return centerText("", uiWidth)
 	}
@@ -778,19 +1287,12 @@ func footerForScene(m model) string {
 
 func boxSection(content string) string {
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(boxBorder()).
 		Width(uiWidth - 4).
 		PaddingLeft(2).PaddingRight(2).
 		Render(content)
 }
 
-func padRight(s string, n int) string {
-	if len(s) >= n {
-		return s
-	}
-	return s + strings.Repeat(" ", n-len(s))
-}
-
 func centerText(s string, width int) string {
 	if len(s) >= width {
 		return s
@@ -827,8 +1329,9 @@ func countLines(s string) int {
 // 	return centerText("[↑/↓] Field │ [Tab] Next │ [Enter] Save │ [A] Apply │ [Esc] Cancel", uiWidth)
 // }
 
-// Loads tfvars for the selected deployment index, from real data
-func loadTfvarsTableForDeployment(appsPath string, infos []deploymentInfo, idx int, fieldMeta map[string]FieldMeta) table.Model {
+// Loads tfvars for the selected deployment index, from real data, sorted by variable; keys
+// holds the variable of each row
+func loadTfvarsTableForDeployment(appsPath string, infos []deploymentInfo, idx int, fieldMeta map[string]FieldMeta, reveal bool) (t table.Model, keys []string) {
 	tfvarsCols := []table.Column{
 		{Title: "Field", Width: 28},
 		{Title: "Value", Width: 35},
@@ -836,11 +1339,16 @@ func loadTfvarsTableForDeployment(appsPath string, infos []deploymentInfo, idx i
 	var tfvarsRows []table.Row
 	if idx >= 0 && idx < len(infos) {
 		tfvars, _ := loadTfvars(filepath.Join(infos[idx].Path, "terraform.tfvars"))
-		for k, v := range tfvars {
+		keys = slices.Sorted(maps.Keys(tfvars))
+		for _, k := range keys {
+			v := tfvars[k]
 			label := k
 			if meta, ok := fieldMeta[k]; ok && meta.Label != "" {
 				label = meta.Label
 			}
+			if !reveal {
+				v = maskFieldValue(fieldMeta[k], v)
+			}
 			tfvarsRows = append(tfvarsRows, table.Row{label, v})
 		}
 	}
@@ -848,23 +1356,132 @@ func loadTfvarsTableForDeployment(appsPath string, infos []deploymentInfo, idx i
 		table.WithColumns(tfvarsCols),
 		table.WithRows(tfvarsRows),
 		table.WithFocused(false),
+		table.WithStyles(tableStyles()),
 	)
 	tfvarsTable.SetHeight(20)
-	return tfvarsTable
+	return tfvarsTable, keys
+}
+
+// Refreshes the right-hand detail pane (tfvars + template version) for the selected deployment
+func loadDeploymentDetail(m *model, idx int) {
+	m.tablesVersion++
+	if idx < 0 || idx >= len(m.deployments) {
+		m.tfvarsTable, m.tfvarsKeys = loadTfvarsTableForDeployment(m.cfg.AppsPath, m.deployments, idx, m.fieldMeta, m.revealSensitive)
+		m.tfvarsTable.SetStyles(tfvarsTableStyles(m.tfvarsFocus))
+		resizeLauncherTables(m)
+		return
+	}
+	dep := m.deployments[idx]
+	t := templateByName(m.templates, dep.Template)
+	row := m.tfvarsTable.Cursor()
+	m.tfvarsTable, m.tfvarsKeys = loadTfvarsTableForDeployment(m.cfg.AppsPath, m.deployments, idx, t.fieldMeta, m.revealSensitive)
+	m.tfvarsTable.SetStyles(tfvarsTableStyles(m.tfvarsFocus))
+	if m.tfvarsFocus {
+		// a refresh while the pane has the focus keeps its row
+		m.tfvarsTable.SetCursor(row)
+	}
+	resizeLauncherTables(m)
 }
 
-// --- Update logic: only allow quit during isBusy
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if m.isBusy {
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			switch msg.String() {
-			case "q", "esc":
-				return m, tea.Quit
-			default:
-				return m, nil
-			}
-		default:
+	next, cmd := m.update(msg)
+	after, titleCmd := updateTerminalTitle(recordStatusMessages(m, next.(model)))
+	after, infoCmd := followTemplateInfo(after)
+	return after, tea.Batch(cmd, titleCmd, infoCmd)
+}
+
+// --- Update logic: while isBusy only background messages and cancel/quit keys get through
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m, ok := handleFocusMsg(m, msg); ok {
+		return m, nil
+	}
+	if m, cmd, ok := handleRefreshMsg(m, msg); ok {
+		return m, cmd
+	}
+	if m, cmd, ok := handleJobMsg(m, msg); ok {
+		return m, cmd
+	}
+	if msg, ok := msg.(templateInfoMsg); ok {
+		if msg.path == m.templateInfoFor {
+			m.templateCommit, m.templateChanges = msg.commit, msg.changes
+		}
+		return m, nil
+	}
+	if msg, ok := msg.(driftResultMsg); ok {
+		return handleDriftResult(m, msg), nil
+	}
+	if msg, ok := msg.(clustersDiscoveredMsg); ok {
+		return handleClustersDiscovered(m, msg), nil
+	}
+	if m, cmd, ok := handleSSHMsg(m, msg); ok {
+		return m, cmd
+	}
+	if msg, ok := msg.(notesEditedMsg); ok {
+		return handleNotesEdited(m, msg), nil
+	}
+	if msg, ok := msg.(healthCheckedMsg); ok {
+		return handleHealthChecked(m, msg)
+	}
+	if msg, ok := msg.(archiveDoneMsg); ok {
+		return handleArchiveDone(m, msg)
+	}
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.termWidth = msg.Width
+		resizeLauncherTables(&m)
+		return m, nil
+	}
+	if m, cmd, ok := handleBusyMsg(m, msg); ok {
+		return m, cmd
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Global.Frames) {
+		m.render.debug = !m.render.debug
+		return m, nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Global.Reveal) {
+		return toggleReveal(m), nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Global.DiffMode) {
+		return toggleDiffMode(m), nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Global.Messages) && !m.showMessages {
+		m.showMessages, m.messagesScroll, m.showKeyHelp = true, 0, false
+		return m, nil
+	}
+	if m.showMessages {
+		return updateMessagePopup(m, msg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Global.Errors) && !m.showErrorPanel {
+		return openErrorPanel(m), nil
+	}
+	if m.showErrorPanel {
+		return updateErrorPanel(m, msg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.lockDialog != nil {
+		return updateLockDialog(m, keyMsg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.applyConfirm != nil {
+		return updateApplyConfirm(m, keyMsg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.destroyConfirm != nil {
+		return updateDestroyDialog(m, keyMsg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.archiveConfirm != nil {
+		return updateArchiveDialog(m, keyMsg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.varsPrompt != nil {
+		return updateVarsPrompt(m, keyMsg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.leaveDialog {
+		return updateLeaveDialog(m, keyMsg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.showKeyHelp {
+			// Any key closes the overlay
+			m.showKeyHelp = false
+			return m, nil
+		}
+		if key.Matches(keyMsg, keys.Global.Help) && !typingScene(m) {
+			m.showKeyHelp = true
 			return m, nil
 		}
 	}
@@ -872,9 +1489,45 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case sceneLauncher:
 		return updateLauncher(m, msg)
 	case sceneCreateForm:
-		return updateCreateForm(m, msg)
+		return autosaveDraft(updateCreateWithHistory(m, msg, updateCreateForm))
 	case sceneEditForm:
-		return updateEditForm(m, msg)
+		return updateEditWithHistory(m, msg)
+	case scenePickTemplate:
+		return updateTemplatePicker(m, msg)
+	case scenePresets:
+		return updateCreateWithHistory(m, msg, updatePresetManager)
+	case sceneLogs:
+		return updateLogViewer(m, msg)
+	case sceneHelp:
+		return updateHelpBrowser(m, msg)
+	case sceneS3State:
+		return updateStateBrowser(m, msg)
+	case sceneArchives:
+		return updateArchives(m, msg)
+	case sceneVault:
+		return updateVaultBrowser(m, msg)
+	case sceneJobs:
+		return updateJobs(m, msg)
+	case sceneSSH:
+		return updateSSHPicker(m, msg)
+	case sceneRollback:
+		return updateRollback(m, msg)
+	case sceneAudit:
+		return updateAudit(m, msg)
+	case sceneBulkEdit:
+		return updateBulkEdit(m, msg)
+	case sceneReplace:
+		return updateReplace(m, msg)
+	case sceneCompare:
+		return updateCompare(m, msg)
+	case sceneDisks:
+		return updateDiskEditor(m, msg)
+	case sceneNotes:
+		return updateNotes(m, msg)
+	case sceneTemplateDiff:
+		return updateTemplateDiff(m, msg)
+	case sceneOutput:
+		return updateJobOutput(m, msg)
 	}
 	return m, nil
 }
@@ -882,64 +1535,235 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func updateLauncher(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k", "down", "j":
+		if m.exportPrompt {
+			return exportFromLauncher(m, msg), nil
+		}
+		if m.draftPrompt {
+			next, cmd, ok := answerDraft(m, msg)
+			if ok {
+				return next, cmd
+			}
+			m = next
+		}
+		if m.quickOpen {
+			return updateQuickOpen(m, msg)
+		}
+		if m.tfvarsFocus {
+			return updateTfvarsPane(m, msg)
+		}
+		switch {
+		case key.Matches(msg, keys.Launcher.Up, keys.Launcher.Down):
 			var cmd tea.Cmd
 			m.deployTable, cmd = m.deployTable.Update(msg)
-			selected := m.deployTable.Cursor()
-			m.tfvarsTable = loadTfvarsTableForDeployment(m.cfg.AppsPath, m.deployments, selected, m.fieldMeta)
+			m.tablesVersion++
+			loadDeploymentDetail(&m, m.deployTable.Cursor())
 			return m, cmd
-		case "n":
-			m.currentScene = sceneCreateForm
-			return m, nil
-		case "enter", "e":
+		case key.Matches(msg, keys.Launcher.New):
+			if len(m.templates) > 1 {
+				m = m.pushScene(scenePickTemplate)
+				return m, nil
+			}
+			if m.cloneSource != "" {
+				// Don't carry a previous clone's values into a fresh form
+				m = useTemplate(m, m.templates[0])
+			}
+			m = m.pushScene(sceneCreateForm)
+			m, placeCmd := resolvePlacement(m, true)
+			return m, tea.Batch(capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template")), placeCmd)
+		case key.Matches(msg, keys.Launcher.Clone):
 			idx := m.deployTable.Cursor()
 			if idx >= 0 && idx < len(m.deployments) {
-				dep := m.deployments[idx]
-				tfvars := filepath.Join(dep.Path, "terraform.tfvars")
-				vals, err := loadTfvars(tfvars)
+				cloned, err := cloneDeploymentForm(m, m.deployments[idx])
+				if err != nil {
+					m.statusMessage = "Could not clone deployment: " + err.Error()
+					return m, nil
+				}
+				return cloned.pushScene(sceneCreateForm), capacityCmd(cloned.activeTemplate.provider(), createValue(cloned, "cluster"), createValue(cloned, "vm_template"))
+			}
+		case key.Matches(msg, keys.Launcher.Edit):
+			idx := m.deployTable.Cursor()
+			if idx >= 0 && idx < len(m.deployments) {
+				dep := m.deployments[idx]
+				tfvars := filepath.Join(dep.Path, "terraform.tfvars")
+				vals, err := loadTfvars(tfvars)
 				if err != nil {
 					m.editStatus = "Could not load tfvars: " + err.Error()
 					return m, nil
 				}
 				// Build edit form with only editable fields
-				inputs, labels := buildEditFormInputs(vals, m.fieldMeta, m.createLabels)
+				t := templateByName(m.templates, dep.Template)
+				m.fieldMeta = t.fieldMeta
+				inputs, labels := buildEditFormInputs(vals, t.fieldMeta, t.Fields)
+				maskSecretInputs(inputs, labels, t.fieldMeta, m.revealSensitive)
+				refs := loadTfvarsRefs(tfvars)
+				for i, key := range labels {
+					if ref, ok := refs[key]; ok && strings.HasPrefix(ref, "sops:") {
+						inputs[i].Placeholder = "(in " + sopsSecretsFile + " — type to replace)"
+					} else if ok {
+						inputs[i].Placeholder = "(in Vault — type to replace)"
+					}
+				}
 				inputs[0].Focus()
 				m.editFormInputs = inputs
 				m.editFormLabels = labels
 				m.editFormPath = tfvars
 				m.editFocusIndex = 0
-				m.currentScene = sceneEditForm
+				m.editHistory = newFormHistory()
+				m = recordEditSaved(m)
+				m = m.pushScene(sceneEditForm)
 				return m, nil
 			}
-		case "q", "esc":
-			return m, tea.Quit
-		case "r", "R":
-			m.statusMessage = "Refreshing deployments..."
-			deployments, _ := listDeployments(m.cfg.AppsPath)
-			m.deployments = deployments
-			// Refresh deployTable and tfvarsTable as needed
-			deployRows := make([]table.Row, len(deployments))
-			for i, info := range deployments {
-				deployRows[i] = table.Row{info.Name, info.Description, info.State, info.LastAction}
+		case key.Matches(msg, keys.Launcher.Drift):
+			if len(m.deployments) == 0 {
+				return m, nil
+			}
+			if safeMode {
+				m.statusMessage = "Drift checks are disabled in safe mode"
+				return m, nil
+			}
+			m.statusMessage = fmt.Sprintf("Checking drift for %d deployment(s)...", len(m.deployments))
+			var cmd tea.Cmd
+			m, cmd = startDriftCheck(m)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.CopyKubeconfig):
+			idx := m.deployTable.Cursor()
+			if idx >= 0 && idx < len(m.deployments) {
+				loc, ok := kubeconfigLocation(m.deployments[idx])
+				if !ok {
+					m.statusMessage = "No kubeconfig recorded for this deployment"
+					return m, nil
+				}
+				m.statusMessage = "Copied kubeconfig path: " + loc
+				return m, copyToClipboard(loc)
 			}
-			m.deployTable.SetRows(deployRows)
-			m.tfvarsTable = loadTfvarsTableForDeployment(m.cfg.AppsPath, deployments, 0, m.fieldMeta)
-			// Refresh status bars in-place
-			updateStatusBars(&m)
-			m.statusMessage = "Deployments refreshed!"
 			return m, nil
+		case key.Matches(msg, keys.Launcher.Export):
+			m.exportPrompt = true
+			k := keys.Export
+			m.statusMessage = fmt.Sprintf("Export %d deployment(s) as %s JSON, %s CSV or %s Markdown? Any other key cancels.",
+				len(m.deployments), k.JSON.Help().Key, k.CSV.Help().Key, k.Markdown.Help().Key)
+			return m, nil
+		case key.Matches(msg, keys.Launcher.Retry):
+			return retryFailed(m)
+		case key.Matches(msg, keys.Launcher.Destroy):
+			return confirmDestroy(m)
+		case key.Matches(msg, keys.Launcher.Archive):
+			return openArchiveDialog(m)
+		case key.Matches(msg, keys.Launcher.Archives):
+			var cmd tea.Cmd
+			m, cmd = openArchives(m)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.ApplyVars):
+			return openVarsPrompt(m)
+		case key.Matches(msg, keys.Launcher.Reinit):
+			idx := m.deployTable.Cursor()
+			if idx < 0 || idx >= len(m.deployments) {
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m, cmd = enqueueJob(m, reinitOperation(m.deployments[idx].Path))
+			m.statusMessage = fmt.Sprintf("Queued job #%d: terraform init for %s", m.nextJobID, m.deployments[idx].Name)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.Plan):
+			idx := m.deployTable.Cursor()
+			if idx < 0 || idx >= len(m.deployments) {
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m, cmd = enqueueJob(m, planOperation(m.deployments[idx].Path))
+			m.statusMessage = fmt.Sprintf("Queued job #%d: terraform plan -out=%s for %s", m.nextJobID, planFile, m.deployments[idx].Name)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.ApplyPlan):
+			idx := m.deployTable.Cursor()
+			if idx < 0 || idx >= len(m.deployments) {
+				return m, nil
+			}
+			dep := m.deployments[idx]
+			p, err := checkSavedPlan(m.cfg, dep.Path)
+			if err != nil {
+				m.statusMessage = "Not applying " + dep.Name + ": " + err.Error()
+				return m, nil
+			}
+			m, cmd, held := guardApply(m, dep, applyPlanOperation(dep.Path, p, postApplyHook(m.cfg, templateByName(m.templates, dep.Template), dep.Path)))
+			if !held {
+				m.statusMessage = fmt.Sprintf("Queued job #%d: apply saved plan for %s (%s)", m.nextJobID, dep.Name, p.Summary)
+			}
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.Logs):
+			return openLogViewer(m), nil
+		case key.Matches(msg, keys.Launcher.Audit):
+			return openAudit(m), nil
+		case key.Matches(msg, keys.Launcher.SSH):
+			var cmd tea.Cmd
+			m, cmd = startSSH(m)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.Jobs):
+			return openJobs(m), nil
+		case key.Matches(msg, keys.Launcher.CancelJob):
+			j := m.selectedDeploymentJob()
+			if j == nil {
+				m.statusMessage = "No running or queued job for this deployment"
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m, cmd = cancelJob(m, j)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.FilterDeployed):
+			return toggleStateFilter(m, filterDeployed), nil
+		case key.Matches(msg, keys.Launcher.FilterFailed):
+			return toggleStateFilter(m, filterFailedOrDrifted), nil
+		case key.Matches(msg, keys.Launcher.FilterZone):
+			return cycleZoneFilter(m), nil
+		case key.Matches(msg, keys.Launcher.FilterClear):
+			m.stateFilter, m.zoneFilter = filterAllStates, ""
+			applyDeploymentFilter(&m)
+			return m, nil
+		case key.Matches(msg, keys.Launcher.Select):
+			return toggleSelected(m), nil
+		case key.Matches(msg, keys.Launcher.BulkEdit):
+			return openBulkEdit(m)
+		case key.Matches(msg, keys.Launcher.Replace):
+			return openReplace(m)
+		case key.Matches(msg, keys.Launcher.Compare):
+			return openCompare(m)
+		case key.Matches(msg, keys.Launcher.Notes):
+			return openNotes(m), nil
+		case key.Matches(msg, keys.Launcher.TemplateDiff):
+			return openTemplateDiff(m)
+		case key.Matches(msg, keys.Launcher.Test):
+			return testDeployment(m)
+		case key.Matches(msg, keys.Launcher.Health):
+			m.statusMessage = healthSummary(m)
+			return m, nil
+		case key.Matches(msg, keys.Launcher.Pin):
+			return toggleFavorite(m), nil
+		case key.Matches(msg, keys.Launcher.NextFavorite):
+			return jumpToFavorite(m), nil
+		case key.Matches(msg, keys.Launcher.Tfvars):
+			return focusTfvarsPane(m, true), nil
+		case key.Matches(msg, keys.Launcher.QuickOpen):
+			return openQuickOpen(m)
+		case key.Matches(msg, keys.Launcher.Vault):
+			var cmd tea.Cmd
+			m, cmd = openVaultBrowser(m)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.StateBrowser):
+			var cmd tea.Cmd
+			m, cmd = openStateBrowser(m)
+			return m, cmd
+		case key.Matches(msg, keys.Launcher.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Launcher.Refresh):
+			m.statusMessage = "Refreshing deployments..."
+			var cmd tea.Cmd
+			m, cmd = startRefresh(m, true)
+			return m, cmd
 
 		}
 	}
 	return m, nil
 }
 
-func (m model) withScene(s scene) model {
-	m.currentScene = s
-	return m
-}
-
 func buildEditFormInputs(tfvars map[string]string, fieldMeta map[string]FieldMeta, orderedFields []string) ([]textinput.Model, []string) {
 	var labels []string
 	for _, key := range orderedFields {
@@ -959,6 +1783,34 @@ func buildEditFormInputs(tfvars map[string]string, fieldMeta map[string]FieldMet
 	return inputs, labels
 }
 
+// Pre-fills the create form from an existing deployment's tfvars, clearing the fields
+// that make up the deployment name so a new platform_id/app has to be chosen
+func cloneDeploymentForm(m model, dep deploymentInfo) (model, error) {
+	vals, err := loadTfvars(filepath.Join(dep.Path, "terraform.tfvars"))
+	if err != nil {
+		return m, err
+	}
+	m = useTemplate(m, templateByName(m.templates, dep.Template))
+	for i, key := range m.createLabels {
+		if v, ok := vals[key]; ok && !isMaskedField(m.fieldMeta[key]) {
+			m.createInputs[i].SetValue(strings.Trim(v, "\"[]"))
+		}
+	}
+	for key, v := range map[string]string{"environment": dep.Environment, "criticality": dep.Criticality} {
+		if i := indexOf(key, m.createLabels); i >= 0 {
+			m.createInputs[i].SetValue(v)
+		}
+	}
+	m.cloneSource = dep.Name
+	if i := indexOf("platform_id", m.createLabels); i >= 0 {
+		m.createInputs[i].SetValue("")
+		m.createInputs[m.createFocus].Blur()
+		m.createFocus = i
+		m.createInputs[i].Focus()
+	}
+	return m, nil
+}
+
 // Utility: find index in your createLabels slice
 func indexOf(label string, labels []string) int {
 	for i, l := range labels {
@@ -976,14 +1828,15 @@ type templatesFetchedMsg struct {
 }
 
 // Async fetch function as a Bubbletea command
-func fetchTemplatesCmd(cluster string) tea.Cmd {
+func fetchTemplatesCmd(p Provider, cluster string) tea.Cmd {
 	return func() tea.Msg {
-		templates, err := fetchTemplatesForCluster(cluster)
+		templates, err := p.Templates(cluster)
 		return templatesFetchedMsg{templates, err}
 	}
 }
 
 type BusyFinishedMsg struct {
+	JobID        int
 	Success      bool
 	ErrorMessage string
 }
@@ -992,25 +1845,62 @@ func applyPresetToForm(m model, presetIdx int) model {
 	for i, label := range m.createLabels {
 		val, ok := m.presets[presetIdx].Values[label]
 		if ok {
-			switch v := val.(type) {
-			case string:
-				m.createInputs[i].SetValue(v)
-			case int:
-				m.createInputs[i].SetValue(fmt.Sprintf("%d", v))
-			case []interface{}:
-				strs := []string{}
-				for _, e := range v {
-					strs = append(strs, fmt.Sprintf("%v", e))
-				}
-				m.createInputs[i].SetValue(strings.Join(strs, ","))
-			default:
-				m.createInputs[i].SetValue(fmt.Sprintf("%v", v))
-			}
+			m.createInputs[i].SetValue(presetValueString(val))
 		}
 	}
 	return m
 }
 
+// Renders a preset YAML value the way the form expects it (lists become comma-separated)
+func presetValueString(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case int:
+		return fmt.Sprintf("%d", v)
+	case []interface{}:
+		strs := []string{}
+		for _, e := range v {
+			strs = append(strs, fmt.Sprintf("%v", e))
+		}
+		return strings.Join(strs, ",")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func newCreateInputs(labels []string, preset Preset) []textinput.Model {
+	inputs := make([]textinput.Model, len(labels))
+	for i, name := range labels {
+		ti := textinput.New()
+		ti.Placeholder = name
+		if val, ok := preset.Values[name]; ok {
+			ti.SetValue(presetValueString(val))
+		}
+		inputs[i] = ti
+	}
+	templateIdx := indexOf("vm_template", labels)
+	if templateIdx >= 0 {
+		inputs[templateIdx].Width = 40 // pick the width you want!
+	}
+	return inputs
+}
+
+// Value of a create-form field, or "" when the active template doesn't have it
+func createValue(m model, key string) string {
+	if i := indexOf(key, m.createLabels); i >= 0 {
+		return m.createInputs[i].Value()
+	}
+	return ""
+}
+
+func editValue(m model, key string) string {
+	if i := indexOf(key, m.editFormLabels); i >= 0 {
+		return m.editFormInputs[i].Value()
+	}
+	return ""
+}
+
 // Replace your updateCreateForm with:
 func updateCreateForm(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Index helpers
@@ -1034,179 +1924,142 @@ func updateCreateForm(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.createStatus = ""
+		capacityConfirmed := m.capacityConfirmed
+		m.capacityConfirmed = false
+		if msg.Paste {
+			return pasteCreateField(m, msg), nil
+		}
+		switch {
+		case key.Matches(msg, keys.Create.Help):
+			return openHelpBrowser(m), nil
+		case key.Matches(msg, keys.Create.Capacity):
+			m.createStatus = "Checking cluster capacity..."
+			m, placeCmd := resolvePlacement(m, false)
+			return m, tea.Batch(capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template")), placeCmd)
+		case key.Matches(msg, keys.Create.Presets):
+			return openPresetManager(m), nil
+		case key.Matches(msg, keys.Create.Advanced):
+			m = toggleAdvanced(m)
+			return m, nil
+		case key.Matches(msg, keys.Create.Disks):
+			return openDiskEditor(m, sceneCreateForm)
+		case key.Matches(msg, keys.Create.CollapseSection):
+			return collapseSection(m), nil
+		case key.Matches(msg, keys.Create.ExpandSections):
+			return expandSections(m), nil
+		case key.Matches(msg, keys.Create.AllTemplates):
+			m.showAllTemplates = !m.showAllTemplates
+			m = applyTemplateFilter(m)
+			return m, capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template"))
+		case key.Matches(msg, keys.Create.PrevPreset):
+			m.presetIdx = (m.presetIdx - 1 + len(m.presets)) % len(m.presets)
+			m = applyPresetToForm(m, m.presetIdx)
+			return resolvePlacement(m, true)
+		case key.Matches(msg, keys.Create.NextPreset):
+			m.presetIdx = (m.presetIdx + 1) % len(m.presets)
+			m = applyPresetToForm(m, m.presetIdx)
+			return resolvePlacement(m, true)
+		}
+		if m, ok := resetCreateFields(m, msg); ok {
+			return m, nil
+		}
+		if m, ok := stepCreateField(m, msg); ok {
+			return m, nil
+		}
+		if status, ok := guardDiskField(curLabel, msg, &keys.Create); ok {
+			m.createStatus = status
+			return m, nil
+		}
+		curOptions := m.fieldMeta[curLabel].Options
 		// Make these fields only cycle with left/right/space, block text input
-		if readonlyFields[curLabel] {
-			switch msg.String() {
-			case "left":
+		if readonlyFields[curLabel] || len(curOptions) > 0 {
+			switch {
+			case key.Matches(msg, keys.Create.OptionPrev):
 				switch curLabel {
 				case "zone":
 					cur := m.createInputs[m.createFocus].Value()
-					m.createInputs[m.createFocus].SetValue(cycleOption(cur, zoneOptions, -1))
+					m.createInputs[m.createFocus].SetValue(cycleOption(cur, zoneNames(m.zones), -1))
+					return resolvePlacement(m, true)
 				case "cluster":
 					cur := m.createInputs[clusterIdx].Value()
-					newCluster := cycleOption(cur, clusterOptions, -1)
+					newCluster := cycleOption(cur, clusterChoices(m, m.activeTemplate), -1)
 					m.createInputs[clusterIdx].SetValue(newCluster)
 					m.isFetchingTemplates = true
-					return m, fetchTemplatesCmd(newCluster)
+					return m, fetchTemplatesCmd(m.activeTemplate.provider(), newCluster)
 				case "vm_template":
 					if len(m.templatesForCluster) > 0 {
 						cur := m.createInputs[templateIdx].Value()
 						m.createInputs[templateIdx].SetValue(cycleOption(cur, m.templatesForCluster, -1))
+						return m, capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template"))
+					}
+				default:
+					cur := m.createInputs[m.createFocus].Value()
+					m.createInputs[m.createFocus].SetValue(cycleOption(cur, curOptions, -1))
+					if curLabel == "vm_clone_mode" {
+						return m, cloneCheckCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template"), createValue(m, "vm_clone_mode"))
 					}
 				}
-			case "right", " ":
+			case key.Matches(msg, keys.Create.OptionNext):
 				switch curLabel {
 				case "zone":
 					cur := m.createInputs[m.createFocus].Value()
-					m.createInputs[m.createFocus].SetValue(cycleOption(cur, zoneOptions, +1))
+					m.createInputs[m.createFocus].SetValue(cycleOption(cur, zoneNames(m.zones), +1))
+					return resolvePlacement(m, true)
 				case "cluster":
 					cur := m.createInputs[clusterIdx].Value()
-					newCluster := cycleOption(cur, clusterOptions, +1)
+					newCluster := cycleOption(cur, clusterChoices(m, m.activeTemplate), +1)
 					m.createInputs[clusterIdx].SetValue(newCluster)
 					m.isFetchingTemplates = true
-					return m, fetchTemplatesCmd(newCluster)
+					return m, fetchTemplatesCmd(m.activeTemplate.provider(), newCluster)
 				case "vm_template":
 					if len(m.templatesForCluster) > 0 {
 						cur := m.createInputs[templateIdx].Value()
 						m.createInputs[templateIdx].SetValue(cycleOption(cur, m.templatesForCluster, +1))
+						return m, capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template"))
+					}
+				default:
+					cur := m.createInputs[m.createFocus].Value()
+					m.createInputs[m.createFocus].SetValue(cycleOption(cur, curOptions, +1))
+					if curLabel == "vm_clone_mode" {
+						return m, cloneCheckCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template"), createValue(m, "vm_clone_mode"))
 					}
 				}
-			case "tab":
-				m.createFocus = (m.createFocus + 1) % len(m.createInputs)
-			case "shift+tab":
-				m.createFocus = (m.createFocus - 1 + len(m.createInputs)) % len(m.createInputs)
-			case "up":
-				m.createFocus = (m.createFocus - 1 + len(m.createInputs)) % len(m.createInputs)
-			case "down":
-				m.createFocus = (m.createFocus + 1) % len(m.createInputs)
-			case "esc", "ctrl+c":
-				return m.withScene(sceneLauncher), nil
-			case "f2":
-				m.presetIdx = (m.presetIdx - 1 + len(m.presets)) % len(m.presets)
-				m = applyPresetToForm(m, m.presetIdx)
-				return m, nil
-			case "f3":
-				m.presetIdx = (m.presetIdx + 1) % len(m.presets)
-				m = applyPresetToForm(m, m.presetIdx)
-				return m, nil
-			case "enter":
-				// You may want to allow enter to submit even if focus is on a cycling field
-				break // let it fall through below
+			case key.Matches(msg, keys.Create.Next):
+				m.createFocus = nextVisibleField(m, +1)
+			case key.Matches(msg, keys.Create.Prev):
+				m.createFocus = nextVisibleField(m, -1)
+			case key.Matches(msg, keys.Create.Up):
+				m.createFocus = nextVisibleField(m, -1)
+			case key.Matches(msg, keys.Create.Down):
+				m.createFocus = nextVisibleField(m, +1)
+			case key.Matches(msg, keys.Create.Cancel):
+				return leaveForm(m)
+			case key.Matches(msg, keys.Create.Save):
+				// Enter submits even when focus is on a cycling field; handled below
 			default:
 				// Ignore typing input for these fields
 				return m, nil
 			}
 		} else {
 			// Handle non-readonly fields as normal
-			switch msg.String() {
-			case "tab":
-				m.createFocus = (m.createFocus + 1) % len(m.createInputs)
-			case "shift+tab":
-				m.createFocus = (m.createFocus - 1 + len(m.createInputs)) % len(m.createInputs)
-			case "up":
-				m.createFocus = (m.createFocus - 1 + len(m.createInputs)) % len(m.createInputs)
-			case "down":
-				m.createFocus = (m.createFocus + 1) % len(m.createInputs)
-			case "esc", "ctrl+c":
-				return m.withScene(sceneLauncher), nil
+			switch {
+			case key.Matches(msg, keys.Create.Next):
+				m.createFocus = nextVisibleField(m, +1)
+			case key.Matches(msg, keys.Create.Prev):
+				m.createFocus = nextVisibleField(m, -1)
+			case key.Matches(msg, keys.Create.Up):
+				m.createFocus = nextVisibleField(m, -1)
+			case key.Matches(msg, keys.Create.Down):
+				m.createFocus = nextVisibleField(m, +1)
+			case key.Matches(msg, keys.Create.Cancel):
+				return leaveForm(m)
 			}
 		}
 
 		// Save/deploy logic (always allowed on Enter)
-		if msg.String() == "enter" {
-			provider := "proxmox"
-			app := m.createInputs[indexOf("vm_app", m.createLabels)].Value()
-			zone := m.createInputs[indexOf("zone", m.createLabels)].Value()
-			platformID := m.createInputs[indexOf("platform_id", m.createLabels)].Value()
-			appDir := fmt.Sprintf("%s_%s_%s_%s", provider, app, zone, platformID)
-			destPath := filepath.Join(m.cfg.AppsPath, appDir)
-
-			if _, err := os.Stat(destPath); err == nil {
-				m.statusMessage = fmt.Sprintf("Deployment '%s' already exists!", appDir)
-				return m, nil
-			}
-			if err := copyDir(m.cfg.TemplatePath, destPath); err != nil {
-				m.statusMessage = "Failed to copy template: " + err.Error()
-				return m, nil
-			}
-			updates := make(map[string]string)
-			stringFields := map[string]bool{
-				"platform_description": true,
-				"vm_app":               true,
-				"zone":                 true,
-				"cluster":              true,
-				"platform_id":          true,
-				"vm_template":          true,
-			}
-			for i, key := range m.createLabels {
-				v := m.createInputs[i].Value()
-				if key == "vm_disk_size" {
-					arr := []string{}
-					for _, part := range strings.Split(v, ",") {
-						s := strings.Trim(strings.TrimSpace(part), "\"")
-						arr = append(arr, fmt.Sprintf("\"%s\"", s))
-					}
-					updates[key] = "[" + strings.Join(arr, ", ") + "]"
-				} else if stringFields[key] {
-					updates[key] = fmt.Sprintf("\"%s\"", v)
-				} else {
-					updates[key] = v
-				}
-			}
-			tfvarsPath := filepath.Join(destPath, "terraform.tfvars")
-			if err := saveTfvars(tfvarsPath, updates); err != nil {
-				m.statusMessage = "Failed to write tfvars: " + err.Error()
-				return m, nil
-			}
-			regionLine := "ap-southeast-2"
-			if m.cfg.AWSRegion != "" {
-				regionLine = m.cfg.AWSRegion
-			}
-			profileLine := ""
-			if m.cfg.AWSProfile != "" {
-				profileLine = fmt.Sprintf("\n    profile         = \"%s\"", m.cfg.AWSProfile)
-			}
-			s3tf := fmt.Sprintf(
-				`terraform {
-  backend "s3" {
-    bucket          = "%s"
-    key             = "%s/s3/terraform.tfstate"
-    use_lockfile    = true
-    region          = "%s"
-    encrypt         = true%s
-  }
-}
-`, m.cfg.S3Bucket, appDir, regionLine, profileLine)
-			s3tfPath := filepath.Join(destPath, "s3.tf")
-			if err := os.WriteFile(s3tfPath, []byte(s3tf), 0644); err != nil {
-				m.statusMessage = "Failed to write s3.tf: " + err.Error()
-				return m, nil
-			}
-			if err := setDeploymentState(destPath, "READY", "save"); err != nil {
-				m.statusMessage = "Failed to write launcher.state: " + err.Error()
-				return m, nil
-			}
-			// Terraform actions
-			m.statusMessage = fmt.Sprintf("Deployment '%s' created. Running terraform init...", appDir)
-			if err := runTerraformInit(destPath); err != nil {
-				m.statusMessage = "terraform init failed: " + err.Error()
-				return m, nil
-			}
-			if err := setDeploymentState(destPath, "INITIALIZED", "init"); err != nil {
-				m.statusMessage = "Failed to update launcher.state (init): " + err.Error()
-				return m, nil
-			}
-			m.statusMessage = fmt.Sprintf("Deployment '%s' initialized. Running terraform apply...", appDir)
-			if err := runTerraformApply(destPath); err != nil {
-				m.statusMessage = "terraform apply failed: " + err.Error()
-				return m, nil
-			}
-			if err := setDeploymentState(destPath, "DEPLOYED", "apply"); err != nil {
-				m.statusMessage = "Failed to update launcher.state (apply): " + err.Error()
-				return m, nil
-			}
-			m.statusMessage = fmt.Sprintf("Deployment '%s' deployed and ready!", appDir)
-			return m.withScene(sceneLauncher), nil
+		if key.Matches(msg, keys.Create.Save) {
+			return submitCreateForm(m, capacityConfirmed)
 		}
 
 		// Focus/blur for all fields
@@ -1217,21 +2070,30 @@ func updateCreateForm(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.createInputs[i].Blur()
 			}
 		}
+	case preflightMsg:
+		return handlePreflight(m, msg)
+	case cloneCheckMsg:
+		m.createStatus = cloneCheckStatus(msg)
+		return m, nil
+	case placementMsg:
+		return handlePlacement(m, msg)
+	case capacityMsg:
+		c := clusterCapacity(msg)
+		m.capacity = &c
+		if m.createStatus == "Checking cluster capacity..." {
+			m.createStatus = ""
+		}
+		return m, nil
 	case templatesFetchedMsg:
 		m.isFetchingTemplates = false
 		if msg.err != nil {
 			m.statusMessage = "Could not fetch templates: " + msg.err.Error()
-			m.templatesForCluster = nil
+			m.clusterTemplates, m.templatesForCluster = nil, nil
 		} else {
-			m.templatesForCluster = msg.templates
-			// Set template field to first available if not empty
-			if templateIdx >= 0 && len(msg.templates) > 0 {
-				m.createInputs[templateIdx].SetValue(msg.templates[0])
-			} else if templateIdx >= 0 {
-				m.createInputs[templateIdx].SetValue("")
-			}
+			m.clusterTemplates = msg.templates
+			m = applyTemplateFilter(m)
 		}
-		return m, nil
+		return m, capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template"))
 	}
 	// Update textinputs
 	var cmds []tea.Cmd
@@ -1243,107 +2105,311 @@ func updateCreateForm(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func getEnvStatus(cfg Config) (vaultOK, awsOK bool) {
-	roleID := os.Getenv("TF_VAR_role_id")
-	secretID := os.Getenv("TF_VAR_secret_id")
-	vaultOK = roleID != "" && secretID != ""
-	awsProfile := os.Getenv("AWS_PROFILE")
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsProfile == "" {
-		awsProfile = cfg.AWSProfile
+// Creates the deployment directory from the form and queues its first apply
+func createDeployment(m model) (model, tea.Cmd) {
+	appDir, err := deploymentName(m)
+	if err != nil {
+		m.createStatus = err.Error()
+		return m, nil
 	}
-	if awsRegion == "" {
-		awsRegion = cfg.AWSRegion
+	destPath := filepath.Join(m.cfg.AppsPath, appDir)
+
+	if _, err := os.Stat(destPath); err == nil {
+		m.createStatus = fmt.Sprintf("Deployment '%s' already exists!", appDir)
+		return m, nil
 	}
-	awsOK = awsProfile != "" && awsRegion != ""
-	return
+	moduleDir, err := createModuleDir(m)
+	if err != nil {
+		m.createStatus = err.Error()
+		return m, nil
+	}
+	// m.vmids was reserved by the preflight command
+	if err := copyDir(moduleDir, destPath); err != nil {
+		m.statusMessage = "Failed to copy template: " + err.Error()
+		return m, nil
+	}
+	updates := make(map[string]string)
+	vaultValues := vaultFieldValues(m.createLabels, func(i int) string { return m.createInputs[i].Value() }, m.fieldMeta)
+	sensitiveValues := sensitiveFieldValues(m.createLabels, func(i int) string { return m.createInputs[i].Value() }, m.fieldMeta)
+	stringFields := map[string]bool{
+		"platform_description": true,
+		"vm_app":               true,
+		"zone":                 true,
+		"cluster":              true,
+		"platform_id":          true,
+		"vm_template":          true,
+	}
+	for i, key := range m.createLabels {
+		if !fieldApplies(m, key) || classificationFields[key] {
+			continue
+		}
+		v := m.createInputs[i].Value()
+		if v == "" && m.fieldMeta[key].Advanced {
+			continue
+		}
+		if isSecretField(m.fieldMeta[key]) && m.fieldMeta[key].Vault || isSensitiveField(m.fieldMeta[key]) {
+			continue
+		}
+		if key == "vm_disk_size" {
+			arr := []string{}
+			for _, part := range strings.Split(v, ",") {
+				s := strings.Trim(strings.TrimSpace(part), "\"")
+				arr = append(arr, fmt.Sprintf("\"%s\"", s))
+			}
+			updates[key] = "[" + strings.Join(arr, ", ") + "]"
+		} else if stringFields[key] || m.fieldMeta[key].Type == "string" || isSecretField(m.fieldMeta[key]) {
+			updates[key] = fmt.Sprintf("\"%s\"", v)
+		} else {
+			updates[key] = v
+		}
+	}
+	if m.vmids != nil {
+		// setTfvars adds the line when the template has none, so the reserved range is used
+		updates[m.cfg.VMIDRegistry.VarName()] = fmt.Sprint(m.vmids.Start)
+	}
+	tfvarsPath := filepath.Join(destPath, "terraform.tfvars")
+	if err := setTfvars(tfvarsPath, updates); err != nil {
+		m.statusMessage = "Failed to write tfvars: " + err.Error()
+		return m, nil
+	}
+	regionLine := "ap-southeast-2"
+	if m.cfg.AWSRegion != "" {
+		regionLine = m.cfg.AWSRegion
+	}
+	profileLine := ""
+	if m.cfg.AWSProfile != "" {
+		profileLine = fmt.Sprintf("\n    profile         = \"%s\"", m.cfg.AWSProfile)
+	}
+	s3tf := fmt.Sprintf(
+		`terraform {
+  backend "s3" {
+    bucket          = "%s"
+    key             = "%s/s3/terraform.tfstate"
+    use_lockfile    = true
+    region          = "%s"
+    encrypt         = true%s
+  }
+}
+`, m.cfg.S3Bucket, appDir, regionLine, profileLine)
+	s3tfPath := filepath.Join(destPath, "s3.tf")
+	if err := os.WriteFile(s3tfPath, []byte(s3tf), 0644); err != nil {
+		m.statusMessage = "Failed to write s3.tf: " + err.Error()
+		return m, nil
+	}
+	if err := setDeploymentState(destPath, "READY", "save"); err != nil {
+		m.statusMessage = "Failed to write launcher.state: " + err.Error()
+		return m, nil
+	}
+	commit, _ := getTemplateCommit(m.activeTemplate.Path)
+	if err := setDeploymentTemplate(destPath, m.activeTemplate.Name, commit); err != nil {
+		m.statusMessage = "Failed to stamp template version: " + err.Error()
+		return m, nil
+	}
+	if err := setDeploymentClassification(destPath, createValue(m, "environment"), createValue(m, "criticality")); err != nil {
+		m.statusMessage = "Failed to write launcher.state: " + err.Error()
+		return m, nil
+	}
+	secretsNote := ""
+	if len(m.activeTemplate.Secrets) > 0 {
+		vaultPath, err := seedSecrets(m.cfg, appDir, m.activeTemplate.Secrets)
+		if err != nil {
+			m.statusMessage = "Failed to seed secrets in Vault: " + err.Error()
+			return m, nil
+		}
+		if err := setDeploymentSecrets(destPath, vaultPath, m.activeTemplate.Secrets); err != nil {
+			m.statusMessage = "Failed to write launcher.state: " + err.Error()
+			return m, nil
+		}
+		secretsNote = fmt.Sprintf(" Secrets stored at vault:%s.", vaultPath)
+	}
+	if err := storeSecretValues(m.cfg, destPath, vaultValues); err != nil {
+		m.statusMessage = "Failed to store secret fields in Vault: " + err.Error()
+		return m, nil
+	}
+	if err := storeSensitiveValues(m.cfg, destPath, sensitiveValues); err != nil {
+		m.statusMessage = "Failed to encrypt sensitive fields with sops: " + err.Error()
+		return m, nil
+	}
+	clearDraft()
+	// Terraform actions run as a background job; progress shows on the launcher
+	recordAudit("create", destPath, "template "+m.activeTemplate.Name, "ok")
+	created := fmt.Sprintf("Deployment '%s' created.", appDir)
+	if m.vmids != nil {
+		created = fmt.Sprintf("Deployment '%s' created with VMIDs %s.", appDir, m.vmids)
+	}
+	if env := createValue(m, "environment"); guardRailsFor(m.cfg, env).RequirePlan {
+		m = m.homeScene()
+		m.statusMessage = fmt.Sprintf("%s %s deployments apply reviewed plans only — %s plans it, %s applies it.",
+			created, env, keys.Launcher.Plan.Help().Key, keys.Launcher.ApplyPlan.Help().Key)
+		return m, refreshDeploymentCmd(destPath)
+	}
+	op := deployOperation(destPath, fmt.Sprintf("Deployment '%s' deployed and ready!%s", appDir, secretsNote))
+	op.OnSuccess = postApplyHook(m.cfg, m.activeTemplate, destPath)
+	var cmd tea.Cmd
+	m, cmd = enqueueJob(m.homeScene(), op)
+	m.statusMessage = fmt.Sprintf("%s Queued job #%d (init + apply).", created, m.nextJobID)
+	return m, tea.Batch(cmd, refreshDeploymentCmd(destPath))
+}
+
+// Validates the create form and starts the pre-deploy checks; capacityConfirmed is true when
+// Enter was pressed again after a capacity warning
+func submitCreateForm(m model, capacityConfirmed bool) (tea.Model, tea.Cmd) {
+	if err := checkCatalogLock(m.cfg); err != nil {
+		m.createStatus = err.Error() + " — the form is kept, create once it's lifted"
+		return m, nil
+	}
+	if err := validateCreateForm(m); err != nil {
+		m.createStatus = err.Error()
+		return m, nil
+	}
+	if _, err := createModuleDir(m); err != nil {
+		m.createStatus = err.Error()
+		return m, nil
+	}
+	name, err := deploymentName(m)
+	if err == nil {
+		err = validateDeploymentName(m, name)
+	}
+	if err != nil {
+		m.createStatus = err.Error()
+		return m, nil
+	}
+	// The cluster checks talk to Proxmox, so they run behind the busy overlay
+	return startBusy(m, "Checking the cluster before deploying...", preflightCmd(m, capacityConfirmed))
+}
+
+// Writes the edit form to terraform.tfvars (and secret fields to Vault/sops); nothing is applied
+func saveEditForm(m model) (model, tea.Cmd) {
+	if err := deploymentBusy(m, filepath.Dir(m.editFormPath)); err != nil {
+		m.editStatus = err.Error() + " — save once it's done"
+		return m, nil
+	}
+	for i, key := range m.editFormLabels {
+		if err := checkStepperValue(m.fieldMeta[key], m.fieldMeta[key].Label, m.editFormInputs[i].Value()); err != nil {
+			m.editStatus = err.Error()
+			return m, nil
+		}
+	}
+	if i := indexOf(diskSizeField, m.editFormLabels); i >= 0 {
+		if err := validateDisks(parseDisks(m.editFormInputs[i].Value()), editValue(m, diskCountField)); err != nil {
+			m.editStatus = err.Error()
+			return m, nil
+		}
+	}
+	updates := make(map[string]string)
+	vaultValues := map[string]string{}
+	sensitiveValues := map[string]string{}
+	for i, key := range m.editFormLabels {
+		v := m.editFormInputs[i].Value()
+		meta := m.fieldMeta[key]
+		if v == "" && meta.Advanced {
+			continue
+		}
+		if isSecretField(meta) && meta.Vault {
+			if v != "" { // empty keeps what's in Vault
+				vaultValues[key] = v
+			}
+			continue
+		}
+		if isSensitiveField(meta) {
+			if v != "" { // empty keeps what's in secrets.sops.json
+				sensitiveValues[key] = v
+			}
+			continue
+		}
+		if key == "vm_disk_size" {
+			arr := []string{}
+			for _, part := range strings.Split(v, ",") {
+				s := strings.Trim(strings.TrimSpace(part), "\"")
+				arr = append(arr, fmt.Sprintf("\"%s\"", s))
+			}
+			updates[key] = "[" + strings.Join(arr, ", ") + "]"
+		} else if meta.Type == "string" || isSecretField(meta) {
+			updates[key] = fmt.Sprintf("\"%s\"", v)
+		} else {
+			updates[key] = v
+		}
+	}
+	changes := editChanges(m)
+	if err := saveTfvars(m.editFormPath, updates); err != nil {
+		m.editStatus = "Save failed: " + err.Error()
+		recordAuditChanges("edit", filepath.Dir(m.editFormPath), "terraform.tfvars", "failed: "+err.Error(), changes)
+	} else if err := storeSecretValues(m.cfg, filepath.Dir(m.editFormPath), vaultValues); err != nil {
+		m.editStatus = "Saved tfvars, but storing secret fields in Vault failed: " + err.Error()
+		recordAuditChanges("edit", filepath.Dir(m.editFormPath), "terraform.tfvars", "vault failed: "+err.Error(), changes)
+	} else if err := storeSensitiveValues(m.cfg, filepath.Dir(m.editFormPath), sensitiveValues); err != nil {
+		m.editStatus = "Saved tfvars, but encrypting sensitive fields with sops failed: " + err.Error()
+		recordAuditChanges("edit", filepath.Dir(m.editFormPath), "terraform.tfvars", "sops failed: "+err.Error(), changes)
+	} else {
+		m.editStatus = "Saved! (You may now apply changes as needed.)"
+		recordAuditChanges("edit", filepath.Dir(m.editFormPath), "terraform.tfvars", "ok", changes)
+		m = recordEditSaved(m)
+	}
+	return m, refreshDeploymentCmd(filepath.Dir(m.editFormPath))
 }
 
 func updateEditForm(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if msg.Paste {
+			return pasteEditField(m, msg), nil
+		}
 		curLabel := m.editFormLabels[m.editFocusIndex]
-		switch msg.String() {
-		case "esc", "q":
-			return m.withScene(sceneLauncher), nil
-		case "tab":
+		if m, ok := resetEditFields(m, msg); ok {
+			return m, nil
+		}
+		if m, ok := stepEditField(m, msg); ok {
+			return m, nil
+		}
+		if status, ok := guardDiskField(curLabel, msg, &keys.Edit); ok {
+			m.editStatus = status
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, keys.Edit.Help):
+			return openHelpBrowser(m), nil
+		case key.Matches(msg, keys.Edit.Rollback):
+			return openRollback(m), nil
+		case key.Matches(msg, keys.Edit.Disks):
+			return openDiskEditor(m, sceneEditForm)
+		case key.Matches(msg, keys.Edit.Cancel):
+			return leaveForm(m)
+		case key.Matches(msg, keys.Edit.Next):
 			m.editFocusIndex = (m.editFocusIndex + 1) % len(m.editFormInputs)
-		case "shift+tab":
+		case key.Matches(msg, keys.Edit.Prev):
 			m.editFocusIndex = (m.editFocusIndex - 1 + len(m.editFormInputs)) % len(m.editFormInputs)
-		case "up":
+		case key.Matches(msg, keys.Edit.Up):
 			m.editFocusIndex = (m.editFocusIndex - 1 + len(m.editFormInputs)) % len(m.editFormInputs)
-		case "down":
+		case key.Matches(msg, keys.Edit.Down):
 			m.editFocusIndex = (m.editFocusIndex + 1) % len(m.editFormInputs)
-		case "left":
+		case key.Matches(msg, keys.Edit.OptionPrev):
 			if curLabel == "zone" {
 				cur := m.editFormInputs[m.editFocusIndex].Value()
-				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, zoneOptions, -1))
+				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, zoneNames(m.zones), -1))
 			} else if curLabel == "cluster" {
 				cur := m.editFormInputs[m.editFocusIndex].Value()
-				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, clusterOptions, -1))
+				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, clusterChoices(m, editTemplate(m)), -1))
 			}
-		case "right":
+		case key.Matches(msg, keys.Edit.OptionNext):
 			if curLabel == "zone" {
 				cur := m.editFormInputs[m.editFocusIndex].Value()
-				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, zoneOptions, +1))
+				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, zoneNames(m.zones), +1))
 			} else if curLabel == "cluster" {
 				cur := m.editFormInputs[m.editFocusIndex].Value()
-				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, clusterOptions, +1))
+				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, clusterChoices(m, editTemplate(m)), +1))
 			}
-		case " ":
-			if curLabel == "zone" {
-				cur := m.editFormInputs[m.editFocusIndex].Value()
-				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, zoneOptions, +1))
-			} else if curLabel == "cluster" {
-				cur := m.editFormInputs[m.editFocusIndex].Value()
-				m.editFormInputs[m.editFocusIndex].SetValue(cycleOption(cur, clusterOptions, +1))
-			}
-		case "enter":
-			// Save tfvars only
-			updates := make(map[string]string)
-			for i, key := range m.editFormLabels {
-				v := m.editFormInputs[i].Value()
-				meta := m.fieldMeta[key]
-				if key == "vm_disk_size" {
-					arr := []string{}
-					for _, part := range strings.Split(v, ",") {
-						s := strings.Trim(strings.TrimSpace(part), "\"")
-						arr = append(arr, fmt.Sprintf("\"%s\"", s))
-					}
-					updates[key] = "[" + strings.Join(arr, ", ") + "]"
-				} else if meta.Type == "string" {
-					updates[key] = fmt.Sprintf("\"%s\"", v)
-				} else {
-					updates[key] = v
-				}
-			}
-			if err := saveTfvars(m.editFormPath, updates); err != nil {
-				m.editStatus = "Save failed: " + err.Error()
-			} else {
-				m.editStatus = "Saved! (You may now apply changes as needed.)"
-			}
-			return m, nil
-		case "a": // [A] Apply
+		case key.Matches(msg, keys.Edit.Save):
+			return saveEditForm(m)
+		case key.Matches(msg, keys.Edit.Apply):
 			deployDir := filepath.Dir(m.editFormPath)
-			m.editStatus = "Running terraform apply..."
-			if err := runTerraformInit(deployDir); err != nil {
-				m.editStatus = "terraform init failed: " + err.Error()
-				return m, nil
+			op := deployOperation(deployDir, "Deployment applied and ready!")
+			dep, _ := deploymentByPath(m.allDeployments, deployDir)
+			op.OnSuccess = postApplyHook(m.cfg, templateByName(m.templates, dep.Template), deployDir)
+			m, cmd, held := guardApply(m, dep, op)
+			if !held {
+				m.editStatus = fmt.Sprintf("Queued job #%d (init + apply) — follow it with %s Jobs on the launcher.", m.nextJobID, keys.Launcher.Jobs.Help().Key)
 			}
-			if err := setDeploymentState(deployDir, "INITIALIZED", "init"); err != nil {
-				m.editStatus = "Failed to update launcher.state (init): " + err.Error()
-				return m, nil
-			}
-			if err := runTerraformApply(deployDir); err != nil {
-				m.editStatus = "terraform apply failed: " + err.Error()
-				return m, nil
-			}
-			if err := setDeploymentState(deployDir, "DEPLOYED", "apply"); err != nil {
-				m.editStatus = "Failed to update launcher.state (apply): " + err.Error()
-				return m, nil
-			}
-			m.editStatus = "Deployment applied and ready!"
-			return m, nil
+			return m, cmd
 		}
 		for i := range m.editFormInputs {
 			if i == m.editFocusIndex {
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// fakeCommands stands in for git and terraform; every command succeeds with no output
+type fakeCommands struct{ calls []string }
+
+func (f *fakeCommands) Output(dir, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	return nil, nil
+}
+
+func (f *fakeCommands) Command(dir, name string, args ...string) *exec.Cmd {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	return exec.Command("true")
+}
+
+// Launcher model over a temporary apps_path holding the given deployments ("name" or
+// "name:STATE", default DEPLOYED), in safe mode and with git/terraform faked, so nothing
+// leaves the test
+func newTestModel(t testing.TB, deployments ...string) (model, *fakeCommands) {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+	fake := &fakeCommands{}
+	oldCommands, oldSafe := commands, safeMode
+	commands, safeMode = fake, true
+	t.Cleanup(func() { commands, safeMode = oldCommands, oldSafe })
+
+	apps := filepath.Join(root, "apps")
+	for _, d := range deployments {
+		name, state, ok := strings.Cut(d, ":")
+		if !ok {
+			state = "DEPLOYED"
+		}
+		dir := filepath.Join(apps, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		tfvars := "app_name = \"" + name + "\"\nvm_count = 1\nvm_memory = 2048\n"
+		if err := os.WriteFile(filepath.Join(dir, "terraform.tfvars"), []byte(tfvars), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeDeploymentState(dir, DeploymentState{State: state, LastAction: "apply"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(apps, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{AppsPath: apps, TemplatePath: filepath.Join(root, "template"), RefreshInterval: "0", DisableWatch: true}
+	preset := Preset{Name: "small", Values: map[string]interface{}{"vm_count": 1, "vm_memory": 2048}}
+	templates := []Template{{Name: "default", Path: cfg.TemplatePath, Fields: []string{"app_name", "vm_count", "vm_memory"},
+		fieldMeta: map[string]FieldMeta{}, presets: []Preset{preset}}}
+	return initialModel(cfg, templates), fake
+}
+
+func TestInitialModelListsDeployments(t *testing.T) {
+	m, _ := newTestModel(t, "web_a", "web_b", "db_a")
+	if len(m.deployments) != 3 {
+		t.Fatalf("got %d deployments, want 3", len(m.deployments))
+	}
+	view := m.View()
+	for _, name := range []string{"web_a", "web_b", "db_a", "DEPLOYED"} {
+		if !strings.Contains(view, name) {
+			t.Errorf("launcher view lacks %q", name)
+		}
+	}
+}
+
+func TestLauncherNavigation(t *testing.T) {
+	m, _ := newTestModel(t, "web_a", "web_b", "web_c")
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(uiWidth+20, uiHeight+10))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("web_c"))
+	}, teatest.WithDuration(5*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyUp})
+	tm.Quit()
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if got := final.deployTable.Cursor(); got != 1 {
+		t.Errorf("cursor at %d after down, down, up; want 1", got)
+	}
+	if d := final.deployments[final.deployTable.Cursor()]; d.Name == "" || final.tfvarsTable.Rows() == nil {
+		t.Errorf("no detail loaded for %+v", d)
+	}
+}
+
+func TestLauncherFilter(t *testing.T) {
+	m, _ := newTestModel(t, "web_a", "web_b:FAILED", "db_a")
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(uiWidth+20, uiHeight+10))
+	tm.Type(keys.Launcher.FilterFailed.Keys()[0])
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("FAILED"))
+	}, teatest.WithDuration(5*time.Second))
+	tm.Quit()
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.deployments) != 1 || final.deployments[0].Name != "web_b" {
+		t.Errorf("failed-only filter kept %d deployments", len(final.deployments))
+	}
+}
+
+func TestTemplateInfoLoadsInBackground(t *testing.T) {
+	m, fake := newTestModel(t, "web_a", "web_b")
+	safeMode = false
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(model)
+	if len(fake.calls) != 0 {
+		t.Fatalf("git ran inside Update: %q", fake.calls)
+	}
+	if want := m.deployments[1].Path; m.templateInfoFor != want {
+		t.Fatalf("templateInfoFor = %q, want %q", m.templateInfoFor, want)
+	}
+	if cmd == nil {
+		t.Fatal("no template lookup started")
+	}
+	// A result for a deployment that is no longer selected is dropped
+	next, _ = m.Update(templateInfoMsg{path: m.deployments[0].Path, commit: "stale"})
+	if got := next.(model).templateCommit; got != "" {
+		t.Errorf("templateCommit = %q from a stale lookup", got)
+	}
+	next, _ = m.Update(templateInfoMsg{path: m.deployments[1].Path, commit: "abc1234"})
+	if got := next.(model).templateCommit; got != "abc1234" {
+		t.Errorf("templateCommit = %q, want abc1234", got)
+	}
+}
+
+func TestTemplateDiffLoadsInBackground(t *testing.T) {
+	m, fake := newTestModel(t, "web_a")
+	m.deployments[0].TemplateCommit, m.templateCommit = "aaaaaaa", "bbbbbbb"
+	m, cmd := openTemplateDiff(m)
+	if len(fake.calls) != 0 {
+		t.Fatalf("git ran inside Update: %q", fake.calls)
+	}
+	if m.currentScene != sceneTemplateDiff || cmd == nil {
+		t.Fatalf("scene %v, cmd %v; want the diff screen with a lookup started", m.currentScene, cmd != nil)
+	}
+	msg, ok := cmd().(templateDiffMsg)
+	if !ok || len(fake.calls) == 0 {
+		t.Fatalf("lookup returned %T after %q", msg, fake.calls)
+	}
+	// A diff asked for by an earlier visit of the screen is dropped
+	next, _ := m.Update(templateDiffMsg{title: "Template default of db_a: ccccccc → bbbbbbb"})
+	if got := next.(model); !got.templateDiffLoading {
+		t.Error("stale diff accepted")
+	}
+	next, _ = m.Update(msg)
+	if got := next.(model); got.templateDiffLoading || !strings.HasSuffix(got.templateDiffTitle, "0 files changed") {
+		t.Errorf("loading %v, title %q after the diff arrived", got.templateDiffLoading, got.templateDiffTitle)
+	}
+}
+
+// Create form over a template whose terraform.tfvars assigns only vm_app and vm_count;
+// values fills the form by field name
+func newCreateModel(t *testing.T, meta map[string]FieldMeta, values map[string]string) model {
+	t.Helper()
+	m, _ := newTestModel(t)
+	tpl := filepath.Join(t.TempDir(), "template")
+	if err := os.MkdirAll(tpl, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tpl, "terraform.tfvars"), []byte("vm_app = \"\"\nvm_count = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"vm_app", "zone", "platform_id", "vm_count"}
+	for name := range meta {
+		fields = append(fields, name)
+	}
+	preset := Preset{Name: "default", Values: map[string]interface{}{}}
+	for k, v := range values {
+		preset.Values[k] = v
+	}
+	m = useTemplate(m, Template{Name: "default", Path: tpl, Fields: fields, fieldMeta: meta, presets: []Preset{preset}})
+	return m
+}
+
+// Variables of the deployment created from m
+func createdTfvars(t *testing.T, m model) map[string]string {
+	t.Helper()
+	m, _ = createDeployment(m)
+	if m.createStatus != "" {
+		t.Fatal(m.createStatus)
+	}
+	name, _ := deploymentName(m)
+	vars, err := loadTfvars(filepath.Join(m.cfg.AppsPath, name, "terraform.tfvars"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vars
+}
+
+func TestCreateDeploymentAddsAdvancedFieldsTheTemplateLacks(t *testing.T) {
+	meta := map[string]FieldMeta{"vm_cpu_type": {Type: "string", Advanced: true}, "vm_numa": {Advanced: true}}
+	m := newCreateModel(t, meta, map[string]string{"vm_app": "web", "zone": "dmz", "platform_id": "12", "vm_count": "2", "vm_cpu_type": "host"})
+	vars := createdTfvars(t, m)
+	if vars["vm_cpu_type"] != `"host"` || vars["vm_count"] != "2" {
+		t.Errorf("tfvars %v: want vm_cpu_type = \"host\", vm_count = 2", vars)
+	}
+	if _, ok := vars["vm_numa"]; ok {
+		t.Error("empty advanced field written")
+	}
+}
+
+func TestCreateDeploymentWritesReservedVMIDStart(t *testing.T) {
+	m := newCreateModel(t, map[string]FieldMeta{}, map[string]string{"vm_app": "web", "zone": "dmz", "platform_id": "12"})
+	m.cfg.VMIDRegistry = VMIDRegistryConfig{Path: "vmid-registry.yaml"}
+	m.vmids = &vmidRange{Cluster: "pve", Start: 3010, Count: 10}
+	if got := createdTfvars(t, m)["vm_id_start"]; got != "3010" {
+		t.Errorf("vm_id_start = %q, want 3010 although the template has no vm_id_start line", got)
+	}
+}
+
+func TestCreateDeploymentAddsConditionalFieldsTheTemplateLacks(t *testing.T) {
+	meta := map[string]FieldMeta{
+		"vm_ssh_keys":      {Type: "string", OSFamily: []string{"linux"}},
+		"vm_admin_pass":    {Type: "string", OSFamily: []string{"windows"}},
+		"vm_cloudinit_url": {Type: "string", ShowWhen: map[string]string{"zone": "dmz"}},
+	}
+	m := newCreateModel(t, meta, map[string]string{"vm_app": "web", "zone": "dmz", "platform_id": "12",
+		"vm_ssh_keys": "ssh-ed25519 AAAA", "vm_admin_pass": "ignored", "vm_cloudinit_url": "http://ci"})
+	vars := createdTfvars(t, m)
+	if vars["vm_ssh_keys"] != `"ssh-ed25519 AAAA"` || vars["vm_cloudinit_url"] != `"http://ci"` {
+		t.Errorf("tfvars %v: applying conditional fields missing", vars)
+	}
+	if _, ok := vars["vm_admin_pass"]; ok {
+		t.Error("field of another OS family written")
+	}
+}
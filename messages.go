@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Status message history ---
+
+const (
+	messageLogSize  = 300
+	messageLogLines = 20
+)
+
+type statusEntry struct {
+	At     time.Time
+	Source string // launcher, create or edit
+	Text   string
+}
+
+// messageLog is a ring buffer of the last messageLogSize status messages.
+// It is shared by pointer so model copies all append to the same history.
+type messageLog struct {
+	entries []statusEntry
+	next    int
+	full    bool
+}
+
+func newMessageLog() *messageLog {
+	return &messageLog{entries: make([]statusEntry, messageLogSize)}
+}
+
+func (l *messageLog) add(source, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if last, ok := l.last(); ok && last.Source == source && last.Text == text {
+		return
+	}
+	l.entries[l.next] = statusEntry{At: time.Now(), Source: source, Text: text}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+func (l *messageLog) last() (statusEntry, bool) {
+	if l.next == 0 && !l.full {
+		return statusEntry{}, false
+	}
+	return l.entries[(l.next-1+len(l.entries))%len(l.entries)], true
+}
+
+// Oldest first
+func (l *messageLog) list() []statusEntry {
+	if !l.full {
+		return append([]statusEntry(nil), l.entries[:l.next]...)
+	}
+	return append(append([]statusEntry(nil), l.entries[l.next:]...), l.entries[:l.next]...)
+}
+
+// Records any status line that changed while handling a message
+func recordStatusMessages(before, after model) model {
+	if after.messages == nil {
+		return after
+	}
+	if after.statusMessage != before.statusMessage {
+		after.messages.add("launcher", after.statusMessage)
+		after = captureErrorReport(after, "launcher", after.statusMessage)
+	}
+	if after.createStatus != before.createStatus {
+		after.messages.add("create", after.createStatus)
+		after = captureErrorReport(after, "create", after.createStatus)
+	}
+	if after.editStatus != before.editStatus {
+		after.messages.add("edit", after.editStatus)
+		after = captureErrorReport(after, "edit", after.editStatus)
+	}
+	return after
+}
+
+// --- Message history popup (F8, from any scene) ---
+
+func updateMessagePopup(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keys.Messages.Close):
+		m.showMessages = false
+	case key.Matches(keyMsg, keys.Messages.Up):
+		m.messagesScroll++
+	case key.Matches(keyMsg, keys.Messages.Down):
+		m.messagesScroll = max(m.messagesScroll-1, 0)
+	case key.Matches(keyMsg, keys.Messages.PageUp):
+		m.messagesScroll += messageLogLines
+	case key.Matches(keyMsg, keys.Messages.PageDown):
+		m.messagesScroll = max(m.messagesScroll-messageLogLines, 0)
+	case key.Matches(keyMsg, keys.Messages.Oldest):
+		m.messagesScroll = messageLogSize
+	case key.Matches(keyMsg, keys.Messages.Newest):
+		m.messagesScroll = 0
+	}
+	return m, nil
+}
+
+func viewMessagePopup(m model) string {
+	entries := m.messages.list()
+	var lines []string
+	for _, e := range entries {
+		// Multi-line messages (terraform errors) are shown by their first line
+		text := strings.SplitN(e.Text, "\n", 2)[0]
+		lines = append(lines, fmt.Sprintf("%s %s %s",
+			e.At.Format("15:04:05"), messageSourceStyle.Render(fmt.Sprintf("%-8s", e.Source)), truncate(text, uiWidth-40)))
+	}
+	scroll := min(m.messagesScroll, max(len(lines)-messageLogLines, 0))
+	end := len(lines) - scroll
+	start := max(end-messageLogLines, 0)
+	content := strings.Join(lines[start:end], "\n")
+	if len(lines) == 0 {
+		content = "(no messages yet)"
+	}
+	title := fmt.Sprintf("Status messages — %d of %d shown, newest last", end-start, len(lines))
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(popupBorder()).
+		Padding(0, 1).
+		Width(uiWidth - 24).
+		Render(title + "\n\n" + content)
+}
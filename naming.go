@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// --- Deployment naming convention ---
+
+const (
+	defaultNamingTemplate = "{{.Provider}}_{{.App}}_{{.Zone}}_{{.PlatformID}}"
+	defaultNamePattern    = `^[A-Za-z0-9][A-Za-z0-9_.-]*$`
+	defaultNameMaxLength  = 64
+)
+
+type namingData struct {
+	Provider   string
+	App        string
+	Zone       string
+	PlatformID string
+	Fields     map[string]string
+}
+
+// Renders the naming template from the create form
+func deploymentName(m model) (string, error) {
+	text := m.cfg.Naming.Template
+	if text == "" {
+		text = defaultNamingTemplate
+	}
+	tmpl, err := template.New("naming").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid naming.template: %w", err)
+	}
+	data := namingData{
+		Provider:   m.activeTemplate.provider().Name(),
+		App:        createValue(m, "vm_app"),
+		Zone:       createValue(m, "zone"),
+		PlatformID: createValue(m, "platform_id"),
+		Fields:     map[string]string{},
+	}
+	for i, key := range m.createLabels {
+		if !isMaskedField(m.fieldMeta[key]) {
+			data.Fields[key] = m.createInputs[i].Value()
+		}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("naming.template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Checks characters, length and that no deployment of that name exists
+func validateDeploymentName(m model, name string) error {
+	pattern := m.cfg.Naming.Pattern
+	if pattern == "" {
+		pattern = defaultNamePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid naming.pattern: %w", err)
+	}
+	maxLen := m.cfg.Naming.MaxLength
+	if maxLen <= 0 {
+		maxLen = defaultNameMaxLength
+	}
+	switch {
+	case name == "":
+		return fmt.Errorf("deployment name is empty")
+	case strings.Contains(name, "__") || strings.HasSuffix(name, "_"):
+		return fmt.Errorf("deployment name %q has an empty part; fill in the fields it is built from", name)
+	case !re.MatchString(name):
+		return fmt.Errorf("deployment name %q does not match %s", name, pattern)
+	case len(name) > maxLen:
+		return fmt.Errorf("deployment name %q is %d characters, at most %d allowed", name, len(name), maxLen)
+	}
+	for _, d := range m.allDeployments {
+		if d.Name == name {
+			return fmt.Errorf("deployment '%s' already exists", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(m.cfg.AppsPath, name)); err == nil {
+		return fmt.Errorf("deployment '%s' already exists", name)
+	}
+	return nil
+}
+
+// Preview line under the create form fields
+func namePreviewLine(m model) string {
+	name, err := deploymentName(m)
+	if err == nil {
+		err = validateDeploymentName(m, name)
+	}
+	line := "Deployment name: " + name
+	if err != nil {
+		return logWarnStyle.Render(line + "  ⚠ " + err.Error())
+	}
+	return line
+}
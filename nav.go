@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// --- Navigation stack and header breadcrumbs ---
+
+// Opens s on top of the current scene; Esc in s comes back here
+func (m model) pushScene(s scene) model {
+	m.navStack = append(append([]scene(nil), m.navStack...), m.currentScene)
+	m.currentScene = s
+	return m
+}
+
+// Goes back one level; the launcher is the bottom of the stack
+func (m model) popScene() model {
+	if len(m.navStack) == 0 {
+		m.currentScene = sceneLauncher
+		return m
+	}
+	m.currentScene = m.navStack[len(m.navStack)-1]
+	m.navStack = m.navStack[:len(m.navStack)-1]
+	return m
+}
+
+// Back to the launcher once a flow is done (deployment created, edit applied)
+func (m model) homeScene() model {
+	m.navStack = nil
+	m.currentScene = sceneLauncher
+	return m
+}
+
+// Scene the current one was opened from
+func (m model) previousScene() scene {
+	if len(m.navStack) == 0 {
+		return sceneLauncher
+	}
+	return m.navStack[len(m.navStack)-1]
+}
+
+var sceneTitles = map[scene]string{
+	sceneLauncher:     "Launcher",
+	sceneCreateForm:   "New deployment",
+	sceneEditForm:     "Edit",
+	scenePickTemplate: "Templates",
+	scenePresets:      "Presets",
+	sceneLogs:         "Logs",
+	sceneHelp:         "Help",
+	sceneS3State:      "S3 state",
+	sceneArchives:     "Archives",
+	sceneJobs:         "Jobs",
+	sceneSSH:          "SSH",
+	sceneRollback:     "Rollback",
+	sceneAudit:        "Audit",
+	sceneBulkEdit:     "Bulk edit",
+	sceneReplace:      "Find/replace",
+	sceneCompare:      "Compare",
+	sceneDisks:        "Disks",
+	sceneNotes:        "Notes",
+	sceneVault:        "Proxmox credentials",
+	sceneTemplateDiff: "Template changes",
+	sceneOutput:       "Output",
+}
+
+// Crumbs of one level: the edit form is named after its deployment, the create form
+// after its template
+func sceneCrumbs(m model, s scene) []string {
+	switch s {
+	case sceneEditForm:
+		return []string{filepath.Base(filepath.Dir(m.editFormPath)), sceneTitles[s]}
+	case sceneCreateForm:
+		if m.cloneSource != "" {
+			return []string{"Clone of " + m.cloneSource}
+		}
+		if m.activeTemplate.Name != "" {
+			return []string{sceneTitles[s] + " (" + m.activeTemplate.Name + ")"}
+		}
+	}
+	return []string{sceneTitles[s]}
+}
+
+// "Launcher › proxmox_web_dmz_12 › Edit"; empty on the launcher itself
+func breadcrumbs(m model) string {
+	if m.currentScene == sceneLauncher {
+		return ""
+	}
+	crumbs := []string{sceneTitles[sceneLauncher]}
+	for _, s := range append(append([]scene(nil), m.navStack...), m.currentScene) {
+		if s != sceneLauncher {
+			crumbs = append(crumbs, sceneCrumbs(m, s)...)
+		}
+	}
+	return strings.Join(crumbs, " › ")
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// --- Operational notes: NOTES.md in the deployment directory, rendered as markdown ---
+
+const notesFile = "NOTES.md"
+
+// notesEditedMsg is sent when the external editor exits
+type notesEditedMsg struct {
+	dir string
+	err error
+}
+
+// "Notes: updated 2026-10-15 09:12 ([N] read)" for the launcher's detail pane
+func notesLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return nil
+	}
+	st, err := os.Stat(filepath.Join(m.deployments[idx].Path, notesFile))
+	if err != nil {
+		return nil
+	}
+	return []string{truncate(fmt.Sprintf("Notes: updated %s (%s read)", relativeTime(st.ModTime()), keys.Launcher.Notes.Help().Key), width)}
+}
+
+func openNotes(m model) model {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m
+	}
+	m.notesDir = m.deployments[idx].Path
+	m.notesViewport = viewport.New(uiWidth-4, uiHeight-14)
+	return reloadNotes(m).pushScene(sceneNotes)
+}
+
+func reloadNotes(m model) model {
+	m.notesStatus = ""
+	data, err := os.ReadFile(filepath.Join(m.notesDir, notesFile))
+	switch {
+	case os.IsNotExist(err):
+		m.notesViewport.SetContent(fmt.Sprintf("(no %s yet — %s writes one)", notesFile, keys.Notes.Edit.Help().Key))
+		return m
+	case err != nil:
+		m.notesStatus = "Could not read notes: " + err.Error()
+		m.notesViewport.SetContent("")
+		return m
+	}
+	m.notesViewport.SetContent(renderMarkdown(string(data), m.notesViewport.Width-2))
+	return m
+}
+
+// Markdown for the terminal; the raw text when glamour can't render it
+func renderMarkdown(text string, width int) string {
+	style := styles.DarkStyle
+	if noColor {
+		style = styles.NoTTYStyle
+	}
+	r, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(width))
+	if err != nil {
+		return text
+	}
+	out, err := r.Render(text)
+	if err != nil {
+		return text
+	}
+	return strings.Trim(out, "\n")
+}
+
+// Suspends the TUI and opens NOTES.md in $VISUAL / $EDITOR (vi when neither is set)
+func editNotesCmd(dir string) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// "code --wait" and the like: the first word is the program
+	args := strings.Fields(editor)
+	args = append(args, filepath.Join(dir, notesFile))
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return notesEditedMsg{dir: dir, err: err}
+	})
+}
+
+func handleNotesEdited(m model, msg notesEditedMsg) model {
+	name := filepath.Base(msg.dir)
+	status := "Editor closed, notes of " + name + " re-read"
+	if msg.err != nil {
+		status = "Editor failed: " + msg.err.Error()
+		logger.Error("notes editor failed", "component", "notes", "deployment", name, "error", msg.err.Error())
+	} else {
+		recordAudit("notes", msg.dir, notesFile, "ok")
+	}
+	if m.currentScene != sceneNotes || m.notesDir != msg.dir {
+		m.statusMessage = status
+		return m
+	}
+	m = reloadNotes(m)
+	if m.notesStatus == "" {
+		m.notesStatus = status
+	}
+	return m
+}
+
+func updateNotes(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Notes.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.Notes.Edit):
+			return m, editNotesCmd(m.notesDir)
+		case key.Matches(keyMsg, keys.Notes.Reload):
+			return reloadNotes(m), nil
+		case key.Matches(keyMsg, keys.Notes.Top):
+			m.notesViewport.GotoTop()
+			return m, nil
+		case key.Matches(keyMsg, keys.Notes.Bottom):
+			m.notesViewport.GotoBottom()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.notesViewport, cmd = m.notesViewport.Update(msg)
+	return m, cmd
+}
+
+func viewNotes(m model) (body, tooltip string) {
+	body += tooltipStyle.Render("Notes: " + filepath.Join(m.notesDir, notesFile))
+	body += "\n" + m.notesViewport.View() + "\n"
+	msg := m.notesStatus
+	if msg == "" {
+		msg = fmt.Sprintf("%s opens the file in $EDITOR — %3.f%%", keys.Notes.Edit.Help().Key, m.notesViewport.ScrollPercent()*100)
+	}
+	tooltip = tooltipStyle.Render(msg)
+	return body, tooltip
+}
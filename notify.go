@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Notifications on job completion ---
+
+const defaultNotifyTemplate = "{{.Operation}}: {{.Result}} after {{.Duration}}{{if .Error}} — {{.Error}}{{end}}"
+
+type notification struct {
+	Deployment string
+	Operation  string
+	Result     string
+	Duration   time.Duration
+	Error      string
+}
+
+func notifyMinDuration(cfg NotifyConfig) time.Duration {
+	if d, err := time.ParseDuration(cfg.MinDuration); err == nil {
+		return d
+	}
+	return 30 * time.Second
+}
+
+func renderNotification(cfg NotifyConfig, n notification) string {
+	text := cfg.Template
+	if text == "" {
+		text = defaultNotifyTemplate
+	}
+	tmpl, err := template.New("notify").Parse(text)
+	if err != nil {
+		logger.Warn("bad notification template", "component", "notify", "error", err.Error())
+		tmpl = template.Must(template.New("notify").Parse(defaultNotifyTemplate))
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, n); err != nil {
+		return fmt.Sprintf("%s: %s", n.Operation, n.Result)
+	}
+	return b.String()
+}
+
+// Drops control characters, so text from a job or a template can't end an escape sequence
+// early or start one of its own
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Prints seq (bell, OSC) through the program, after text on a line of its own above the UI,
+// so it never lands in the middle of a frame being drawn
+func printSequence(text, seq string) tea.Cmd {
+	return tea.Printf("%s%s", text, seq)
+}
+
+// Bell and desktop notification escapes; OSC 777 separates title and body with ';'
+func notifyTerminal(cfg NotifyConfig, title, text string) tea.Cmd {
+	title, text = stripControl(title), stripControl(text)
+	var seq string
+	if cfg.Bell {
+		seq += "\a"
+	}
+	switch cfg.Desktop {
+	case "osc777":
+		seq += fmt.Sprintf("\x1b]777;notify;%s;%s\x1b\\", strings.ReplaceAll(title, ";", ","), text)
+	case "osc9":
+		seq += fmt.Sprintf("\x1b]9;%s\x1b\\", text)
+	}
+	if seq == "" {
+		return nil
+	}
+	return printSequence(text, seq)
+}
+
+func postWebhook(url, text string) error {
+	body, _ := json.Marshal(map[string]string{"text": text})
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Window focus as reported by the terminal (tea.WithReportFocus)
+type windowFocus struct {
+	reported bool // the terminal sent at least one focus event
+	blurred  bool
+}
+
+func handleFocusMsg(m model, msg tea.Msg) (model, bool) {
+	switch msg.(type) {
+	case tea.FocusMsg:
+		m.focus = windowFocus{reported: true}
+	case tea.BlurMsg:
+		m.focus = windowFocus{reported: true, blurred: true}
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// Whether bell/desktop notifications should fire now
+func terminalNotifyAllowed(cfg NotifyConfig, focus windowFocus) bool {
+	return !cfg.WhenUnfocused || (focus.reported && focus.blurred)
+}
+
+// notifyJobCmd sends the configured notifications for a finished job
+func notifyJobCmd(cfg NotifyConfig, j *job, focus windowFocus) tea.Cmd {
+	duration := j.Finished.Sub(j.Op.started).Round(time.Second)
+	if duration < notifyMinDuration(cfg) {
+		return nil
+	}
+	terminal := terminalNotifyAllowed(cfg, focus)
+	if !(terminal && (cfg.Bell || cfg.Desktop != "")) && cfg.WebhookURL == "" {
+		return nil
+	}
+	n := notification{
+		Deployment: filepath.Base(j.Op.Dir),
+		Operation:  j.Op.Label,
+		Result:     j.Status.String(),
+		Duration:   duration,
+		Error:      strings.SplitN(j.Err, "\n", 2)[0],
+	}
+	text := renderNotification(cfg, n)
+	var cmds []tea.Cmd
+	if terminal {
+		cmds = append(cmds, notifyTerminal(cfg, "Infrastructure Catalog", text))
+	}
+	if cfg.WebhookURL != "" {
+		cmds = append(cmds, func() tea.Msg {
+			err := postWebhook(cfg.WebhookURL, text)
+			if err != nil {
+				logger.Error("webhook notification failed", "component", "notify", "error", err.Error())
+			} else {
+				logger.Info("webhook notification sent", "component", "notify", "deployment", n.Deployment)
+			}
+			return nil
+		})
+	}
+	return tea.Batch(cmds...)
+}
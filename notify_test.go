@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNotifyTerminalStripsControlCharacters(t *testing.T) {
+	cmd := notifyTerminal(NotifyConfig{Bell: true, Desktop: "osc777"}, "Catalog;x", "apply: failed — \x1b]0;pwned\a\u009b2J")
+	if cmd == nil {
+		t.Fatal("no notification")
+	}
+	out := fmt.Sprint(cmd())
+	if want := "\a\x1b]777;notify;Catalog,x;apply: failed — ]0;pwned2J\x1b\\"; !strings.Contains(out, want) {
+		t.Errorf("printed %q, want it to contain %q", out, want)
+	}
+	if n := strings.Count(out, "\a"); n != 1 {
+		t.Errorf("%d bells in %q, want 1", n, out)
+	}
+	if cmd := notifyTerminal(NotifyConfig{}, "Catalog", "done"); cmd != nil {
+		t.Error("notification printed with bell and desktop off")
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/terraform"
+)
+
+// --- Long-running terraform operations ---
+
+// tfStep is one terraform invocation within an operation
+type tfStep = terraform.Step
+
+var (
+	tfInitStep  = tfStep{Name: "init", Args: []string{"init", "-input=false", "-no-color"}, State: "INITIALIZED"}
+	tfApplyStep = tfStep{Name: "apply", Args: []string{"apply", "-auto-approve", "-input=false", "-no-color"}, State: "DEPLOYED"}
+	// Confirmed in the destroy dialog (guardrails.go)
+	tfDestroyStep = tfStep{Name: "destroy", Args: []string{"destroy", "-auto-approve", "-input=false", "-no-color"}, State: "DESTROYED"}
+)
+
+// tfOperation tracks a sequence of terraform steps streaming output into the UI
+type tfOperation struct {
+	Label          string // e.g. "Deploying proxmox_web_dmz_12"
+	Dir            string
+	Steps          []tfStep
+	SuccessMessage string
+	// Optional post-success step (e.g. collecting artifacts), run off the UI loop
+	OnSuccess func() error
+	// Optional step before terraform apply starts (e.g. VM snapshots); an error fails the apply
+	BeforeApply func() error
+	// Run init even when .terraform is up to date with the lock file
+	ForceInit bool
+
+	jobID int
+	proc  *os.Process // running terraform process, nil between steps
+	// Set once the user asked to cancel; the running step is interrupted
+	cancelling bool
+	step       int
+	started    time.Time
+	events     chan tea.Msg
+	output     []string // tail of the current step's output
+
+	planned   int
+	added     int
+	changed   int
+	destroyed int
+}
+
+const opOutputTail = 20
+
+type tfLineMsg struct {
+	jobID int
+	line  string
+}
+
+type tfStepDoneMsg struct {
+	jobID int
+	err   error
+}
+
+var (
+	planRe          = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy`)
+	applyCompleteRe = regexp.MustCompile(`Resources: (\d+) added, (\d+) changed, (\d+) destroyed`)
+)
+
+// Step names joined, e.g. "init+apply", used as the action in the audit log
+func (op *tfOperation) action() string {
+	names := make([]string, len(op.Steps))
+	for i, s := range op.Steps {
+		names[i] = s.Name
+	}
+	return strings.Join(names, "+")
+}
+
+// runStepCmd starts the current step and returns the first event from its output stream
+func runStepCmd(op *tfOperation) tea.Cmd {
+	step := op.Steps[op.step]
+	id := op.jobID
+	op.events = make(chan tea.Msg, 64)
+	op.output = nil
+	events := op.events
+	return func() tea.Msg {
+		env, err := secretsEnv(op.Dir)
+		if err != nil {
+			return tfStepDoneMsg{jobID: id, err: fmt.Errorf("could not load deployment secrets: %w", err)}
+		}
+		if step.Args[0] == "apply" {
+			if err := requireTerraform(); err != nil {
+				return tfStepDoneMsg{jobID: id, err: err}
+			}
+			if op.BeforeApply != nil {
+				if err := op.BeforeApply(); err != nil {
+					return tfStepDoneMsg{jobID: id, err: fmt.Errorf("before apply: %w", err)}
+				}
+			}
+		}
+		if step.Args[0] == "init" && !op.ForceInit && initUpToDate(op.Dir) {
+			events <- tfStepDoneMsg{jobID: id}
+			return tfLineMsg{jobID: id, line: "Skipping terraform init: .terraform matches the lock file and configuration"}
+		}
+		cmd := terraformCommand(step.Args...)
+		cmd.Dir = op.Dir
+		cmd.Env = append(os.Environ(), env...)
+		pr, pw := io.Pipe()
+		cmd.Stdout = pw
+		cmd.Stderr = pw
+		started := time.Now()
+		if err := cmd.Start(); err != nil {
+			logTerraform(op.Dir, step.Args, started, err)
+			return tfStepDoneMsg{jobID: id, err: err}
+		}
+		events <- tfStartedMsg{jobID: id, proc: cmd.Process}
+		scanned := make(chan struct{})
+		go func() {
+			defer close(scanned)
+			scanner := bufio.NewScanner(pr)
+			for scanner.Scan() {
+				events <- tfLineMsg{jobID: id, line: scanner.Text()}
+			}
+		}()
+		go func() {
+			err := cmd.Wait()
+			logTerraform(op.Dir, step.Args, started, err)
+			if err == nil && step.Args[0] == "init" {
+				recordInit(op.Dir)
+			}
+			pw.Close()
+			<-scanned
+			events <- tfStepDoneMsg{jobID: id, err: err}
+		}()
+		return <-events
+	}
+}
+
+func waitForOpEvent(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// recordLine keeps the output tail and updates resource counters from terraform's output
+func (op *tfOperation) recordLine(line string) {
+	op.output = append(op.output, line)
+	if len(op.output) > opOutputTail {
+		op.output = op.output[len(op.output)-opOutputTail:]
+	}
+	if g := planRe.FindStringSubmatch(line); g != nil {
+		op.planned = atoi(g[1]) + atoi(g[2]) + atoi(g[3])
+		return
+	}
+	if g := applyCompleteRe.FindStringSubmatch(line); g != nil {
+		op.added, op.changed, op.destroyed = atoi(g[1]), atoi(g[2]), atoi(g[3])
+		return
+	}
+	switch {
+	case strings.Contains(line, ": Creation complete"):
+		op.added++
+	case strings.Contains(line, ": Modifications complete"):
+		op.changed++
+	case strings.Contains(line, ": Destruction complete"):
+		op.destroyed++
+	}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// viewOperation renders a running operation: spinner, step, elapsed time, resource progress and last output line
+func viewOperation(m model, op *tfOperation) string {
+	step := op.Steps[op.step]
+	elapsed := time.Since(op.started).Round(time.Second)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s — terraform %s (%d/%d) — %s\n", m.opSpinner.View(), op.Label, step.Name, op.step+1, len(op.Steps), elapsed)
+	done := op.added + op.changed + op.destroyed
+	if op.planned > 0 {
+		b.WriteString(m.opProgress.ViewAs(float64(done) / float64(op.planned)))
+		fmt.Fprintf(&b, "  %d/%d resources", done, op.planned)
+	} else {
+		b.WriteString("waiting for plan...")
+	}
+	fmt.Fprintf(&b, "  (%d added, %d changed, %d destroyed)\n", op.added, op.changed, op.destroyed)
+	if n := len(op.output); n > 0 {
+		b.WriteString(truncate(op.output[n-1], uiWidth-8))
+	}
+	return b.String()
+}
+
+func newOpProgress() progress.Model {
+	return progress.New(progressFill(), progress.WithWidth(50))
+}
+
+// Init+apply for a deployment directory
+func deployOperation(dir, successMessage string) *tfOperation {
+	return &tfOperation{
+		Label:          "Deploying " + filepath.Base(dir),
+		Dir:            dir,
+		Steps:          []tfStep{tfInitStep, tfApplyStep},
+		SuccessMessage: successMessage,
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- One-off -var overrides for a single apply ---
+//
+// The launcher's override key asks for `name=value` pairs (e.g. `vm_count=3`) and queues
+// init + apply with `-var name=value` on the apply step. terraform.tfvars stays as it is, so
+// the next plain apply goes back to the file's values. The overrides are kept in the state
+// history entry the apply writes (and in the audit log), and a retry of a failed apply reuses
+// them since they are part of the recorded step.
+
+// Apply with overrides waiting for the pairs to be typed
+type varsPrompt struct {
+	dep    deploymentInfo
+	input  textinput.Model
+	status string
+}
+
+func openVarsPrompt(m model) (model, tea.Cmd) {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m, nil
+	}
+	in := textinput.New()
+	in.Placeholder = "vm_count=3 vm_memory=8192"
+	in.Width = 60
+	in.Focus()
+	m.varsPrompt = &varsPrompt{dep: m.deployments[idx], input: in}
+	return m, textinput.Blink
+}
+
+// Splits "a=1 b=\"two words\" c=[\"x\", \"y\"]" into name=value pairs; spaces inside quotes or
+// brackets don't separate pairs, and the quotes around a string value are dropped
+func parseVarOverrides(s string) ([][2]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	depth, quoted := 0, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == '[' || r == '{'):
+			depth++
+		case !quoted && (r == ']' || r == '}'):
+			depth--
+		case r == ' ' && !quoted && depth == 0:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if quoted || depth != 0 {
+		return nil, fmt.Errorf("unbalanced quotes or brackets")
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	var pairs [][2]string
+	for _, t := range tokens {
+		name, value, ok := strings.Cut(t, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q isn't name=value", t)
+		}
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+		pairs = append(pairs, [2]string{name, value})
+	}
+	return pairs, nil
+}
+
+// Checks the pairs against the deployment's variables and fields.yaml: known names only,
+// each once, no secret or sensitive fields (they would end up in the history), valid values
+func checkVarOverrides(pairs [][2]string, tfvars map[string]string, fieldMeta map[string]FieldMeta) error {
+	seen := map[string]bool{}
+	for _, p := range pairs {
+		name, value := p[0], p[1]
+		meta, known := fieldMeta[name]
+		if _, ok := tfvars[name]; !ok && !known {
+			return fmt.Errorf("%s isn't a variable of this deployment", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s is given twice", name)
+		}
+		seen[name] = true
+		if isMaskedField(meta) {
+			return fmt.Errorf("%s is a secret — overrides are recorded, change it in the edit form", name)
+		}
+		if err := checkFieldValue(meta, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy of steps with -var name=value added to the apply step
+func withVarOverrides(steps []tfStep, pairs [][2]string) []tfStep {
+	out := slices.Clone(steps)
+	for i, s := range out {
+		if s.Args[0] != "apply" {
+			continue
+		}
+		args := slices.Clone(s.Args)
+		for _, p := range pairs {
+			args = append(args, "-var", p[0]+"="+p[1])
+		}
+		out[i].Args = args
+	}
+	return out
+}
+
+func updateVarsPrompt(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := *m.varsPrompt
+	k := keys.Overrides
+	switch {
+	case key.Matches(msg, k.Cancel):
+		m.varsPrompt = nil
+		return m, nil
+	case !key.Matches(msg, k.Apply):
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		p.status = ""
+		m.varsPrompt = &p
+		return m, cmd
+	}
+	pairs, err := parseVarOverrides(strings.TrimSpace(p.input.Value()))
+	if err == nil && len(pairs) == 0 {
+		err = fmt.Errorf("type at least one name=value")
+	}
+	if err == nil {
+		tfvars, _ := loadTfvars(filepath.Join(p.dep.Path, "terraform.tfvars"))
+		err = checkVarOverrides(pairs, tfvars, templateByName(m.templates, p.dep.Template).fieldMeta)
+	}
+	if err != nil {
+		p.status = err.Error()
+		m.varsPrompt = &p
+		return m, nil
+	}
+	m.varsPrompt = nil
+	op := deployOperation(p.dep.Path, "Deployment applied with overrides — terraform.tfvars unchanged")
+	op.Steps = withVarOverrides(op.Steps, pairs)
+	op.Label += " with -var " + strings.Join(op.Steps[len(op.Steps)-1].VarOverrides(), " ")
+	op.OnSuccess = postApplyHook(m.cfg, templateByName(m.templates, p.dep.Template), p.dep.Path)
+	m, cmd, held := guardApply(m, p.dep, op)
+	if !held {
+		m.statusMessage = fmt.Sprintf("Queued job #%d: %s", m.nextJobID, op.Label)
+	}
+	return m, cmd
+}
+
+func viewVarsPrompt(m model) string {
+	p := m.varsPrompt
+	k := keys.Overrides
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Apply "+p.dep.Name+" with overrides") + "\n\n")
+	b.WriteString("Variables for this apply only, as name=value separated by spaces.\n")
+	b.WriteString(logDimStyle.Render("terraform.tfvars isn't changed; the overrides go in the state history.") + "\n\n")
+	b.WriteString(p.input.View() + "\n\n")
+	if p.status != "" {
+		b.WriteString(errorStyle.Render(p.status) + "\n\n")
+	}
+	fmt.Fprintf(&b, "%s init + apply, %s cancels", k.Apply.Help().Key, k.Cancel.Help().Key)
+	return dialogBox(b.String())
+}
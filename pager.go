@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Pager for long output (job output, the log), modeled on less ---
+//
+// "/" searches (case-insensitive unless the pattern has a capital), n/N go to the next and
+// previous matching line, e jumps to the first error and w toggles wrapping long lines.
+// Following the end (G) sticks while a running job adds lines.
+
+type pagerLine struct {
+	Text string
+	// Drawn dim after Text, e.g. the attributes of a log entry
+	Attrs string
+	Style lipgloss.Style
+	Error bool
+}
+
+// Part of a line shown on one screen row: runes [start, end) of the line's plain text
+type pagerRow struct {
+	line, start, end int
+}
+
+type pager struct {
+	lines         []pagerLine
+	width, height int
+	top           int // first row shown
+	wrap          bool
+	follow        bool // keep the last line in view as lines are added
+	query         string
+	matches       []int // lines containing query
+	match         int   // index into matches, -1 until n/N or a search picks one
+	input         textinput.Model
+	status        string
+}
+
+func newPager(width, height int) pager {
+	in := textinput.New()
+	in.Prompt = "/"
+	in.Placeholder = "search"
+	return pager{width: width, height: height, match: -1, follow: true, input: in}
+}
+
+func (p pager) searching() bool {
+	return p.input.Focused()
+}
+
+// Replaces the lines, keeping the position (or the end when following) and the search
+func (p *pager) setLines(lines []pagerLine) {
+	for i := range lines {
+		lines[i].Text = strings.ReplaceAll(lines[i].Text, "\t", "    ")
+	}
+	p.lines = lines
+	p.findMatches()
+	if p.follow {
+		p.gotoBottom()
+	} else {
+		p.top = min(p.top, p.maxTop())
+	}
+}
+
+func (l pagerLine) plain() string {
+	if l.Attrs == "" {
+		return l.Text
+	}
+	return l.Text + " " + l.Attrs
+}
+
+// Screen rows of every line: one per line, or as many as it takes when wrapping
+func (p pager) rows() []pagerRow {
+	var rows []pagerRow
+	for i, l := range p.lines {
+		n := len([]rune(l.plain()))
+		if !p.wrap || n <= p.width {
+			rows = append(rows, pagerRow{i, 0, n})
+			continue
+		}
+		for s := 0; s < n; s += p.width {
+			rows = append(rows, pagerRow{i, s, min(s+p.width, n)})
+		}
+	}
+	return rows
+}
+
+func (p pager) maxTop() int {
+	return max(len(p.rows())-p.height, 0)
+}
+
+func (p *pager) scroll(n int) {
+	p.top = min(max(p.top+n, 0), p.maxTop())
+	p.follow = p.top == p.maxTop()
+}
+
+func (p *pager) gotoBottom() {
+	p.top = p.maxTop()
+	p.follow = true
+}
+
+// Scrolls so line i is the first one shown, or as close as the end allows
+func (p *pager) gotoLine(i int) {
+	for r, row := range p.rows() {
+		if row.line == i {
+			p.top = min(r, p.maxTop())
+			break
+		}
+	}
+	p.follow = false
+}
+
+// Smart case: a capital in the pattern makes the search case-sensitive
+func (p pager) foldCase() bool {
+	return !strings.ContainsFunc(p.query, unicode.IsUpper)
+}
+
+func (p *pager) findMatches() {
+	p.matches = nil
+	if p.query == "" {
+		p.match = -1
+		return
+	}
+	for i, l := range p.lines {
+		if len(matchRanges(l.plain(), p.query, p.foldCase())) > 0 {
+			p.matches = append(p.matches, i)
+		}
+	}
+	if p.match >= len(p.matches) {
+		p.match = len(p.matches) - 1
+	}
+}
+
+// Rune ranges of the occurrences of query in s
+func matchRanges(s, query string, fold bool) [][2]int {
+	text, q := []rune(s), []rune(query)
+	if fold {
+		text, q = []rune(strings.ToLower(s)), []rune(strings.ToLower(query))
+		if len(text) != len([]rune(s)) {
+			// lower-casing changed the length; fall back to an exact search
+			text, q = []rune(s), []rune(query)
+		}
+	}
+	var out [][2]int
+	for i := 0; len(q) > 0 && i+len(q) <= len(text); i++ {
+		if string(text[i:i+len(q)]) == string(q) {
+			out = append(out, [2]int{i, i + len(q)})
+			i += len(q) - 1
+		}
+	}
+	return out
+}
+
+// Moves to the next (dir 1) or previous (dir -1) matching line from the top of the screen,
+// wrapping around like less does
+func (p *pager) nextMatch(dir int) {
+	if len(p.matches) == 0 {
+		if p.query != "" {
+			p.status = "Pattern not found: " + p.query
+		}
+		return
+	}
+	if p.match < 0 {
+		rows := p.rows()
+		first := 0
+		if p.top < len(rows) {
+			first = rows[p.top].line
+		}
+		p.match = 0
+		for i, l := range p.matches {
+			if l >= first {
+				p.match = i
+				break
+			}
+		}
+		if dir < 0 {
+			p.match--
+		}
+	} else {
+		p.match += dir
+	}
+	p.match = (p.match + len(p.matches)) % len(p.matches)
+	p.gotoLine(p.matches[p.match])
+	p.status = ""
+}
+
+func (p *pager) firstError() {
+	for i, l := range p.lines {
+		if l.Error {
+			p.gotoLine(i)
+			p.status = ""
+			return
+		}
+	}
+	p.status = "No errors"
+}
+
+func (p pager) update(msg tea.Msg) (pager, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	k := keys.Pager
+	if p.searching() {
+		switch {
+		case key.Matches(keyMsg, k.SearchDone):
+			p.input.Blur()
+			p.query = p.input.Value()
+			p.match = -1
+			p.findMatches()
+			p.nextMatch(1)
+			return p, nil
+		case key.Matches(keyMsg, k.SearchCancel):
+			p.input.Blur()
+			p.input.SetValue(p.query)
+			return p, nil
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return p, cmd
+	}
+	p.status = ""
+	switch {
+	case key.Matches(keyMsg, k.Up):
+		p.scroll(-1)
+	case key.Matches(keyMsg, k.Down):
+		p.scroll(1)
+	case key.Matches(keyMsg, k.PageUp):
+		p.scroll(-p.height)
+	case key.Matches(keyMsg, k.PageDown):
+		p.scroll(p.height)
+	case key.Matches(keyMsg, k.Top):
+		p.top, p.follow = 0, false
+	case key.Matches(keyMsg, k.Bottom):
+		p.gotoBottom()
+	case key.Matches(keyMsg, k.Search):
+		p.input.SetValue("")
+		p.input.Focus()
+		return p, textinput.Blink
+	case key.Matches(keyMsg, k.Next):
+		p.nextMatch(1)
+	case key.Matches(keyMsg, k.Prev):
+		p.nextMatch(-1)
+	case key.Matches(keyMsg, k.FirstError):
+		p.firstError()
+	case key.Matches(keyMsg, k.Wrap):
+		// keep the first shown line in view
+		first := -1
+		if rows := p.rows(); p.top < len(rows) {
+			first = rows[p.top].line
+		}
+		p.wrap = !p.wrap
+		if first >= 0 && !p.follow {
+			p.gotoLine(first)
+		} else if p.follow {
+			p.gotoBottom()
+		}
+	}
+	return p, nil
+}
+
+// The shown rows, matches highlighted (the current match's line in the focus color)
+func (p pager) view() string {
+	rows := p.rows()
+	var b strings.Builder
+	for r := p.top; r < min(p.top+p.height, len(rows)); r++ {
+		b.WriteString(p.renderRow(rows[r]) + "\n")
+	}
+	for r := len(rows) - p.top; r < p.height; r++ {
+		b.WriteString(logDimStyle.Render("~") + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (p pager) renderRow(row pagerRow) string {
+	l := p.lines[row.line]
+	text := []rune(l.plain())
+	end, cut := row.end, false
+	if !p.wrap && end-row.start > p.width {
+		end, cut = row.start+p.width-1, true
+	}
+	// Style of each rune: 0 text, 1 attributes, 2 match, 3 match on the current line
+	class := make([]int, len(text))
+	textLen := len([]rune(l.Text))
+	for i := textLen; i < len(text); i++ {
+		class[i] = 1
+	}
+	if p.query != "" {
+		hit := 2
+		if p.match >= 0 && p.match < len(p.matches) && p.matches[p.match] == row.line {
+			hit = 3
+		}
+		for _, m := range matchRanges(l.plain(), p.query, p.foldCase()) {
+			for i := m[0]; i < m[1]; i++ {
+				class[i] = hit
+			}
+		}
+	}
+	styles := []lipgloss.Style{l.Style, logDimStyle, lipgloss.NewStyle().Reverse(true), focusedStyle}
+	var b strings.Builder
+	for s := row.start; s < end; {
+		e := s
+		for e < end && class[e] == class[s] {
+			e++
+		}
+		b.WriteString(styles[class[s]].Render(string(text[s:e])))
+		s = e
+	}
+	if cut {
+		b.WriteString(logDimStyle.Render("…"))
+	}
+	return b.String()
+}
+
+// "/pattern  match 3 of 12  wrap  42%" for the scene's tooltip; the search input while typing
+func (p pager) statusLine() string {
+	if p.searching() {
+		return p.input.View()
+	}
+	var parts []string
+	if p.status != "" {
+		parts = append(parts, p.status)
+	}
+	if p.query != "" && (len(p.matches) > 0 || p.status == "") {
+		if p.match >= 0 && len(p.matches) > 0 {
+			parts = append(parts, fmt.Sprintf("/%s  match %d of %d", p.query, p.match+1, len(p.matches)))
+		} else {
+			parts = append(parts, fmt.Sprintf("/%s  %s", p.query, plural(len(p.matches), "matching line")))
+		}
+	}
+	if p.wrap {
+		parts = append(parts, "wrap")
+	}
+	pct := 100
+	if mt := p.maxTop(); mt > 0 {
+		pct = p.top * 100 / mt
+	}
+	parts = append(parts, fmt.Sprintf("%d lines  %d%%", len(p.lines), pct))
+	return strings.Join(parts, "  •  ")
+}
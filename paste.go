@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Bracketed paste into the create and edit forms ---
+//
+// With bracketed paste the terminal sends a paste as one KeyMsg with Paste set instead of as
+// keystrokes, so pasted text never reaches the form's keys (Enter, Space, -/+). Text fields
+// get the pasted lines joined with spaces; list fields (an HCL list such as `["a", "b"]`) get
+// one element per line, so a block of SSH keys or DNS servers pastes as a list.
+
+// Pastes text into a form field; status says why a paste was refused or reshaped. cycling is
+// set for fields picked with left/right, which take no typing and so no paste either.
+func pasteIntoField(ti *textinput.Model, label string, meta FieldMeta, cycling bool, text string) (status string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := pastedLines(text)
+	switch {
+	case label == diskSizeField:
+		return fmt.Sprintf("Disk sizes are edited as a list — press %s", keys.Create.Disks.Help().Key)
+	case cycling:
+		return "This field is picked from a list; pasting only works in text fields"
+	case len(lines) == 0:
+		return ""
+	case isStepper(meta):
+		v := strings.Join(lines, "")
+		if strings.ContainsFunc(v, func(r rune) bool { return !unicode.IsDigit(r) }) {
+			return fmt.Sprintf("Digits only — %q wasn't pasted", truncate(v, 20))
+		}
+		ti.SetValue(v)
+		return ""
+	case isListField(meta, ti.Value()) && (len(lines) > 1 || !strings.HasPrefix(lines[0], "[")):
+		ti.SetValue(appendListElements(ti.Value(), lines))
+		ti.CursorEnd()
+		return fmt.Sprintf("Pasted %s into the list", plural(len(lines), "element"))
+	}
+	*ti, _ = ti.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(strings.Join(lines, " ")), Paste: true})
+	if len(lines) > 1 {
+		return fmt.Sprintf("Pasted %d lines as one line", len(lines))
+	}
+	return ""
+}
+
+// Non-blank lines, trimmed, control characters dropped
+func pastedLines(text string) []string {
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if r == '\t' {
+				return ' '
+			}
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, l))
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// Fields holding an HCL list: `type: list` in fields.yaml, or a value already written as one
+func isListField(meta FieldMeta, value string) bool {
+	return meta.Type == "list" || strings.HasPrefix(strings.TrimSpace(value), "[")
+}
+
+// Adds the lines to the list value as quoted elements; lines pasted from a list keep their
+// quotes and lose their trailing commas and brackets
+func appendListElements(value string, lines []string) string {
+	var elems []string
+	for _, l := range lines {
+		l = strings.TrimSpace(strings.Trim(l, "[],"))
+		if l == "" {
+			continue
+		}
+		if !strings.HasPrefix(l, `"`) {
+			l = `"` + strings.ReplaceAll(l, `"`, `\"`) + `"`
+		}
+		elems = append(elems, l)
+	}
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]"))
+	if inner != "" {
+		elems = append([]string{strings.TrimSuffix(inner, ",")}, elems...)
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+// Paste into the create form's focused field
+func pasteCreateField(m model, msg tea.KeyMsg) model {
+	label := m.createLabels[m.createFocus]
+	meta := m.fieldMeta[label]
+	cycling := label == "zone" || label == "cluster" || label == "vm_template" || len(meta.Options) > 0
+	m.createStatus = pasteIntoField(&m.createInputs[m.createFocus], label, meta, cycling, string(msg.Runes))
+	return m
+}
+
+// Paste into the edit form's focused field
+func pasteEditField(m model, msg tea.KeyMsg) model {
+	label := m.editFormLabels[m.editFocusIndex]
+	cycling := label == "zone" || label == "cluster"
+	m.editStatus = pasteIntoField(&m.editFormInputs[m.editFocusIndex], label, m.fieldMeta[label], cycling, string(msg.Runes))
+	return m
+}
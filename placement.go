@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Placement policy of a preset: preferred clusters per zone, picked by live capacity ---
+
+// Clusters to try per zone, in order; "*" is used for zones without an entry:
+//
+//	placement:
+//	  dmz: [pve-dmz-a, pve-dmz-b]
+//	  "*": [pve-core]
+type placementPolicy map[string][]string
+
+func (p placementPolicy) candidates(zone string) []string {
+	if c, ok := p[zone]; ok {
+		return c
+	}
+	return p["*"]
+}
+
+// placementMsg is the cluster chosen for zone, or "" when none of the candidates has room
+type placementMsg struct {
+	zone     string
+	cluster  string
+	skipped  []string // "pve-dmz-a: 2 of 3 VM(s) don't fit..." for every cluster passed over
+	capacity *clusterCapacity
+}
+
+// Outcome of the last placement, kept to tell an automatic choice from a manual one
+type placementResult struct {
+	cluster string
+	skipped []string
+}
+
+// Checks the policy's clusters for the form's zone in order and picks the first where the
+// requested VMs fit; nil when the preset has no policy for that zone
+func placementCmd(m model) tea.Cmd {
+	zone := createValue(m, "zone")
+	candidates := m.presets[m.presetIdx].Placement.candidates(zone)
+	if len(candidates) == 0 || indexOf("cluster", m.createLabels) < 0 || safeMode {
+		return nil
+	}
+	provider, tpl, req := m.activeTemplate.provider(), createValue(m, "vm_template"), createCapacityRequest(m)
+	return func() tea.Msg {
+		res := placementMsg{zone: zone}
+		for _, cluster := range candidates {
+			c := provider.Capacity(cluster, tpl)
+			if c.err != nil {
+				res.skipped = append(res.skipped, fmt.Sprintf("%s: %v", cluster, c.err))
+				continue
+			}
+			if err := capacityShortfall(c, req); err != nil {
+				res.skipped = append(res.skipped, err.Error())
+				continue
+			}
+			res.cluster, res.capacity = cluster, &c
+			break
+		}
+		return res
+	}
+}
+
+// Sets the chosen cluster unless the user picked another one since the last placement
+func handlePlacement(m model, msg placementMsg) (model, tea.Cmd) {
+	if msg.zone != createValue(m, "zone") {
+		return m, nil // the zone changed while the clusters were checked
+	}
+	overridden := m.placement != nil && createValue(m, "cluster") != m.placement.cluster
+	m.placementPending = false
+	m.placement = &placementResult{cluster: msg.cluster, skipped: msg.skipped}
+	if msg.cluster == "" {
+		m.createStatus = "Placement: no preferred cluster has room — " + strings.Join(msg.skipped, "; ")
+		return m, nil
+	}
+	if overridden {
+		return m, nil
+	}
+	m.capacity = msg.capacity
+	if createValue(m, "cluster") == msg.cluster {
+		return m, nil
+	}
+	m.createInputs[indexOf("cluster", m.createLabels)].SetValue(msg.cluster)
+	m.isFetchingTemplates = true
+	return m, fetchTemplatesCmd(m.activeTemplate.provider(), msg.cluster)
+}
+
+// Resolves the placement again; fresh drops the last result (new preset or zone) so the
+// policy's choice replaces a cluster picked by hand
+func resolvePlacement(m model, fresh bool) (model, tea.Cmd) {
+	cmd := placementCmd(m)
+	if fresh || cmd == nil {
+		m.placement = nil
+	}
+	m.placementPending = cmd != nil
+	return m, cmd
+}
+
+// "[Placement: pve-dmz-b, pve-dmz-a is full]" for the create form header
+func placementLabel(m model) string {
+	switch {
+	case m.placementPending:
+		return "[Placement: checking clusters...] "
+	case m.placement == nil:
+		return ""
+	case m.placement.cluster == "":
+		return "[Placement: no room on the preferred clusters] "
+	case createValue(m, "cluster") != m.placement.cluster:
+		return fmt.Sprintf("[Placement: %s, overridden] ", createValue(m, "cluster"))
+	case len(m.placement.skipped) > 0:
+		return fmt.Sprintf("[Placement: %s, %s skipped] ", m.placement.cluster, plural(len(m.placement.skipped), "cluster"))
+	}
+	return fmt.Sprintf("[Placement: %s] ", m.placement.cluster)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"launcher/internal/state"
+)
+
+// --- Saved plans: [P] writes tfplan, [A] applies exactly that plan ---
+
+const (
+	planFile           = "tfplan"
+	defaultPlanMaxAge  = time.Hour
+	planNoChangesLabel = "No changes"
+)
+
+// savedPlan is recorded in launcher.state when a plan job succeeds
+type savedPlan = state.Plan
+
+// planMaxAge parses cfg.PlanMaxAge; empty or invalid means the default
+func planMaxAge(cfg Config) time.Duration {
+	if d, err := time.ParseDuration(cfg.PlanMaxAge); err == nil && d > 0 {
+		return d
+	}
+	return defaultPlanMaxAge
+}
+
+// Digest of the inputs a plan was made from; a different digest means the plan is stale
+func tfvarsDigest(dir string) string {
+	h := sha256.New()
+	for _, name := range []string{"terraform.tfvars", sopsSecretsFile} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Summary line of a saved plan from `terraform show`
+func planSummary(dir string) (string, error) {
+	cmd := terraformCommand("show", "-no-color", planFile)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("terraform show %s: %w", planFile, err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if planRe.MatchString(line) {
+			return planRe.FindString(line), nil
+		}
+	}
+	return planNoChangesLabel, nil
+}
+
+func recordPlan(dir string) error {
+	summary, err := planSummary(dir)
+	if err != nil {
+		return err
+	}
+	s, _ := getDeploymentState(dir)
+	s.Plan = &savedPlan{
+		File:       planFile,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		By:         currentIdentity(),
+		Summary:    summary,
+		TfvarsHash: tfvarsDigest(dir),
+	}
+	recordAudit("plan", dir, summary, "ok")
+	return writeDeploymentState(dir, s)
+}
+
+// Drops the plan file and its record once applied: terraform refuses to apply a plan twice
+func clearPlan(dir string) error {
+	s, _ := getDeploymentState(dir)
+	s.Plan = nil
+	if err := os.Remove(filepath.Join(dir, planFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return writeDeploymentState(dir, s)
+}
+
+// Init + `terraform plan -out=tfplan`; the deployment keeps its state
+func planOperation(dir string) *tfOperation {
+	initStep := tfInitStep
+	initStep.State = ""
+	return &tfOperation{
+		Label:          "Planning " + filepath.Base(dir),
+		Dir:            dir,
+		Steps:          []tfStep{initStep, {Name: "plan", Args: []string{"plan", "-input=false", "-no-color", "-out=" + planFile}}},
+		SuccessMessage: fmt.Sprintf("Plan saved for %s — review it, then %s applies it", filepath.Base(dir), keys.Launcher.ApplyPlan.Help().Key),
+		OnSuccess:      func() error { return recordPlan(dir) },
+	}
+}
+
+// Why the saved plan of a deployment can't be applied, nil when it can
+func checkSavedPlan(cfg Config, dir string) (*savedPlan, error) {
+	s, _ := getDeploymentState(dir)
+	p := s.Plan
+	if p == nil || !pathExists(filepath.Join(dir, p.File)) {
+		return nil, fmt.Errorf("no saved plan — create one with %s", keys.Launcher.Plan.Help().Key)
+	}
+	created, err := time.Parse(time.RFC3339, p.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("saved plan has no valid timestamp — plan again")
+	}
+	if age := time.Since(created); age > planMaxAge(cfg) {
+		return nil, fmt.Errorf("saved plan is %s old (plan_max_age %s) — plan again", age.Round(time.Minute), planMaxAge(cfg))
+	}
+	if tfvarsDigest(dir) != p.TfvarsHash {
+		return nil, fmt.Errorf("terraform.tfvars changed since the plan was saved — plan again")
+	}
+	return p, nil
+}
+
+// Init + `terraform apply tfplan`; no -auto-approve needed, the plan was the approval
+func applyPlanOperation(dir string, p *savedPlan, onSuccess func() error) *tfOperation {
+	return &tfOperation{
+		Label:          "Applying saved plan of " + filepath.Base(dir),
+		Dir:            dir,
+		Steps:          []tfStep{tfInitStep, {Name: "apply", Args: []string{"apply", "-input=false", "-no-color", p.File}, State: "DEPLOYED"}},
+		SuccessMessage: fmt.Sprintf("Saved plan applied to %s (%s)", filepath.Base(dir), p.Summary),
+		OnSuccess: func() error {
+			if err := clearPlan(dir); err != nil {
+				return err
+			}
+			if onSuccess != nil {
+				return onSuccess()
+			}
+			return nil
+		},
+	}
+}
+
+// Detail pane line for the selected deployment's saved plan; staleness is checked on apply
+func planLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || m.deployments[idx].Plan == nil {
+		return nil
+	}
+	p := m.deployments[idx].Plan
+	return []string{truncate(fmt.Sprintf("Saved plan: %s, %s by %s — %s applies it", p.Summary, describeTime(p.CreatedAt), p.By, keys.Launcher.ApplyPlan.Help().Key), width)}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckSavedPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxAge  string
+		age     time.Duration
+		noFile  bool
+		tfvars  string // written after the plan when set
+		wantErr string // "" when the plan can be applied
+	}{
+		{name: "fresh", age: time.Minute},
+		{name: "too old", age: 2 * time.Hour, wantErr: "(plan_max_age 1h0m0s)"},
+		{name: "within plan_max_age", maxAge: "3h", age: 2 * time.Hour},
+		{name: "older than plan_max_age", maxAge: "10m", age: 15 * time.Minute, wantErr: "saved plan is 15m0s old"},
+		{name: "changed tfvars", age: time.Minute, tfvars: "vm_count = 2\n", wantErr: "terraform.tfvars changed"},
+		{name: "missing file", age: time.Minute, noFile: true, wantErr: "no saved plan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestModel(t, "web_a")
+			m.cfg.PlanMaxAge = tt.maxAge
+			dir := filepath.Join(m.cfg.AppsPath, "web_a")
+			if !tt.noFile {
+				writeTestFile(t, filepath.Join(dir, planFile), "binary plan")
+			}
+			s, _ := getDeploymentState(dir)
+			s.Plan = &savedPlan{
+				File:       planFile,
+				CreatedAt:  time.Now().Add(-tt.age).UTC().Format(time.RFC3339),
+				Summary:    "Plan: 1 to add, 0 to change, 0 to destroy",
+				TfvarsHash: tfvarsDigest(dir),
+			}
+			if err := writeDeploymentState(dir, s); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tfvars != "" {
+				writeTestFile(t, filepath.Join(dir, "terraform.tfvars"), tt.tfvars)
+			}
+
+			p, err := checkSavedPlan(m.cfg, dir)
+			switch {
+			case tt.wantErr == "" && (err != nil || p == nil || p.File != planFile):
+				t.Errorf("checkSavedPlan = %+v, %v; want the plan", p, err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("checkSavedPlan error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckSavedPlanWithoutRecord(t *testing.T) {
+	m, _ := newTestModel(t, "web_a")
+	dir := filepath.Join(m.cfg.AppsPath, "web_a")
+	writeTestFile(t, filepath.Join(dir, planFile), "binary plan")
+	if _, err := checkSavedPlan(m.cfg, dir); err == nil || !strings.Contains(err.Error(), "no saved plan") {
+		t.Errorf("checkSavedPlan error %v, want no saved plan", err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Pre-deploy cluster checks ---
+
+// preflightMsg is the outcome of the cluster checks run when the create form is submitted
+type preflightMsg struct {
+	status    string // blocking problem, "" when deployment may go ahead
+	warning   string // capacity shortfall in warn mode
+	confirmed bool   // the user already confirmed the capacity warning
+	capacity  *clusterCapacity
+	vmids     *vmidRange
+}
+
+// preflightCmd captures what the checks need from the form so they can run off the UI loop
+func preflightCmd(m model, confirmed bool) func() tea.Msg {
+	cluster, tpl := createValue(m, "cluster"), createValue(m, "vm_template")
+	provider := m.activeTemplate.provider()
+	// Clone mode and SDN checks ask the Proxmox API
+	_, proxmox := provider.(proxmoxProvider)
+	cloneMode := ""
+	if fieldApplies(m, "vm_clone_mode") && proxmox {
+		cloneMode = createValue(m, "vm_clone_mode")
+	}
+	checkSDN := sdnFieldsSet(m) && proxmox
+	sdnZone, vnet, vlanTag := createValue(m, "vm_sdn_zone"), createValue(m, "vm_vnet"), createValue(m, "vm_vlan_tag")
+	mode := capacityMode(m.cfg)
+	req := createCapacityRequest(m)
+	cached := m.capacity
+	cfg, registry := m.cfg, m.cfg.VMIDRegistry.Enabled() && proxmox
+	requestedVMID := createValue(m, m.cfg.VMIDRegistry.VarName())
+	appDir, nameErr := deploymentName(m)
+	checks := func() preflightMsg {
+		res := preflightMsg{confirmed: confirmed, capacity: cached}
+		if nameErr != nil {
+			res.status = nameErr.Error()
+			return res
+		}
+		if cloneMode != "" {
+			if err := checkCloneMode(cluster, tpl, cloneMode); err != nil {
+				res.status = "Clone mode check: " + err.Error()
+				return res
+			}
+		}
+		if checkSDN {
+			if err := checkNetworking(cluster, sdnZone, vnet, vlanTag); err != nil {
+				res.status = "Network check: " + err.Error()
+				return res
+			}
+		}
+		if registry {
+			rng, err := proposeVMIDRange(cfg, cluster, req.count, requestedVMID)
+			if err != nil {
+				res.status = "VMID registry: " + err.Error()
+				return res
+			}
+			res.vmids = &rng
+		}
+		if mode == "off" {
+			return res
+		}
+		if cached == nil || cached.cluster != cluster || cached.template != tpl {
+			c := provider.Capacity(cluster, tpl)
+			res.capacity = &c
+		}
+		if err := capacityShortfall(*res.capacity, req); err != nil {
+			if mode == "block" {
+				res.status = "Capacity check: " + err.Error()
+			} else {
+				res.warning = "Capacity warning: " + err.Error() + " — " + keys.Create.Save.Help().Key + " again deploys anyway"
+			}
+		}
+		return res
+	}
+	return func() tea.Msg {
+		res := checks()
+		// The range is reserved here rather than in createDeployment: the registry lock may
+		// be held by another launcher for a while
+		if res.vmids != nil && res.status == "" && (res.warning == "" || confirmed) {
+			if _, err := os.Stat(filepath.Join(cfg.AppsPath, appDir)); err == nil {
+				res.status = fmt.Sprintf("Deployment '%s' already exists!", appDir)
+			} else if err := reserveVMIDRange(cfg, appDir, *res.vmids); err != nil {
+				res.status = "VMID registry: " + err.Error()
+			}
+		}
+		return res
+	}
+}
+
+func handlePreflight(m model, msg preflightMsg) (tea.Model, tea.Cmd) {
+	m.capacity = msg.capacity
+	m.vmids = msg.vmids
+	if msg.status != "" {
+		m.createStatus = msg.status
+		return m, nil
+	}
+	if msg.warning != "" && !msg.confirmed {
+		m.createStatus = msg.warning
+		m.capacityConfirmed = true
+		return m, nil
+	}
+	return createDeployment(m)
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// --- Preset management scene ---
+
+type presetMgrMode int
+
+const (
+	presetMgrBrowse presetMgrMode = iota
+	presetMgrSave
+	presetMgrRename
+	presetMgrDelete
+)
+
+var presetNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func openPresetManager(m model) model {
+	m.presetMgrIdx = m.presetIdx
+	m.presetMgrMode = presetMgrBrowse
+	m.presetMgrStatus = ""
+	return m.pushScene(scenePresets)
+}
+
+// Directory new presets are written to: the template's own presets dir if it has one
+func presetsWriteDir(m model) string {
+	if m.activeTemplate.presetsDir != "" {
+		return m.activeTemplate.presetsDir
+	}
+	return m.cfg.PresetsPath
+}
+
+// Converts the create form into preset values (lists for comma values, ints for numeric non-string fields)
+func presetValuesFromForm(m model) map[string]interface{} {
+	values := map[string]interface{}{}
+	for i, key := range m.createLabels {
+		v := strings.TrimSpace(m.createInputs[i].Value())
+		if v == "" || isMaskedField(m.fieldMeta[key]) {
+			continue // secret and sensitive values never go into preset files
+		}
+		// Only `type: list` fields are lists; a comma in a description or a disk size string stays
+		if m.fieldMeta[key].Type == "list" {
+			var list []string
+			for _, part := range strings.Split(v, ",") {
+				list = append(list, strings.Trim(strings.TrimSpace(part), "\""))
+			}
+			values[key] = list
+		} else if n, err := strconv.Atoi(v); err == nil && m.fieldMeta[key].Type != "string" {
+			values[key] = n
+		} else {
+			values[key] = v
+		}
+	}
+	return values
+}
+
+func savePreset(dir, name string, values map[string]interface{}) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".yaml"), data, 0644)
+}
+
+// Reloads global + template presets/fields after presets changed on disk
+func reloadCatalog(m *model) error {
+	fieldMeta, err := loadFieldMeta(globalFieldsPath)
+	if err != nil {
+		return err
+	}
+	presets, err := loadPresets(m.cfg.PresetsPath)
+	if err != nil {
+		return err
+	}
+	templates, err := loadTemplates(m.cfg, fieldMeta, presets)
+	if err != nil {
+		return err
+	}
+	m.templates = templates
+	m.activeTemplate = templateByName(templates, m.activeTemplate.Name)
+	m.presets = m.activeTemplate.presets
+	if len(m.presets) == 0 {
+		return fmt.Errorf("no presets left")
+	}
+	m.presetIdx = min(m.presetIdx, len(m.presets)-1)
+	m.presetMgrIdx = min(m.presetMgrIdx, len(m.presets)-1)
+	return nil
+}
+
+func presetIndexByName(presets []Preset, name string) int {
+	for i, p := range presets {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func updatePresetManager(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.presetMgrMode == presetMgrSave || m.presetMgrMode == presetMgrRename {
+		return updatePresetNamePrompt(m, keyMsg)
+	}
+	if m.presetMgrMode == presetMgrDelete {
+		switch {
+		case key.Matches(keyMsg, keys.Confirm.Yes):
+			p := m.presets[m.presetMgrIdx]
+			m.presetMgrMode = presetMgrBrowse
+			if len(m.presets) == 1 {
+				m.presetMgrStatus = "Cannot delete the last preset"
+				return m, nil
+			}
+			if err := os.Remove(p.Path); err != nil {
+				m.presetMgrStatus = "Delete failed: " + err.Error()
+				return m, nil
+			}
+			if err := reloadCatalog(&m); err != nil {
+				m.presetMgrStatus = "Reload failed: " + err.Error()
+				return m, nil
+			}
+			m.presetMgrStatus = fmt.Sprintf("Deleted preset '%s'", p.Name)
+		default:
+			m.presetMgrMode = presetMgrBrowse
+			m.presetMgrStatus = "Delete cancelled"
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, keys.Presets.Up):
+		m.presetMgrIdx = (m.presetMgrIdx - 1 + len(m.presets)) % len(m.presets)
+	case key.Matches(keyMsg, keys.Presets.Down):
+		m.presetMgrIdx = (m.presetMgrIdx + 1) % len(m.presets)
+	case key.Matches(keyMsg, keys.Presets.Save):
+		m.presetMgrMode = presetMgrSave
+		m.presetNameInput = textinput.New()
+		m.presetNameInput.Placeholder = "new-preset-name"
+		m.presetNameInput.Focus()
+	case key.Matches(keyMsg, keys.Presets.Rename):
+		m.presetMgrMode = presetMgrRename
+		m.presetNameInput = textinput.New()
+		m.presetNameInput.SetValue(m.presets[m.presetMgrIdx].Name)
+		m.presetNameInput.Focus()
+	case key.Matches(keyMsg, keys.Presets.Delete):
+		m.presetMgrMode = presetMgrDelete
+	case key.Matches(keyMsg, keys.Presets.Use):
+		m.presetIdx = m.presetMgrIdx
+		m = applyPresetToForm(m, m.presetIdx)
+		return resolvePlacement(m.popScene(), true)
+	case key.Matches(keyMsg, keys.Presets.Back):
+		return m.popScene(), nil
+	}
+	return m, nil
+}
+
+func updatePresetNamePrompt(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Presets.CancelName):
+		m.presetMgrMode = presetMgrBrowse
+		m.presetMgrStatus = ""
+		return m, nil
+	case key.Matches(msg, keys.Presets.ConfirmName):
+		name := strings.TrimSpace(m.presetNameInput.Value())
+		if !presetNameRe.MatchString(name) {
+			m.presetMgrStatus = "Preset names may only contain letters, digits, '.', '_' and '-'"
+			return m, nil
+		}
+		if presetIndexByName(m.presets, name) >= 0 {
+			m.presetMgrStatus = fmt.Sprintf("Preset '%s' already exists", name)
+			return m, nil
+		}
+		if m.presetMgrMode == presetMgrSave {
+			if err := savePreset(presetsWriteDir(m), name, presetValuesFromForm(m)); err != nil {
+				m.presetMgrStatus = "Save failed: " + err.Error()
+				return m, nil
+			}
+			m.presetMgrStatus = fmt.Sprintf("Saved form as preset '%s'", name)
+		} else {
+			old := m.presets[m.presetMgrIdx]
+			newPath := filepath.Join(filepath.Dir(old.Path), name+".yaml")
+			if err := os.Rename(old.Path, newPath); err != nil {
+				m.presetMgrStatus = "Rename failed: " + err.Error()
+				return m, nil
+			}
+			m.presetMgrStatus = fmt.Sprintf("Renamed '%s' to '%s'", old.Name, name)
+		}
+		m.presetMgrMode = presetMgrBrowse
+		if err := reloadCatalog(&m); err != nil {
+			m.presetMgrStatus = "Reload failed: " + err.Error()
+			return m, nil
+		}
+		if i := presetIndexByName(m.presets, name); i >= 0 {
+			m.presetMgrIdx = i
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.presetNameInput, cmd = m.presetNameInput.Update(msg)
+	return m, cmd
+}
+
+// Description (bold) and docs of a preset wrapped to the screen; maxDocLines 0 keeps every line
+func presetNotes(p Preset, maxDocLines int) string {
+	if p.Description == "" && p.Docs == "" {
+		return ""
+	}
+	var lines []string
+	if p.Description != "" {
+		lines = append(lines, titleStyle.Render(p.Description))
+	}
+	if docs := strings.TrimSpace(p.Docs); docs != "" {
+		wrapped := strings.Split(lipgloss.NewStyle().Width(uiWidth-10).Render(docs), "\n")
+		if maxDocLines > 0 && len(wrapped) > maxDocLines {
+			wrapped = append(wrapped[:maxDocLines], "… ("+keys.Create.Presets.Help().Key+" shows the full notes)")
+		}
+		lines = append(lines, wrapped...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func viewPresetManager(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(fmt.Sprintf("Presets for template '%s' (%s)", m.activeTemplate.Name, presetsWriteDir(m)))
+	body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+
+	// Side-by-side comparison: one column per preset, windowed around the selection
+	const fieldCol, valCol, maxCols = 26, 20, 6
+	start := 0
+	if m.presetMgrIdx >= maxCols {
+		start = m.presetMgrIdx - maxCols + 1
+	}
+	end := min(start+maxCols, len(m.presets))
+
+	header := padRight("", fieldCol)
+	for i := start; i < end; i++ {
+		name := truncate(m.presets[i].Name, valCol-2)
+		if i == m.presetMgrIdx {
+			header += focusedStyle.Render(padRight(name, valCol))
+		} else {
+			header += normalStyle.Render(padRight(name, valCol))
+		}
+	}
+	body += header + "\n"
+	for _, key := range m.createLabels {
+		label := key
+		if meta, ok := m.fieldMeta[key]; ok && meta.Label != "" {
+			label = meta.Label
+		}
+		row := padRight(truncate(label, fieldCol-2), fieldCol)
+		differs := false
+		first := ""
+		for i := start; i < end; i++ {
+			v := ""
+			if val, ok := m.presets[i].Values[key]; ok {
+				v = presetValueString(val)
+			}
+			if i == start {
+				first = v
+			} else if v != first {
+				differs = true
+			}
+		}
+		for i := start; i < end; i++ {
+			v := "-"
+			if val, ok := m.presets[i].Values[key]; ok {
+				v = presetValueString(val)
+			}
+			cell := padRight(truncate(v, valCol-2), valCol)
+			if differs {
+				cell = presetDiffStyle.Render(cell)
+			}
+			row += cell
+		}
+		body += row + "\n"
+	}
+	if notes := presetNotes(m.presets[m.presetMgrIdx], 0); notes != "" {
+		body += " " + strings.Repeat("─", uiWidth-4) + "\n"
+		body += notes + "\n"
+	}
+
+	switch m.presetMgrMode {
+	case presetMgrSave:
+		tooltip = tooltipStyle.Render("Save current form as preset: " + m.presetNameInput.View())
+	case presetMgrRename:
+		tooltip = tooltipStyle.Render("Rename preset to: " + m.presetNameInput.View())
+	case presetMgrDelete:
+		tooltip = tooltipStyle.Render(fmt.Sprintf("Delete preset '%s'? %s yes, any other key keeps it", m.presets[m.presetMgrIdx].Name, keys.Confirm.Yes.Help().Key))
+	default:
+		msg := m.presetMgrStatus
+		if msg == "" {
+			msg = "Highlighted rows differ between presets."
+		}
+		tooltip = tooltipStyle.Render(msg)
+	}
+	return body, tooltip
+}
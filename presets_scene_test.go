@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func TestPresetValuesFromFormSplitsListFieldsOnly(t *testing.T) {
+	values := map[string]string{
+		"platform_description": "web, api and workers",
+		"vm_tags":              "web, \"dmz\"",
+		"vm_count":             "3",
+	}
+	m := model{fieldMeta: map[string]FieldMeta{
+		"platform_description": {Type: "string"},
+		"vm_tags":              {Type: "list"},
+	}}
+	for _, key := range []string{"platform_description", "vm_tags", "vm_count"} {
+		in := textinput.New()
+		in.SetValue(values[key])
+		m.createLabels = append(m.createLabels, key)
+		m.createInputs = append(m.createInputs, in)
+	}
+	got := presetValuesFromForm(m)
+	want := map[string]interface{}{
+		"platform_description": "web, api and workers",
+		"vm_tags":              []string{"web", "dmz"},
+		"vm_count":             3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
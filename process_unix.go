@@ -0,0 +1,17 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Whether a process with this PID is running; EPERM means it exists but belongs to someone else
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false // 0 and -1 would signal whole process groups
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Virtualization providers (template.yaml `provider:`) ---
+
+// Provider is the platform a template deploys to. Each implementation fetches its own
+// credentials: Proxmox through the secrets provider, libvirt through the SSH/TLS setup
+// of its connection URIs.
+type Provider interface {
+	Name() string
+	// Clusters it can deploy to; nil means the configured (or discovered) clusters
+	Clusters() []string
+	// Ping gets the cluster's credentials and checks that its API answers
+	Ping(cluster string) error
+	// VM templates (golden images) available on a cluster
+	Templates(cluster string) ([]string, error)
+	// Node resources, and the storage holding the template when it can be found
+	Capacity(cluster, template string) clusterCapacity
+}
+
+const defaultProvider = "proxmox"
+
+var libvirtSettings LibvirtConfig
+
+func configureProviders(cfg Config) {
+	libvirtSettings = cfg.Libvirt
+}
+
+func providerByName(name string) (Provider, error) {
+	switch name {
+	case "", defaultProvider:
+		return proxmoxProvider{}, nil
+	case "libvirt":
+		return libvirtProvider{libvirtSettings}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (want proxmox or libvirt)", name)
+}
+
+// Provider of a template; loadTemplate has already rejected unknown names
+func (t Template) provider() Provider {
+	p, err := providerByName(t.Provider)
+	if err != nil {
+		return proxmoxProvider{}
+	}
+	return p
+}
+
+// Cluster options of the form for a template's provider
+func clusterChoices(m model, t Template) []string {
+	if clusters := t.provider().Clusters(); clusters != nil {
+		return clusters
+	}
+	return m.clusterOptions
+}
+
+// --- Proxmox VE ---
+
+type proxmoxProvider struct{}
+
+func (proxmoxProvider) Name() string { return "proxmox" }
+
+func (proxmoxProvider) Clusters() []string { return nil }
+
+func (proxmoxProvider) Ping(cluster string) error {
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(cluster)
+	if err != nil {
+		return err
+	}
+	var version map[string]interface{}
+	return proxmoxGet(apiURL, tokenID, tokenSecret, "version", &version)
+}
+
+func (proxmoxProvider) Templates(cluster string) ([]string, error) {
+	return fetchTemplatesForCluster(cluster)
+}
+
+func (proxmoxProvider) Capacity(cluster, template string) clusterCapacity {
+	return fetchCapacity(cluster, template)
+}
+
+// --- libvirt (through virsh) ---
+
+const virshTimeout = 10 * time.Second
+
+type libvirtProvider struct {
+	cfg LibvirtConfig
+}
+
+func (libvirtProvider) Name() string { return "libvirt" }
+
+func (p libvirtProvider) Clusters() []string {
+	names := make([]string, 0, len(p.cfg.Clusters))
+	for name := range p.cfg.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p libvirtProvider) pool() string {
+	if p.cfg.Pool != "" {
+		return p.cfg.Pool
+	}
+	return "default"
+}
+
+func (p libvirtProvider) hosts(cluster string) ([]string, error) {
+	uris := p.cfg.Clusters[cluster]
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("no libvirt hosts configured for %s (libvirt.clusters)", cluster)
+	}
+	return uris, nil
+}
+
+func virsh(uri string, args ...string) (string, error) {
+	if safeMode {
+		return "", fmt.Errorf("libvirt: %w", errSafeMode)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), virshTimeout)
+	defer cancel()
+	started := time.Now()
+	out, err := exec.CommandContext(ctx, "virsh", append([]string{"-c", uri}, args...)...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		err = fmt.Errorf("virsh %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	attrs := []any{"component", "libvirt", "uri", uri, "command", args[0], "duration_ms", time.Since(started).Milliseconds()}
+	if err != nil {
+		logger.Error("virsh failed", append(attrs, "error", err.Error())...)
+		return "", err
+	}
+	logger.Info("virsh", attrs...)
+	return string(out), nil
+}
+
+// "Key: value" lines of virsh output (nodeinfo, pool-info, nodememstats)
+func virshFields(out string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			fields[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return fields
+}
+
+// "16318568 KiB" -> bytes
+func kibField(s string) int64 {
+	n, _ := strconv.ParseInt(strings.TrimSuffix(s, " KiB"), 10, 64)
+	return n << 10
+}
+
+func (p libvirtProvider) Ping(cluster string) error {
+	uris, err := p.hosts(cluster)
+	if err != nil {
+		return err
+	}
+	for _, uri := range uris {
+		if _, err := virsh(uri, "version"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Volumes of the template pool on every host of the cluster
+func (p libvirtProvider) Templates(cluster string) ([]string, error) {
+	uris, err := p.hosts(cluster)
+	if err != nil {
+		return nil, err
+	}
+	var templates []string
+	for _, uri := range uris {
+		out, err := virsh(uri, "vol-list", "--pool", p.pool())
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(out, "\n") {
+			f := strings.Fields(line)
+			if len(f) == 0 || f[0] == "Name" || strings.HasPrefix(f[0], "---") || slices.Contains(templates, f[0]) {
+				continue
+			}
+			templates = append(templates, f[0])
+		}
+	}
+	sort.Strings(templates)
+	return templates, nil
+}
+
+func (p libvirtProvider) Capacity(cluster, template string) clusterCapacity {
+	c := clusterCapacity{cluster: cluster, template: template, storage: p.pool()}
+	uris, err := p.hosts(cluster)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	for _, uri := range uris {
+		n := nodeCapacity{Node: uri}
+		if u, err := url.Parse(uri); err == nil && u.Hostname() != "" {
+			n.Node = u.Hostname()
+		}
+		info, err := virsh(uri, "nodeinfo")
+		if err != nil {
+			c.nodes = append(c.nodes, n) // offline
+			continue
+		}
+		n.Online = true
+		n.MaxCPU, _ = strconv.Atoi(virshFields(info)["CPU(s)"])
+		if out, err := virsh(uri, "nodememstats"); err == nil {
+			mem := virshFields(out)
+			n.MaxMem = kibField(mem["total"])
+			n.Mem = n.MaxMem - kibField(mem["free"]) - kibField(mem["buffers"]) - kibField(mem["cached"])
+		}
+		if out, err := virsh(uri, "nodecpustats", "--percent"); err == nil {
+			usage, _ := strconv.ParseFloat(strings.TrimSuffix(virshFields(out)["usage"], "%"), 64)
+			n.CPU = usage / 100
+		}
+		if out, err := virsh(uri, "pool-info", "--bytes", p.pool()); err == nil {
+			pool := virshFields(out)
+			n.MaxDisk, _ = strconv.ParseInt(pool["Capacity"], 10, 64)
+			n.Disk, _ = strconv.ParseInt(pool["Allocation"], 10, 64)
+		}
+		c.nodes = append(c.nodes, n)
+	}
+	sort.Slice(c.nodes, func(i, j int) bool { return c.nodes[i].Node < c.nodes[j].Node })
+	return c
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Quick edit: one tfvars value changed from the launcher's tfvars pane ---
+//
+// Tab moves the focus to the pane, Enter opens the selected value in a one-line input and
+// Enter again writes just that variable (backed up like any save), without the edit form.
+// fields.yaml still applies: read-only, secret and sensitive fields and the disk list stay
+// with the edit form, options cycle with left/right and steppers take digits within range.
+
+type quickEdit struct {
+	Dir   string // deployment directory
+	Key   string
+	Label string
+	Meta  FieldMeta
+	// The value as read, and whether it was a quoted string (the input holds it unquoted)
+	Saved  string
+	Quoted bool
+	Input  textinput.Model
+	Status string
+}
+
+func focusTfvarsPane(m model, on bool) model {
+	m.tfvarsFocus = on
+	m.tablesVersion++
+	m.tfvarsTable.SetStyles(tfvarsTableStyles(on))
+	if on && len(m.tfvarsKeys) == 0 {
+		m.tfvarsFocus = false
+		m.tfvarsTable.SetStyles(tfvarsTableStyles(false))
+		m.statusMessage = "No tfvars to edit"
+	}
+	return m
+}
+
+// Why key can't be quick-edited, "" when it can
+func quickEditRefusal(key string, meta FieldMeta) string {
+	switch {
+	case meta.ReadOnly:
+		return "is read-only"
+	case isMaskedField(meta):
+		return fmt.Sprintf("is a secret — change it in the edit form (%s)", keys.Launcher.Edit.Help().Key)
+	case key == diskSizeField || key == diskCountField:
+		return fmt.Sprintf("goes with the disk list — change it in the edit form (%s)", keys.Launcher.Edit.Help().Key)
+	}
+	return ""
+}
+
+func openQuickEdit(m model) (model, tea.Cmd) {
+	idx, row := m.deployTable.Cursor(), m.tfvarsTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || row < 0 || row >= len(m.tfvarsKeys) {
+		return m, nil
+	}
+	dep := m.deployments[idx]
+	k := m.tfvarsKeys[row]
+	meta := templateByName(m.templates, dep.Template).fieldMeta[k]
+	label := meta.Label
+	if label == "" {
+		label = k
+	}
+	if why := quickEditRefusal(k, meta); why != "" {
+		m.statusMessage = label + " " + why
+		return m, nil
+	}
+	vals, err := loadTfvars(filepath.Join(dep.Path, "terraform.tfvars"))
+	if err != nil {
+		m.statusMessage = "Could not load tfvars: " + err.Error()
+		return m, nil
+	}
+	v := vals[k]
+	quoted := len(v) >= 2 && strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`)
+	if quoted {
+		v = v[1 : len(v)-1]
+	}
+	in := textinput.New()
+	in.Prompt = ""
+	in.SetValue(v)
+	in.CursorEnd()
+	in.Width = max(m.layout.right-len([]rune(label))-6, 10)
+	in.Focus()
+	m.quickEdit = &quickEdit{Dir: dep.Path, Key: k, Label: label, Meta: meta, Saved: v, Quoted: quoted, Input: in}
+	return m, textinput.Blink
+}
+
+// Keys of the focused tfvars pane
+func updateTfvarsPane(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.quickEdit != nil {
+		return updateQuickEdit(m, msg)
+	}
+	k := keys.Tfvars
+	switch {
+	case key.Matches(msg, k.Back):
+		return focusTfvarsPane(m, false), nil
+	case key.Matches(msg, k.Up):
+		m.tfvarsTable.MoveUp(1)
+		m.tablesVersion++
+	case key.Matches(msg, k.Down):
+		m.tfvarsTable.MoveDown(1)
+		m.tablesVersion++
+	case key.Matches(msg, k.Edit):
+		return openQuickEdit(m)
+	}
+	return m, nil
+}
+
+func updateQuickEdit(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	q := *m.quickEdit
+	k := keys.Tfvars
+	if msg.Paste {
+		q.Status = pasteIntoField(&q.Input, q.Key, q.Meta, len(q.Meta.Options) > 0, string(msg.Runes))
+		m.quickEdit = &q
+		return m, nil
+	}
+	if isStepper(q.Meta) {
+		if status, ok := stepInput(&q.Input, q.Meta, msg, &keys.Tfvars,
+			[]key.Binding{k.Decrease, k.OptionPrev}, []key.Binding{k.Increase, k.OptionNext}); ok {
+			q.Status = status
+			m.quickEdit = &q
+			return m, nil
+		}
+	}
+	switch {
+	case key.Matches(msg, k.Cancel):
+		m.quickEdit = nil
+		return m, nil
+	case key.Matches(msg, k.Save):
+		return saveQuickEdit(m, q)
+	case len(q.Meta.Options) > 0 && key.Matches(msg, k.OptionPrev, k.OptionNext):
+		dir := 1
+		if key.Matches(msg, k.OptionPrev) {
+			dir = -1
+		}
+		n := len(q.Meta.Options)
+		i := indexOf(q.Input.Value(), q.Meta.Options)
+		if i < 0 && dir < 0 {
+			i = 0
+		}
+		q.Input.SetValue(q.Meta.Options[(i+dir+n)%n])
+		q.Input.CursorEnd()
+		m.quickEdit = &q
+		return m, nil
+	case len(q.Meta.Options) > 0 && msg.Type == tea.KeyRunes:
+		q.Status = fmt.Sprintf("Pick one of the options with %s/%s", k.OptionPrev.Help().Key, k.OptionNext.Help().Key)
+		m.quickEdit = &q
+		return m, nil
+	}
+	var cmd tea.Cmd
+	q.Input, cmd = q.Input.Update(msg)
+	q.Status = ""
+	m.quickEdit = &q
+	return m, cmd
+}
+
+// Writes the one variable, quoted as it was (or as fields.yaml types it)
+func saveQuickEdit(m model, q quickEdit) (tea.Model, tea.Cmd) {
+	v := q.Input.Value()
+	if v == q.Saved {
+		m.quickEdit = nil
+		m.statusMessage = q.Label + " unchanged"
+		return m, nil
+	}
+	if err := checkFieldValue(q.Meta, q.Label, v); err != nil {
+		q.Status = err.Error()
+		m.quickEdit = &q
+		return m, nil
+	}
+	if err := deploymentBusy(m, q.Dir); err != nil {
+		q.Status = err.Error() + " — save once it's done"
+		m.quickEdit = &q
+		return m, nil
+	}
+	written := v
+	if q.Quoted || q.Meta.Type == "string" {
+		written = fmt.Sprintf("\"%s\"", v)
+	}
+	changes := []string{fmt.Sprintf("%s: %s → %s", q.Key, q.Saved, v)}
+	if err := saveTfvars(filepath.Join(q.Dir, "terraform.tfvars"), map[string]string{q.Key: written}); err != nil {
+		q.Status = "Save failed: " + err.Error()
+		m.quickEdit = &q
+		recordAuditChanges("edit", q.Dir, "terraform.tfvars", "failed: "+err.Error(), changes)
+		return m, nil
+	}
+	recordAuditChanges("edit", q.Dir, "terraform.tfvars", "ok", changes)
+	m.quickEdit = nil
+	loadDeploymentDetail(&m, m.deployTable.Cursor())
+	m.statusMessage = fmt.Sprintf("Saved %s = %s (apply to roll it out)", q.Key, written)
+	return m, refreshDeploymentCmd(q.Dir)
+}
+
+// Detail line under the tables: the input while editing, else the pane's keys while focused
+func quickEditLines(m model, width int) []string {
+	k := keys.Tfvars
+	if q := m.quickEdit; q != nil {
+		lines := []string{focusedStyle.Render(q.Label+": ") + q.Input.View()}
+		hint := fmt.Sprintf("%s save, %s cancel", k.Save.Help().Key, k.Cancel.Help().Key)
+		switch {
+		case len(q.Meta.Options) > 0:
+			hint = pairHelp(k.OptionPrev, k.OptionNext, "").Help().Key + " pick, " + hint
+		case isStepper(q.Meta):
+			hint = fmt.Sprintf("%s/%s step by %d, ", k.Decrease.Help().Key, k.Increase.Help().Key, q.Meta.Step) + hint
+		}
+		if q.Status != "" {
+			hint = q.Status
+		}
+		return append(lines, truncate(hint, width))
+	}
+	if m.tfvarsFocus {
+		return []string{truncate(fmt.Sprintf("tfvars: %s row, %s edit value, %s back",
+			pairHelp(k.Up, k.Down, "").Help().Key, k.Edit.Help().Key, k.Back.Help().Key), width)}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Quick-open (Ctrl+O): jump to a deployment by typing part of its name ---
+
+const quickOpenRows = 10
+
+// Scores name against a fuzzy query: every query rune must appear in order. Runs of
+// consecutive matches and matches at the start of a word score higher; ok is false
+// when the query isn't a subsequence of name.
+func fuzzyScore(query, name string) (score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+	n := []rune(strings.ToLower(name))
+	qi, run := 0, 0
+	for i, r := range n {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			run = 0
+			continue
+		}
+		score++
+		run++
+		score += run
+		if i == 0 || !unicode.IsLetter(n[i-1]) && !unicode.IsDigit(n[i-1]) {
+			score += 3
+		}
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Shorter names win ties: "web" ranks above "web_staging_old" for "web"
+	return score*100 - len(n), true
+}
+
+// Deployments matching the query, best first
+func quickOpenMatches(m model) []deploymentInfo {
+	type scored struct {
+		d     deploymentInfo
+		score int
+	}
+	var found []scored
+	for _, d := range m.allDeployments {
+		if s, ok := fuzzyScore(m.quickOpenInput.Value(), d.Name); ok {
+			found = append(found, scored{d, s})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	out := make([]deploymentInfo, len(found))
+	for i, f := range found {
+		out[i] = f.d
+	}
+	return out
+}
+
+func openQuickOpen(m model) (model, tea.Cmd) {
+	m.quickOpen = true
+	m.quickOpenIdx = 0
+	m.quickOpenInput = textinput.New()
+	m.quickOpenInput.Placeholder = "deployment name"
+	m.quickOpenInput.Focus()
+	return m, textinput.Blink
+}
+
+// Selects the deployment in the launcher table, clearing filters that hide it
+func selectDeployment(m model, path string) model {
+	if _, ok := deploymentByPath(m.deployments, path); !ok {
+		m.stateFilter, m.zoneFilter = filterAllStates, ""
+		applyDeploymentFilter(&m)
+	}
+	for i, d := range m.deployments {
+		if d.Path == path {
+			m.deployTable.SetCursor(i)
+			m.tablesVersion++
+			loadDeploymentDetail(&m, i)
+		}
+	}
+	return m
+}
+
+func updateQuickOpen(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	matches := quickOpenMatches(m)
+	switch {
+	case key.Matches(keyMsg, keys.QuickOpen.Cancel):
+		m.quickOpen = false
+		return m, nil
+	case key.Matches(keyMsg, keys.QuickOpen.Up):
+		if n := min(len(matches), quickOpenRows); n > 0 {
+			m.quickOpenIdx = (m.quickOpenIdx - 1 + n) % n
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.QuickOpen.Down):
+		if n := min(len(matches), quickOpenRows); n > 0 {
+			m.quickOpenIdx = (m.quickOpenIdx + 1) % n
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.QuickOpen.Open):
+		if m.quickOpenIdx >= len(matches) {
+			return m, nil
+		}
+		m.quickOpen = false
+		d := matches[m.quickOpenIdx]
+		m = selectDeployment(m, d.Path)
+		m.statusMessage = "Selected " + d.Name
+		return m, nil
+	}
+	prev := m.quickOpenInput.Value()
+	var cmd tea.Cmd
+	m.quickOpenInput, cmd = m.quickOpenInput.Update(msg)
+	if m.quickOpenInput.Value() != prev {
+		m.quickOpenIdx = 0
+	}
+	return m, cmd
+}
+
+func viewQuickOpen(m model) string {
+	matches := quickOpenMatches(m)
+	var b strings.Builder
+	b.WriteString("Open: " + m.quickOpenInput.View() + "\n\n")
+	for i, d := range matches {
+		if i == quickOpenRows {
+			fmt.Fprintf(&b, "  … %d more, keep typing\n", len(matches)-quickOpenRows)
+			break
+		}
+		line := padRight(truncate(d.Name, 40), 42) + stateBadge(d, m.drift)
+		if i == m.quickOpenIdx {
+			b.WriteString(focusedStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString(normalStyle.Render("  "+line) + "\n")
+		}
+	}
+	if len(matches) == 0 {
+		b.WriteString("  (no deployment matches)\n")
+	}
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(popupBorder()).
+		Padding(0, 1).
+		Width(uiWidth - 40).
+		Render(strings.TrimRight(b.String(), "\n"))
+}
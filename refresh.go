@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Background refresh ---
+
+const defaultRefreshInterval = 30 * time.Second
+
+type refreshTickMsg time.Time
+
+// refreshDoneMsg carries the result of an async status/deployments refresh
+type refreshDoneMsg struct {
+	status      statusSnapshot
+	deployments []deploymentInfo
+	err         error
+	manual      bool
+}
+
+// refreshInterval parses cfg.RefreshInterval; empty means the default, "0" disables the ticker
+func refreshInterval(cfg Config) time.Duration {
+	if cfg.RefreshInterval == "" {
+		return defaultRefreshInterval
+	}
+	d, err := time.ParseDuration(cfg.RefreshInterval)
+	if err != nil || d < 0 {
+		return defaultRefreshInterval
+	}
+	return d
+}
+
+func scheduleRefresh(d time.Duration) tea.Cmd {
+	if d <= 0 {
+		return nil
+	}
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return refreshTickMsg(t)
+	})
+}
+
+func refreshCmd(cfg Config, manual bool) tea.Cmd {
+	return func() tea.Msg {
+		deployments, err := listDeployments(cfg.AppsPath)
+		status := collectStatus(cfg)
+		if !safeMode {
+			checkHealth(cfg, &status, manual)
+		}
+		return refreshDoneMsg{
+			status:      status,
+			deployments: deployments,
+			err:         err,
+			manual:      manual,
+		}
+	}
+}
+
+// deploymentChangedMsg carries one deployment re-read after an action changed it on disk
+type deploymentChangedMsg struct {
+	dir    string
+	info   deploymentInfo
+	exists bool
+}
+
+// Re-reads a single deployment (row, state, tfvars panel) without a full refresh
+func refreshDeploymentCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		st, err := os.Stat(dir)
+		if err != nil || !st.IsDir() {
+			return deploymentChangedMsg{dir: dir}
+		}
+		return deploymentChangedMsg{dir: dir, info: cachedDeploymentInfo(dir, st.ModTime()), exists: true}
+	}
+}
+
+// Replaces, inserts (in directory order, as listDeployments returns them) or drops one deployment
+func updateDeployment(m *model, msg deploymentChangedMsg) {
+	all := slices.DeleteFunc(slices.Clone(m.allDeployments), func(d deploymentInfo) bool { return d.Path == msg.dir })
+	if msg.exists {
+		i, _ := slices.BinarySearchFunc(all, msg.dir, func(d deploymentInfo, dir string) int { return strings.Compare(d.Path, dir) })
+		all = slices.Insert(all, i, msg.info)
+	}
+	setDeployments(m, all)
+}
+
+func startRefresh(m model, manual bool) (model, tea.Cmd) {
+	if m.isRefreshing {
+		return m, nil
+	}
+	m.isRefreshing = true
+	return m, tea.Batch(refreshCmd(m.cfg, manual), m.refreshSpinner.Tick)
+}
+
+// handleRefreshMsg processes refresh messages regardless of the current scene or busy state.
+// The bool result reports whether msg was consumed.
+func handleRefreshMsg(m model, msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case refreshTickMsg:
+		next := scheduleRefresh(refreshInterval(m.cfg))
+		m, cmd := startRefresh(m, false)
+		return m, tea.Batch(cmd, next), true
+	case deploymentChangedMsg:
+		updateDeployment(&m, msg)
+		return m, nil, true
+	case filesChangedMsg:
+		m, cmd := handleFilesChanged(m, msg)
+		return m, cmd, true
+	case refreshDoneMsg:
+		m.isRefreshing = false
+		applyStatusSnapshot(&m, msg.status)
+		if msg.err == nil {
+			setDeployments(&m, msg.deployments)
+		}
+		if msg.manual {
+			if msg.err != nil {
+				m.statusMessage = "Refresh failed: " + msg.err.Error()
+			} else {
+				m.statusMessage = "Deployments refreshed!"
+			}
+		}
+		// The template may have moved on since the selection's last lookup
+		return m, templateInfoCmd(m), true
+	case spinner.TickMsg:
+		if msg.ID != m.refreshSpinner.ID() {
+			return m, nil, false
+		}
+		if !m.isRefreshing {
+			return m, nil, true
+		}
+		var cmd tea.Cmd
+		m.refreshSpinner, cmd = m.refreshSpinner.Update(msg)
+		return m, cmd, true
+	}
+	return m, nil, false
+}
+
+// setDeployments swaps in a fresh deployments list, keeping the selected deployment selected
+func setDeployments(m *model, deployments []deploymentInfo) {
+	m.allDeployments = deployments
+	pruneSelection(m)
+	applyDeploymentFilter(m)
+}
+
+func refreshIndicator(m model) string {
+	if m.isRefreshing {
+		return m.refreshSpinner.View()
+	}
+	return " "
+}
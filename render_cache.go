@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"time"
+)
+
+// --- Render cache and frame timing (F12) ---
+
+const frameSamples = 120
+
+type cachedPane struct {
+	key uint64
+	out string
+}
+
+// renderCache keeps rendered panes between frames. View has a value receiver,
+// so the cache is shared by pointer; View and Update run on the same goroutine.
+type renderCache struct {
+	panes        map[string]cachedPane
+	hits, misses int
+	frames       []time.Duration // ring of recent View durations
+	next         int
+	debug        bool
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{
+		panes: map[string]cachedPane{},
+		debug: os.Getenv("INFRA_CATALOG_DEBUG_FRAMES") != "",
+	}
+}
+
+// Hashes what a pane is rendered from
+func paneKey(parts ...string) uint64 {
+	h := fnv.New64a()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// Returns the pane rendered last time when its key is unchanged, re-rendering it otherwise
+func (c *renderCache) pane(name string, key uint64, render func() string) string {
+	if p, ok := c.panes[name]; ok && p.key == key {
+		c.hits++
+		return p.out
+	}
+	c.misses++
+	out := render()
+	c.panes[name] = cachedPane{key: key, out: out}
+	return out
+}
+
+func (c *renderCache) recordFrame(d time.Duration) {
+	if len(c.frames) < frameSamples {
+		c.frames = append(c.frames, d)
+		return
+	}
+	c.frames[c.next] = d
+	c.next = (c.next + 1) % frameSamples
+}
+
+// One-line frame time summary over the recent frames
+func (c *renderCache) overlay() string {
+	if len(c.frames) == 0 {
+		return "frame: no samples yet"
+	}
+	sorted := slices.Clone(c.frames)
+	slices.Sort(sorted)
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	last := c.frames[(c.next-1+len(c.frames))%len(c.frames)]
+	hitRate := 0.0
+	if c.hits+c.misses > 0 {
+		hitRate = float64(c.hits) * 100 / float64(c.hits+c.misses)
+	}
+	return fmt.Sprintf("frame %s │ avg %s │ p95 %s │ max %s over %d frames │ pane cache %.0f%% hits, %d panes",
+		last.Round(time.Microsecond), (total / time.Duration(len(sorted))).Round(time.Microsecond),
+		sorted[len(sorted)*95/100].Round(time.Microsecond), sorted[len(sorted)-1].Round(time.Microsecond),
+		len(sorted), hitRate, len(c.panes))
+}
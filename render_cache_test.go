@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLauncherPaneCache(t *testing.T) {
+	m, _ := newTestModel(t, "web_a", "web_b")
+	first := m.View()
+	hits := m.render.hits
+	if m.View() != first || m.render.hits <= hits {
+		t.Fatalf("unchanged launcher was re-rendered (hits %d -> %d)", hits, m.render.hits)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if next.(model).View() == first {
+		t.Error("launcher pane kept after the cursor moved")
+	}
+}
+
+func BenchmarkLauncherView(b *testing.B) {
+	var names []string
+	for i := range 200 {
+		names = append(names, fmt.Sprintf("app_%03d", i))
+	}
+	m, _ := newTestModel(b, names...)
+	b.ResetTimer()
+	for range b.N {
+		m.View()
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Find and replace across every deployment's terraform.tfvars (Ctrl+F on the launcher) ---
+
+// A tfvars file with matches, before and after the replacement
+type replaceFile struct {
+	Name    string
+	Dir     string
+	Before  []string
+	After   []string
+	Matches int
+	Skip    bool // opted out in the preview
+}
+
+// Replacer of the find/replace inputs; literal unless regex, where $1 etc. expand groups.
+// Matches never span lines.
+func replacer(find, with string, regex bool) (func(line string) (string, int), error) {
+	if find == "" {
+		return nil, fmt.Errorf("type the text to find")
+	}
+	if !regex {
+		return func(line string) (string, int) {
+			return strings.ReplaceAll(line, find, with), strings.Count(line, find)
+		}, nil
+	}
+	re, err := regexp.Compile(find)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return func(line string) (string, int) {
+		return re.ReplaceAllString(line, with), len(re.FindAllStringIndex(line, -1))
+	}, nil
+}
+
+func replaceLines(lines []string, replace func(string) (string, int)) ([]string, int) {
+	out := make([]string, len(lines))
+	total := 0
+	for i, line := range lines {
+		var n int
+		out[i], n = replace(line)
+		total += n
+	}
+	return out, total
+}
+
+func openReplace(m model) (model, tea.Cmd) {
+	m.replaceFind = textinput.New()
+	m.replaceFind.Placeholder = "text to find, e.g. pve-old"
+	m.replaceWith = textinput.New()
+	m.replaceWith.Placeholder = "replacement"
+	m.replaceFind.Focus()
+	m.replaceFocus, m.replaceIdx, m.replaceConfirm, m.replaceStatus = 0, 0, false, ""
+	m.replaceFiles = nil
+	return m.pushScene(sceneReplace), textinput.Blink
+}
+
+// Dry run over every deployment on disk; files without a match are left out.
+// Opt-outs survive as long as the file still matches.
+func previewReplace(m model) model {
+	skipped := map[string]bool{}
+	for _, f := range m.replaceFiles {
+		if f.Skip {
+			skipped[f.Dir] = true
+		}
+	}
+	m.replaceFiles, m.replaceConfirm, m.replaceStatus = nil, false, ""
+	replace, err := replacer(m.replaceFind.Value(), m.replaceWith.Value(), m.replaceRegex)
+	if err != nil {
+		if m.replaceFind.Value() != "" {
+			m.replaceStatus = err.Error() // invalid regex
+		}
+		return m
+	}
+	for _, dep := range m.allDeployments {
+		before := readLines(filepath.Join(dep.Path, "terraform.tfvars"))
+		after, n := replaceLines(before, replace)
+		if n > 0 {
+			m.replaceFiles = append(m.replaceFiles, replaceFile{Name: dep.Name, Dir: dep.Path, Before: before, After: after, Matches: n, Skip: skipped[dep.Path]})
+		}
+	}
+	sort.Slice(m.replaceFiles, func(i, j int) bool { return m.replaceFiles[i].Name < m.replaceFiles[j].Name })
+	m.replaceIdx = min(m.replaceIdx, max(len(m.replaceFiles)-1, 0))
+	return m
+}
+
+// Files and matches the replacement would write, opted-out files excluded
+func replaceCounts(m model) (files, matches int) {
+	for _, f := range m.replaceFiles {
+		if !f.Skip {
+			files++
+			matches += f.Matches
+		}
+	}
+	return files, matches
+}
+
+// Writes the replacement to the files that weren't opted out. Each file is re-read and
+// backed up first, so a change made since the preview is neither lost nor unrecoverable.
+func applyReplace(m model) (model, tea.Cmd) {
+	find, with := m.replaceFind.Value(), m.replaceWith.Value()
+	replace, err := replacer(find, with, m.replaceRegex)
+	if err != nil {
+		m.replaceStatus = err.Error()
+		return m, nil
+	}
+	var cmds []tea.Cmd
+	var failed []string
+	written, total := 0, 0
+	for _, f := range m.replaceFiles {
+		if f.Skip {
+			continue
+		}
+		n, err := 0, deploymentBusy(m, f.Dir)
+		if err == nil {
+			n, err = replaceInFile(filepath.Join(f.Dir, "terraform.tfvars"), replace)
+		}
+		if err != nil {
+			logger.Error("find/replace failed", "component", "replace", "deployment", f.Name, "error", err.Error())
+			recordAudit("replace", f.Dir, find, "failed: "+err.Error())
+			failed = append(failed, f.Name)
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		recordAuditChanges("replace", f.Dir, find, "ok", []string{fmt.Sprintf("%q → %q (%s)", find, with, plural(n, "occurrence"))})
+		cmds = append(cmds, refreshDeploymentCmd(f.Dir))
+		written++
+		total += n
+	}
+	m.statusMessage = fmt.Sprintf("Replaced %s in %s — apply them to roll it out", plural(total, "occurrence"), plural(written, "deployment"))
+	if len(failed) > 0 {
+		m.statusMessage += fmt.Sprintf("; failed for %s (see log)", strings.Join(failed, ", "))
+	}
+	return m.homeScene(), tea.Batch(cmds...)
+}
+
+func replaceInFile(filename string, replace func(string) (string, int)) (int, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	lines, n := replaceLines(strings.Split(string(data), "\n"), replace)
+	if n == 0 {
+		return 0, nil
+	}
+	if err := backupTfvars(filename); err != nil {
+		return 0, fmt.Errorf("could not back up tfvars: %w", err)
+	}
+	return n, os.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+func updateReplace(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	n := len(m.replaceFiles)
+	switch {
+	case key.Matches(keyMsg, keys.Replace.Back):
+		return m.popScene(), nil
+	case key.Matches(keyMsg, keys.Replace.Up):
+		if n > 0 {
+			m.replaceIdx = (m.replaceIdx - 1 + n) % n
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.Replace.Down):
+		if n > 0 {
+			m.replaceIdx = (m.replaceIdx + 1) % n
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.Replace.Next):
+		m.replaceFocus = 1 - m.replaceFocus
+		if m.replaceFocus == 0 {
+			m.replaceWith.Blur()
+			m.replaceFind.Focus()
+		} else {
+			m.replaceFind.Blur()
+			m.replaceWith.Focus()
+		}
+		return m, textinput.Blink
+	case key.Matches(keyMsg, keys.Replace.Regex):
+		m.replaceRegex = !m.replaceRegex
+		return previewReplace(m), nil
+	case key.Matches(keyMsg, keys.Replace.Skip):
+		if n > 0 {
+			m.replaceFiles[m.replaceIdx].Skip = !m.replaceFiles[m.replaceIdx].Skip
+			m.replaceConfirm, m.replaceStatus = false, ""
+		}
+		return m, nil
+	case key.Matches(keyMsg, keys.Replace.Apply):
+		_, err := replacer(m.replaceFind.Value(), m.replaceWith.Value(), m.replaceRegex)
+		switch files, matches := replaceCounts(m); {
+		case err != nil:
+			m.replaceStatus = err.Error()
+		case files == 0:
+			m.replaceStatus = "Nothing to replace"
+		case !m.replaceConfirm:
+			m.replaceConfirm = true
+			m.replaceStatus = fmt.Sprintf("Press %s again to replace %s in %s", keys.Replace.Apply.Help().Key, plural(matches, "occurrence"), plural(files, "terraform.tfvars file"))
+		default:
+			return applyReplace(m)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	if m.replaceFocus == 0 {
+		m.replaceFind, cmd = m.replaceFind.Update(msg)
+	} else {
+		m.replaceWith, cmd = m.replaceWith.Update(msg)
+	}
+	return previewReplace(m), cmd
+}
+
+func viewReplace(m model) (body, tooltip string) {
+	const listWidth = 34
+	mode := "literal"
+	if m.replaceRegex {
+		mode = "regex"
+	}
+	body += tooltipStyle.Render(fmt.Sprintf("Find and replace in every deployment's terraform.tfvars (%s, %s toggles)", mode, keys.Replace.Regex.Help().Key)) + "\n\n"
+	body += " " + padRight("Find", 10) + m.replaceFind.View() + "\n"
+	body += " " + padRight("Replace", 10) + m.replaceWith.View() + "\n"
+	body += " " + strings.Repeat("─", uiWidth-4) + "\n"
+	var left []string
+	for i, f := range m.replaceFiles {
+		mark := "~ "
+		if f.Skip {
+			mark = "x "
+		}
+		line := padRight(truncate(fmt.Sprintf("%s%s (%d)", mark, f.Name, f.Matches), listWidth), listWidth)
+		if i == m.replaceIdx {
+			line = focusedStyle.Render(line)
+		} else {
+			line = normalStyle.Render(line)
+		}
+		left = append(left, line)
+	}
+	// Diff of the file under the cursor
+	var right []string
+	if m.replaceIdx < len(m.replaceFiles) {
+		f := m.replaceFiles[m.replaceIdx]
+		if f.Skip {
+			right = []string{fmt.Sprintf("(skipped — %s to include it again)", keys.Replace.Skip.Help().Key)}
+		}
+		right = append(right, tfvarsDiffLines(m, f.Before, f.After, uiWidth-listWidth-12)...)
+	} else if m.replaceFind.Value() != "" && m.replaceStatus == "" {
+		right = []string{"(no matches)"}
+	}
+	body += splitPane(left, right, listWidth, 20)
+	if len(m.replaceFiles) > 0 {
+		files, matches := replaceCounts(m)
+		body += "\n " + logDimStyle.Render(fmt.Sprintf("Dry run: %s in %s (%d skipped)", plural(matches, "occurrence"), plural(files, "file"), len(m.replaceFiles)-files)) + "\n"
+	}
+	if m.replaceStatus != "" {
+		body += "\n " + m.replaceStatus + "\n"
+	}
+	tooltip = tooltipStyle.Render("Nothing is written until you confirm; each file is backed up first (" + keys.Edit.Rollback.Help().Key + " in the edit form rolls back). Deployments aren't applied.")
+	return body, tooltip
+}
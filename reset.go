@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Reset fields to the preset (create form) or last-saved tfvars (edit form) ---
+
+// Marks fields whose value differs from what a reset would restore
+const modifiedMarker = "•"
+
+// Value the create form field had when the current preset was applied
+func presetDefault(m model, label string) string {
+	if m.presetIdx >= len(m.presets) {
+		return ""
+	}
+	if val, ok := m.presets[m.presetIdx].Values[label]; ok {
+		return presetValueString(val)
+	}
+	return ""
+}
+
+func createFieldModified(m model, i int) bool {
+	label := m.createLabels[i]
+	return !isMaskedField(m.fieldMeta[label]) && m.createInputs[i].Value() != presetDefault(m, label)
+}
+
+// editSaved holds the values as loaded from or last written to terraform.tfvars
+func recordEditSaved(m model) model {
+	m.editSaved = formValues(m.editFormInputs)
+	return m
+}
+
+func editFieldModified(m model, i int) bool {
+	return i < len(m.editSaved) && m.editFormInputs[i].Value() != m.editSaved[i]
+}
+
+// "field: saved → edited" for each changed field, secret and sensitive values masked
+func editChanges(m model) []string {
+	var changes []string
+	for i, label := range m.editFormLabels {
+		if editFieldModified(m, i) {
+			meta := m.fieldMeta[label]
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", label, maskFieldValue(meta, m.editSaved[i]), maskFieldValue(meta, m.editFormInputs[i].Value())))
+		}
+	}
+	return changes
+}
+
+func countModified(n int, modified func(i int) bool) int {
+	count := 0
+	for i := 0; i < n; i++ {
+		if modified(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// Form header note: how many fields Enter would change
+func modifiedSummary(n int, from string) string {
+	if n == 0 {
+		return ""
+	}
+	return "   " + modifiedStyle.Render(fmt.Sprintf("%s %d field(s) changed %s", fieldMarker(true), n, from))
+}
+
+func fieldMarker(modified bool) string {
+	if !modified {
+		return " "
+	}
+	if icons == asciiIcons {
+		return "*"
+	}
+	return modifiedMarker
+}
+
+// Reset keys for the create form; ok is false when msg isn't one of them
+func resetCreateFields(m model, msg tea.KeyMsg) (model, bool) {
+	all := key.Matches(msg, keys.Create.ResetAll)
+	if !all && !key.Matches(msg, keys.Create.ResetField) {
+		return m, false
+	}
+	n := 0
+	for i, label := range m.createLabels {
+		if (all || i == m.createFocus) && createFieldModified(m, i) {
+			m.createInputs[i].SetValue(presetDefault(m, label))
+			n++
+		}
+	}
+	m.createStatus = resetStatus(n, "preset "+m.presets[m.presetIdx].Name)
+	return m, true
+}
+
+// Reset keys for the edit form; ok is false when msg isn't one of them
+func resetEditFields(m model, msg tea.KeyMsg) (model, bool) {
+	all := key.Matches(msg, keys.Edit.ResetAll)
+	if !all && !key.Matches(msg, keys.Edit.ResetField) {
+		return m, false
+	}
+	n := 0
+	for i := range m.editFormInputs {
+		if (all || i == m.editFocusIndex) && editFieldModified(m, i) {
+			m.editFormInputs[i].SetValue(m.editSaved[i])
+			n++
+		}
+	}
+	m.editStatus = resetStatus(n, "saved terraform.tfvars")
+	return m, true
+}
+
+func resetStatus(n int, source string) string {
+	switch n {
+	case 0:
+		return "Nothing to reset: already matches the " + source
+	case 1:
+		return "Reset 1 field to the " + source
+	}
+	return fmt.Sprintf("Reset %d fields to the %s", n, source)
+}
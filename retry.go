@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/state"
+)
+
+// --- Retry a failed job from the step that failed (Ctrl+R on the launcher) ---
+
+// Where the last job of a deployment failed, kept in launcher.state until a later step succeeds
+type failedRun = state.Failure
+
+// Records the failed step of op and the steps it didn't get to. The error is terraform's
+// first "Error:" line when the output tail has one.
+func recordFailure(op *tfOperation, err error) error {
+	reason := strings.SplitN(err.Error(), "\n", 2)[0]
+	for _, line := range op.output {
+		if isTerraformError(line) {
+			reason = strings.TrimSpace(strings.TrimLeft(line, "│ "))
+			break
+		}
+	}
+	s, _ := getDeploymentState(op.Dir)
+	s.Failure = &failedRun{
+		Label: op.Label,
+		Phase: op.Steps[op.step].Name,
+		Error: reason,
+		At:    time.Now().UTC().Format(time.RFC3339),
+		Steps: slices.Clone(op.Steps[op.step:]),
+	}
+	return writeDeploymentState(op.Dir, s)
+}
+
+// terraform's "Error: …" line, also inside the "│ " box of diagnostics
+func isTerraformError(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, "│ "), "Error:")
+}
+
+// "FAILED:apply" in the state column
+func failedBadge(info deploymentInfo) string {
+	if info.State != "FAILED" || info.Failure == nil {
+		return info.State
+	}
+	return "FAILED:" + info.Failure.Phase
+}
+
+// Failure detail of the selected deployment for the launcher's detail pane
+func failureLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || m.deployments[idx].State != "FAILED" || m.deployments[idx].Failure == nil {
+		return nil
+	}
+	f := m.deployments[idx].Failure
+	return []string{
+		truncate(fmt.Sprintf("Failed at %s, %s — %s retries from there", f.Phase, describeTime(f.At), keys.Launcher.Retry.Help().Key), width),
+		truncate("  "+f.Error, width),
+	}
+}
+
+// Operation running the recorded steps again, with the follow-up of the original job
+func retryOperation(m model, dep deploymentInfo) (*tfOperation, error) {
+	f := dep.Failure
+	if dep.State != "FAILED" || f == nil || len(f.Steps) == 0 {
+		return nil, fmt.Errorf("%s has no failed job to retry", dep.Name)
+	}
+	for _, s := range f.Steps {
+		if slices.Contains(s.Args, planFile) && s.Name == "apply" {
+			return nil, fmt.Errorf("a saved plan can't be applied again after a failed apply — plan again with %s", keys.Launcher.Plan.Help().Key)
+		}
+	}
+	names := make([]string, len(f.Steps))
+	for i, s := range f.Steps {
+		names[i] = s.Name
+	}
+	op := &tfOperation{
+		Label:          fmt.Sprintf("Retrying %s of %s", strings.Join(names, "+"), dep.Name),
+		Dir:            dep.Path,
+		Steps:          slices.Clone(f.Steps),
+		SuccessMessage: fmt.Sprintf("%s recovered: %s succeeded", dep.Name, strings.Join(names, "+")),
+	}
+	for _, s := range f.Steps {
+		switch {
+		case s.Name == "plan":
+			dir := dep.Path
+			op.OnSuccess = func() error { return recordPlan(dir) }
+		case s.State == "DEPLOYED":
+			op.OnSuccess = postApplyHook(m.cfg, templateByName(m.templates, dep.Template), dep.Path)
+		}
+	}
+	return op, nil
+}
+
+func retryFailed(m model) (model, tea.Cmd) {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m, nil
+	}
+	dep := m.deployments[idx]
+	op, err := retryOperation(m, dep)
+	if err != nil {
+		m.statusMessage = err.Error()
+		return m, nil
+	}
+	m, cmd, held := guardApply(m, dep, op)
+	if !held {
+		m.statusMessage = fmt.Sprintf("Queued job #%d: %s", m.nextJobID, op.Label)
+	}
+	return m, cmd
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- tfvars history and rollback ---
+
+const (
+	tfvarsHistoryDir  = ".history"
+	tfvarsHistoryKeep = 50
+	historyTimeFormat = "20060102-150405.000"
+)
+
+type tfvarsVersion struct {
+	Path string
+	Time time.Time
+}
+
+// Copies filename into <dir>/.history before it is overwritten; keeps the newest tfvarsHistoryKeep copies
+func backupTfvars(filename string) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(filepath.Dir(filename), tfvarsHistoryDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := filepath.Base(filename) + "." + time.Now().UTC().Format(historyTimeFormat)
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return err
+	}
+	versions, _ := listTfvarsVersions(filename)
+	for _, v := range versions[min(len(versions), tfvarsHistoryKeep):] {
+		os.Remove(v.Path)
+	}
+	return nil
+}
+
+// Backups of filename, newest first
+func listTfvarsVersions(filename string) ([]tfvarsVersion, error) {
+	prefix := filepath.Base(filename) + "."
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(filename), tfvarsHistoryDir))
+	if err != nil {
+		return nil, err
+	}
+	var versions []tfvarsVersion
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		t, err := time.Parse(historyTimeFormat, strings.TrimPrefix(e.Name(), prefix))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, tfvarsVersion{Path: filepath.Join(filepath.Dir(filename), tfvarsHistoryDir, e.Name()), Time: t})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Time.After(versions[j].Time) })
+	return versions, nil
+}
+
+// Rows of a list on the left and a pane on the right, at most maxRows
+func splitPane(left, right []string, listWidth, maxRows int) string {
+	var body string
+	for i := 0; i < min(max(len(left), len(right)), maxRows); i++ {
+		l, r := strings.Repeat(" ", listWidth), ""
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		body += " " + padRight(l, listWidth) + " │ " + r + "\n"
+	}
+	return body
+}
+
+func readLines(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+// Restores a backup over filename (the current file is backed up first, so a rollback can be undone)
+func restoreTfvars(filename string, v tfvarsVersion) error {
+	data, err := os.ReadFile(v.Path)
+	if err != nil {
+		return err
+	}
+	if err := backupTfvars(filename); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// Reloads the edit form inputs from the tfvars file on disk
+func reloadEditForm(m model) model {
+	vals, err := loadTfvars(m.editFormPath)
+	if err != nil {
+		m.editStatus = "Could not load tfvars: " + err.Error()
+		return m
+	}
+	for i, key := range m.editFormLabels {
+		m.editFormInputs[i].SetValue(strings.Trim(vals[key], "\"[]"))
+	}
+	m.editHistory = newFormHistory()
+	return recordEditSaved(m)
+}
+
+// --- Rollback picker (F9 in the edit form) ---
+
+func openRollback(m model) model {
+	versions, err := listTfvarsVersions(m.editFormPath)
+	if err != nil || len(versions) == 0 {
+		m.editStatus = "No previous versions of terraform.tfvars yet — one is kept on every save"
+		return m
+	}
+	m.rollbackVersions = versions
+	m.rollbackIdx = 0
+	return m.pushScene(sceneRollback)
+}
+
+func updateRollback(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	n := len(m.rollbackVersions)
+	switch {
+	case key.Matches(keyMsg, keys.Rollback.Back):
+		return m.popScene(), nil
+	case key.Matches(keyMsg, keys.Rollback.Up):
+		m.rollbackIdx = (m.rollbackIdx - 1 + n) % n
+	case key.Matches(keyMsg, keys.Rollback.Down):
+		m.rollbackIdx = (m.rollbackIdx + 1) % n
+	case key.Matches(keyMsg, keys.Rollback.Restore, keys.Rollback.RestoreApply):
+		v := m.rollbackVersions[m.rollbackIdx]
+		if err := deploymentBusy(m, filepath.Dir(m.editFormPath)); err != nil {
+			m.editStatus = err.Error()
+			return m.popScene(), nil
+		}
+		if err := restoreTfvars(m.editFormPath, v); err != nil {
+			m.editStatus = "Rollback failed: " + err.Error()
+			return m.popScene(), nil
+		}
+		deployDir := filepath.Dir(m.editFormPath)
+		recordAudit("rollback-tfvars", deployDir, v.Time.Format(time.RFC3339), "ok")
+		m = reloadEditForm(m.popScene())
+		m.editStatus = fmt.Sprintf("Restored terraform.tfvars from %s. Press [A] to apply.", localTime(v.Time).Format("2006-01-02 15:04:05"))
+		if key.Matches(keyMsg, keys.Rollback.Restore) {
+			return m, refreshDeploymentCmd(deployDir)
+		}
+		op := deployOperation(deployDir, "Rolled-back tfvars applied!")
+		dep, _ := deploymentByPath(m.allDeployments, deployDir)
+		op.OnSuccess = postApplyHook(m.cfg, templateByName(m.templates, dep.Template), deployDir)
+		m, cmd, held := guardApply(m, dep, op)
+		if !held {
+			m.editStatus = fmt.Sprintf("Restored terraform.tfvars from %s and queued job #%d (init + apply).", localTime(v.Time).Format("2006-01-02 15:04:05"), m.nextJobID)
+		}
+		return m, tea.Batch(cmd, refreshDeploymentCmd(deployDir))
+	}
+	return m, nil
+}
+
+func viewRollback(m model) (body, tooltip string) {
+	const listWidth = 30
+	body += tooltipStyle.Render("Previous versions of " + m.editFormPath)
+	body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+	var left []string
+	for i, v := range m.rollbackVersions {
+		line := padRight(localTime(v.Time).Format("2006-01-02 15:04:05"), listWidth)
+		if i == m.rollbackIdx {
+			line = focusedStyle.Render(line)
+		} else {
+			line = normalStyle.Render(line)
+		}
+		left = append(left, line)
+	}
+	// Diff from the selected version to the current file, i.e. what restoring would undo
+	right := tfvarsDiffLines(m, readLines(m.rollbackVersions[m.rollbackIdx].Path), readLines(m.editFormPath), uiWidth-listWidth-12)
+	if len(right) == 0 {
+		right = []string{"(identical to the current file)"}
+	}
+	body += splitPane(left, right, listWidth, 24)
+	tooltip = tooltipStyle.Render("'-' lines come back on restore, '+' lines (current file) go away")
+	return body, tooltip
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- S3 remote state browser ---
+
+const stateKeySuffix = "/s3/terraform.tfstate"
+
+type stateEntry struct {
+	Deployment string
+	Key        string // "" when the deployment has no remote state
+	Size       int64
+	Modified   time.Time
+	Orphaned   bool // state key with no local deployment directory
+}
+
+type s3StateMsg struct {
+	entries []stateEntry
+	err     error
+}
+
+type s3ActionMsg struct {
+	status string
+	reload bool
+}
+
+func newS3Client(cfg Config) (*s3.Client, error) {
+	awsCfg, err := loadAWSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(awsCfg), nil
+}
+
+// SDK config for the configured profile and region
+func loadAWSConfig(cfg Config) (aws.Config, error) {
+	region := cfg.AWSRegion
+	if region == "" {
+		region = "ap-southeast-2"
+	}
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if cfg.AWSProfile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(cfg.AWSProfile))
+	}
+	return awsconfig.LoadDefaultConfig(context.Background(), opts...)
+}
+
+// Lists state keys in the bucket and matches them against local deployment directories
+func listStateEntries(cfg Config, deployments []deploymentInfo) ([]stateEntry, error) {
+	if cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("s3_bucket is not configured")
+	}
+	client, err := newS3Client(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	local := map[string]bool{}
+	for _, d := range deployments {
+		local[filepath.Base(d.Path)] = true
+	}
+	withState := map[string]bool{}
+	var entries []stateEntry
+	p := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{Bucket: aws.String(cfg.S3Bucket)})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		logS3("list", cfg.S3Bucket, "", err)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if !strings.HasSuffix(key, ".tfstate") {
+				continue // lock files, backups, ...
+			}
+			dep := strings.TrimSuffix(key, stateKeySuffix)
+			if dep == key {
+				dep = strings.SplitN(key, "/", 2)[0]
+			}
+			withState[dep] = true
+			entries = append(entries, stateEntry{
+				Deployment: dep,
+				Key:        key,
+				Size:       aws.ToInt64(obj.Size),
+				Modified:   aws.ToTime(obj.LastModified),
+				Orphaned:   !local[dep],
+			})
+		}
+	}
+	for name := range local {
+		if !withState[name] {
+			entries = append(entries, stateEntry{Deployment: name})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Orphaned != entries[j].Orphaned {
+			return entries[i].Orphaned
+		}
+		return entries[i].Deployment < entries[j].Deployment
+	})
+	return entries, nil
+}
+
+func loadStateEntriesCmd(cfg Config, deployments []deploymentInfo) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := listStateEntries(cfg, deployments)
+		return s3StateMsg{entries: entries, err: err}
+	}
+}
+
+func stateBackupDir() string {
+	return filepath.Join(filepath.Dir(logPath()), "state-backups")
+}
+
+// Downloads a state object to the local backup dir and returns the file path
+func downloadState(cfg Config, key string) (string, error) {
+	client, err := newS3Client(cfg)
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: aws.String(cfg.S3Bucket), Key: aws.String(key)})
+	logS3("get", cfg.S3Bucket, key, err)
+	if err != nil {
+		return "", err
+	}
+	defer out.Body.Close()
+	if err := os.MkdirAll(stateBackupDir(), 0700); err != nil {
+		return "", err
+	}
+	name := strings.ReplaceAll(key, "/", "_") + "." + time.Now().Format("20060102-150405")
+	path := filepath.Join(stateBackupDir(), name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, out.Body); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func deleteState(cfg Config, key string) error {
+	client, err := newS3Client(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteObject(context.Background(), &s3.DeleteObjectInput{Bucket: aws.String(cfg.S3Bucket), Key: aws.String(key)})
+	logS3("delete", cfg.S3Bucket, key, err)
+	return err
+}
+
+func openStateBrowser(m model) (model, tea.Cmd) {
+	m.stateEntries = nil
+	m.stateConfirmDelete = false
+	m.stateStatus = fmt.Sprintf("Listing state in s3://%s ...", m.cfg.S3Bucket)
+	m.stateTable = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Deployment", Width: 40},
+			{Title: "State key", Width: 56},
+			{Title: "Size", Width: 10},
+			{Title: "Modified", Width: 20},
+			{Title: "Status", Width: 12},
+		}),
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeys(keys.State.Up, keys.State.Down)),
+		table.WithStyles(tableStyles()),
+		table.WithHeight(uiHeight-16),
+	)
+	return m.pushScene(sceneS3State), loadStateEntriesCmd(m.cfg, m.allDeployments)
+}
+
+func stateRows(entries []stateEntry) []table.Row {
+	rows := make([]table.Row, len(entries))
+	for i, e := range entries {
+		status, size, modified := "OK", "", ""
+		if e.Key == "" {
+			status = "NO STATE"
+		} else {
+			size = formatBytes(e.Size)
+			modified = localTime(e.Modified).Format("2006-01-02 15:04")
+		}
+		if e.Orphaned {
+			status = "ORPHANED"
+		}
+		rows[i] = table.Row{e.Deployment, e.Key, size, modified, status}
+	}
+	return rows
+}
+
+func selectedStateEntry(m model) (stateEntry, bool) {
+	i := m.stateTable.Cursor()
+	if i < 0 || i >= len(m.stateEntries) {
+		return stateEntry{}, false
+	}
+	return m.stateEntries[i], true
+}
+
+func updateStateBrowser(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case s3StateMsg:
+		if msg.err != nil {
+			m.stateStatus = "Could not list S3 state: " + msg.err.Error()
+			return m, nil
+		}
+		m.stateEntries = msg.entries
+		m.stateTable.SetRows(stateRows(msg.entries))
+		orphaned := 0
+		for _, e := range msg.entries {
+			if e.Orphaned {
+				orphaned++
+			}
+		}
+		m.stateStatus = fmt.Sprintf("%d entries, %d orphaned state key(s)", len(msg.entries), orphaned)
+		return m, nil
+	case s3ActionMsg:
+		m.stateStatus = msg.status
+		if msg.reload {
+			return m, loadStateEntriesCmd(m.cfg, m.allDeployments)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.stateConfirmDelete {
+			m.stateConfirmDelete = false
+			e, ok := selectedStateEntry(m)
+			if !ok || !key.Matches(msg, keys.Confirm.Yes) {
+				m.stateStatus = "Delete cancelled"
+				return m, nil
+			}
+			cfg := m.cfg
+			m.stateStatus = "Deleting " + e.Key + " ..."
+			return m, func() tea.Msg {
+				if err := deleteState(cfg, e.Key); err != nil {
+					recordAudit("delete-state", e.Deployment, "s3://"+cfg.S3Bucket+"/"+e.Key, "failed: "+err.Error())
+					return s3ActionMsg{status: "Delete failed: " + err.Error()}
+				}
+				recordAudit("delete-state", e.Deployment, "s3://"+cfg.S3Bucket+"/"+e.Key, "ok")
+				return s3ActionMsg{status: "Deleted s3://" + cfg.S3Bucket + "/" + e.Key, reload: true}
+			}
+		}
+		switch {
+		case key.Matches(msg, keys.State.Back):
+			return m.popScene(), nil
+		case key.Matches(msg, keys.State.Reload):
+			m.stateStatus = "Reloading..."
+			return m, loadStateEntriesCmd(m.cfg, m.allDeployments)
+		case key.Matches(msg, keys.State.Download):
+			e, ok := selectedStateEntry(m)
+			if !ok || !e.Orphaned {
+				m.stateStatus = "Only orphaned state can be downloaded or deleted here"
+				return m, nil
+			}
+			cfg := m.cfg
+			m.stateStatus = "Downloading " + e.Key + " ..."
+			return m, func() tea.Msg {
+				path, err := downloadState(cfg, e.Key)
+				if err != nil {
+					return s3ActionMsg{status: "Download failed: " + err.Error()}
+				}
+				return s3ActionMsg{status: "Saved " + e.Key + " to " + path}
+			}
+		case key.Matches(msg, keys.State.Delete):
+			e, ok := selectedStateEntry(m)
+			if !ok || !e.Orphaned {
+				m.stateStatus = "Only orphaned state can be downloaded or deleted here"
+				return m, nil
+			}
+			m.stateConfirmDelete = true
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.stateTable, cmd = m.stateTable.Update(msg)
+	return m, cmd
+}
+
+func viewStateBrowser(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(fmt.Sprintf("Terraform state in s3://%s — orphaned keys have no matching directory in %s", m.cfg.S3Bucket, m.cfg.AppsPath))
+	body += "\n" + m.stateTable.View() + "\n"
+	if m.stateConfirmDelete {
+		e, _ := selectedStateEntry(m)
+		tooltip = tooltipStyle.Render(fmt.Sprintf("Permanently delete s3://%s/%s? Download it first with [D]. [y/N]", m.cfg.S3Bucket, e.Key))
+	} else {
+		tooltip = tooltipStyle.Render(m.stateStatus)
+	}
+	return body, tooltip
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- SDN / VLAN validation ---
+
+type sdnZone struct {
+	Zone string `json:"zone"`
+	Type string `json:"type"`
+}
+
+type sdnVNet struct {
+	VNet  string `json:"vnet"`
+	Zone  string `json:"zone"`
+	Tag   int    `json:"tag"`
+	Alias string `json:"alias"`
+}
+
+// Fields checked against the cluster's SDN configuration
+var sdnFields = []string{"vm_sdn_zone", "vm_vnet", "vm_vlan_tag"}
+
+func sdnFieldsSet(m model) bool {
+	for _, k := range sdnFields {
+		if fieldApplies(m, k) && createValue(m, k) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func vnetNames(vnets []sdnVNet) string {
+	names := make([]string, len(vnets))
+	for i, v := range vnets {
+		names[i] = v.VNet
+	}
+	return strings.Join(names, ", ")
+}
+
+// Validates zone/vnet/vlan values against the SDN zones and VNets defined on the cluster
+func checkNetworking(cluster, zone, vnet, vlanTag string) error {
+	tag := 0
+	if vlanTag != "" {
+		n, err := strconv.Atoi(vlanTag)
+		if err != nil || n < 1 || n > 4094 {
+			return fmt.Errorf("VLAN tag %q must be a number between 1 and 4094", vlanTag)
+		}
+		tag = n
+	}
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get Proxmox creds: %w", err)
+	}
+	var zones []sdnZone
+	if err := proxmoxGet(apiURL, tokenID, tokenSecret, "cluster/sdn/zones", &zones); err != nil {
+		return fmt.Errorf("could not list SDN zones on %s: %w", cluster, err)
+	}
+	var vnets []sdnVNet
+	if err := proxmoxGet(apiURL, tokenID, tokenSecret, "cluster/sdn/vnets", &vnets); err != nil {
+		return fmt.Errorf("could not list SDN VNets on %s: %w", cluster, err)
+	}
+
+	var z *sdnZone
+	if zone != "" {
+		for i := range zones {
+			if zones[i].Zone == zone {
+				z = &zones[i]
+			}
+		}
+		if z == nil {
+			var names []string
+			for _, zz := range zones {
+				names = append(names, zz.Zone)
+			}
+			return fmt.Errorf("SDN zone '%s' does not exist on %s (available: %s)", zone, cluster, strings.Join(names, ", "))
+		}
+	}
+	var v *sdnVNet
+	if vnet != "" {
+		for i := range vnets {
+			if vnets[i].VNet == vnet {
+				v = &vnets[i]
+			}
+		}
+		if v == nil {
+			return fmt.Errorf("VNet '%s' does not exist on %s (available: %s)", vnet, cluster, vnetNames(vnets))
+		}
+		if z != nil && v.Zone != z.Zone {
+			return fmt.Errorf("VNet '%s' belongs to SDN zone '%s', not '%s'", vnet, v.Zone, zone)
+		}
+		if z == nil {
+			for i := range zones {
+				if zones[i].Zone == v.Zone {
+					z = &zones[i]
+				}
+			}
+		}
+	}
+	if tag != 0 {
+		if v != nil && v.Tag != 0 && v.Tag != tag {
+			return fmt.Errorf("VNet '%s' is already tagged with VLAN %d; leave VLAN Tag empty or use %d", vnet, v.Tag, v.Tag)
+		}
+		if z != nil && z.Type == "simple" {
+			return fmt.Errorf("SDN zone '%s' is a simple zone and does not support VLAN tags", z.Zone)
+		}
+	}
+	return nil
+}
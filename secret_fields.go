@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+
+	"launcher/internal/vaultclient"
+)
+
+// --- Secret fields (type: secret in fields.yaml) and sensitive ones (sensitive: true) ---
+
+const maskedValue = "••••"
+
+// "# db_password: vault:secret/data/...#db_password" or "# api_key: sops:secrets.sops.json#api_key" in terraform.tfvars
+var tfvarsRefRe = regexp.MustCompile(`^#\s*(\w+):\s*((?:vault|sops):\S+)`)
+
+func isSecretField(meta FieldMeta) bool {
+	return meta.Type == "secret"
+}
+
+// Values hidden on screen (until revealed) and left out of presets, exports and the logs
+func isMaskedField(meta FieldMeta) bool {
+	return isSecretField(meta) || meta.Sensitive
+}
+
+// Switches secret and sensitive fields to password echo, unless reveal is on
+func maskSecretInputs(inputs []textinput.Model, labels []string, fieldMeta map[string]FieldMeta, reveal bool) {
+	for i, key := range labels {
+		meta := fieldMeta[key]
+		if !isMaskedField(meta) {
+			continue
+		}
+		inputs[i].Placeholder = "(secret)"
+		switch {
+		case isSecretField(meta) && meta.Vault:
+			inputs[i].Placeholder = "(stored in Vault)"
+		case !isSecretField(meta):
+			inputs[i].Placeholder = "(sensitive)"
+		}
+	}
+	setMaskedEcho(inputs, labels, fieldMeta, reveal)
+}
+
+func setMaskedEcho(inputs []textinput.Model, labels []string, fieldMeta map[string]FieldMeta, reveal bool) {
+	for i, key := range labels {
+		if !isMaskedField(fieldMeta[key]) {
+			continue
+		}
+		inputs[i].EchoCharacter = '•'
+		inputs[i].EchoMode = textinput.EchoPassword
+		if reveal {
+			inputs[i].EchoMode = textinput.EchoNormal
+		}
+	}
+}
+
+// Shows or hides masked values in the forms, the tfvars panel and the rollback diff
+func toggleReveal(m model) model {
+	m.revealSensitive = !m.revealSensitive
+	setMaskedEcho(m.createInputs, m.createLabels, m.fieldMeta, m.revealSensitive)
+	setMaskedEcho(m.editFormInputs, m.editFormLabels, m.fieldMeta, m.revealSensitive)
+	if m.vaultForm != nil {
+		m.vaultForm.setEcho(m.revealSensitive)
+	}
+	if m.currentScene == sceneVault {
+		m.vaultTable.SetRows(vaultRows(m.vaultCreds, m.vaultTests, m.revealSensitive))
+	}
+	loadDeploymentDetail(&m, m.deployTable.Cursor())
+	if m.revealSensitive {
+		m.statusMessage = "Sensitive values shown — " + keys.Global.Reveal.Help().Key + " to hide them again"
+	} else {
+		m.statusMessage = "Sensitive values hidden"
+	}
+	return m
+}
+
+// Text the forms draw for an input; password inputs show the echo character or their placeholder
+func inputDisplay(ti textinput.Model) string {
+	if ti.EchoMode != textinput.EchoPassword {
+		return ti.Value()
+	}
+	if ti.Value() == "" {
+		return ti.Placeholder
+	}
+	return strings.Repeat(string(ti.EchoCharacter), len([]rune(ti.Value())))
+}
+
+// Value as shown outside the form (tables, diffs, messages)
+func maskFieldValue(meta FieldMeta, v string) string {
+	if isMaskedField(meta) && v != "" {
+		return maskedValue
+	}
+	return v
+}
+
+// Masks the value of a "key = value" tfvars line (optionally prefixed, e.g. "- " in diffs)
+func maskTfvarsLine(line string, fieldMeta map[string]FieldMeta) string {
+	prefix, rest := "", line
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "+ ") {
+		prefix, rest = line[:2], line[2:]
+	}
+	parts := strings.SplitN(rest, "=", 2)
+	if len(parts) != 2 || !isMaskedField(fieldMeta[strings.TrimSpace(parts[0])]) {
+		return line
+	}
+	return prefix + parts[0] + "= " + maskedValue
+}
+
+// Vault and sops references written in place of secret values, by variable name
+func loadTfvarsRefs(filename string) map[string]string {
+	refs := map[string]string{}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return refs
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if mm := tfvarsRefRe.FindStringSubmatch(strings.TrimSpace(line)); mm != nil {
+			refs[mm[1]] = mm[2]
+		}
+	}
+	return refs
+}
+
+// Replaces each variable's assignment (or earlier reference) with a reference comment
+// ("vault:<path>#<name>" or "sops:<file>#<name>"). Terraform gets the value through
+// TF_VAR_<name>, which a tfvars assignment would override.
+func writeTfvarsRefs(filename string, refs map[string]string) error {
+	input, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := strings.Split(strings.TrimRight(string(input), "\n"), "\n")
+	for _, name := range names {
+		ref := fmt.Sprintf("# %s: %s (passed as TF_VAR_%s)", name, refs[name], name)
+		found := false
+		for i, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			mm := tfvarsRefRe.FindStringSubmatch(trimmed)
+			if strings.HasPrefix(trimmed, name+" ") || strings.HasPrefix(trimmed, name+"=") || (mm != nil && mm[1] == name) {
+				lines[i] = ref
+				found = true
+			}
+		}
+		if !found {
+			lines = append(lines, ref)
+		}
+	}
+	return os.WriteFile(filename, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// Non-empty values of the secret fields kept in Vault rather than in tfvars
+func vaultFieldValues(labels []string, values func(i int) string, fieldMeta map[string]FieldMeta) map[string]string {
+	out := map[string]string{}
+	for i, key := range labels {
+		meta := fieldMeta[key]
+		if v := values(i); isSecretField(meta) && meta.Vault && v != "" {
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// Merges values into the deployment's Vault secrets (next to seeded ones), records the
+// names in launcher.state and swaps the tfvars assignments for references
+func storeSecretValues(cfg Config, dir string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	s, _ := getDeploymentState(dir)
+	path := s.SecretsPath
+	if path == "" {
+		mount := cfg.VaultSecretsPath
+		if mount == "" {
+			mount = defaultVaultSecretsPath
+		}
+		path = fmt.Sprintf("%s/%s", mount, filepath.Base(dir))
+	}
+	client, err := newVaultClient()
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{}
+	if len(s.Secrets) > 0 {
+		kv, err := client.Logical().Read(path)
+		logVault("read", path, err)
+		if err != nil {
+			return &VaultError{fmt.Errorf("vault read failed for %s: %w", path, err)}
+		}
+		if existing := vaultclient.Data(kv); existing != nil {
+			data = existing
+		}
+	}
+	names := append([]string{}, s.Secrets...)
+	refs := map[string]string{}
+	for name, v := range values {
+		data[name] = v
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		refs[name] = fmt.Sprintf("vault:%s#%s", path, name)
+	}
+	err = vaultclient.WriteKV(client.Logical(), path, data)
+	logVault("write", path, err)
+	if err != nil {
+		return &VaultError{err}
+	}
+	if err := setDeploymentSecrets(dir, path, names); err != nil {
+		return err
+	}
+	return writeTfvarsRefs(filepath.Join(dir, "terraform.tfvars"), refs)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"launcher/internal/vaultclient"
+)
+
+// --- Seeded deployment secrets (db passwords, ...) ---
+
+const defaultVaultSecretsPath = "secret/data/infra-catalog/secrets"
+
+const passwordAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+func generatePassword(n int) (string, error) {
+	var b strings.Builder
+	limit := big.NewInt(int64(len(passwordAlphabet)))
+	for i := 0; i < n; i++ {
+		idx, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(passwordAlphabet[idx.Int64()])
+	}
+	return b.String(), nil
+}
+
+// Generates one random password per terraform variable and writes them to Vault.
+// Returns the Vault path; the values never touch terraform.tfvars.
+func seedSecrets(cfg Config, deployment string, names []string) (string, error) {
+	client, err := newVaultClient()
+	if err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{}
+	for _, name := range names {
+		pw, err := generatePassword(32)
+		if err != nil {
+			return "", err
+		}
+		data[name] = pw
+	}
+	mount := cfg.VaultSecretsPath
+	if mount == "" {
+		mount = defaultVaultSecretsPath
+	}
+	path := fmt.Sprintf("%s/%s", mount, deployment)
+	err = vaultclient.WriteKV(client.Logical(), path, data)
+	logVault("write", path, err)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Reads a deployment's seeded secrets back from Vault, and its sops-encrypted sensitive
+// values, as TF_VAR_* environment entries
+func secretsEnv(dir string) ([]string, error) {
+	env, err := sopsSecretsEnv(dir)
+	if err != nil {
+		return nil, err
+	}
+	s, _ := getDeploymentState(dir)
+	if s.SecretsPath == "" || len(s.Secrets) == 0 {
+		return env, nil
+	}
+	client, err := newVaultClient()
+	if err != nil {
+		return nil, err
+	}
+	data, err := vaultclient.ReadKV(client.Logical(), s.SecretsPath)
+	logVault("read", s.SecretsPath, err)
+	if err != nil {
+		return nil, &VaultError{err}
+	}
+	for _, name := range s.Secrets {
+		v, ok := data[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("secret %s missing from %s", name, s.SecretsPath)
+		}
+		env = append(env, fmt.Sprintf("TF_VAR_%s=%s", name, v))
+	}
+	return env, nil
+}
+
+func setDeploymentSecrets(path, vaultPath string, names []string) error {
+	s, err := getDeploymentState(path)
+	if err != nil {
+		return err
+	}
+	s.SecretsPath = vaultPath
+	s.Secrets = names
+	return writeDeploymentState(path, s)
+}
+
+// Detail pane line: only the Vault location is ever shown
+func secretsLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || m.deployments[idx].SecretsPath == "" {
+		return nil
+	}
+	dep := m.deployments[idx]
+	return []string{truncate(fmt.Sprintf("Secrets: vault:%s (%s)", dep.SecretsPath, strings.Join(dep.Secrets, ", ")), width)}
+}
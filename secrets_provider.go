@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/vaultclient"
+)
+
+// --- Secrets providers ---
+
+// SecretsProvider fetches the Proxmox API credentials of a cluster
+type SecretsProvider interface {
+	Name() string
+	// Ready reports whether the provider has what it needs (env vars, token, file) to be tried
+	Ready() bool
+	ProxmoxCreds(cluster string) (apiUrl, tokenId, tokenSecret string, err error)
+}
+
+var secretsProvider SecretsProvider = vaultAppRoleProvider{}
+
+func newSecretsProvider(cfg Config) (SecretsProvider, error) {
+	sp := cfg.SecretsProvider
+	switch sp.Type {
+	case "", "vault-approle":
+		return vaultAppRoleProvider{}, nil
+	case "vault-token":
+		return vaultTokenProvider{}, nil
+	case "env":
+		return envProvider{}, nil
+	case "sops", "file":
+		if sp.File == "" {
+			return nil, fmt.Errorf("secrets_provider.file is required for type %s", sp.Type)
+		}
+		path := sp.File
+		// sops files live in the terraform repo; a relative `type: file` path keeps meaning
+		// the launcher's working directory, as before sops existed
+		if sp.Type == "sops" && !filepath.IsAbs(path) {
+			path = filepath.Join(cfg.TerraformPath, path)
+		}
+		// "sops" always decrypts with sops; "file" picks age for *.age files
+		return fileProvider{name: sp.Type, path: path, ageIdentity: sp.AgeIdentity}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q (want vault-approle, vault-token, env, sops or file)", sp.Type)
+}
+
+func getProxmoxCreds(cluster string) (apiUrl, tokenId, tokenSecret string, err error) {
+	if safeMode {
+		return "", "", "", fmt.Errorf("proxmox credentials: %w", errSafeMode)
+	}
+	return secretsProvider.ProxmoxCreds(cluster)
+}
+
+// Returns a Vault client authenticated with the provider's method (AppRole unless vault-token is configured)
+func newVaultClient() (*vault.Client, error) {
+	if safeMode {
+		return nil, &VaultError{fmt.Errorf("vault: %w", errSafeMode)}
+	}
+	var client *vault.Client
+	var err error
+	if _, ok := secretsProvider.(vaultTokenProvider); ok {
+		client, err = vaultTokenClient()
+	} else {
+		client, err = vaultAppRoleClient()
+	}
+	if err != nil {
+		return nil, &VaultError{err}
+	}
+	return client, nil
+}
+
+// Vault client logged in with the AppRole credentials from the environment
+func vaultAppRoleClient() (*vault.Client, error) {
+	roleID := os.Getenv("TF_VAR_role_id")
+	secretID := os.Getenv("TF_VAR_secret_id")
+	if roleID == "" || secretID == "" {
+		return nil, fmt.Errorf("vault approle credentials not set")
+	}
+	client, err := vaultclient.New()
+	if err != nil {
+		return nil, err
+	}
+	token, err := vaultclient.LoginAppRole(client.Logical(), roleID, secretID)
+	logVault("login", "auth/approle/login", err)
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(token)
+	return client, nil
+}
+
+func vaultTokenClient() (*vault.Client, error) {
+	token := vaultclient.Token()
+	if token == "" {
+		return nil, fmt.Errorf("no Vault token: set VAULT_TOKEN or run `vault login`")
+	}
+	client, err := vaultclient.New()
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(token)
+	return client, nil
+}
+
+func readProxmoxCredsFromVault(l vaultclient.Logical, cluster string) (apiUrl, tokenId, tokenSecret string, err error) {
+	secretPath := vaultclient.CredsPath(cluster)
+	data, err := vaultclient.ReadKV(l, secretPath)
+	logVault("read", secretPath, err)
+	if err != nil {
+		return "", "", "", err
+	}
+	apiUrl, tokenId, tokenSecret, err = vaultclient.ProxmoxCreds(data)
+	if err != nil {
+		return "", "", "", fmt.Errorf("%v in Vault secret %s", err, secretPath)
+	}
+	return apiUrl, tokenId, tokenSecret, nil
+}
+
+// --- Vault AppRole ---
+
+type vaultAppRoleProvider struct{}
+
+func (vaultAppRoleProvider) Name() string { return "vault-approle" }
+
+func (vaultAppRoleProvider) Ready() bool {
+	return os.Getenv("TF_VAR_role_id") != "" && os.Getenv("TF_VAR_secret_id") != ""
+}
+
+func (vaultAppRoleProvider) ProxmoxCreds(cluster string) (string, string, string, error) {
+	client, err := vaultAppRoleClient()
+	if err != nil {
+		return "", "", "", err
+	}
+	return readProxmoxCredsFromVault(client.Logical(), cluster)
+}
+
+// --- Vault token ---
+
+type vaultTokenProvider struct{}
+
+func (vaultTokenProvider) Name() string { return "vault-token" }
+
+func (vaultTokenProvider) Ready() bool { return vaultclient.Token() != "" }
+
+func (vaultTokenProvider) ProxmoxCreds(cluster string) (string, string, string, error) {
+	client, err := vaultTokenClient()
+	if err != nil {
+		return "", "", "", err
+	}
+	return readProxmoxCredsFromVault(client.Logical(), cluster)
+}
+
+// --- Environment variables ---
+
+// envProvider reads PROXMOX_API_URL, PROXMOX_API_TOKEN_ID and PROXMOX_API_TOKEN_SECRET,
+// preferring the cluster-suffixed form (e.g. PROXMOX_API_URL_CL10400) when set
+type envProvider struct{}
+
+func (envProvider) Name() string { return "env" }
+
+func (envProvider) Ready() bool {
+	return os.Getenv("PROXMOX_API_TOKEN_SECRET") != "" || envHasPrefix("PROXMOX_API_TOKEN_SECRET_")
+}
+
+func envHasPrefix(prefix string) bool {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func clusterEnv(name, cluster string) string {
+	suffix := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(cluster))
+	if v := os.Getenv(name + "_" + suffix); v != "" {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+func (envProvider) ProxmoxCreds(cluster string) (string, string, string, error) {
+	apiUrl := clusterEnv("PROXMOX_API_URL", cluster)
+	tokenId := clusterEnv("PROXMOX_API_TOKEN_ID", cluster)
+	tokenSecret := clusterEnv("PROXMOX_API_TOKEN_SECRET", cluster)
+	if apiUrl == "" || tokenId == "" || tokenSecret == "" {
+		return "", "", "", fmt.Errorf("PROXMOX_API_URL/PROXMOX_API_TOKEN_ID/PROXMOX_API_TOKEN_SECRET not set for %s", cluster)
+	}
+	return apiUrl, tokenId, tokenSecret, nil
+}
+
+// --- Encrypted local file ---
+
+// fileProvider decrypts a YAML file with `sops -d`, or `age -d` for *.age files:
+//
+//	clusters:
+//	  cl10400:
+//	    proxmox_api_url: pve.example.com
+//	    proxmox_api_token_id: terraform@pve!launcher
+//	    proxmox_api_token_secret: ...
+type fileProvider struct {
+	name        string // "sops" or "file"
+	path        string
+	ageIdentity string
+}
+
+type credsFile struct {
+	Clusters map[string]struct {
+		APIURL      string `yaml:"proxmox_api_url"`
+		TokenID     string `yaml:"proxmox_api_token_id"`
+		TokenSecret string `yaml:"proxmox_api_token_secret"`
+	} `yaml:"clusters"`
+}
+
+func (p fileProvider) Name() string { return p.name }
+
+func (p fileProvider) Ready() bool { return pathExists(p.path) }
+
+func (p fileProvider) identity() string {
+	if p.ageIdentity != "" {
+		return p.ageIdentity
+	}
+	if f := os.Getenv("SOPS_AGE_KEY_FILE"); f != "" {
+		return f
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "sops", "age", "keys.txt")
+}
+
+func (p fileProvider) decrypt() ([]byte, error) {
+	var cmd *exec.Cmd
+	if p.name == "file" && strings.HasSuffix(p.path, ".age") {
+		cmd = commands.Command("", "age", "-d", "-i", p.identity(), p.path)
+	} else {
+		cmd = commands.Command("", "sops", "-d", p.path)
+		if p.ageIdentity != "" {
+			cmd.Env = append(os.Environ(), "SOPS_AGE_KEY_FILE="+p.ageIdentity)
+		}
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("%s failed: %s", cmd.Args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s failed: %w", cmd.Args[0], err)
+	}
+	return out, nil
+}
+
+func (p fileProvider) ProxmoxCreds(cluster string) (string, string, string, error) {
+	data, err := p.decrypt()
+	if err != nil {
+		logger.Error("secrets file decrypt failed", "component", "secrets", "file", p.path, "error", err.Error())
+		return "", "", "", err
+	}
+	var f credsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return "", "", "", fmt.Errorf("could not parse %s: %w", p.path, err)
+	}
+	c, ok := f.Clusters[cluster]
+	if !ok || c.APIURL == "" || c.TokenID == "" || c.TokenSecret == "" {
+		return "", "", "", fmt.Errorf("no complete credentials for %s in %s", cluster, p.path)
+	}
+	return c.APIURL, c.TokenID, c.TokenSecret, nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNewSecretsProviderRelativeFile(t *testing.T) {
+	for _, tc := range []struct{ typ, want string }{
+		{"sops", filepath.Join("/srv/terraform", "creds.sops.yaml")},
+		{"file", "creds.sops.yaml"},
+	} {
+		cfg := Config{TerraformPath: "/srv/terraform", SecretsProvider: SecretsProviderConfig{Type: tc.typ, File: "creds.sops.yaml"}}
+		p, err := newSecretsProvider(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.(fileProvider).path; got != tc.want {
+			t.Errorf("type %s: path %q, want %q", tc.typ, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Launcher selection and header summary ---
+
+const selectedMarker = "● "
+
+// Toggles the deployment under the cursor in or out of the selection
+func toggleSelected(m model) model {
+	i := m.deployTable.Cursor()
+	if i < 0 || i >= len(m.deployments) {
+		return m
+	}
+	path := m.deployments[i].Path
+	if m.selected[path] {
+		delete(m.selected, path)
+	} else {
+		m.selected[path] = true
+	}
+	m.deployTable.SetRows(deploymentRows(m.deployments, m.drift, m.selected, m.favorites))
+	m.tablesVersion++
+	return m
+}
+
+// Forgets selected deployments that are gone from disk
+func pruneSelection(m *model) {
+	for path := range m.selected {
+		if _, ok := deploymentByPath(m.allDeployments, path); !ok {
+			delete(m.selected, path)
+		}
+	}
+}
+
+// "42 deployments • 3 filtered • 2 selected • 1 running job"
+func headerSummary(m model) string {
+	parts := []string{plural(len(m.allDeployments), "deployment")}
+	if m.stateFilter != filterAllStates || m.zoneFilter != "" {
+		parts = append(parts, fmt.Sprintf("%d filtered", len(m.deployments)))
+	}
+	if n := len(m.selected); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d selected", n))
+	}
+	if n := len(m.runningJobs()); n > 0 {
+		parts = append(parts, plural(n, "running job"))
+	}
+	if n := m.queuedJobCount(); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d queued", n))
+	}
+	summary := headerSummaryStyle.Render(strings.Join(parts, " • "))
+	if safeMode {
+		summary = safeModeStyle.Render("SAFE MODE") + headerSummaryStyle.Render(" • ") + summary
+	}
+	return summary
+}
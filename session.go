@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/filelock"
+)
+
+// --- Session tracking and safe mode ---
+//
+// Each start is recorded in session.yaml and marked clean on a normal exit. After
+// safe_mode_after unclean exits in a row the launcher starts in safe mode: no Vault,
+// Proxmox or git calls and no background fetches, so the catalog can still be browsed.
+
+const (
+	defaultSafeModeAfter = 3
+	sessionKeep          = 10
+	// Another launcher starting or exiting holds the session lock for a moment only
+	sessionLockTimeout = 5 * time.Second
+)
+
+var errSafeMode = errors.New("disabled in safe mode")
+
+// safeMode is decided once at startup
+var safeMode bool
+
+type sessionStart struct {
+	Started string `yaml:"started"`
+	PID     int    `yaml:"pid"`
+	Clean   bool   `yaml:"clean"`
+}
+
+type sessionFile struct {
+	Starts []sessionStart `yaml:"starts"`
+	// Deployment directory names pinned to the top of the launcher
+	Favorites []string `yaml:"favorites,omitempty"`
+}
+
+func sessionPath() string {
+	return filepath.Join(filepath.Dir(logPath()), "session.yaml")
+}
+
+func readSession() sessionFile {
+	var s sessionFile
+	if data, err := os.ReadFile(sessionPath()); err == nil {
+		yaml.Unmarshal(data, &s)
+	}
+	return s
+}
+
+func writeSession(s sessionFile) error {
+	if len(s.Starts) > sessionKeep {
+		s.Starts = s.Starts[len(s.Starts)-sessionKeep:]
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(sessionPath()), 0700); err != nil {
+		return err
+	}
+	// Renamed into place so a launcher reading favorites never sees half a file
+	tmp := sessionPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, sessionPath())
+}
+
+// Read-modify-write of session.yaml under session.yaml.lock, so launchers starting, exiting
+// or pinning favorites at the same time don't drop each other's changes
+func updateSession(update func(*sessionFile)) error {
+	lock := sessionPath() + ".lock"
+	unlock, err := filelock.LockTimeout(lock, sessionLockTimeout)
+	if errors.Is(err, filelock.ErrLocked) {
+		return fmt.Errorf("session file is locked (%s)", lock)
+	}
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s := readSession()
+	update(&s)
+	return writeSession(s)
+}
+
+// Number of most recent starts that never exited cleanly. Starts of launchers still
+// running are skipped: they haven't exited at all.
+func uncleanStarts(s sessionFile) int {
+	n := 0
+	for i := len(s.Starts) - 1; i >= 0 && !s.Starts[i].Clean; i-- {
+		if !processAlive(s.Starts[i].PID) {
+			n++
+		}
+	}
+	return n
+}
+
+// beginSession records this start and reports why safe mode is on ("" when it isn't)
+func beginSession(cfg Config, forced bool) string {
+	var crashes int
+	err := updateSession(func(s *sessionFile) {
+		crashes = uncleanStarts(*s)
+		s.Starts = append(s.Starts, sessionStart{Started: time.Now().UTC().Format(time.RFC3339), PID: os.Getpid()})
+	})
+	if err != nil {
+		logger.Warn("could not write session file", "component", "session", "error", err.Error())
+	}
+	limit := cfg.SafeModeAfter
+	if limit == 0 {
+		limit = defaultSafeModeAfter
+	}
+	switch {
+	case forced:
+		return "started with --safe-mode"
+	case limit > 0 && crashes >= limit:
+		return fmt.Sprintf("the last %d starts did not exit cleanly", crashes)
+	}
+	return ""
+}
+
+// endSession marks this start as clean
+func endSession() {
+	updateSession(func(s *sessionFile) {
+		for i := len(s.Starts) - 1; i >= 0; i-- {
+			if s.Starts[i].PID == os.Getpid() {
+				s.Starts[i].Clean = true
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// PID of a process that has already exited and been reaped
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip("no true command:", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestUncleanStarts(t *testing.T) {
+	dead, alive := exitedPID(t), os.Getpid()
+	tests := []struct {
+		name   string
+		starts []sessionStart
+		want   int
+	}{
+		{"none", nil, 0},
+		{"clean last", []sessionStart{{PID: dead}, {PID: dead, Clean: true}}, 0},
+		{"crashes since the last clean exit", []sessionStart{{PID: dead}, {PID: dead, Clean: true}, {PID: dead}, {PID: dead}}, 2},
+		{"running launcher skipped", []sessionStart{{PID: dead}, {PID: alive}, {PID: dead}}, 2},
+		{"only running launchers", []sessionStart{{PID: dead, Clean: true}, {PID: alive}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uncleanStarts(sessionFile{Starts: tt.starts}); got != tt.want {
+				t.Errorf("uncleanStarts = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionKeepsFavorites(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	beginSession(Config{}, false)
+	if err := saveFavorites(map[string]bool{"web_a": true}); err != nil {
+		t.Fatal(err)
+	}
+	endSession()
+
+	s := readSession()
+	if len(s.Starts) != 1 || !s.Starts[0].Clean {
+		t.Errorf("starts = %+v, want one clean start", s.Starts)
+	}
+	if len(s.Favorites) != 1 || s.Favorites[0] != "web_a" {
+		t.Errorf("favorites = %q, want web_a", s.Favorites)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// --- Sensitive fields (sensitive: true in fields.yaml), kept sops-encrypted next to terraform.tfvars ---
+
+// Not a *.auto.tfvars file: terraform would load the encrypted values as they are.
+// The values are decrypted at apply time and passed as TF_VAR_<name> instead.
+const sopsSecretsFile = "secrets.sops.json"
+
+func isSensitiveField(meta FieldMeta) bool {
+	return meta.Sensitive && !(isSecretField(meta) && meta.Vault)
+}
+
+// Non-empty values of the sensitive fields kept out of terraform.tfvars
+func sensitiveFieldValues(labels []string, values func(i int) string, fieldMeta map[string]FieldMeta) map[string]string {
+	out := map[string]string{}
+	for i, key := range labels {
+		if v := values(i); isSensitiveField(fieldMeta[key]) && v != "" {
+			out[key] = v
+		}
+	}
+	return out
+}
+
+func runSops(stdin []byte, args ...string) ([]byte, error) {
+	cmd := commands.Command("", "sops", args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("sops failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("sops failed: %w", err)
+	}
+	return out, nil
+}
+
+// Decrypted sensitive values of a deployment; none when it has no secrets file
+func readSopsSecrets(dir string) (map[string]string, error) {
+	path := filepath.Join(dir, sopsSecretsFile)
+	if !pathExists(path) {
+		return nil, nil
+	}
+	out, err := runSops(nil, "--decrypt", "--input-type", "json", "--output-type", "json", path)
+	if err != nil {
+		logger.Error("sops decrypt failed", "component", "secrets", "file", path, "error", err.Error())
+		return nil, err
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(out, &values); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Merges values into the deployment's secrets file, re-encrypts it and swaps the tfvars
+// assignments for references. Encryption follows the repo's .sops.yaml creation rules,
+// or cfg.SopsAgeRecipients when set.
+func storeSensitiveValues(cfg Config, dir string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	data, err := readSopsSecrets(dir)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	var changed []string
+	for name, v := range values {
+		data[name] = v
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	plain, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, sopsSecretsFile)
+	args := []string{"--encrypt", "--input-type", "json", "--output-type", "json", "--filename-override", path}
+	if cfg.SopsAgeRecipients != "" {
+		args = append(args, "--age", cfg.SopsAgeRecipients)
+	}
+	enc, err := runSops(plain, append(args, "/dev/stdin")...)
+	if err != nil {
+		logger.Error("sops encrypt failed", "component", "secrets", "file", path, "error", err.Error())
+		return err
+	}
+	if err := os.WriteFile(path, enc, 0644); err != nil {
+		return err
+	}
+	refs := map[string]string{}
+	for _, name := range changed {
+		refs[name] = fmt.Sprintf("sops:%s#%s", sopsSecretsFile, name)
+	}
+	return writeTfvarsRefs(filepath.Join(dir, "terraform.tfvars"), refs)
+}
+
+// Sensitive values as TF_VAR_* environment entries for terraform runs
+func sopsSecretsEnv(dir string) ([]string, error) {
+	values, err := readSopsSecrets(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	env := make([]string, len(names))
+	for i, name := range names {
+		env[i] = fmt.Sprintf("TF_VAR_%s=%s", name, values[name])
+	}
+	return env, nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- SSH into deployed VMs ---
+
+type sshTarget struct {
+	Name string
+	IP   string
+}
+
+type sshTargetsMsg struct {
+	dir     string
+	targets []sshTarget
+	err     error
+}
+
+type sshDoneMsg struct {
+	target sshTarget
+	err    error
+}
+
+func sshIPOutput(cfg SSHConfig) string {
+	if cfg.IPOutput != "" {
+		return cfg.IPOutput
+	}
+	return "vm_ips"
+}
+
+// Accepts a string, a list (possibly nested, as the Proxmox provider reports
+// per-interface addresses) or a map of VM name to IP(s)
+func parseSSHTargets(raw json.RawMessage) ([]sshTarget, error) {
+	var byName map[string]interface{}
+	if err := json.Unmarshal(raw, &byName); err == nil {
+		var targets []sshTarget
+		for name, v := range byName {
+			for _, ip := range flattenIPs(v) {
+				targets = append(targets, sshTarget{Name: name, IP: ip})
+			}
+		}
+		sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+		return targets, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	var targets []sshTarget
+	for i, ip := range flattenIPs(v) {
+		targets = append(targets, sshTarget{Name: fmt.Sprintf("vm %d", i+1), IP: ip})
+	}
+	return targets, nil
+}
+
+func flattenIPs(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if v == "" || strings.HasPrefix(v, "127.") || v == "::1" {
+			return nil
+		}
+		return []string{v}
+	case []interface{}:
+		var ips []string
+		for _, e := range v {
+			ips = append(ips, flattenIPs(e)...)
+		}
+		return ips
+	}
+	return nil
+}
+
+func loadSSHTargetsCmd(cfg SSHConfig, dir string) tea.Cmd {
+	return func() tea.Msg {
+		outputs, err := readTerraformOutputs(dir)
+		if err != nil {
+			return sshTargetsMsg{dir: dir, err: err}
+		}
+		o, ok := outputs[sshIPOutput(cfg)]
+		if !ok {
+			return sshTargetsMsg{dir: dir, err: fmt.Errorf("terraform output %q not found", sshIPOutput(cfg))}
+		}
+		targets, err := parseSSHTargets(o.Value)
+		if err == nil && len(targets) == 0 {
+			err = fmt.Errorf("terraform output %q has no IP addresses", sshIPOutput(cfg))
+		}
+		return sshTargetsMsg{dir: dir, targets: targets, err: err}
+	}
+}
+
+// Suspends the TUI for an interactive ssh session
+func sshCmd(cfg SSHConfig, t sshTarget) tea.Cmd {
+	user := cfg.User
+	if user == "" {
+		user = "ubuntu"
+	}
+	args := append([]string{}, cfg.Options...)
+	if cfg.Key != "" {
+		args = append(args, "-i", cfg.Key)
+	}
+	args = append(args, user+"@"+t.IP)
+	logger.Info("ssh session", "component", "ssh", "target", t.Name, "ip", t.IP, "user", user)
+	return tea.ExecProcess(exec.Command("ssh", args...), func(err error) tea.Msg {
+		return sshDoneMsg{target: t, err: err}
+	})
+}
+
+func startSSH(m model) (model, tea.Cmd) {
+	i := m.deployTable.Cursor()
+	if i < 0 || i >= len(m.deployments) {
+		return m, nil
+	}
+	d := m.deployments[i]
+	if d.State != "DEPLOYED" {
+		m.statusMessage = fmt.Sprintf("%s is %s — SSH needs a deployed deployment", d.Name, d.State)
+		return m, nil
+	}
+	m.statusMessage = "Reading VM addresses from terraform output..."
+	return m, loadSSHTargetsCmd(m.cfg.SSH, d.Path)
+}
+
+// handleSSHMsg processes ssh results on the launcher and in the picker
+func handleSSHMsg(m model, msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case sshTargetsMsg:
+		if msg.err != nil {
+			m.statusMessage = "SSH: " + msg.err.Error()
+			return m, nil, true
+		}
+		if len(msg.targets) == 1 {
+			m.statusMessage = "SSH to " + msg.targets[0].IP
+			return m, sshCmd(m.cfg.SSH, msg.targets[0]), true
+		}
+		m.sshTargets, m.sshIdx, m.sshDir = msg.targets, 0, msg.dir
+		return m.pushScene(sceneSSH), nil, true
+	case sshDoneMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("SSH to %s ended: %v", msg.target.IP, msg.err)
+		} else {
+			m.statusMessage = "SSH session to " + msg.target.IP + " closed"
+		}
+		return m.homeScene(), nil, true
+	}
+	return m, nil, false
+}
+
+func updateSSHPicker(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		n := len(m.sshTargets)
+		switch {
+		case key.Matches(keyMsg, keys.SSH.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.SSH.Up):
+			m.sshIdx = (m.sshIdx - 1 + n) % n
+		case key.Matches(keyMsg, keys.SSH.Down):
+			m.sshIdx = (m.sshIdx + 1) % n
+		case key.Matches(keyMsg, keys.SSH.Select):
+			return m, sshCmd(m.cfg.SSH, m.sshTargets[m.sshIdx])
+		}
+	}
+	return m, nil
+}
+
+func viewSSHPicker(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(fmt.Sprintf("SSH into %s — %d VM address(es) from output %q", filepath.Base(m.sshDir), len(m.sshTargets), sshIPOutput(m.cfg.SSH)))
+	body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+	for i, t := range m.sshTargets {
+		line := fmt.Sprintf("  %-30s %s", t.Name, t.IP)
+		if i == m.sshIdx {
+			body += focusedStyle.Render(line) + "\n"
+		} else {
+			body += normalStyle.Render(line) + "\n"
+		}
+	}
+	user := m.cfg.SSH.User
+	if user == "" {
+		user = "ubuntu"
+	}
+	tooltip = tooltipStyle.Render(fmt.Sprintf("%s runs ssh %s@%s; the launcher comes back when the session ends", keys.SSH.Select.Help().Key, user, m.sshTargets[m.sshIdx].IP))
+	return body, tooltip
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Stale state lock detection and force-unlock ---
+
+// Lock holder details from terraform's "Error acquiring the state lock" output
+type stateLock struct {
+	ID        string
+	Path      string
+	Operation string
+	Who       string
+	Version   string
+	Created   string
+	Dir       string // deployment the failed job ran in
+}
+
+var lockInfoRe = regexp.MustCompile(`^\s*(ID|Path|Operation|Who|Version|Created):\s*(.*)$`)
+
+// Finds the lock info block in a failed job's output
+func parseStateLock(lines []string) (stateLock, bool) {
+	var l stateLock
+	inLock := false
+	for _, line := range lines {
+		switch {
+		case strings.Contains(line, "Error acquiring the state lock"):
+			inLock = true
+		case inLock:
+			g := lockInfoRe.FindStringSubmatch(line)
+			if g == nil {
+				continue
+			}
+			v := strings.TrimSpace(g[2])
+			switch g[1] {
+			case "ID":
+				l.ID = v
+			case "Path":
+				l.Path = v
+			case "Operation":
+				l.Operation = v
+			case "Who":
+				l.Who = v
+			case "Version":
+				l.Version = v
+			case "Created":
+				l.Created = v
+			}
+		}
+	}
+	return l, l.ID != ""
+}
+
+// "2h13m ago" from the Created field, or "" when it doesn't parse
+func (l stateLock) age() string {
+	created, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", l.Created)
+	if err != nil {
+		return ""
+	}
+	return relativeTime(created)
+}
+
+// One-line status text used instead of the raw terraform error
+func (l stateLock) summary() string {
+	s := fmt.Sprintf("state of %s is locked by %s", filepath.Base(l.Dir), l.Who)
+	if age := l.age(); age != "" {
+		s += ", taken " + age
+	}
+	return s
+}
+
+// Operation running `terraform force-unlock -force <id>`; the deployment keeps its state
+func forceUnlockOperation(l stateLock) *tfOperation {
+	name := filepath.Base(l.Dir)
+	return &tfOperation{
+		Label:          "Unlocking state of " + name,
+		Dir:            l.Dir,
+		Steps:          []tfStep{{Name: "force-unlock", Args: []string{"force-unlock", "-force", l.ID}}},
+		SuccessMessage: fmt.Sprintf("State lock %s released for %s — run the job again", l.ID, name),
+	}
+}
+
+// Keys while the lock dialog is open: confirm runs force-unlock, anything else dismisses it
+func updateLockDialog(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := *m.lockDialog
+	m.lockDialog = nil
+	if !key.Matches(msg, keys.Confirm.Yes) {
+		m.statusMessage = "Left the lock in place: " + l.summary()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m, cmd = enqueueJob(m, forceUnlockOperation(l))
+	m.statusMessage = fmt.Sprintf("Queued job #%d: terraform force-unlock %s", m.nextJobID, l.ID)
+	return m, cmd
+}
+
+func viewLockDialog(m model) string {
+	l := m.lockDialog
+	rows := [][2]string{
+		{"Deployment", filepath.Base(l.Dir)},
+		{"Lock ID", l.ID},
+		{"Held by", l.Who},
+		{"Operation", l.Operation},
+		{"Created", strings.TrimSpace(l.Created + "  " + l.age())},
+		{"Terraform", l.Version},
+		{"State", l.Path},
+	}
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Terraform state is locked") + "\n\n")
+	for _, r := range rows {
+		if r[1] != "" {
+			fmt.Fprintf(&b, "%-11s %s\n", r[0], r[1])
+		}
+	}
+	b.WriteString("\n" + warnStyle.Render("Only unlock when no other apply is running for this deployment;") + "\n")
+	b.WriteString(warnStyle.Render("releasing a live lock lets two runs write the state at once.") + "\n\n")
+	fmt.Fprintf(&b, "Run terraform force-unlock? %s yes, any other key leaves the lock", keys.Confirm.Yes.Help().Key)
+	return lipgloss.NewStyle().
+		Border(boxBorder()).
+		BorderForeground(popupBorder()).
+		Padding(0, 1).
+		Width(uiWidth - 24).
+		Render(b.String())
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Numeric steppers (fields.yaml `step:`, `min:`, `max:`) ---
+
+func isStepper(meta FieldMeta) bool {
+	return meta.Step > 0
+}
+
+// Next value on the step grid in direction dir, within the bounds. An empty or invalid
+// value starts from min (or 0).
+func stepValue(meta FieldMeta, v string, dir int) string {
+	n, err := strconv.Atoi(v)
+	switch {
+	case err != nil && meta.Min != nil:
+		n = *meta.Min
+	case err != nil:
+		n = 0
+	case dir > 0:
+		n = (n/meta.Step + 1) * meta.Step
+	default:
+		n = ((n+meta.Step-1)/meta.Step - 1) * meta.Step
+	}
+	if meta.Min != nil {
+		n = max(n, *meta.Min)
+	}
+	if meta.Max != nil {
+		n = min(n, *meta.Max)
+	}
+	return strconv.Itoa(n)
+}
+
+// "between 1024 and 262144" for the bounds that are set
+func stepperRange(meta FieldMeta) string {
+	switch {
+	case meta.Min != nil && meta.Max != nil:
+		return fmt.Sprintf("between %d and %d", *meta.Min, *meta.Max)
+	case meta.Min != nil:
+		return fmt.Sprintf("of at least %d", *meta.Min)
+	case meta.Max != nil:
+		return fmt.Sprintf("of at most %d", *meta.Max)
+	}
+	return ""
+}
+
+func checkStepperValue(meta FieldMeta, label, v string) error {
+	if !isStepper(meta) || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || meta.Min != nil && n < *meta.Min || meta.Max != nil && n > *meta.Max {
+		return fmt.Errorf("%s must be a whole number %s", label, stepperRange(meta))
+	}
+	return nil
+}
+
+// Tooltip of a field; steppers add their range and step
+func fieldHelp(meta FieldMeta) string {
+	if !isStepper(meta) {
+		return meta.Help
+	}
+	return fmt.Sprintf("%s Whole number %s; %s/%s steps by %d.", meta.Help, stepperRange(meta), keys.Create.Decrease.Help().Key, keys.Create.Increase.Help().Key, meta.Step)
+}
+
+// Steps the input, or swallows typed characters other than digits that aren't bound in the
+// form's keymap; ok is false when msg is for the usual handling
+func stepInput(ti *textinput.Model, meta FieldMeta, msg tea.KeyMsg, formKeys any, dec, inc []key.Binding) (status string, ok bool) {
+	switch {
+	case key.Matches(msg, dec...):
+		ti.SetValue(stepValue(meta, ti.Value(), -1))
+		return "", true
+	case key.Matches(msg, inc...):
+		ti.SetValue(stepValue(meta, ti.Value(), +1))
+		return "", true
+	case msg.Type == tea.KeyRunes:
+		_, bindings := namedBindings(formKeys)
+		for _, b := range bindings {
+			if key.Matches(msg, *b) {
+				return "", false
+			}
+		}
+		for _, r := range msg.Runes {
+			if !unicode.IsDigit(r) {
+				return fmt.Sprintf("Digits only — %s/%s steps by %d", dec[0].Help().Key, inc[0].Help().Key, meta.Step), true
+			}
+		}
+	}
+	return "", false
+}
+
+// Stepper keys for the create form; ok is false when msg isn't one of them
+func stepCreateField(m model, msg tea.KeyMsg) (model, bool) {
+	meta := m.fieldMeta[m.createLabels[m.createFocus]]
+	if !isStepper(meta) {
+		return m, false
+	}
+	status, ok := stepInput(&m.createInputs[m.createFocus], meta, msg, &keys.Create,
+		[]key.Binding{keys.Create.Decrease, keys.Create.OptionPrev}, []key.Binding{keys.Create.Increase, keys.Create.OptionNext})
+	if ok {
+		m.createStatus = status
+	}
+	return m, ok
+}
+
+// Stepper keys for the edit form; ok is false when msg isn't one of them
+func stepEditField(m model, msg tea.KeyMsg) (model, bool) {
+	meta := m.fieldMeta[m.editFormLabels[m.editFocusIndex]]
+	if !isStepper(meta) {
+		return m, false
+	}
+	status, ok := stepInput(&m.editFormInputs[m.editFocusIndex], meta, msg, &keys.Edit,
+		[]key.Binding{keys.Edit.Decrease, keys.Edit.OptionPrev}, []key.Binding{keys.Edit.Increase, keys.Edit.OptionNext})
+	if ok {
+		m.editStatus = status
+	}
+	return m, ok
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/git"
+)
+
+// --- Template changes: the file diff a deployment would pick up by moving to the current template ---
+
+const templateDiffContext = 3
+
+// A template file in the deployment's commit (Before) and the current one (After); nil when
+// the file doesn't exist in that commit
+type templateFileDiff struct {
+	Path          string
+	Before, After []string
+}
+
+// Files of the template directory that differ between from and to, paths relative to it
+func templateFileDiffs(templatePath, from, to string) ([]templateFileDiff, error) {
+	names, err := git.ChangedFiles(commands, templatePath, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var files []templateFileDiff
+	for _, name := range names {
+		files = append(files, templateFileDiff{
+			Path:   name,
+			Before: gitShowLines(templatePath, from, name),
+			After:  gitShowLines(templatePath, to, name),
+		})
+	}
+	return files, nil
+}
+
+// Lines of path (relative to dir) at commit; nil when it isn't there
+func gitShowLines(dir, commit, path string) []string {
+	return git.ShowLines(commands, dir, commit, path)
+}
+
+// Template file diffs from templateDiffCmd; title is the screen title they were asked for
+type templateDiffMsg struct {
+	title string
+	files []templateFileDiff
+	err   error
+}
+
+// Opens the screen right away; git diff and the git show per file run in templateDiffCmd
+func openTemplateDiff(m model) (model, tea.Cmd) {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return m, nil
+	}
+	dep := m.deployments[idx]
+	switch {
+	case dep.TemplateCommit == "" || m.templateCommit == "":
+		m.statusMessage = "The template version of " + dep.Name + " isn't tracked, nothing to compare"
+		return m, nil
+	case dep.TemplateCommit == m.templateCommit:
+		m.statusMessage = dep.Name + " is on the current template version"
+		return m, nil
+	}
+	t := templateByName(m.templates, dep.Template)
+	m.templateDiffTitle = fmt.Sprintf("Template %s of %s: %s → %s", t.Name, dep.Name,
+		shortCommit(dep.TemplateCommit), shortCommit(m.templateCommit))
+	m.templateDiffFiles, m.templateDiffLoading = nil, true
+	m.templateDiffViewport = viewport.New(uiWidth-4, uiHeight-14)
+	m.templateDiffViewport.SetContent("Comparing template versions ...")
+	return m.pushScene(sceneTemplateDiff), templateDiffCmd(m.templateDiffTitle, t.Path, dep.TemplateCommit, m.templateCommit)
+}
+
+func templateDiffCmd(title, templatePath, from, to string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := templateFileDiffs(templatePath, from, to)
+		return templateDiffMsg{title: title, files: files, err: err}
+	}
+}
+
+// Fills the viewport in the current diff mode, one section per file
+func renderTemplateDiff(m model) model {
+	var lines []string
+	for _, f := range m.templateDiffFiles {
+		title := f.Path
+		switch {
+		case f.Before == nil:
+			title += " (new)"
+		case f.After == nil:
+			title += " (removed)"
+		}
+		lines = append(lines, titleStyle.Render(title))
+		v := diffView{Mode: m.diffMode, Width: m.templateDiffViewport.Width, Syntax: syntaxFor(f.Path), Context: templateDiffContext}
+		lines = append(lines, v.render(f.Before, f.After)...)
+		lines = append(lines, "")
+	}
+	if len(lines) == 0 {
+		lines = []string{"(only commits outside the template directory)"}
+	}
+	m.templateDiffViewport.SetContent(strings.Join(lines, "\n"))
+	return m
+}
+
+func updateTemplateDiff(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(templateDiffMsg); ok {
+		// A diff asked for before the screen was reopened for another deployment
+		if msg.title != m.templateDiffTitle {
+			return m, nil
+		}
+		m.templateDiffLoading = false
+		if msg.err != nil {
+			m.templateDiffViewport.SetContent("Could not diff the template: " + msg.err.Error())
+			return m, nil
+		}
+		m.templateDiffTitle += ", " + plural(len(msg.files), "file") + " changed"
+		m.templateDiffFiles = msg.files
+		return renderTemplateDiff(m), nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.TemplateDiff.Back):
+			return m.popScene(), nil
+		case key.Matches(keyMsg, keys.TemplateDiff.Top):
+			m.templateDiffViewport.GotoTop()
+			return m, nil
+		case key.Matches(keyMsg, keys.TemplateDiff.Bottom):
+			m.templateDiffViewport.GotoBottom()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.templateDiffViewport, cmd = m.templateDiffViewport.Update(msg)
+	return m, cmd
+}
+
+func viewTemplateDiff(m model) (body, tooltip string) {
+	body += tooltipStyle.Render(m.templateDiffTitle)
+	body += "\n" + m.templateDiffViewport.View() + "\n"
+	tooltip = tooltipStyle.Render(fmt.Sprintf("What upgrading to the current template changes in its files — %s switches unified / side by side — %3.f%%",
+		keys.Global.DiffMode.Help().Key, m.templateDiffViewport.ScrollPercent()*100))
+	return body, tooltip
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// --- Proxmox template name filter ---
+
+// Used when config.yaml has no template_filter
+var defaultTemplateFilter = TemplateFilter{
+	Include: []string{`^ubuntu-server-24\.04\..*`},
+	Exclude: []string{`-test$`},
+}
+
+func templateFilterFor(cfg Config, cluster string) TemplateFilter {
+	tf := cfg.TemplateFilter
+	global := tf.TemplateFilter
+	if len(global.Include) == 0 && len(global.Exclude) == 0 && len(tf.Clusters) == 0 {
+		global = defaultTemplateFilter
+	}
+	f := TemplateFilter{Include: global.Include, Exclude: append([]string(nil), global.Exclude...)}
+	if c, ok := tf.Clusters[cluster]; ok {
+		if len(c.Include) > 0 {
+			f.Include = c.Include
+		}
+		f.Exclude = append(f.Exclude, c.Exclude...)
+	}
+	return f
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("bad template_filter pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// Returns the template names that pass the cluster's filter
+func filterTemplates(cfg Config, cluster string, names []string) ([]string, error) {
+	f := templateFilterFor(cfg, cluster)
+	include, err := compilePatterns(f.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compilePatterns(f.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, name := range names {
+		ok := len(include) == 0
+		for _, re := range include {
+			if re.MatchString(name) {
+				ok = true
+				break
+			}
+		}
+		for _, re := range exclude {
+			if re.MatchString(name) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			out = append(out, name)
+		}
+	}
+	return out, nil
+}
+
+// Recomputes the template options of the create form from the cluster's full list
+func applyTemplateFilter(m model) model {
+	cluster := createValue(m, "cluster")
+	if m.showAllTemplates {
+		m.templatesForCluster = m.clusterTemplates
+	} else {
+		filtered, err := filterTemplates(m.cfg, cluster, m.clusterTemplates)
+		if err != nil {
+			m.createStatus = err.Error()
+		}
+		m.templatesForCluster = filtered
+		if len(filtered) == 0 && len(m.clusterTemplates) > 0 {
+			m.createStatus = fmt.Sprintf("No template on %s matches the template filter — %s shows all %d", cluster, keys.Create.AllTemplates.Help().Key, len(m.clusterTemplates))
+		}
+	}
+	if i := indexOf("vm_template", m.createLabels); i >= 0 {
+		if len(m.templatesForCluster) > 0 {
+			if indexOf(m.createInputs[i].Value(), m.templatesForCluster) < 0 {
+				m.createInputs[i].SetValue(m.templatesForCluster[0])
+			}
+		} else {
+			m.createInputs[i].SetValue("")
+		}
+	}
+	return m
+}
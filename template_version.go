@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/git"
+)
+
+// --- Template versioning ---
+
+// Returns the last commit touching the template directory
+func getTemplateCommit(templatePath string) (string, error) {
+	if safeMode {
+		return "", errSafeMode
+	}
+	return git.LastCommit(commands, templatePath)
+}
+
+func setDeploymentTemplate(path, name, commit string) error {
+	s, err := getDeploymentState(path)
+	if err != nil {
+		return err
+	}
+	s.Template = name
+	s.TemplateCommit = commit
+	return writeDeploymentState(path, s)
+}
+
+// Lists the template commits between from and to (oldest last), one "<short> <subject>" per entry.
+// This is the change list an upgrade of a deployment from `from` to `to` would pick up.
+func templateChangeList(templatePath, from, to string) ([]string, error) {
+	if from == "" || to == "" || from == to {
+		return nil, nil
+	}
+	if safeMode {
+		return nil, errSafeMode
+	}
+	return git.Log(commands, templatePath, from, to)
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// Template commit and changes for the deployment at path, from templateInfoCmd
+type templateInfoMsg struct {
+	path    string
+	commit  string
+	changes []string
+}
+
+// Runs the git lookups for the selected deployment's template in the background
+func templateInfoCmd(m model) tea.Cmd {
+	idx := m.deployTable.Cursor()
+	if safeMode || idx < 0 || idx >= len(m.deployments) {
+		return nil
+	}
+	dep := m.deployments[idx]
+	templatePath := templateByName(m.templates, dep.Template).Path
+	return func() tea.Msg {
+		commit, _ := getTemplateCommit(templatePath)
+		changes, _ := templateChangeList(templatePath, dep.TemplateCommit, commit)
+		return templateInfoMsg{path: dep.Path, commit: commit, changes: changes}
+	}
+}
+
+// Starts templateInfoCmd when the selection moved to another deployment; the previous
+// deployment's commit is cleared so the pane never shows it for the new one
+func followTemplateInfo(m model) (model, tea.Cmd) {
+	path := ""
+	if idx := m.deployTable.Cursor(); idx >= 0 && idx < len(m.deployments) {
+		path = m.deployments[idx].Path
+	}
+	if path == m.templateInfoFor {
+		return m, nil
+	}
+	m.templateInfoFor, m.templateCommit, m.templateChanges = path, "", nil
+	return m, templateInfoCmd(m)
+}
+
+// Detail pane lines comparing the selected deployment's template version with the current one
+func templateVersionLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) {
+		return nil
+	}
+	dep := m.deployments[idx]
+	current := shortCommit(m.templateCommit)
+	if current == "" {
+		current = "unknown"
+	}
+	var lines []string
+	switch {
+	case dep.TemplateCommit == "":
+		lines = append(lines, fmt.Sprintf("Template version: untracked  (current: %s)", current))
+	case dep.TemplateCommit == m.templateCommit:
+		lines = append(lines, fmt.Sprintf("Template version: %s  (up to date)", shortCommit(dep.TemplateCommit)))
+	default:
+		lines = append(lines, fmt.Sprintf("Template version: %s  (current: %s, %d change(s) behind, %s diff)",
+			shortCommit(dep.TemplateCommit), current, len(m.templateChanges), keys.Launcher.TemplateDiff.Help().Key))
+		for i, c := range m.templateChanges {
+			if i == 5 {
+				lines = append(lines, fmt.Sprintf("  … %d more", len(m.templateChanges)-i))
+				break
+			}
+			lines = append(lines, "  • "+c)
+		}
+	}
+	for i, l := range lines {
+		lines[i] = truncate(l, width)
+	}
+	return lines
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Template catalog ---
+
+// Form fields used when a template doesn't list its own
+var defaultFormFields = []string{
+	"vm_app", "platform_description", "environment", "criticality", "zone", "platform_id", "vm_network_suffix", "vm_id_prefix",
+	"vm_memory", "vm_cpu_cores", "vm_disk_count", "vm_disk_size", "vm_count", "vm_template",
+	"vm_clone_mode", "cluster",
+}
+
+// Template is one instantiable deployment template, described by an optional template.yaml in its directory
+type Template struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Fields      []string `yaml:"fields"`      // ordered create-form fields
+	FieldsFile  string   `yaml:"fields_file"` // fields.yaml merged over the global one (default: <template>/fields.yaml)
+	PresetsDir  string   `yaml:"presets_dir"` // presets merged over the global ones (default: <template>/presets)
+	// Terraform outputs (kubeconfig, join commands, ...) to keep after apply
+	Artifacts ArtifactsSpec `yaml:"artifacts"`
+	// Probes run after apply and with [T]; results fill the Health column
+	HealthChecks HealthChecksSpec `yaml:"health_checks"`
+	// Terraform variables to fill with generated passwords stored in Vault (passed as TF_VAR_*)
+	Secrets []string `yaml:"secrets"`
+	// "linux" (default) or "windows"; selects fields marked with a matching osFamily
+	OSFamily string `yaml:"os_family"`
+	// Virtualization platform: "proxmox" (default) or "libvirt"
+	Provider string `yaml:"provider"`
+
+	Path       string `yaml:"-"`
+	fieldMeta  map[string]FieldMeta
+	presets    []Preset
+	presetsDir string // template-local presets dir, if any
+}
+
+// Loads every template under cfg.TemplatesPath, or the single cfg.TemplatePath when no catalog dir is configured
+func loadTemplates(cfg Config, fieldMeta map[string]FieldMeta, presets []Preset) ([]Template, error) {
+	if cfg.TemplatesPath == "" {
+		return []Template{{
+			Name:      "default",
+			Path:      cfg.TemplatePath,
+			Fields:    templateDefaultFields(fieldMeta),
+			fieldMeta: fieldMeta,
+			presets:   presets,
+		}}, nil
+	}
+	entries, err := os.ReadDir(cfg.TemplatesPath)
+	if err != nil {
+		return nil, err
+	}
+	var out []Template
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		t, err := loadTemplate(filepath.Join(cfg.TemplatesPath, e.Name()), fieldMeta, presets)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", e.Name(), err)
+		}
+		out = append(out, t)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no templates found in %s", cfg.TemplatesPath)
+	}
+	return out, nil
+}
+
+func loadTemplate(dir string, fieldMeta map[string]FieldMeta, presets []Preset) (Template, error) {
+	var t Template
+	data, err := os.ReadFile(filepath.Join(dir, "template.yaml"))
+	if err == nil {
+		if err := decodeStrict(filepath.Join(dir, "template.yaml"), data, &t); err != nil {
+			return t, err
+		}
+	} else if !os.IsNotExist(err) {
+		return t, err
+	}
+	t.Path = dir
+	if t.Name == "" {
+		t.Name = filepath.Base(dir)
+	}
+	if _, err := providerByName(t.Provider); err != nil {
+		return t, err
+	}
+	// Template-local fields.yaml and presets/ are picked up automatically unless template.yaml points elsewhere
+	fieldsFile := t.FieldsFile
+	if fieldsFile == "" && pathExists(filepath.Join(dir, "fields.yaml")) {
+		fieldsFile = "fields.yaml"
+	}
+	t.fieldMeta = fieldMeta
+	if fieldsFile != "" {
+		path := filepath.Join(dir, fieldsFile)
+		local, err := loadFieldMeta(path)
+		if err != nil {
+			return t, err
+		}
+		t.fieldMeta = mergeFieldMeta(fieldMeta, local)
+		if len(t.Fields) == 0 {
+			order, err := loadFieldOrder(path)
+			if err != nil {
+				return t, err
+			}
+			t.Fields = mergeFieldOrder(templateDefaultFields(fieldMeta), order)
+		}
+	}
+	if len(t.Fields) == 0 {
+		t.Fields = templateDefaultFields(fieldMeta)
+	}
+	presetsDir := t.PresetsDir
+	if presetsDir == "" && pathExists(filepath.Join(dir, "presets")) {
+		presetsDir = "presets"
+	}
+	t.presets = presets
+	if presetsDir != "" {
+		t.presetsDir = filepath.Join(dir, presetsDir)
+		local, err := loadPresets(t.presetsDir)
+		if err != nil {
+			return t, err
+		}
+		t.presets = mergePresets(presets, local)
+	}
+	return t, nil
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Template entries replace global ones key by key
+func mergeFieldMeta(global, local map[string]FieldMeta) map[string]FieldMeta {
+	out := make(map[string]FieldMeta, len(global)+len(local))
+	for k, v := range global {
+		out[k] = v
+	}
+	for k, v := range local {
+		out[k] = v
+	}
+	return out
+}
+
+// Keeps the base order and appends keys only the template knows about
+func mergeFieldOrder(base, extra []string) []string {
+	out := append([]string{}, base...)
+	for _, k := range extra {
+		if indexOf(k, out) < 0 {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Template presets replace global presets of the same name; new ones are appended
+func mergePresets(global, local []Preset) []Preset {
+	out := append([]Preset{}, global...)
+	for _, p := range local {
+		replaced := false
+		for i := range out {
+			if out[i].Name == p.Name {
+				out[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Finds a template by name; deployments created before the catalog fall back to the first one
+// Template of the deployment open in the edit form
+func editTemplate(m model) Template {
+	dep, _ := deploymentByPath(m.deployments, filepath.Dir(m.editFormPath))
+	return templateByName(m.templates, dep.Template)
+}
+
+// Terraform module a new deployment is copied from: the preset's template_dir when it sets
+// one, the active template's directory otherwise
+func createModuleDir(m model) (string, error) {
+	dir := m.presets[m.presetIdx].TemplateDir
+	if dir == "" {
+		return m.activeTemplate.Path, nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(m.activeTemplate.Path, dir)
+	}
+	if st, err := os.Stat(dir); err != nil || !st.IsDir() {
+		return "", fmt.Errorf("preset %s: template_dir %s is not a directory", m.presets[m.presetIdx].Name, dir)
+	}
+	return dir, nil
+}
+
+func templateByName(templates []Template, name string) Template {
+	for _, t := range templates {
+		if t.Name == name {
+			return t
+		}
+	}
+	return templates[0]
+}
+
+// Scopes the create form (fields, presets, inputs) to the given template
+func useTemplate(m model, t Template) model {
+	m.activeTemplate = t
+	m.fieldMeta = t.fieldMeta
+	m.presets = t.presets
+	m.presetIdx = 0
+	m.placement, m.placementPending = nil, false
+	m.createLabels = formFieldOrder(t.Fields, t.fieldMeta)
+	m.createInputs = newCreateInputs(m.createLabels, t.presets[0])
+	maskSecretInputs(m.createInputs, m.createLabels, t.fieldMeta, m.revealSensitive)
+	m.createFocus = 0
+	m.createInputs[0].Focus()
+	m.createHistory = newFormHistory()
+	m.templatesForCluster = nil
+	m.cloneSource = ""
+	m.createStatus = ""
+	m.showAdvanced = false
+	m.collapsedSections = nil
+	return m
+}
+
+func updateTemplatePicker(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Templates.Up):
+			m.templateIdx = (m.templateIdx - 1 + len(m.templates)) % len(m.templates)
+		case key.Matches(msg, keys.Templates.Down):
+			m.templateIdx = (m.templateIdx + 1) % len(m.templates)
+		case key.Matches(msg, keys.Templates.Select):
+			m = useTemplate(m, m.templates[m.templateIdx]).pushScene(sceneCreateForm)
+			m, placeCmd := resolvePlacement(m, true)
+			return m, tea.Batch(capacityCmd(m.activeTemplate.provider(), createValue(m, "cluster"), createValue(m, "vm_template")), placeCmd)
+		case key.Matches(msg, keys.Templates.Back):
+			return m.popScene(), nil
+		}
+	}
+	return m, nil
+}
+
+func viewTemplatePicker(m model) (body, tooltip string) {
+	body += tooltipStyle.Render("Choose a template to instantiate")
+	body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+	for i, t := range m.templates {
+		line := fmt.Sprintf("  %-28s %s", t.Name, t.Description)
+		if i == m.templateIdx {
+			body += focusedStyle.Render(padRight(line, 90)) + "\n"
+		} else {
+			body += normalStyle.Render(line) + "\n"
+		}
+	}
+	t := m.templates[m.templateIdx]
+	tooltip = tooltipStyle.Render(fmt.Sprintf("%s — %d fields, %d presets (%s)", t.Name, len(t.Fields), len(t.presets), t.Path))
+	return body, tooltip
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Operation status in the terminal title (status_title: true) ---
+//
+// The title is set with OSC 2, which tmux shows as #{pane_title} (in the default
+// status-right) and screen as the window's hardstatus (%h).
+
+const idleTitle = "Infrastructure Catalog"
+
+var stepVerbs = map[string]string{"init": "initializing", "plan": "planning", "apply": "applying"}
+
+// e.g. "applying proxmox_web_dmz_12 3m21s (+1 job)"
+func terminalTitle(m model) string {
+	j := m.spotlightJob()
+	if j == nil {
+		return idleTitle
+	}
+	step := j.Op.Steps[j.Op.step].Name
+	verb, ok := stepVerbs[step]
+	if !ok {
+		verb = step
+	}
+	title := fmt.Sprintf("%s %s %s", verb, filepath.Base(j.Op.Dir), time.Since(j.Op.started).Round(time.Second))
+	if n := len(m.runningJobs()) + m.queuedJobCount() - 1; n > 0 {
+		title += fmt.Sprintf(" (+%s)", plural(n, "job"))
+	}
+	return title
+}
+
+// Sets the title when it changed since the last update
+func updateTerminalTitle(m model) (model, tea.Cmd) {
+	if !m.cfg.StatusTitle {
+		return m, nil
+	}
+	title := terminalTitle(m)
+	if title == m.lastTitle {
+		return m, nil
+	}
+	m.lastTitle = title
+	return m, tea.SetWindowTitle(title)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// --- Provider plugin cache and init skipping ---
+
+// Written into .terraform after a successful init: hash of the lock file and *.tf files
+const initStampFile = "launcher-init.sha256"
+
+// Points TF_PLUGIN_CACHE_DIR at plugin_cache_dir (default ~/.terraform.d/plugin-cache)
+// unless it is already set in the environment; "off" leaves terraform's default alone
+func configurePluginCache(cfg Config) error {
+	if os.Getenv("TF_PLUGIN_CACHE_DIR") != "" || cfg.PluginCacheDir == "off" {
+		return nil
+	}
+	dir := cfg.PluginCacheDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(home, ".terraform.d", "plugin-cache")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.Setenv("TF_PLUGIN_CACHE_DIR", dir)
+}
+
+// What init depends on: the provider lock file and the configuration (modules, backend)
+func initInputsHash(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.tf"))
+	sort.Strings(files)
+	files = append(files, filepath.Join(dir, ".terraform.lock.hcl"))
+	h := sha256.New()
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		h.Write([]byte(filepath.Base(f)))
+		h.Write([]byte{0})
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// True when .terraform exists and neither the lock file nor the .tf files changed since the last init
+func initUpToDate(dir string) bool {
+	stamp, err := os.ReadFile(filepath.Join(dir, ".terraform", initStampFile))
+	return err == nil && string(stamp) == initInputsHash(dir)
+}
+
+func recordInit(dir string) {
+	path := filepath.Join(dir, ".terraform", initStampFile)
+	if err := os.WriteFile(path, []byte(initInputsHash(dir)), 0644); err != nil {
+		logger.Warn("could not record init", "component", "terraform", "deployment", filepath.Base(dir), "error", err.Error())
+	}
+}
+
+// Operation that runs init even when it looks up to date; the deployment keeps its state
+func reinitOperation(dir string) *tfOperation {
+	step := tfInitStep
+	step.State = ""
+	return &tfOperation{
+		Label:          "Re-initializing " + filepath.Base(dir),
+		Dir:            dir,
+		Steps:          []tfStep{step},
+		SuccessMessage: "terraform init done for " + filepath.Base(dir),
+		ForceInit:      true,
+	}
+}
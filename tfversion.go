@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"launcher/internal/terraform"
+)
+
+// --- Terraform binary detection and version gate ---
+
+// tfEngineInfo describes the terraform (or OpenTofu) binary found at startup
+type tfEngineInfo = terraform.Engine
+
+var tfEngine tfEngineInfo
+
+// Finds terraform_binary, or terraform then tofu on PATH, and checks the version against cfg.RequiredVersion
+func detectTerraform(cfg Config) tfEngineInfo {
+	candidates := []string{"terraform", "tofu"}
+	if cfg.TerraformBinary != "" {
+		candidates = []string{cfg.TerraformBinary}
+	}
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate)
+		if err != nil {
+			continue
+		}
+		name := "terraform"
+		if strings.Contains(filepath.Base(path), "tofu") {
+			name = "tofu"
+		}
+		e := tfEngineInfo{Binary: path, Name: name, Args: cfg.TerraformArgs}
+		e.Version, err = terraform.Version(commands, path)
+		if err != nil {
+			e.Err = fmt.Errorf("%s version failed: %w", name, err)
+		} else if err := terraform.CheckVersionConstraint(e.Version, cfg.RequiredVersion); err != nil {
+			e.Err = fmt.Errorf("%s %s: %w", name, e.Version, err)
+		}
+		logger.Info("terraform detected", "component", "terraform", "binary", path, "version", e.Version)
+		return e
+	}
+	if cfg.TerraformBinary != "" {
+		return tfEngineInfo{Name: cfg.TerraformBinary, Err: fmt.Errorf("terraform_binary %q was not found", cfg.TerraformBinary)}
+	}
+	return tfEngineInfo{Name: "terraform", Err: fmt.Errorf("neither terraform nor tofu was found in PATH")}
+}
+
+// Builds every terraform invocation: the detected binary, with the configured
+// extra arguments for the subcommand inserted right after it
+func terraformCommand(args ...string) *exec.Cmd {
+	return tfEngine.Command(commands, args...)
+}
+
+// Error to refuse an apply with, nil when the detected binary is usable
+func requireTerraform() error {
+	if tfEngine.Err != nil {
+		return &TerraformError{fmt.Errorf("refusing to apply: %w", tfEngine.Err)}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// --- Color themes (theme:), NO_COLOR and ASCII icons (icons: ascii) ---
+
+type palette struct {
+	Accent      string // title, popup borders
+	Highlight   string // focused field background, busy border
+	OnHighlight string // focused field text
+	Text        string
+	Dim         string // tooltips, log attributes
+	Muted       string // header summary
+	Selected    string // table cursor row
+	OK          string
+	Warn        string
+	Error       string
+	Gradient    [2]string // progress bars; empty for a solid OK fill
+}
+
+var themes = map[string]palette{
+	"dark": {
+		Accent: "81", Highlight: "#FFEB3B", OnHighlight: "#111", Text: "#EEE", Dim: "240", Muted: "245", Selected: "212",
+		OK: "#44cc11", Warn: "#FFA500", Error: "#ff4444", Gradient: [2]string{"#5A56E0", "#EE6FF8"},
+	},
+	"light": {
+		Accent: "25", Highlight: "#1E66F5", OnHighlight: "#FFF", Text: "#222", Dim: "244", Muted: "240", Selected: "162",
+		OK: "#1A7F37", Warn: "#B35900", Error: "#CF222E", Gradient: [2]string{"#1E66F5", "#8839EF"},
+	},
+	"high-contrast": {
+		Accent: "#00FFFF", Highlight: "#FFFF00", OnHighlight: "#000", Text: "#FFF", Dim: "#FFF", Muted: "#FFF", Selected: "#FFFF00",
+		OK: "#00FF00", Warn: "#FFFF00", Error: "#FF0000",
+	},
+}
+
+// Status bar icons; the Nerd Font glyphs need a patched font
+type iconSet struct {
+	AWS, Vault, Git string
+}
+
+var (
+	nerdIcons  = iconSet{AWS: "", Vault: "󰌾", Git: ""}
+	asciiIcons = iconSet{AWS: "aws", Vault: "vault", Git: "git"}
+)
+
+var (
+	theme   = themes["dark"]
+	icons   = nerdIcons
+	noColor bool // NO_COLOR is set: bold/reverse only
+)
+
+// Styles that carry theme colors; built by applyTheme
+var (
+	focusedStyle       lipgloss.Style
+	normalStyle        lipgloss.Style
+	tooltipStyle       lipgloss.Style
+	titleStyle         lipgloss.Style
+	okStyle            lipgloss.Style
+	warnStyle          lipgloss.Style
+	errorStyle         lipgloss.Style
+	busyBoxStyle       lipgloss.Style
+	filterLabelStyle   lipgloss.Style
+	helpKeyStyle       lipgloss.Style
+	logErrorStyle      lipgloss.Style
+	logWarnStyle       lipgloss.Style
+	logDimStyle        lipgloss.Style
+	messageSourceStyle lipgloss.Style
+	presetDiffStyle    lipgloss.Style
+	headerSummaryStyle lipgloss.Style
+	safeModeStyle      lipgloss.Style
+	modifiedStyle      lipgloss.Style
+)
+
+// Picks the theme and icon set from config and NO_COLOR; called before anything renders
+func configureTheme(cfg Config) error {
+	name := cfg.Theme
+	if name == "" {
+		name = "dark"
+	}
+	p, ok := themes[name]
+	if !ok {
+		return &ConfigError{Err: fmt.Errorf("unknown theme %q (dark, light or high-contrast)", name)}
+	}
+	theme = p
+	switch cfg.Icons {
+	case "", "nerd":
+		icons = nerdIcons
+	case "ascii":
+		icons = asciiIcons
+	default:
+		return &ConfigError{Err: fmt.Errorf("unknown icons %q (nerd or ascii)", cfg.Icons)}
+	}
+	// lipgloss drops every attribute under NO_COLOR; keep bold and reverse so focus stays visible
+	noColor = os.Getenv("NO_COLOR") != ""
+	if noColor {
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+	applyTheme()
+	return nil
+}
+
+func color(c string) lipgloss.TerminalColor {
+	if noColor || c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
+
+func fg(c string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(color(c))
+}
+
+func applyTheme() {
+	focusedStyle = lipgloss.NewStyle().Background(color(theme.Highlight)).Foreground(color(theme.OnHighlight)).Bold(true)
+	if noColor {
+		focusedStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+	}
+	normalStyle = fg(theme.Text)
+	tooltipStyle = fg(theme.Dim).Border(lipgloss.RoundedBorder()).Width(uiWidth - 4)
+	titleStyle = fg(theme.Accent).Bold(true)
+	okStyle = fg(theme.OK)
+	warnStyle = fg(theme.Warn)
+	errorStyle = fg(theme.Error)
+	busyBoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(color(theme.Highlight)).
+		Padding(1, 3)
+	filterLabelStyle = fg(theme.Warn).Bold(true)
+	helpKeyStyle = fg(theme.Highlight).Bold(true)
+	logErrorStyle = fg(theme.Error)
+	logWarnStyle = fg(theme.Warn)
+	logDimStyle = fg(theme.Dim)
+	messageSourceStyle = fg(theme.Dim)
+	presetDiffStyle = fg(theme.Warn)
+	headerSummaryStyle = fg(theme.Muted)
+	safeModeStyle = fg(theme.Error).Bold(true)
+	modifiedStyle = fg(theme.Warn).Bold(true)
+}
+
+// Table header and cursor row in theme colors (reverse video under NO_COLOR)
+func tableStyles() table.Styles {
+	s := table.DefaultStyles()
+	s.Header = s.Header.BorderForeground(color(theme.Dim))
+	s.Selected = lipgloss.NewStyle().Bold(true).Foreground(color(theme.Selected))
+	if noColor {
+		s.Selected = s.Selected.Reverse(true)
+	}
+	return s
+}
+
+// The tfvars pane shows its cursor row only while it has the focus
+func tfvarsTableStyles(focused bool) table.Styles {
+	s := tableStyles()
+	if !focused {
+		s.Selected = lipgloss.NewStyle()
+	}
+	return s
+}
+
+// Under NO_COLOR, states shown only by color get a text mark instead
+func noColorMark(mark string) string {
+	if noColor {
+		return mark
+	}
+	return ""
+}
+
+// Border color for popups drawn over a scene
+func popupBorder() lipgloss.TerminalColor {
+	return color(theme.Accent)
+}
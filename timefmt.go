@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- Displayed times: launcher.state and the logs keep UTC, the UI shows timezone (default local) ---
+
+var displayLocation = time.Local
+
+// Sets the zone times are shown in from `timezone:` (an IANA name such as "Europe/Paris")
+func configureTimezone(cfg Config) error {
+	if cfg.Timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone %q: %w", cfg.Timezone, err)
+	}
+	displayLocation = loc
+	return nil
+}
+
+func localTime(t time.Time) time.Time {
+	return t.In(displayLocation)
+}
+
+// "2026-10-15 09:12 CEST"
+func formatTime(t time.Time) string {
+	return localTime(t).Format("2006-01-02 15:04 MST")
+}
+
+// "just now", "12m ago", "2h ago", "3d ago"; the date once it's over a month old
+func relativeTime(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < 0:
+		return "in " + roundDuration(-d)
+	case d < time.Minute:
+		return "just now"
+	case d < 30*24*time.Hour:
+		return roundDuration(d) + " ago"
+	}
+	return localTime(t).Format("2006-01-02")
+}
+
+// "2026-10-15 09:12 CEST (2h ago)" for an RFC 3339 timestamp from launcher.state; s as-is
+// when it doesn't parse
+func describeTime(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return fmt.Sprintf("%s (%s)", formatTime(t), relativeTime(t))
+}
+
+// Last Action column: how long ago launcher.state last changed
+func lastActionAge(info deploymentInfo) string {
+	if info.LastActionAt.IsZero() {
+		return ""
+	}
+	return relativeTime(info.LastActionAt)
+}
+
+// "Last action: apply, 2026-10-15 09:12 CEST (2h ago) by alice@ws01" for the detail pane
+func lastActionLines(m model, width int) []string {
+	idx := m.deployTable.Cursor()
+	if idx < 0 || idx >= len(m.deployments) || m.deployments[idx].LastActionAt.IsZero() {
+		return nil
+	}
+	d := m.deployments[idx]
+	line := fmt.Sprintf("Last action: %s, %s (%s)", d.LastAction, formatTime(d.LastActionAt), relativeTime(d.LastActionAt))
+	if d.LastBy != "" {
+		line += " by " + d.LastBy
+	}
+	if len(d.LastVars) > 0 {
+		line += ", with -var " + strings.Join(d.LastVars, " ")
+	}
+	return []string{truncate(line, width)}
+}
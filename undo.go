@@ -0,0 +1,128 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Undo/redo for the create and edit forms (Ctrl+Z / Ctrl+Y) ---
+
+const maxUndoSteps = 100
+
+type formSnapshot struct {
+	values []string
+	focus  int
+}
+
+type formHistory struct {
+	undo, redo []formSnapshot
+	typingIn   int // field being typed into; its keystrokes make one undo step
+}
+
+func newFormHistory() formHistory {
+	return formHistory{typingIn: -1}
+}
+
+func formValues(inputs []textinput.Model) []string {
+	values := make([]string, len(inputs))
+	for i, ti := range inputs {
+		values[i] = ti.Value()
+	}
+	return values
+}
+
+// Records the form as it was before a change, unless the change continues typing in the same field
+func (h *formHistory) record(before formSnapshot, typed bool) {
+	if typed && h.typingIn == before.focus {
+		h.redo = nil
+		return
+	}
+	h.typingIn = -1
+	if typed {
+		h.typingIn = before.focus
+	}
+	h.undo = append(h.undo, before)
+	if len(h.undo) > maxUndoSteps {
+		h.undo = h.undo[1:]
+	}
+	h.redo = nil
+}
+
+// Moves one step from one stack to the other and restores it into inputs
+func (h *formHistory) step(inputs []textinput.Model, focus *int, from, to *[]formSnapshot) bool {
+	if len(*from) == 0 {
+		return false
+	}
+	snap := (*from)[len(*from)-1]
+	if len(snap.values) != len(inputs) {
+		// The form was rebuilt (other template) since; the history no longer applies
+		*h = newFormHistory()
+		return false
+	}
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, formSnapshot{values: formValues(inputs), focus: *focus})
+	for i, v := range snap.values {
+		inputs[i].SetValue(v)
+	}
+	inputs[*focus].Blur()
+	*focus = snap.focus
+	inputs[*focus].Focus()
+	h.typingIn = -1
+	return true
+}
+
+// Runs update on the create form (or the preset manager, which fills it) and records value changes
+func updateCreateWithHistory(m model, msg tea.Msg, update func(model, tea.Msg) (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	if k, ok := msg.(tea.KeyMsg); ok && m.currentScene == sceneCreateForm {
+		h := &m.createHistory
+		switch {
+		case key.Matches(k, keys.Create.Undo):
+			if !h.step(m.createInputs, &m.createFocus, &h.undo, &h.redo) {
+				m.createStatus = "Nothing to undo"
+			}
+			return m, nil
+		case key.Matches(k, keys.Create.Redo):
+			if !h.step(m.createInputs, &m.createFocus, &h.redo, &h.undo) {
+				m.createStatus = "Nothing to redo"
+			}
+			return m, nil
+		}
+	}
+	before := formSnapshot{values: formValues(m.createInputs), focus: m.createFocus}
+	next, cmd := update(m, msg)
+	after := next.(model)
+	if len(after.createInputs) == len(before.values) && !slices.Equal(before.values, formValues(after.createInputs)) {
+		k, ok := msg.(tea.KeyMsg)
+		after.createHistory.record(before, ok && isTypingKey(k) && after.currentScene == sceneCreateForm)
+	}
+	return after, cmd
+}
+
+func updateEditWithHistory(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if k, ok := msg.(tea.KeyMsg); ok {
+		h := &m.editHistory
+		switch {
+		case key.Matches(k, keys.Edit.Undo):
+			if !h.step(m.editFormInputs, &m.editFocusIndex, &h.undo, &h.redo) {
+				m.editStatus = "Nothing to undo"
+			}
+			return m, nil
+		case key.Matches(k, keys.Edit.Redo):
+			if !h.step(m.editFormInputs, &m.editFocusIndex, &h.redo, &h.undo) {
+				m.editStatus = "Nothing to redo"
+			}
+			return m, nil
+		}
+	}
+	before := formSnapshot{values: formValues(m.editFormInputs), focus: m.editFocusIndex}
+	next, cmd := updateEditForm(m, msg)
+	after := next.(model)
+	if len(after.editFormInputs) == len(before.values) && !slices.Equal(before.values, formValues(after.editFormInputs)) {
+		k, ok := msg.(tea.KeyMsg)
+		after.editHistory.record(before, ok && isTypingKey(k))
+	}
+	return after, cmd
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"launcher/internal/vaultclient"
+)
+
+// --- Proxmox credentials in Vault ([K] on the launcher): list, test, create and update ---
+//
+// One KV v2 secret per cluster under proxmox_api_keys, with the fields getProxmoxCreds reads.
+// Onboarding a cluster is New, fill in the three fields, save; the entry is tested right after.
+
+const (
+	vaultCredsMount = vaultclient.CredsMount
+	vaultURLField   = vaultclient.URLField
+	vaultIDField    = vaultclient.TokenIDField
+	vaultSecField   = vaultclient.TokenSecField
+)
+
+// One cluster's entry; err is set when its secret couldn't be read
+type vaultCred struct {
+	Cluster string
+	URL     string
+	TokenID string
+	Secret  string
+	err     error
+}
+
+type vaultCredsMsg struct {
+	creds []vaultCred
+	err   error
+}
+
+// vaultActionMsg reports a save or a credentials test of cluster
+type vaultActionMsg struct {
+	cluster string
+	status  string
+	test    string // new Test column value, "" keeps it
+	reload  bool
+}
+
+// Create/update form: cluster, API host, token ID, token secret
+type vaultCredForm struct {
+	inputs []textinput.Model
+	focus  int
+	isNew  bool
+}
+
+var vaultFormLabels = []string{"Cluster", "API host", "Token ID", "Token secret"}
+
+func openVaultBrowser(m model) (model, tea.Cmd) {
+	m.vaultCreds = nil
+	m.vaultForm = nil
+	m.vaultTests = map[string]string{}
+	m.vaultCredsStatus = "Listing " + vaultCredsMount + " ..."
+	m.vaultTable = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Cluster", Width: 16},
+			{Title: "API host", Width: 36},
+			{Title: "Token ID", Width: 36},
+			{Title: "Secret", Width: 10},
+			{Title: "Test", Width: 30},
+		}),
+		table.WithFocused(true),
+		table.WithKeyMap(tableKeys(keys.Vault.Up, keys.Vault.Down)),
+		table.WithStyles(tableStyles()),
+		table.WithHeight(uiHeight-16),
+	)
+	return m.pushScene(sceneVault), loadVaultCredsCmd()
+}
+
+func loadVaultCredsCmd() tea.Cmd {
+	return func() tea.Msg {
+		clusters, err := discoverVaultClusters(vaultCredsMount + "/metadata")
+		if err != nil {
+			return vaultCredsMsg{err: err}
+		}
+		client, err := newVaultClient()
+		if err != nil {
+			return vaultCredsMsg{err: err}
+		}
+		creds := make([]vaultCred, len(clusters))
+		for i, c := range clusters {
+			creds[i] = vaultCred{Cluster: c}
+			creds[i].URL, creds[i].TokenID, creds[i].Secret, creds[i].err = readProxmoxCredsFromVault(client.Logical(), c)
+		}
+		return vaultCredsMsg{creds: creds}
+	}
+}
+
+// Writes the entry of c, keeping any other fields the secret already has
+func writeVaultCred(c vaultCred) error {
+	client, err := newVaultClient()
+	if err != nil {
+		return err
+	}
+	path := vaultclient.CredsPath(c.Cluster)
+	data := map[string]interface{}{}
+	if existing, err := client.Logical().Read(path); err == nil {
+		maps.Copy(data, vaultclient.Data(existing))
+	}
+	data[vaultURLField], data[vaultIDField], data[vaultSecField] = c.URL, c.TokenID, c.Secret
+	err = vaultclient.WriteKV(client.Logical(), path, data)
+	logVault("write", path, err)
+	return err
+}
+
+// Calls the Proxmox API's version endpoint with c's token
+func testVaultCred(c vaultCred) vaultActionMsg {
+	var v struct {
+		Version string `json:"version"`
+	}
+	if err := proxmoxGet(c.URL, c.TokenID, c.Secret, "version", &v); err != nil {
+		return vaultActionMsg{cluster: c.Cluster, status: fmt.Sprintf("%s: %v", c.Cluster, err), test: "FAILED: " + err.Error()}
+	}
+	return vaultActionMsg{cluster: c.Cluster, status: fmt.Sprintf("%s: Proxmox VE %s answered", c.Cluster, v.Version), test: "OK " + v.Version}
+}
+
+func vaultRows(creds []vaultCred, tests map[string]string, reveal bool) []table.Row {
+	rows := make([]table.Row, len(creds))
+	for i, c := range creds {
+		if c.err != nil {
+			rows[i] = table.Row{c.Cluster, "", "", "", "UNREADABLE: " + c.err.Error()}
+			continue
+		}
+		secret := maskedValue
+		if reveal {
+			secret = c.Secret
+		}
+		rows[i] = table.Row{c.Cluster, c.URL, c.TokenID, secret, tests[c.Cluster]}
+	}
+	return rows
+}
+
+func selectedVaultCred(m model) (vaultCred, bool) {
+	i := m.vaultTable.Cursor()
+	if i < 0 || i >= len(m.vaultCreds) {
+		return vaultCred{}, false
+	}
+	return m.vaultCreds[i], true
+}
+
+// Form for c; a new entry starts on the cluster name, an existing one on the API host
+func newVaultCredForm(c vaultCred, isNew, reveal bool) *vaultCredForm {
+	f := &vaultCredForm{isNew: isNew}
+	for i, v := range []string{c.Cluster, c.URL, c.TokenID, c.Secret} {
+		ti := textinput.New()
+		ti.Placeholder = strings.ToLower(vaultFormLabels[i])
+		ti.SetValue(v)
+		f.inputs = append(f.inputs, ti)
+	}
+	f.inputs[1].Placeholder = "pve1.example.com"
+	f.inputs[2].Placeholder = "terraform@pve!launcher"
+	f.setEcho(reveal)
+	if !isNew {
+		f.focus = 1
+	}
+	f.inputs[f.focus].Focus()
+	return f
+}
+
+func (f *vaultCredForm) setEcho(reveal bool) {
+	f.inputs[3].EchoCharacter = '•'
+	f.inputs[3].EchoMode = textinput.EchoPassword
+	if reveal {
+		f.inputs[3].EchoMode = textinput.EchoNormal
+	}
+}
+
+// Moves focus by delta; the cluster name of an existing entry can't change
+func (f *vaultCredForm) move(delta int) {
+	first := 0
+	if !f.isNew {
+		first = 1
+	}
+	n := len(f.inputs) - first
+	f.inputs[f.focus].Blur()
+	f.focus = first + ((f.focus-first+delta)%n+n)%n
+	f.inputs[f.focus].Focus()
+}
+
+func (f *vaultCredForm) cred() vaultCred {
+	return vaultCred{
+		Cluster: strings.TrimSpace(f.inputs[0].Value()),
+		URL:     strings.TrimSpace(f.inputs[1].Value()),
+		TokenID: strings.TrimSpace(f.inputs[2].Value()),
+		Secret:  strings.TrimSpace(f.inputs[3].Value()),
+	}
+}
+
+func updateVaultBrowser(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case vaultCredsMsg:
+		if msg.err != nil {
+			m.vaultCredsStatus = "Could not list " + vaultCredsMount + ": " + msg.err.Error()
+			return m, nil
+		}
+		sort.Slice(msg.creds, func(i, j int) bool { return msg.creds[i].Cluster < msg.creds[j].Cluster })
+		m.vaultCreds = msg.creds
+		m.vaultTable.SetRows(vaultRows(m.vaultCreds, m.vaultTests, m.revealSensitive))
+		m.vaultCredsStatus = fmt.Sprintf("%s in %s", plural(len(msg.creds), "cluster"), vaultCredsMount)
+		return m, nil
+	case vaultActionMsg:
+		m.vaultCredsStatus = msg.status
+		if msg.test != "" {
+			m.vaultTests[msg.cluster] = msg.test
+		}
+		if msg.reload {
+			return m, loadVaultCredsCmd()
+		}
+		m.vaultTable.SetRows(vaultRows(m.vaultCreds, m.vaultTests, m.revealSensitive))
+		return m, nil
+	case tea.KeyMsg:
+		if m.vaultForm != nil {
+			return updateVaultForm(m, msg)
+		}
+		k := keys.Vault
+		switch {
+		case key.Matches(msg, k.Back):
+			return m.popScene(), nil
+		case key.Matches(msg, k.Reload):
+			m.vaultCredsStatus = "Reloading..."
+			return m, loadVaultCredsCmd()
+		case key.Matches(msg, k.New):
+			m.vaultForm = newVaultCredForm(vaultCred{}, true, m.revealSensitive)
+			m.vaultCredsStatus = ""
+			return m, textinput.Blink
+		case key.Matches(msg, k.Edit):
+			c, ok := selectedVaultCred(m)
+			if !ok {
+				return m, nil
+			}
+			m.vaultForm = newVaultCredForm(c, false, m.revealSensitive)
+			m.vaultCredsStatus = ""
+			return m, textinput.Blink
+		case key.Matches(msg, k.Test):
+			c, ok := selectedVaultCred(m)
+			if !ok || c.err != nil {
+				m.vaultCredsStatus = "Nothing to test: the entry couldn't be read"
+				return m, nil
+			}
+			m.vaultCredsStatus = "Testing " + c.Cluster + " ..."
+			m.vaultTests[c.Cluster] = "testing..."
+			m.vaultTable.SetRows(vaultRows(m.vaultCreds, m.vaultTests, m.revealSensitive))
+			return m, func() tea.Msg { return testVaultCred(c) }
+		}
+	}
+	var cmd tea.Cmd
+	m.vaultTable, cmd = m.vaultTable.Update(msg)
+	return m, cmd
+}
+
+func updateVaultForm(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.vaultForm
+	k := keys.Vault
+	switch {
+	case key.Matches(msg, k.FormCancel):
+		m.vaultForm = nil
+		m.vaultCredsStatus = "Not saved"
+		return m, nil
+	case key.Matches(msg, k.FormNext):
+		f.move(+1)
+		return m, nil
+	case key.Matches(msg, k.FormPrev):
+		f.move(-1)
+		return m, nil
+	case key.Matches(msg, k.FormSave):
+		c := f.cred()
+		for i, v := range []string{c.Cluster, c.URL, c.TokenID, c.Secret} {
+			if v == "" {
+				m.vaultCredsStatus = vaultFormLabels[i] + " is required"
+				return m, nil
+			}
+		}
+		if strings.ContainsAny(c.Cluster, "/ ") {
+			m.vaultCredsStatus = "The cluster name can't contain spaces or slashes"
+			return m, nil
+		}
+		if f.isNew {
+			for _, e := range m.vaultCreds {
+				if e.Cluster == c.Cluster {
+					m.vaultCredsStatus = c.Cluster + " already exists — select it and edit it instead"
+					return m, nil
+				}
+			}
+		}
+		m.vaultForm = nil
+		m.vaultCredsStatus = "Saving " + c.Cluster + " ..."
+		action := "vault-update"
+		if f.isNew {
+			action = "vault-create"
+		}
+		return m, func() tea.Msg {
+			path := vaultCredsMount + "/data/" + c.Cluster
+			if err := writeVaultCred(c); err != nil {
+				recordAudit(action, c.Cluster, path, "failed: "+err.Error())
+				return vaultActionMsg{cluster: c.Cluster, status: "Save failed: " + err.Error()}
+			}
+			recordAudit(action, c.Cluster, path, "ok")
+			res := testVaultCred(c)
+			res.status = "Saved " + path + " — " + res.status
+			res.reload = true
+			return res
+		}
+	}
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return m, cmd
+}
+
+func viewVaultBrowser(m model) (body, tooltip string) {
+	if f := m.vaultForm; f != nil {
+		title := "New Proxmox credentials in " + vaultCredsMount
+		if !f.isNew {
+			title = "Proxmox credentials of " + f.inputs[0].Value()
+		}
+		body += tooltipStyle.Render(title)
+		body += "\n" + " " + strings.Repeat("─", uiWidth-4) + "\n"
+		for i, ti := range f.inputs {
+			body += formFieldLine(m, fmt.Sprintf("vault:%d", i), vaultFormLabels[i], inputDisplay(ti), i == f.focus, false) + "\n"
+		}
+		tooltip = m.vaultCredsStatus
+		if tooltip == "" {
+			tooltip = fmt.Sprintf("API host is the Proxmox host name (port 8006). %s saves and tests the token, %s cancels.",
+				keys.Vault.FormSave.Help().Key, keys.Vault.FormCancel.Help().Key)
+		}
+		return body, tooltipStyle.Render(tooltip)
+	}
+	body += tooltipStyle.Render("Proxmox API credentials in Vault (" + vaultCredsMount + "), one secret per cluster")
+	body += "\n" + m.vaultTable.View() + "\n"
+	return body, tooltipStyle.Render(m.vaultCredsStatus)
+}
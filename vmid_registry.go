@@ -0,0 +1,380 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"launcher/internal/filelock"
+)
+
+// --- VMID registry: which deployment owns which range of Proxmox VM IDs ---
+//
+// One YAML file shared by everyone creating deployments (by default in the terraform repo
+// checkout, so it travels with git). Creating a deployment reserves the first free block
+// that is neither registered nor in use on the cluster and writes its first ID to tfvars;
+// destroying it releases the block. `launcher vmids` reconciles the file with Proxmox.
+
+// IDs are reserved in blocks of vmidBlock, so a deployment can grow by a few VMs in place
+const (
+	vmidBlock       = 10
+	vmidLockTimeout = 10 * time.Second
+)
+
+type vmidRange struct {
+	Deployment string   `yaml:"deployment"`
+	Cluster    string   `yaml:"cluster"`
+	Start      int      `yaml:"start"`
+	Count      int      `yaml:"count"`
+	Allocated  string   `yaml:"allocated"`
+	By         identity `yaml:"by"`
+}
+
+type vmidRegistry struct {
+	Ranges []vmidRange `yaml:"ranges"`
+}
+
+// errVMIDTaken is returned (wrapped) when a range overlaps a registered one
+var errVMIDTaken = errors.New("VMID range already registered")
+
+func (r vmidRange) end() int { return r.Start + r.Count - 1 }
+
+func (r vmidRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Start, r.end())
+}
+
+func vmidRegistryPath(cfg Config) string {
+	if filepath.IsAbs(cfg.VMIDRegistry.Path) {
+		return cfg.VMIDRegistry.Path
+	}
+	return filepath.Join(cfg.TerraformPath, cfg.VMIDRegistry.Path)
+}
+
+// Block-sized number of IDs reserved for count VMs
+func vmidBlockSize(count int) int {
+	if count < 1 {
+		count = 1
+	}
+	return (count + vmidBlock - 1) / vmidBlock * vmidBlock
+}
+
+func loadVMIDRegistry(path string) (vmidRegistry, error) {
+	var r vmidRegistry
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+func (r vmidRegistry) save(path string) error {
+	sort.Slice(r.Ranges, func(i, j int) bool { return r.Ranges[i].Start < r.Ranges[j].Start })
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// The registered range overlapping start..start+count-1, if any
+func (r vmidRegistry) overlapping(start, count int) (vmidRange, bool) {
+	for _, e := range r.Ranges {
+		if start <= e.end() && e.Start <= start+count-1 {
+			return e, true
+		}
+	}
+	return vmidRange{}, false
+}
+
+func (r vmidRegistry) byDeployment(name string) (vmidRange, bool) {
+	for _, e := range r.Ranges {
+		if e.Deployment == name {
+			return e, true
+		}
+	}
+	return vmidRange{}, false
+}
+
+// First block of size IDs within lo..hi that is neither registered nor used on the cluster
+func (r vmidRegistry) firstFree(size, lo, hi int, used map[int]string) (int, error) {
+	for start := (lo + vmidBlock - 1) / vmidBlock * vmidBlock; start+size-1 <= hi; start += vmidBlock {
+		if _, ok := r.overlapping(start, size); ok {
+			continue
+		}
+		if _, ok := firstUsed(start, size, used); ok {
+			continue
+		}
+		return start, nil
+	}
+	return 0, fmt.Errorf("no free block of %d VMIDs between %d and %d", size, lo, hi)
+}
+
+func firstUsed(start, count int, used map[int]string) (int, bool) {
+	for id := start; id < start+count; id++ {
+		if _, ok := used[id]; ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Runs fn on the registry under its lock file and saves the result
+func updateVMIDRegistry(cfg Config, fn func(*vmidRegistry) error) error {
+	path := vmidRegistryPath(cfg)
+	unlock, err := lockVMIDRegistry(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	r, err := loadVMIDRegistry(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(&r); err != nil {
+		return err
+	}
+	return r.save(path)
+}
+
+// Takes a flock on <registry>.lock, waiting up to vmidLockTimeout; the lock goes away with
+// the process holding it, so a crash leaves nothing to clean up
+func lockVMIDRegistry(path string) (func(), error) {
+	lock := path + ".lock"
+	unlock, err := filelock.LockTimeout(lock, vmidLockTimeout)
+	if errors.Is(err, filelock.ErrLocked) {
+		return nil, fmt.Errorf("VMID registry is locked (%s)", lock)
+	}
+	return unlock, err
+}
+
+// VM IDs in use on a Proxmox cluster, with the VM names
+func proxmoxUsedVMIDs(cluster string) (map[int]string, error) {
+	apiURL, tokenID, tokenSecret, err := getProxmoxCreds(cluster)
+	if err != nil {
+		return nil, err
+	}
+	var vms []ProxmoxVM
+	if err := proxmoxGet(apiURL, tokenID, tokenSecret, "cluster/resources?type=vm", &vms); err != nil {
+		return nil, err
+	}
+	used := make(map[int]string, len(vms))
+	for _, vm := range vms {
+		used[vm.VmID] = vm.Name
+	}
+	return used, nil
+}
+
+// Range proposed for a new deployment of count VMs on cluster; requested is the variable's
+// value when the form already has one, checked instead of picking a block
+func proposeVMIDRange(cfg Config, cluster string, count int, requested string) (vmidRange, error) {
+	r, err := loadVMIDRegistry(vmidRegistryPath(cfg))
+	if err != nil {
+		return vmidRange{}, err
+	}
+	used, err := proxmoxUsedVMIDs(cluster)
+	if err != nil {
+		return vmidRange{}, fmt.Errorf("could not list the VMIDs of %s: %w", cluster, err)
+	}
+	size := vmidBlockSize(count)
+	if requested != "" {
+		var start int
+		if _, err := fmt.Sscanf(requested, "%d", &start); err != nil {
+			return vmidRange{}, fmt.Errorf("%s: %q is not a VMID", cfg.VMIDRegistry.VarName(), requested)
+		}
+		if e, ok := r.overlapping(start, count); ok {
+			return vmidRange{}, fmt.Errorf("%w: %d-%d overlaps %s of %s", errVMIDTaken, start, start+count-1, e, e.Deployment)
+		}
+		if id, ok := firstUsed(start, count, used); ok {
+			return vmidRange{}, fmt.Errorf("VMID %d is in use on %s (%s)", id, cluster, used[id])
+		}
+		return vmidRange{Cluster: cluster, Start: start, Count: count}, nil
+	}
+	lo, hi := cfg.VMIDRegistry.Bounds()
+	start, err := r.firstFree(size, lo, hi, used)
+	if err != nil {
+		return vmidRange{}, err
+	}
+	return vmidRange{Cluster: cluster, Start: start, Count: size}, nil
+}
+
+// Registers rng for deployment, unless someone took an overlapping range since it was proposed
+func reserveVMIDRange(cfg Config, deployment string, rng vmidRange) error {
+	return updateVMIDRegistry(cfg, func(r *vmidRegistry) error {
+		if e, ok := r.overlapping(rng.Start, rng.Count); ok {
+			return fmt.Errorf("%w: %s was just taken by %s — submit again", errVMIDTaken, rng, e.Deployment)
+		}
+		rng.Deployment = deployment
+		rng.Allocated = time.Now().UTC().Format(time.RFC3339)
+		rng.By = currentIdentity()
+		r.Ranges = append(r.Ranges, rng)
+		recordAudit("vmid-reserve", deployment, rng.String(), "ok")
+		return nil
+	})
+}
+
+// Frees the range of deployment once it is destroyed
+func releaseVMIDRange(cfg Config, deployment string) error {
+	if !cfg.VMIDRegistry.Enabled() {
+		return nil
+	}
+	return updateVMIDRegistry(cfg, func(r *vmidRegistry) error {
+		for i, e := range r.Ranges {
+			if e.Deployment == deployment {
+				r.Ranges = append(r.Ranges[:i], r.Ranges[i+1:]...)
+				recordAudit("vmid-release", deployment, e.String(), "ok")
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// launcher vmids [reconcile|import]
+//
+//	vmids            prints the registry
+//	vmids reconcile  compares it with the clusters: VMs outside any range, ranges of deployments
+//	                 that no longer exist, overlapping ranges
+//	vmids import     registers the deployments missing from the registry from their vm_ids output
+func cliVMIDs(cfg Config, templates []Template, args []string) error {
+	fsFlags := flag.NewFlagSet("vmids", flag.ContinueOnError)
+	if err := fsFlags.Parse(args); err != nil {
+		return &ValidationError{err}
+	}
+	if !cfg.VMIDRegistry.Enabled() {
+		return validationErrorf("vmid_registry.path is not set in config.yaml")
+	}
+	switch fsFlags.Arg(0) {
+	case "":
+		r, err := loadVMIDRegistry(vmidRegistryPath(cfg))
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RANGE\tDEPLOYMENT\tCLUSTER\tALLOCATED\tBY")
+		for _, e := range r.Ranges {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e, e.Deployment, e.Cluster, e.Allocated, e.By)
+		}
+		return w.Flush()
+	case "reconcile":
+		problems, err := reconcileVMIDs(cfg)
+		if err != nil {
+			return err
+		}
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if len(problems) > 0 {
+			return validationErrorf("%s", plural(len(problems), "problem"))
+		}
+		fmt.Println("registry matches the clusters")
+		return nil
+	case "import":
+		return importVMIDs(cfg, templates)
+	}
+	return validationErrorf("usage: launcher vmids [reconcile|import]")
+}
+
+// Differences between the registry, the deployment directories and the clusters
+func reconcileVMIDs(cfg Config) ([]string, error) {
+	r, err := loadVMIDRegistry(vmidRegistryPath(cfg))
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	clusters := map[string]bool{}
+	for _, c := range configClusters(cfg) {
+		clusters[c] = true
+	}
+	for i, e := range r.Ranges {
+		clusters[e.Cluster] = true
+		if _, err := os.Stat(filepath.Join(cfg.AppsPath, e.Deployment)); os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("STALE       %s registered to %s, which no longer exists", e, e.Deployment))
+		}
+		for _, o := range r.Ranges[i+1:] {
+			if e.Start <= o.end() && o.Start <= e.end() {
+				problems = append(problems, fmt.Sprintf("OVERLAP     %s (%s) and %s (%s)", e, e.Deployment, o, o.Deployment))
+			}
+		}
+	}
+	names := make([]string, 0, len(clusters))
+	for c := range clusters {
+		if c != "" {
+			names = append(names, c)
+		}
+	}
+	sort.Strings(names)
+	for _, c := range names {
+		used, err := proxmoxUsedVMIDs(c)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("UNREACHABLE %s: %v", c, err))
+			continue
+		}
+		ids := make([]int, 0, len(used))
+		for id := range used {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			if _, ok := r.overlapping(id, 1); !ok {
+				problems = append(problems, fmt.Sprintf("UNREGISTERED VM %d (%s) on %s", id, used[id], c))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// Registers every deployment without a range from the VM IDs in its terraform output
+func importVMIDs(cfg Config, templates []Template) error {
+	deployments, err := listDeployments(cfg.AppsPath)
+	if err != nil {
+		return err
+	}
+	return updateVMIDRegistry(cfg, func(r *vmidRegistry) error {
+		for _, d := range deployments {
+			if _, ok := r.byDeployment(d.Name); ok {
+				continue
+			}
+			outputs, err := readTerraformOutputs(d.Path)
+			var ids []int
+			if err == nil {
+				ids, err = outputVMIDs(templateByName(templates, d.Template), outputs)
+			}
+			if err != nil || len(ids) == 0 {
+				fmt.Printf("skipped %s: no VM IDs in its outputs\n", d.Name)
+				continue
+			}
+			sort.Ints(ids)
+			rng := vmidRange{Deployment: d.Name, Start: ids[0], Count: ids[len(ids)-1] - ids[0] + 1,
+				Allocated: time.Now().UTC().Format(time.RFC3339), By: currentIdentity()}
+			if vals, err := loadTfvars(filepath.Join(d.Path, "terraform.tfvars")); err == nil {
+				rng.Cluster = strings.Trim(vals["cluster"], "\"")
+			}
+			if e, ok := r.overlapping(rng.Start, rng.Count); ok {
+				fmt.Printf("conflict %s: %s overlaps %s of %s\n", d.Name, rng, e, e.Deployment)
+				continue
+			}
+			r.Ranges = append(r.Ranges, rng)
+			recordAudit("vmid-import", d.Path, rng.String(), "ok")
+			fmt.Printf("registered %s: %s\n", d.Name, rng)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestReserveVMIDRangeConcurrent(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", root)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+	cfg := Config{TerraformPath: root, VMIDRegistry: VMIDRegistryConfig{Path: "vmid-registry.yaml"}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- reserveVMIDRange(cfg, fmt.Sprintf("app_%d", i), vmidRange{Cluster: "pve", Start: 1000 + i*vmidBlock, Count: vmidBlock})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := loadVMIDRegistry(vmidRegistryPath(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Ranges) != 8 {
+		t.Errorf("registry holds %d ranges after 8 reservations, want 8", len(r.Ranges))
+	}
+	if err := reserveVMIDRange(cfg, "late", vmidRange{Start: 1005, Count: 2}); err == nil {
+		t.Error("overlapping reservation accepted")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// --- Watch mode: refresh deployments when their files change on disk ---
+
+// Editors save in several writes (temp file, rename, chmod); changes are coalesced for this long
+const watchDebounce = 300 * time.Millisecond
+
+// Files of a deployment the launcher shows; terraform's own churn (.terraform, plans, state) is ignored
+var watchedFiles = map[string]bool{"terraform.tfvars": true, "launcher.state": true}
+
+// filesChangedMsg lists the deployment directories changed on disk since the last one
+type filesChangedMsg struct {
+	dirs []string
+}
+
+// Watches the apps directory and every deployment directory in it (fsnotify isn't recursive).
+// Returns the channel the debounced filesChangedMsg are sent on.
+func startWatcher(appsPath string) (chan tea.Msg, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Add(appsPath); err != nil {
+		w.Close()
+		return nil, err
+	}
+	entries, err := os.ReadDir(appsPath)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := w.Add(filepath.Join(appsPath, e.Name())); err != nil {
+				logger.Warn("could not watch deployment", "component", "watch", "dir", e.Name(), "error", err.Error())
+			}
+		}
+	}
+	events := make(chan tea.Msg, 1)
+	go watchLoop(w, appsPath, events)
+	return events, nil
+}
+
+func watchLoop(w *fsnotify.Watcher, appsPath string, events chan tea.Msg) {
+	pending := map[string]bool{}
+	flush := time.NewTimer(watchDebounce)
+	flush.Stop()
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if dir := watchedDeployment(w, appsPath, ev); dir != "" {
+				if len(pending) == 0 {
+					flush.Reset(watchDebounce)
+				}
+				pending[dir] = true
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			logger.Warn("file watcher error", "component", "watch", "error", err.Error())
+		case <-flush.C:
+			msg := filesChangedMsg{}
+			for dir := range pending {
+				msg.dirs = append(msg.dirs, dir)
+			}
+			clear(pending)
+			events <- msg
+		}
+	}
+}
+
+// Deployment directory an event concerns, or "" when it doesn't change anything shown.
+// New deployment directories are watched as they appear.
+func watchedDeployment(w *fsnotify.Watcher, appsPath string, ev fsnotify.Event) string {
+	parent := filepath.Dir(ev.Name)
+	if parent == filepath.Clean(appsPath) {
+		if ev.Has(fsnotify.Create) {
+			if st, err := os.Stat(ev.Name); err != nil || !st.IsDir() {
+				return ""
+			}
+			if err := w.Add(ev.Name); err != nil {
+				logger.Warn("could not watch deployment", "component", "watch", "dir", filepath.Base(ev.Name), "error", err.Error())
+			}
+			return ev.Name
+		}
+		if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+			return ev.Name // refreshDeploymentCmd drops it when it's gone
+		}
+		return ""
+	}
+	if filepath.Dir(parent) == filepath.Clean(appsPath) && watchedFiles[filepath.Base(ev.Name)] && !ev.Has(fsnotify.Chmod) {
+		return parent
+	}
+	return ""
+}
+
+func waitForWatchEvent(events chan tea.Msg) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// Re-reads the changed deployments and waits for the next change
+func handleFilesChanged(m model, msg filesChangedMsg) (model, tea.Cmd) {
+	cmds := []tea.Cmd{waitForWatchEvent(m.watchEvents)}
+	for _, dir := range msg.dirs {
+		cmds = append(cmds, refreshDeploymentCmd(dir))
+	}
+	return m, tea.Batch(cmds...)
+}